@@ -198,6 +198,10 @@ const (
 	REQUESTS_1000MS = "requests_1000ms"
 	REQUESTS_5000MS = "requests_5000ms"
 
+	PREPARED_CACHE_HITS      = "prepared_cache_hits"
+	PREPARED_CACHE_MISSES    = "prepared_cache_misses"
+	PREPARED_CACHE_EVICTIONS = "prepared_cache_evictions"
+
 	DURATION_250MS  = 250 * time.Millisecond
 	DURATION_500MS  = 500 * time.Millisecond
 	DURATION_1000MS = 1000 * time.Millisecond
@@ -207,7 +211,7 @@ const (
 var metricNames = []string{REQUESTS, SELECTS, UPDATES, INSERTS, DELETES, ACTIVE_REQUESTS,
 	QUEUED_REQUESTS, REQUEST_TIME, SERVICE_TIME, RESULT_COUNT, RESULT_SIZE, ERRORS,
 	REQUESTS_250MS, REQUESTS_500MS, REQUESTS_1000MS, REQUESTS_5000MS,
-	WARNINGS, MUTATIONS}
+	WARNINGS, MUTATIONS, PREPARED_CACHE_HITS, PREPARED_CACHE_MISSES, PREPARED_CACHE_EVICTIONS}
 
 // Use the give AccountingStore to create counters for all the metrics we are interested in:
 func RegisterMetrics(acctstore AccountingStore) {
@@ -101,9 +101,9 @@ value, and if the cumulative value is zero then return the
 partial value.
 */
 func (this *CountDistinct) CumulateIntermediate(part, cumulative value.Value, context Context) (value.Value, error) {
-	if part == value.ZERO_VALUE {
+	if part.Equals(value.ZERO_VALUE) {
 		return cumulative, nil
-	} else if cumulative == value.ZERO_VALUE {
+	} else if cumulative.Equals(value.ZERO_VALUE) {
 		return part, nil
 	}
 
@@ -116,7 +116,7 @@ a zero value return it. Return the length of the set
 as the count (number of elements in the set).
 */
 func (this *CountDistinct) ComputeFinal(cumulative value.Value, context Context) (c value.Value, e error) {
-	if cumulative == value.ZERO_VALUE {
+	if cumulative.Equals(value.ZERO_VALUE) {
 		return cumulative, nil
 	}
 
@@ -43,25 +43,28 @@ func setAdd(item, cumulative value.Value) (value.Value, error) {
 
 /*
 Aggregate distinct intermediate results and return them.
-If no partial result exists(its value is a null) return the
-cumulative value. If the cumulative input value is null,
-return the partial value. Get the input partial and cumulative
-sets and add the smaller set to the bigger. Return this set.
+If no partial result exists (it is a null without a set) return
+the cumulative value. If the cumulative input value is a null
+without a set, return the partial value. The values holding sets
+are themselves null, so the sets are checked first. Add the
+smaller set to the bigger. Return this set.
 */
 func cumulateSets(part, cumulative value.Value) (value.Value, error) {
-	if part.Type() == value.NULL {
-		return cumulative, nil
-	} else if cumulative.Type() == value.NULL {
-		return part, nil
-	}
-
 	pset, e := getSet(part)
 	if e != nil {
+		if part.Type() == value.NULL {
+			return cumulative, nil
+		}
+
 		return nil, e
 	}
 
 	cset, e := getSet(cumulative)
 	if e != nil {
+		if cumulative.Type() == value.NULL {
+			return part, nil
+		}
+
 		return nil, e
 	}
 
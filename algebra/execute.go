@@ -10,6 +10,8 @@
 package algebra
 
 import (
+	"fmt"
+
 	"github.com/couchbaselabs/query/datastore"
 	"github.com/couchbaselabs/query/errors"
 	"github.com/couchbaselabs/query/expression"
@@ -20,21 +22,24 @@ import (
 Represents the Execute command. The argument to EXECUTE must
 evaluate to a prepared statement or a string. Type Execute
 is a struct that contains a json object value that represents
-a plan.Prepared.
+a plan.Prepared, or a string that names a prepared statement,
+and the optional arguments of EXECUTE ... USING.
 */
 type Execute struct {
 	statementBase
 
-	prepared value.Value `json:"prepared"`
+	prepared value.Value           `json:"prepared"`
+	using    expression.Expression `json:"using"`
 }
 
 /*
 The function NewExecute returns a pointer to the Execute
 struct with the input argument expressions value as a field.
 */
-func NewExecute(prepared expression.Expression) *Execute {
+func NewExecute(prepared, using expression.Expression) *Execute {
 	rv := &Execute{
 		prepared: prepared.Value(),
+		using:    using,
 	}
 
 	rv.stmt = rv
@@ -60,9 +65,19 @@ func (this *Execute) Signature() value.Value {
 }
 
 /*
-Returns nil.
+Checks that the arguments of USING are a constant array of
+positional arguments, or a constant object of named arguments.
 */
 func (this *Execute) Formalize() error {
+	if this.using == nil {
+		return nil
+	}
+
+	using := this.using.Value()
+	if using == nil || (using.Type() != value.ARRAY && using.Type() != value.OBJECT) {
+		return fmt.Errorf("EXECUTE USING arguments must be a constant array or object.")
+	}
+
 	return nil
 }
 
@@ -94,3 +109,25 @@ statement.
 func (this *Execute) Prepared() value.Value {
 	return this.prepared
 }
+
+/*
+Returns the name of the prepared statement, if it is named.
+*/
+func (this *Execute) Name() string {
+	if this.prepared.Type() == value.STRING {
+		return this.prepared.Actual().(string)
+	}
+
+	return ""
+}
+
+/*
+Returns the arguments of USING, or nil.
+*/
+func (this *Execute) Using() value.Value {
+	if this.using == nil {
+		return nil
+	}
+
+	return this.using.Value()
+}
@@ -18,21 +18,50 @@ import (
 
 /*
 Represents the explain text for a query. Type Explain is
-a struct that represents the explain json statement.
+a struct that represents the explain json statement. EXPLAIN
+ANALYZE also runs the statement, and annotates its plan with
+the statistics of each operator.
 */
 type Explain struct {
 	statementBase
 
-	stmt Statement `json:"stmt"`
+	stmt    Statement `json:"stmt"`
+	analyze bool      `json:"analyze"`
+	format  string    `json:"format"`
+}
+
+/*
+The formats of the explain output. The plan is JSON by
+default.
+*/
+const (
+	EXPLAIN_JSON = "json"
+	EXPLAIN_TEXT = "text"
+	EXPLAIN_DOT  = "dot"
+)
+
+/*
+Returns true if the input is an explain format.
+*/
+func IsExplainFormat(format string) bool {
+	switch format {
+	case EXPLAIN_JSON, EXPLAIN_TEXT, EXPLAIN_DOT:
+		return true
+	default:
+		return false
+	}
 }
 
 /*
 The function NewExplain returns a pointer to the Explain
 struct that has its field stmt set to the input Statement.
+An empty format uses that of the request.
 */
-func NewExplain(stmt Statement) *Explain {
+func NewExplain(stmt Statement, analyze bool, format string) *Explain {
 	rv := &Explain{
-		stmt: stmt,
+		stmt:    stmt,
+		analyze: analyze,
+		format:  format,
 	}
 
 	rv.statementBase.stmt = rv
@@ -77,9 +106,14 @@ func (this *Explain) Expressions() expression.Expressions {
 }
 
 /*
-Returns all required privileges.
+Returns all required privileges. EXPLAIN ANALYZE requires
+the privileges of the statement, which it runs.
 */
 func (this *Explain) Privileges() (datastore.Privileges, errors.Error) {
+	if this.analyze {
+		return this.stmt.Privileges()
+	}
+
 	return nil, nil
 }
 
@@ -89,3 +123,18 @@ Return the explain statement.
 func (this *Explain) Statement() Statement {
 	return this.stmt
 }
+
+/*
+Returns true for EXPLAIN ANALYZE.
+*/
+func (this *Explain) Analyze() bool {
+	return this.analyze
+}
+
+/*
+Returns the format of the explain output, or empty for that
+of the request.
+*/
+func (this *Explain) Format() string {
+	return this.format
+}
@@ -84,7 +84,9 @@ inputs to the query.
 
 Type KeyspaceTerm is a struct that contains namespace,
 keyspace as strings, projection as the path, the alias
-string as, and the keys expression.
+string as, the keys expression, and the join hint. The flag
+fromWith is set when the term names a WITH term rather than a
+keyspace.
 */
 type KeyspaceTerm struct {
 	namespace  string
@@ -92,14 +94,30 @@ type KeyspaceTerm struct {
 	projection expression.Path
 	as         string
 	keys       expression.Expression
+	joinHint   JoinHint
+	fromWith   bool
 }
 
+/*
+JoinHint is the join method requested by USE HASH on the right-hand
+term of an ANSI join. USE_HASH_BUILD builds the hash table from the
+right-hand term; USE_HASH_PROBE builds it from the left-hand side
+and probes it with the right-hand term.
+*/
+type JoinHint int
+
+const (
+	JOIN_HINT_NONE JoinHint = iota
+	USE_HASH_BUILD
+	USE_HASH_PROBE
+)
+
 /*
 The function NewKeyspaceTerm returns a pointer to the KeyspaceTerm
 struct by assigning the input attributes to the fields of the struct.
 */
 func NewKeyspaceTerm(namespace, keyspace string, projection expression.Path, as string, keys expression.Expression) *KeyspaceTerm {
-	return &KeyspaceTerm{namespace, keyspace, projection, as, keys}
+	return &KeyspaceTerm{namespace, keyspace, projection, as, keys, JOIN_HINT_NONE, false}
 }
 
 /*
@@ -156,6 +174,10 @@ Returns all required privileges.
 */
 func (this *KeyspaceTerm) Privileges() (datastore.Privileges, errors.Error) {
 	privs := datastore.NewPrivileges()
+	if this.fromWith {
+		return privs, nil
+	}
+
 	privs[this.namespace+":"+this.keyspace] = datastore.PRIV_READ
 	return privs, nil
 }
@@ -187,6 +209,13 @@ func (this *KeyspaceTerm) toString(join bool) string {
 		s += " as `" + this.as + "`"
 	}
 
+	switch this.joinHint {
+	case USE_HASH_BUILD:
+		s += " use hash(build)"
+	case USE_HASH_PROBE:
+		s += " use hash(probe)"
+	}
+
 	if this.keys != nil {
 		if join {
 			s += " on keys " + this.keys.String()
@@ -216,8 +245,14 @@ func (this *KeyspaceTerm) Formalize(parent *expression.Formalizer) (f *expressio
 		}
 	}
 
+	err = this.formalizeWith(parent.Allowed)
+	if err != nil {
+		return
+	}
+
+	// The alias may hide a WITH term
 	_, ok := parent.Allowed.Field(keyspace)
-	if ok {
+	if ok && !isWithAlias(parent.Allowed, keyspace) {
 		err = errors.NewError(nil, fmt.Sprintf("Duplicate subquery alias %s.", keyspace))
 		return nil, err
 	}
@@ -231,6 +266,30 @@ func (this *KeyspaceTerm) Formalize(parent *expression.Formalizer) (f *expressio
 	return
 }
 
+/*
+An unqualified term without keys names a WITH term if one is in
+scope.
+*/
+func (this *KeyspaceTerm) formalizeWith(allowed value.Value) error {
+	if this.namespace != "" || this.keys != nil || !isWithAlias(allowed, this.keyspace) {
+		return nil
+	}
+
+	if this.projection != nil {
+		return errors.NewError(nil, fmt.Sprintf("Path not allowed on WITH term %s.", this.keyspace))
+	}
+
+	this.fromWith = true
+	return nil
+}
+
+/*
+Returns true if the term names a WITH term rather than a keyspace.
+*/
+func (this *KeyspaceTerm) FromWith() bool {
+	return this.fromWith
+}
+
 /*
 Return the primary term in the from clause.
 */
@@ -269,6 +328,29 @@ func (this *KeyspaceTerm) SetDefaultNamespace(namespace string) {
 	}
 }
 
+/*
+Set the keys expression. Used by the parser to attach ON KEYS
+to a join term.
+*/
+func (this *KeyspaceTerm) SetKeys(keys expression.Expression) {
+	this.keys = keys
+}
+
+/*
+Returns the join hint.
+*/
+func (this *KeyspaceTerm) JoinHint() JoinHint {
+	return this.joinHint
+}
+
+/*
+Set the join hint. Used by the parser to attach USE HASH to a join
+term.
+*/
+func (this *KeyspaceTerm) SetJoinHint(hint JoinHint) {
+	this.joinHint = hint
+}
+
 /*
 Returns the keyspace string (buckets).
 */
@@ -311,6 +393,12 @@ func (this *KeyspaceTerm) MarshalJSON() ([]byte, error) {
 	if this.projection != nil {
 		r["projection"] = expression.NewStringer().Visit(this.projection)
 	}
+	switch this.joinHint {
+	case USE_HASH_BUILD:
+		r["join_hint"] = "use_hash_build"
+	case USE_HASH_PROBE:
+		r["join_hint"] = "use_hash_probe"
+	}
 	return json.Marshal(r)
 }
 
@@ -366,7 +454,8 @@ Qualify all identifiers for the parent expression. Checks for
 duplicate aliases.
 */
 func (this *SubqueryTerm) Formalize(parent *expression.Formalizer) (f *expression.Formalizer, err error) {
-	err = this.subquery.Formalize()
+	// The subquery can only see the WITH terms in scope
+	err = this.subquery.FormalizeSubquery(withScope(parent))
 	if err != nil {
 		return
 	}
@@ -378,7 +467,7 @@ func (this *SubqueryTerm) Formalize(parent *expression.Formalizer) (f *expressio
 	}
 
 	_, ok := parent.Allowed.Field(alias)
-	if ok {
+	if ok && !isWithAlias(parent.Allowed, alias) {
 		err = errors.NewError(nil, fmt.Sprintf("Duplicate subquery alias %s.", alias))
 		return nil, err
 	}
@@ -419,14 +508,17 @@ objects by combining two or more source objects.
 They can be chained. Type Join is a struct containing
 fields left and right that represent the two source
 objects being joined (one is a from term and the
-other a keyspace term), and outer which is a bool
+other a keyspace term), outer which is a bool
 value representing if the join is an outer or inner
-join.
+join, and onclause which holds the join predicate of an
+ANSI join (JOIN ... ON expr). Lookup joins (JOIN ... ON KEYS)
+carry their keys in the right keyspace term instead.
 */
 type Join struct {
-	left  FromTerm
-	right *KeyspaceTerm
-	outer bool
+	left     FromTerm
+	right    *KeyspaceTerm
+	outer    bool
+	onclause expression.Expression
 }
 
 /*
@@ -434,7 +526,15 @@ The function NewJoin returns a pointer to the Join struct
 by assigning the input attributes to the fields of the struct.
 */
 func NewJoin(left FromTerm, outer bool, right *KeyspaceTerm) *Join {
-	return &Join{left, right, outer}
+	return &Join{left, right, outer, nil}
+}
+
+/*
+The function NewAnsiJoin returns a pointer to a Join struct
+whose right term is matched using an arbitrary ON predicate.
+*/
+func NewAnsiJoin(left FromTerm, outer bool, right *KeyspaceTerm, onclause expression.Expression) *Join {
+	return &Join{left, right, outer, onclause}
 }
 
 /*
@@ -446,7 +546,8 @@ func (this *Join) Accept(visitor NodeVisitor) (interface{}, error) {
 }
 
 /*
-Maps left and right source objects of the join.
+Maps left and right source objects of the join, and the
+ON predicate if any.
 */
 func (this *Join) MapExpressions(mapper expression.Mapper) (err error) {
 	err = this.left.MapExpressions(mapper)
@@ -454,14 +555,28 @@ func (this *Join) MapExpressions(mapper expression.Mapper) (err error) {
 		return
 	}
 
-	return this.right.MapExpressions(mapper)
+	err = this.right.MapExpressions(mapper)
+	if err != nil {
+		return
+	}
+
+	if this.onclause != nil {
+		this.onclause, err = mapper.Map(this.onclause)
+	}
+
+	return
 }
 
 /*
    Returns all contained Expressions.
 */
 func (this *Join) Expressions() expression.Expressions {
-	return append(this.left.Expressions(), this.right.Expressions()...)
+	exprs := append(this.left.Expressions(), this.right.Expressions()...)
+	if this.onclause != nil {
+		exprs = append(exprs, this.onclause)
+	}
+
+	return exprs
 }
 
 /*
@@ -495,6 +610,10 @@ func (this *Join) String() string {
 	}
 
 	s += this.right.toString(true)
+	if this.onclause != nil {
+		s += " on " + this.onclause.String()
+	}
+
 	return s
 }
 
@@ -509,9 +628,11 @@ func (this *Join) Formalize(parent *expression.Formalizer) (f *expression.Formal
 	}
 
 	f.Keyspace = ""
-	this.right.keys, err = f.Map(this.right.keys)
-	if err != nil {
-		return
+	if this.right.keys != nil {
+		this.right.keys, err = f.Map(this.right.keys)
+		if err != nil {
+			return
+		}
 	}
 
 	alias := this.Alias()
@@ -520,13 +641,28 @@ func (this *Join) Formalize(parent *expression.Formalizer) (f *expression.Formal
 		return nil, err
 	}
 
+	if this.right.namespace == "" && isWithAlias(f.Allowed, this.right.keyspace) {
+		err = errors.NewError(nil, fmt.Sprintf("Cannot JOIN WITH term %s; use it as the first FROM term.",
+			this.right.keyspace))
+		return nil, err
+	}
+
 	_, ok := f.Allowed.Field(alias)
-	if ok {
+	if ok && !isWithAlias(f.Allowed, alias) {
 		err = errors.NewError(nil, fmt.Sprintf("Duplicate JOIN alias %s.", alias))
 		return nil, err
 	}
 
 	f.Allowed.SetField(alias, alias)
+
+	// The ON predicate may refer to both sides of the join
+	if this.onclause != nil {
+		this.onclause, err = f.Map(this.onclause)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return
 }
 
@@ -567,6 +703,14 @@ func (this *Join) Outer() bool {
 	return this.outer
 }
 
+/*
+Returns the ON predicate of an ANSI join, or nil for
+a lookup join.
+*/
+func (this *Join) Onclause() expression.Expression {
+	return this.onclause
+}
+
 /*
 Marshals input join terms.
 */
@@ -575,6 +719,9 @@ func (this *Join) MarshalJSON() ([]byte, error) {
 	r["left"] = this.left
 	r["right"] = this.right
 	r["outer"] = this.outer
+	if this.onclause != nil {
+		r["on"] = expression.NewStringer().Visit(this.onclause)
+	}
 	return json.Marshal(r)
 }
 
@@ -587,13 +734,15 @@ for each left hand input, while the corresponding right
 hand inputs are collected into an array and nested as a
 single array-valued field in the result object. Type
 Nest is a struct containing the left hand input (from term)
-the right hand keyspace and a boolean outer representing
-if outer or inner nest.
+the right hand keyspace, a boolean outer representing
+if outer or inner nest, and the ON predicate of an ANSI
+nest, if any.
 */
 type Nest struct {
-	left  FromTerm
-	right *KeyspaceTerm
-	outer bool
+	left     FromTerm
+	right    *KeyspaceTerm
+	outer    bool
+	onclause expression.Expression
 }
 
 /*
@@ -601,7 +750,15 @@ The function NewNest returns a pointer to the Nest struct
 by assigning the input attributes to the fields of the struct.
 */
 func NewNest(left FromTerm, outer bool, right *KeyspaceTerm) *Nest {
-	return &Nest{left, right, outer}
+	return &Nest{left, right, outer, nil}
+}
+
+/*
+The function NewAnsiNest returns a pointer to a Nest struct
+whose right term is matched using an arbitrary ON predicate.
+*/
+func NewAnsiNest(left FromTerm, outer bool, right *KeyspaceTerm, onclause expression.Expression) *Nest {
+	return &Nest{left, right, outer, onclause}
 }
 
 /*
@@ -614,7 +771,7 @@ func (this *Nest) Accept(visitor NodeVisitor) (interface{}, error) {
 
 /*
 Maps the right input of the nest if the left is mapped
-successfully.
+successfully, and then the ON predicate if any.
 */
 func (this *Nest) MapExpressions(mapper expression.Mapper) (err error) {
 	err = this.left.MapExpressions(mapper)
@@ -622,14 +779,28 @@ func (this *Nest) MapExpressions(mapper expression.Mapper) (err error) {
 		return
 	}
 
-	return this.right.MapExpressions(mapper)
+	err = this.right.MapExpressions(mapper)
+	if err != nil {
+		return
+	}
+
+	if this.onclause != nil {
+		this.onclause, err = mapper.Map(this.onclause)
+	}
+
+	return
 }
 
 /*
    Returns all contained Expressions.
 */
 func (this *Nest) Expressions() expression.Expressions {
-	return append(this.left.Expressions(), this.right.Expressions()...)
+	exprs := append(this.left.Expressions(), this.right.Expressions()...)
+	if this.onclause != nil {
+		exprs = append(exprs, this.onclause)
+	}
+
+	return exprs
 }
 
 /*
@@ -663,6 +834,10 @@ func (this *Nest) String() string {
 	}
 
 	s += this.right.toString(true)
+	if this.onclause != nil {
+		s += " on " + this.onclause.String()
+	}
+
 	return s
 }
 
@@ -677,9 +852,11 @@ func (this *Nest) Formalize(parent *expression.Formalizer) (f *expression.Formal
 	}
 
 	f.Keyspace = ""
-	this.right.keys, err = f.Map(this.right.keys)
-	if err != nil {
-		return
+	if this.right.keys != nil {
+		this.right.keys, err = f.Map(this.right.keys)
+		if err != nil {
+			return
+		}
 	}
 
 	alias := this.Alias()
@@ -688,13 +865,28 @@ func (this *Nest) Formalize(parent *expression.Formalizer) (f *expression.Formal
 		return nil, err
 	}
 
+	if this.right.namespace == "" && isWithAlias(f.Allowed, this.right.keyspace) {
+		err = errors.NewError(nil, fmt.Sprintf("Cannot NEST WITH term %s; use it as the first FROM term.",
+			this.right.keyspace))
+		return nil, err
+	}
+
 	_, ok := f.Allowed.Field(alias)
-	if ok {
+	if ok && !isWithAlias(f.Allowed, alias) {
 		err = errors.NewError(nil, fmt.Sprintf("Duplicate NEST alias %s.", alias))
 		return nil, err
 	}
 
 	f.Allowed.SetField(alias, alias)
+
+	// The ON predicate may refer to both sides of the nest
+	if this.onclause != nil {
+		this.onclause, err = f.Map(this.onclause)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return
 }
 
@@ -734,6 +926,14 @@ func (this *Nest) Outer() bool {
 	return this.outer
 }
 
+/*
+Returns the ON predicate of an ANSI nest, or nil for
+a lookup nest.
+*/
+func (this *Nest) Onclause() expression.Expression {
+	return this.onclause
+}
+
 /*
 Marshals input nest terms into byte array.
 */
@@ -742,6 +942,9 @@ func (this *Nest) MarshalJSON() ([]byte, error) {
 	r["left"] = this.left
 	r["right"] = this.right
 	r["outer"] = this.outer
+	if this.onclause != nil {
+		r["on"] = expression.NewStringer().Visit(this.onclause)
+	}
 	return json.Marshal(r)
 }
 
@@ -850,7 +1053,7 @@ func (this *Unnest) Formalize(parent *expression.Formalizer) (f *expression.Form
 	}
 
 	_, ok := f.Allowed.Field(alias)
-	if ok {
+	if ok && !isWithAlias(f.Allowed, alias) {
 		err = errors.NewError(nil, fmt.Sprintf("Duplicate UNNEST alias %s.", alias))
 		return nil, err
 	}
@@ -0,0 +1,65 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package algebra
+
+import (
+	"encoding/json"
+)
+
+/*
+Represents the name of a user-defined function in CREATE FUNCTION
+and DROP FUNCTION. It contains two fields namespace and name.
+*/
+type FunctionRef struct {
+	namespace string `json:"namespace"`
+	name      string `json:"name"`
+}
+
+/*
+The function NewFunctionRef returns a pointer to the
+FunctionRef struct by assigning the input attributes
+to the fields of the struct.
+*/
+func NewFunctionRef(namespace, name string) *FunctionRef {
+	return &FunctionRef{namespace, name}
+}
+
+/*
+Returns the namespace string.
+*/
+func (this *FunctionRef) Namespace() string {
+	return this.namespace
+}
+
+/*
+Set the default namespace.
+*/
+func (this *FunctionRef) SetDefaultNamespace(namespace string) {
+	if this.namespace == "" {
+		this.namespace = namespace
+	}
+}
+
+/*
+Returns the name of the function.
+*/
+func (this *FunctionRef) Name() string {
+	return this.name
+}
+
+/*
+Marshals input into byte array.
+*/
+func (this *FunctionRef) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"type": "functionRef"}
+	r["namespace"] = this.namespace
+	r["name"] = this.name
+	return json.Marshal(r)
+}
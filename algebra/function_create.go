@@ -0,0 +1,188 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package algebra
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/value"
+)
+
+/*
+Represents the Create function ddl statement. Type CreateFunction
+is a struct that contains fields mapping to each clause in the
+create function statement, namely the function name, the
+parameters, the body and OR REPLACE.
+*/
+type CreateFunction struct {
+	statementBase
+
+	function   *FunctionRef          `json:"function"`
+	parameters []string              `json:"parameters"`
+	body       expression.Expression `json:"body"`
+	replace    bool                  `json:"replace"`
+}
+
+/*
+The function NewCreateFunction returns a pointer to the
+CreateFunction struct with the input argument values as fields.
+*/
+func NewCreateFunction(function *FunctionRef, parameters []string,
+	body expression.Expression, replace bool) *CreateFunction {
+	rv := &CreateFunction{
+		function:   function,
+		parameters: parameters,
+		body:       body,
+		replace:    replace,
+	}
+
+	rv.stmt = rv
+	return rv
+}
+
+/*
+It calls the VisitCreateFunction method by passing in the
+receiver and returns the interface. It is a visitor
+pattern.
+*/
+func (this *CreateFunction) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitCreateFunction(this)
+}
+
+/*
+Returns nil.
+*/
+func (this *CreateFunction) Signature() value.Value {
+	return nil
+}
+
+/*
+Checks that the name is not that of a built-in function, and that
+the body is an inline expression over the parameters.
+*/
+func (this *CreateFunction) Formalize() error {
+	name := this.function.Name()
+	_, ok := GetWindowFunction(name, false)
+	if ok || expression.IsBuiltinFunction(name) {
+		return fmt.Errorf("Cannot redefine built-in function %s.", name)
+	}
+
+	f := expression.NewFormalizer()
+	for _, param := range this.parameters {
+		_, ok := f.Allowed.Field(param)
+		if ok {
+			return fmt.Errorf("Duplicate parameter %s in function %s.", param, name)
+		}
+
+		f.Allowed.SetField(param, param)
+	}
+
+	err := checkFunctionBody(name, this.body)
+	if err != nil {
+		return err
+	}
+
+	this.body, err = f.Map(this.body)
+	return err
+}
+
+/*
+Function bodies are evaluated outside of any query, and cannot
+contain subqueries, aggregates, or window functions.
+*/
+func checkFunctionBody(name string, expr expression.Expression) error {
+	switch expr.(type) {
+	case expression.Subquery:
+		return fmt.Errorf("Function %s cannot contain subqueries.", name)
+	case Aggregate, WindowFunction:
+		return fmt.Errorf("Function %s cannot contain aggregates or window functions.", name)
+	}
+
+	for _, child := range expr.Children() {
+		if child == nil {
+			continue
+		}
+
+		err := checkFunctionBody(name, child)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+This method maps the body of the function.
+*/
+func (this *CreateFunction) MapExpressions(mapper expression.Mapper) (err error) {
+	this.body, err = mapper.Map(this.body)
+	return
+}
+
+/*
+Returns all contained Expressions.
+*/
+func (this *CreateFunction) Expressions() expression.Expressions {
+	return expression.Expressions{this.body}
+}
+
+/*
+Returns all required privileges.
+*/
+func (this *CreateFunction) Privileges() (datastore.Privileges, errors.Error) {
+	return datastore.Privileges{
+		this.function.Namespace() + ":" + this.function.Name(): datastore.PRIV_DDL,
+	}, nil
+}
+
+/*
+Returns the function name.
+*/
+func (this *CreateFunction) Function() *FunctionRef {
+	return this.function
+}
+
+/*
+Returns the names of the parameters.
+*/
+func (this *CreateFunction) Parameters() []string {
+	return this.parameters
+}
+
+/*
+Returns the body of the function.
+*/
+func (this *CreateFunction) Body() expression.Expression {
+	return this.body
+}
+
+/*
+Returns true for CREATE OR REPLACE.
+*/
+func (this *CreateFunction) Replace() bool {
+	return this.replace
+}
+
+/*
+Marshals input receiver into byte array.
+*/
+func (this *CreateFunction) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"type": "createFunction"}
+	r["function"] = this.function
+	r["parameters"] = this.parameters
+	r["body"] = expression.NewStringer().Visit(this.body)
+	r["replace"] = this.replace
+	return json.Marshal(r)
+}
@@ -18,22 +18,26 @@ import (
 
 /*
 Represents a prepared statement. Type Prepare is a
-struct that contains a statement (json statement).
+struct that contains a statement (json statement), and
+an optional name, given in PREPARE name FROM statement.
 */
 type Prepare struct {
 	statementBase
 
 	stmt Statement `json:"stmt"`
+	name string    `json:"name"`
+	text string    `json:"text"`
 }
 
 /*
 The function NewPrepare returns a pointer to the
 Prepare struct with the input argument statement
-as a field.
+and name as fields.
 */
-func NewPrepare(stmt Statement) *Prepare {
+func NewPrepare(name string, stmt Statement) *Prepare {
 	rv := &Prepare{
 		stmt: stmt,
+		name: name,
 	}
 
 	rv.statementBase.stmt = rv
@@ -90,3 +94,24 @@ Return the prepared statement.
 func (this *Prepare) Statement() Statement {
 	return this.stmt
 }
+
+/*
+Return the name of the prepared statement.
+*/
+func (this *Prepare) Name() string {
+	return this.name
+}
+
+/*
+Return the text of the PREPARE statement.
+*/
+func (this *Prepare) Text() string {
+	return this.text
+}
+
+/*
+Set the text of the PREPARE statement.
+*/
+func (this *Prepare) SetText(text string) {
+	this.text = text
+}
@@ -24,11 +24,12 @@ intermediate result interface for the select clause.
 The order field maps to the order by clause, the offset
 is an expression that maps to the offset clause and
 similarly limit is an expression that maps to the limit
-clause.
+clause. The with field maps to the optional WITH clause.
 */
 type Select struct {
 	statementBase
 
+	with      *With                 `json:"with"`
 	subresult Subresult             `json:"subresult"`
 	order     *Order                `json:"order"`
 	offset    expression.Expression `json:"offset"`
@@ -80,6 +81,13 @@ This method maps all the constituent clauses, namely the subresult,
 order, limit and offset within a Select statement.
 */
 func (this *Select) MapExpressions(mapper expression.Mapper) (err error) {
+	if this.with != nil {
+		err = this.with.MapExpressions(mapper)
+		if err != nil {
+			return
+		}
+	}
+
 	err = this.subresult.MapExpressions(mapper)
 	if err != nil {
 		return
@@ -109,6 +117,10 @@ func (this *Select) MapExpressions(mapper expression.Mapper) (err error) {
 func (this *Select) Expressions() expression.Expressions {
 	exprs := this.subresult.Expressions()
 
+	if this.with != nil {
+		exprs = append(exprs, this.with.Expressions()...)
+	}
+
 	if this.order != nil {
 		exprs = append(exprs, this.order.Expressions()...)
 	}
@@ -133,6 +145,15 @@ func (this *Select) Privileges() (datastore.Privileges, errors.Error) {
 		return nil, err
 	}
 
+	if this.with != nil {
+		withprivs, err := this.with.Privileges()
+		if err != nil {
+			return nil, err
+		}
+
+		privs.Add(withprivs)
+	}
+
 	exprs := make(expression.Expressions, 0, 16)
 
 	if this.order != nil {
@@ -160,7 +181,13 @@ func (this *Select) Privileges() (datastore.Privileges, errors.Error) {
    Representation as a N1QL string.
 */
 func (this *Select) String() string {
-	s := this.subresult.String()
+	s := ""
+
+	if this.with != nil {
+		s += this.with.String() + " "
+	}
+
+	s += this.subresult.String()
 
 	if this.order != nil {
 		s += " " + this.order.String()
@@ -184,6 +211,13 @@ For the subresult of the subquery, call Formalize, for the order
 by clause call MapExpressions, for limit and offset call Accept.
 */
 func (this *Select) FormalizeSubquery(parent *expression.Formalizer) (err error) {
+	if this.with != nil {
+		parent, err = this.with.Formalize(parent)
+		if err != nil {
+			return err
+		}
+	}
+
 	formalizer, err := this.subresult.Formalize(parent)
 	if err != nil {
 		return err
@@ -213,6 +247,20 @@ func (this *Select) FormalizeSubquery(parent *expression.Formalizer) (err error)
 	return
 }
 
+/*
+Return the WITH clause of the select statement.
+*/
+func (this *Select) With() *With {
+	return this.with
+}
+
+/*
+This method sets the WITH clause of the select statement.
+*/
+func (this *Select) SetWith(with *With) {
+	this.with = with
+}
+
 /*
 Return the subresult of the select statement.
 */
@@ -0,0 +1,131 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package algebra
+
+import (
+	"encoding/json"
+
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/value"
+)
+
+/*
+Represents the UPDATE STATISTICS statement, which samples the
+documents of a keyspace and stores the statistics of each term, or
+deletes the stored statistics of the terms. Like index keys, terms
+are expressions on the documents of the keyspace. A delete without
+terms deletes all the statistics of the keyspace.
+*/
+type UpdateStatistics struct {
+	statementBase
+
+	keyspace *KeyspaceRef           `json:"keyspace"`
+	terms    expression.Expressions `json:"terms"`
+	delete   bool                   `json:"delete"`
+	with     value.Value            `json:"with"`
+}
+
+func NewUpdateStatistics(keyspace *KeyspaceRef, terms expression.Expressions,
+	with value.Value) *UpdateStatistics {
+	rv := &UpdateStatistics{
+		keyspace: keyspace,
+		terms:    terms,
+		with:     with,
+	}
+
+	rv.stmt = rv
+	return rv
+}
+
+func NewDeleteStatistics(keyspace *KeyspaceRef, terms expression.Expressions) *UpdateStatistics {
+	rv := &UpdateStatistics{
+		keyspace: keyspace,
+		terms:    terms,
+		delete:   true,
+	}
+
+	rv.stmt = rv
+	return rv
+}
+
+func (this *UpdateStatistics) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitUpdateStatistics(this)
+}
+
+func (this *UpdateStatistics) Signature() value.Value {
+	return nil
+}
+
+func (this *UpdateStatistics) Formalize() error {
+	return nil
+}
+
+func (this *UpdateStatistics) MapExpressions(mapper expression.Mapper) error {
+	return this.terms.MapExpressions(mapper)
+}
+
+func (this *UpdateStatistics) Expressions() expression.Expressions {
+	return this.terms
+}
+
+/*
+Returns all required privileges.
+*/
+func (this *UpdateStatistics) Privileges() (datastore.Privileges, errors.Error) {
+	return datastore.Privileges{
+		this.keyspace.Namespace() + ":" + this.keyspace.Keyspace(): datastore.PRIV_DDL,
+	}, nil
+}
+
+func (this *UpdateStatistics) Keyspace() *KeyspaceRef {
+	return this.keyspace
+}
+
+/*
+Returns the terms, which are nil for a delete of all the
+statistics of the keyspace.
+*/
+func (this *UpdateStatistics) Terms() expression.Expressions {
+	return this.terms
+}
+
+/*
+Returns true if the statistics of the terms are to be deleted.
+*/
+func (this *UpdateStatistics) Delete() bool {
+	return this.delete
+}
+
+/*
+Returns the WITH options, such as sample_size.
+*/
+func (this *UpdateStatistics) With() value.Value {
+	return this.with
+}
+
+func (this *UpdateStatistics) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"type": "updateStatistics"}
+	r["keyspaceRef"] = this.keyspace
+	if this.terms != nil {
+		terms := make([]string, len(this.terms))
+		for i, term := range this.terms {
+			terms[i] = expression.NewStringer().Visit(term)
+		}
+		r["terms"] = terms
+	}
+	r["delete"] = this.delete
+	if this.with != nil {
+		r["with"] = this.with
+	}
+
+	return json.Marshal(r)
+}
@@ -37,6 +37,18 @@ type Visitor interface {
 	VisitAlterIndex(stmt *AlterIndex) (interface{}, error)
 	VisitBuildIndexes(stmt *BuildIndexes) (interface{}, error)
 
+	/*
+	   Visitor for function DDL statements Create function
+	   and Drop function.
+	*/
+	VisitCreateFunction(stmt *CreateFunction) (interface{}, error)
+	VisitDropFunction(stmt *DropFunction) (interface{}, error)
+
+	/*
+	   Visitor for UPDATE STATISTICS.
+	*/
+	VisitUpdateStatistics(stmt *UpdateStatistics) (interface{}, error)
+
 	/*
 	   Visitor for EXPLAIN statements.
 	*/
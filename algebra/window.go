@@ -0,0 +1,636 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package algebra
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/value"
+)
+
+/*
+Represents the OVER clause of a window function: PARTITION BY,
+ORDER BY, and an optional ROWS or RANGE frame. Type WindowTerm is a
+struct containing the partition expressions, the sort terms and the
+frame.
+*/
+type WindowTerm struct {
+	partitionBy expression.Expressions
+	orderBy     SortTerms
+	frame       *WindowFrame
+}
+
+/*
+The function NewWindowTerm returns a pointer to the WindowTerm
+struct with the input partition expressions, sort terms and frame.
+*/
+func NewWindowTerm(partitionBy expression.Expressions, orderBy SortTerms,
+	frame *WindowFrame) *WindowTerm {
+	return &WindowTerm{
+		partitionBy: partitionBy,
+		orderBy:     orderBy,
+		frame:       frame,
+	}
+}
+
+/*
+Returns the PARTITION BY expressions.
+*/
+func (this *WindowTerm) PartitionBy() expression.Expressions {
+	return this.partitionBy
+}
+
+/*
+Returns the ORDER BY sort terms.
+*/
+func (this *WindowTerm) OrderBy() SortTerms {
+	return this.orderBy
+}
+
+/*
+Returns the frame, or nil if no frame was specified.
+*/
+func (this *WindowTerm) Frame() *WindowFrame {
+	return this.frame
+}
+
+/*
+Returns all contained Expressions.
+*/
+func (this *WindowTerm) Expressions() expression.Expressions {
+	exprs := make(expression.Expressions, 0, len(this.partitionBy)+len(this.orderBy)+2)
+	exprs = append(exprs, this.partitionBy...)
+	exprs = append(exprs, this.orderBy.Expressions()...)
+
+	if this.frame != nil {
+		exprs = append(exprs, this.frame.Expressions()...)
+	}
+
+	return exprs
+}
+
+/*
+Apply a Mapper to all the expressions in the window.
+*/
+func (this *WindowTerm) MapExpressions(mapper expression.Mapper) (err error) {
+	err = this.partitionBy.MapExpressions(mapper)
+	if err != nil {
+		return
+	}
+
+	err = this.orderBy.MapExpressions(mapper)
+	if err != nil {
+		return
+	}
+
+	if this.frame != nil {
+		err = this.frame.MapExpressions(mapper)
+	}
+
+	return
+}
+
+/*
+Returns a deep copy of the window.
+*/
+func (this *WindowTerm) Copy() *WindowTerm {
+	rv := &WindowTerm{}
+
+	if this.partitionBy != nil {
+		rv.partitionBy = make(expression.Expressions, len(this.partitionBy))
+		for i, expr := range this.partitionBy {
+			rv.partitionBy[i] = expr.Copy()
+		}
+	}
+
+	if this.orderBy != nil {
+		rv.orderBy = make(SortTerms, len(this.orderBy))
+		for i, term := range this.orderBy {
+			rv.orderBy[i] = NewSortTerm(term.Expression().Copy(), term.Descending())
+		}
+	}
+
+	if this.frame != nil {
+		rv.frame = this.frame.Copy()
+	}
+
+	return rv
+}
+
+/*
+Representation as a N1QL string, without the enclosing OVER ().
+*/
+func (this *WindowTerm) String() string {
+	s := ""
+
+	if len(this.partitionBy) > 0 {
+		s += "partition by "
+		for i, expr := range this.partitionBy {
+			if i > 0 {
+				s += ", "
+			}
+
+			s += expr.String()
+		}
+	}
+
+	if len(this.orderBy) > 0 {
+		if s != "" {
+			s += " "
+		}
+
+		s += "order by " + this.orderBy.String()
+	}
+
+	if this.frame != nil {
+		if s != "" {
+			s += " "
+		}
+
+		s += this.frame.String()
+	}
+
+	return s
+}
+
+/*
+Split items into partitions on the PARTITION BY expressions, and
+sort each partition on the ORDER BY terms. Partitions are returned
+in the order in which their first item was encountered.
+*/
+func (this *WindowTerm) Partition(items value.AnnotatedValues, context Context) (
+	[]*WindowPartition, error) {
+	partitions := make([]*WindowPartition, 0, 16)
+	index := make(map[string]*WindowPartition, 16)
+
+	for _, item := range items {
+		key, err := this.partitionKey(item, context)
+		if err != nil {
+			return nil, err
+		}
+
+		partition, ok := index[key]
+		if !ok {
+			partition = &WindowPartition{window: this}
+			index[key] = partition
+			partitions = append(partitions, partition)
+		}
+
+		keys, err := this.sortKeys(item, context)
+		if err != nil {
+			return nil, err
+		}
+
+		partition.rows = append(partition.rows, item)
+		partition.keys = append(partition.keys, keys)
+	}
+
+	for _, partition := range partitions {
+		sort.Stable(partition)
+		partition.setPeers()
+	}
+
+	return partitions, nil
+}
+
+func (this *WindowTerm) partitionKey(item value.Value, context Context) (string, error) {
+	if len(this.partitionBy) == 0 {
+		return "", nil
+	}
+
+	kvs := make([]interface{}, len(this.partitionBy))
+	for i, expr := range this.partitionBy {
+		k, e := expr.Evaluate(item, context)
+		if e != nil {
+			return "", e
+		}
+
+		kvs[i] = k
+	}
+
+	bytes, _ := value.NewValue(kvs).MarshalJSON()
+	return string(bytes), nil
+}
+
+func (this *WindowTerm) sortKeys(item value.Value, context Context) (value.Values, error) {
+	keys := make(value.Values, len(this.orderBy))
+	for i, term := range this.orderBy {
+		k, e := term.Expression().Evaluate(item, context)
+		if e != nil {
+			return nil, e
+		}
+
+		keys[i] = k
+	}
+
+	return keys, nil
+}
+
+/*
+Frame bound types.
+*/
+type FrameBoundType int
+
+const (
+	UNBOUNDED_PRECEDING FrameBoundType = iota
+	PRECEDING
+	CURRENT_ROW
+	FOLLOWING
+	UNBOUNDED_FOLLOWING
+)
+
+/*
+One end of a window frame. The offset is only used by PRECEDING and
+FOLLOWING.
+*/
+type WindowFrameBound struct {
+	bound  FrameBoundType
+	offset expression.Expression
+}
+
+/*
+The function NewWindowFrameBound returns a pointer to a
+WindowFrameBound with the input type and offset.
+*/
+func NewWindowFrameBound(bound FrameBoundType, offset expression.Expression) *WindowFrameBound {
+	return &WindowFrameBound{
+		bound:  bound,
+		offset: offset,
+	}
+}
+
+/*
+Returns the bound type.
+*/
+func (this *WindowFrameBound) Bound() FrameBoundType {
+	return this.bound
+}
+
+/*
+Returns the offset expression.
+*/
+func (this *WindowFrameBound) Offset() expression.Expression {
+	return this.offset
+}
+
+/*
+Representation as a N1QL string.
+*/
+func (this *WindowFrameBound) String() string {
+	switch this.bound {
+	case UNBOUNDED_PRECEDING:
+		return "unbounded preceding"
+	case PRECEDING:
+		return this.offset.String() + " preceding"
+	case FOLLOWING:
+		return this.offset.String() + " following"
+	case UNBOUNDED_FOLLOWING:
+		return "unbounded following"
+	default:
+		return "current row"
+	}
+}
+
+/*
+Represents a ROWS or RANGE window frame. A ROWS frame counts offsets
+in rows; a RANGE frame counts offsets in values of the single ORDER
+BY key, and includes the peers of the current row.
+*/
+type WindowFrame struct {
+	rows  bool
+	start *WindowFrameBound
+	end   *WindowFrameBound
+}
+
+/*
+The function NewWindowFrame returns a pointer to a WindowFrame with
+the input units and bounds.
+*/
+func NewWindowFrame(rows bool, start, end *WindowFrameBound) *WindowFrame {
+	return &WindowFrame{
+		rows:  rows,
+		start: start,
+		end:   end,
+	}
+}
+
+/*
+Returns true for a ROWS frame, false for a RANGE frame.
+*/
+func (this *WindowFrame) Rows() bool {
+	return this.rows
+}
+
+/*
+Returns the start bound.
+*/
+func (this *WindowFrame) Start() *WindowFrameBound {
+	return this.start
+}
+
+/*
+Returns the end bound.
+*/
+func (this *WindowFrame) End() *WindowFrameBound {
+	return this.end
+}
+
+/*
+Check that the start bound does not follow the end bound.
+*/
+func (this *WindowFrame) Validate() error {
+	if this.start.bound == UNBOUNDED_FOLLOWING {
+		return fmt.Errorf("Window frame cannot start at UNBOUNDED FOLLOWING.")
+	}
+
+	if this.end.bound == UNBOUNDED_PRECEDING {
+		return fmt.Errorf("Window frame cannot end at UNBOUNDED PRECEDING.")
+	}
+
+	if this.start.bound > this.end.bound {
+		return fmt.Errorf("Window frame start %s follows end %s.",
+			this.start.String(), this.end.String())
+	}
+
+	return nil
+}
+
+/*
+Returns the offset expressions.
+*/
+func (this *WindowFrame) Expressions() expression.Expressions {
+	exprs := make(expression.Expressions, 0, 2)
+	for _, b := range []*WindowFrameBound{this.start, this.end} {
+		if b.offset != nil {
+			exprs = append(exprs, b.offset)
+		}
+	}
+
+	return exprs
+}
+
+/*
+Apply a Mapper to the offset expressions.
+*/
+func (this *WindowFrame) MapExpressions(mapper expression.Mapper) (err error) {
+	for _, b := range []*WindowFrameBound{this.start, this.end} {
+		if b.offset != nil {
+			b.offset, err = mapper.Map(b.offset)
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+/*
+Returns a deep copy of the frame.
+*/
+func (this *WindowFrame) Copy() *WindowFrame {
+	bounds := make([]*WindowFrameBound, 2)
+	for i, b := range []*WindowFrameBound{this.start, this.end} {
+		bounds[i] = NewWindowFrameBound(b.bound, nil)
+		if b.offset != nil {
+			bounds[i].offset = b.offset.Copy()
+		}
+	}
+
+	return NewWindowFrame(this.rows, bounds[0], bounds[1])
+}
+
+/*
+Representation as a N1QL string.
+*/
+func (this *WindowFrame) String() string {
+	s := "range"
+	if this.rows {
+		s = "rows"
+	}
+
+	return s + " between " + this.start.String() + " and " + this.end.String()
+}
+
+/*
+The rows of one partition of a window, sorted on the ORDER BY terms.
+Rows with equal sort keys are peers.
+*/
+type WindowPartition struct {
+	window    *WindowTerm
+	rows      value.AnnotatedValues
+	keys      []value.Values
+	peerStart []int
+	peerEnd   []int
+}
+
+/*
+Returns the sorted rows.
+*/
+func (this *WindowPartition) Rows() value.AnnotatedValues {
+	return this.rows
+}
+
+func (this *WindowPartition) Len() int {
+	return len(this.rows)
+}
+
+func (this *WindowPartition) Less(i, j int) bool {
+	return this.compare(i, j) < 0
+}
+
+func (this *WindowPartition) Swap(i, j int) {
+	this.rows[i], this.rows[j] = this.rows[j], this.rows[i]
+	this.keys[i], this.keys[j] = this.keys[j], this.keys[i]
+}
+
+func (this *WindowPartition) compare(i, j int) int {
+	for k, term := range this.window.orderBy {
+		c := this.keys[i][k].Collate(this.keys[j][k])
+		if c == 0 {
+			continue
+		} else if term.Descending() {
+			return -c
+		} else {
+			return c
+		}
+	}
+
+	return 0
+}
+
+func (this *WindowPartition) setPeers() {
+	n := len(this.rows)
+	this.peerStart = make([]int, n)
+	this.peerEnd = make([]int, n)
+
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && this.compare(i, j) == 0 {
+			j++
+		}
+
+		for k := i; k < j; k++ {
+			this.peerStart[k] = i
+			this.peerEnd[k] = j - 1
+		}
+
+		i = j
+	}
+}
+
+/*
+Returns the index of the first peer of row i.
+*/
+func (this *WindowPartition) PeerStart(i int) int {
+	return this.peerStart[i]
+}
+
+/*
+Returns the index of the last peer of row i.
+*/
+func (this *WindowPartition) PeerEnd(i int) int {
+	return this.peerEnd[i]
+}
+
+/*
+Returns the frame of row i as the inclusive range of rows [lo, hi].
+The frame is empty if lo > hi. Without a frame, the frame is the
+whole partition if there is no ORDER BY, and otherwise runs from the
+start of the partition to the last peer of row i.
+*/
+func (this *WindowPartition) Frame(i int, context Context) (lo, hi int, err error) {
+	frame := this.window.frame
+	if frame == nil {
+		if len(this.window.orderBy) == 0 {
+			return 0, len(this.rows) - 1, nil
+		}
+
+		return 0, this.peerEnd[i], nil
+	}
+
+	lo, err = this.frameBound(frame, frame.start, i, true, context)
+	if err != nil {
+		return
+	}
+
+	hi, err = this.frameBound(frame, frame.end, i, false, context)
+	if err != nil {
+		return
+	}
+
+	if lo < 0 {
+		lo = 0
+	}
+
+	if hi >= len(this.rows) {
+		hi = len(this.rows) - 1
+	}
+
+	return
+}
+
+func (this *WindowPartition) frameBound(frame *WindowFrame, bound *WindowFrameBound,
+	i int, start bool, context Context) (int, error) {
+	n := len(this.rows)
+
+	switch bound.bound {
+	case UNBOUNDED_PRECEDING:
+		return 0, nil
+	case UNBOUNDED_FOLLOWING:
+		return n - 1, nil
+	case CURRENT_ROW:
+		if frame.rows {
+			return i, nil
+		} else if start {
+			return this.peerStart[i], nil
+		} else {
+			return this.peerEnd[i], nil
+		}
+	}
+
+	offset, err := frameOffset(bound.offset, this.rows[i], context)
+	if err != nil {
+		return 0, err
+	}
+
+	if frame.rows {
+		var pos float64
+		if bound.bound == PRECEDING {
+			pos = float64(i) - offset
+		} else {
+			pos = float64(i) + offset
+		}
+
+		return int(math.Max(-1, math.Min(float64(n), pos))), nil
+	}
+
+	// RANGE offsets apply to the value of the single ORDER BY key
+	if len(this.window.orderBy) != 1 {
+		return 0, fmt.Errorf("RANGE with offset requires exactly one ORDER BY term.")
+	}
+
+	key := this.keys[i][0]
+	if key.Type() != value.NUMBER {
+		// Non-numeric keys only match their peers
+		if start {
+			return this.peerStart[i], nil
+		}
+
+		return this.peerEnd[i], nil
+	}
+
+	// Offsets follow the sort direction
+	k := key.Actual().(float64)
+	if (bound.bound == PRECEDING) != this.window.orderBy[0].Descending() {
+		k -= offset
+	} else {
+		k += offset
+	}
+
+	target := value.NewValue(k)
+	desc := this.window.orderBy[0].Descending()
+	before := func(j int) bool {
+		c := this.keys[j][0].Collate(target)
+		if desc {
+			c = -c
+		}
+
+		if start {
+			return c < 0
+		}
+
+		return c <= 0
+	}
+
+	j := sort.Search(n, func(j int) bool { return !before(j) })
+	if start {
+		return j, nil
+	}
+
+	return j - 1, nil
+}
+
+func frameOffset(expr expression.Expression, item value.Value, context Context) (float64, error) {
+	ov, err := expr.Evaluate(item, context)
+	if err != nil {
+		return 0, err
+	}
+
+	offset, ok := ov.Actual().(float64)
+	if !ok || offset < 0 {
+		return 0, fmt.Errorf("Window frame offset must be a non-negative number: %v.", ov)
+	}
+
+	return offset, nil
+}
@@ -0,0 +1,111 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package algebra
+
+import (
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/value"
+)
+
+/*
+This represents an aggregate function, such as SUM() or COUNT(),
+computed over the frame of each row of a window. It shares its
+operands with the underlying aggregate, which computes the values.
+*/
+type WindowAggregate struct {
+	WindowFunctionBase
+	agg Aggregate
+}
+
+func NewWindowAggregate(agg Aggregate, window *WindowTerm) WindowFunction {
+	rv := &WindowAggregate{
+		*NewWindowFunctionBase(agg.Name(), window, agg.Operands()...),
+		agg,
+	}
+
+	rv.SetExpr(rv)
+	return rv
+}
+
+func (this *WindowAggregate) Accept(visitor expression.Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *WindowAggregate) Type() value.Type { return this.agg.Type() }
+
+func (this *WindowAggregate) Evaluate(item value.Value, context expression.Context) (value.Value, error) {
+	return this.evaluate(this, item, context)
+}
+
+func (this *WindowAggregate) Distinct() bool { return this.agg.Distinct() }
+
+func (this *WindowAggregate) MinArgs() int { return this.agg.MinArgs() }
+
+func (this *WindowAggregate) MaxArgs() int { return this.agg.MaxArgs() }
+
+func (this *WindowAggregate) Constructor() expression.FunctionConstructor {
+	return func(operands ...expression.Expression) expression.Function {
+		agg := this.agg.Constructor()(operands...).(Aggregate)
+		return NewWindowAggregate(agg, copyWindow(this.window))
+	}
+}
+
+/*
+Returns the underlying aggregate.
+*/
+func (this *WindowAggregate) Aggregate() Aggregate {
+	return this.agg
+}
+
+/*
+Aggregates the frame of each row. Consecutive frames that share
+their start and only grow, such as the default frame, are aggregated
+incrementally.
+*/
+func (this *WindowAggregate) Compute(partition *WindowPartition, context Context) (value.Values, error) {
+	rows := partition.Rows()
+	rv := make(value.Values, len(rows))
+
+	cumulative := this.agg.Default()
+	clo, chi := 0, -1
+
+	for i := range rows {
+		lo, hi, err := partition.Frame(i, context)
+		if err != nil {
+			return nil, err
+		}
+
+		if lo > hi {
+			rv[i] = this.agg.Default()
+			continue
+		}
+
+		if lo != clo || hi < chi {
+			cumulative = this.agg.Default()
+			clo, chi = lo, lo-1
+		}
+
+		for j := chi + 1; j <= hi; j++ {
+			cumulative, err = this.agg.CumulateInitial(rows[j], cumulative, context)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		chi = hi
+
+		rv[i], err = this.agg.ComputeFinal(cumulative.Copy(), context)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return rv, nil
+}
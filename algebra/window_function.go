@@ -0,0 +1,248 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package algebra
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/value"
+)
+
+type WindowFunctions []WindowFunction
+
+/*
+The WindowFunction interface represents functions with an OVER
+clause, such as ROW_NUMBER(), RANK(), LAG(), and aggregates such as
+SUM() computed over a window.
+
+Window functions are computed after grouping, over all the rows of
+each partition of their window. Compute() returns the value of the
+function for each row of a sorted partition. The results are then
+attached to the rows, and Evaluate() looks them up.
+*/
+type WindowFunction interface {
+	/*
+	   Represents the window function, including its OVER clause.
+	*/
+	expression.WindowFunction
+
+	/*
+	   Returns the OVER clause.
+	*/
+	Window() *WindowTerm
+
+	/*
+	   Sets the OVER clause. Used by the parser.
+	*/
+	SetWindow(window *WindowTerm)
+
+	/*
+	   Computes the function for each row of partition.
+	*/
+	Compute(partition *WindowPartition, context Context) (value.Values, error)
+}
+
+/*
+This method is used to retrieve a window function by the parser.
+Functions that are only valid with an OVER clause are looked up
+first; otherwise the aggregate of the same name is returned as a
+window aggregate.
+*/
+func GetWindowFunction(name string, distinct bool) (WindowFunction, bool) {
+	if !distinct {
+		rv, ok := _WINDOW_FUNCTIONS[strings.ToLower(name)]
+		if ok {
+			return rv, ok
+		}
+	}
+
+	agg, ok := GetAggregate(name, distinct)
+	if !ok {
+		return nil, false
+	}
+
+	return NewWindowAggregate(agg, nil), true
+}
+
+/*
+Functions that are only valid with an OVER clause.
+*/
+var _WINDOW_FUNCTIONS = map[string]WindowFunction{
+	"row_number":  &RowNumber{},
+	"rank":        &Rank{},
+	"dense_rank":  &DenseRank{},
+	"ntile":       &Ntile{},
+	"lag":         &Lag{},
+	"lead":        &Lead{},
+	"first_value": &FirstValue{},
+	"last_value":  &LastValue{},
+}
+
+/*
+Base class for window functions. It inherits from expressions
+FunctionBase, and has field window which represents the OVER clause.
+The expressions of the OVER clause are children of the function, so
+that they are formalized and traversed with it.
+*/
+type WindowFunctionBase struct {
+	expression.FunctionBase
+	window *WindowTerm
+}
+
+/*
+This method creates a new function using the input name and
+operands, and returns it as a pointer to a WindowFunctionBase.
+*/
+func NewWindowFunctionBase(name string, window *WindowTerm,
+	operands ...expression.Expression) *WindowFunctionBase {
+	return &WindowFunctionBase{
+		*expression.NewFunctionBase(name, operands...),
+		window,
+	}
+}
+
+/*
+Returns the OVER clause.
+*/
+func (this *WindowFunctionBase) Window() *WindowTerm {
+	return this.window
+}
+
+/*
+Sets the OVER clause.
+*/
+func (this *WindowFunctionBase) SetWindow(window *WindowTerm) {
+	this.window = window
+}
+
+/*
+Returns the OVER clause as a N1QL string.
+*/
+func (this *WindowFunctionBase) OverClause() string {
+	if this.window == nil {
+		return ""
+	}
+
+	return this.window.String()
+}
+
+/*
+This method evaluates the input window function, by retrieving the
+windows map from the attachments and performing a lookup using the
+function's string value.
+*/
+func (this *WindowFunctionBase) evaluate(fn WindowFunction, item value.Value,
+	context expression.Context) (result value.Value, err error) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			err = fmt.Errorf("Error evaluating window function: %v.", r)
+		}
+	}()
+
+	av := item.(value.AnnotatedValue)
+	windows := av.GetAttachment("windows")
+	if windows != nil {
+		wins := windows.(map[string]value.Value)
+		result = wins[fn.String()]
+	}
+
+	if result == nil {
+		err = fmt.Errorf("Window function %s not found.", fn.String())
+	}
+
+	return
+}
+
+/*
+Window functions are never static.
+*/
+func (this *WindowFunctionBase) Value() value.Value {
+	return nil
+}
+
+/*
+Not indexable.
+*/
+func (this *WindowFunctionBase) Indexable() bool {
+	return false
+}
+
+/*
+Return false.
+*/
+func (this *WindowFunctionBase) EquivalentTo(other expression.Expression) bool {
+	return false
+}
+
+/*
+Return false.
+*/
+func (this *WindowFunctionBase) SubsetOf(other expression.Expression) bool {
+	return false
+}
+
+/*
+Return the operands of the function, followed by the expressions of
+the OVER clause.
+*/
+func (this *WindowFunctionBase) Children() expression.Expressions {
+	children := make(expression.Expressions, 0, len(this.Operands())+4)
+	for _, op := range this.Operands() {
+		if op != nil {
+			children = append(children, op)
+		}
+	}
+
+	if this.window != nil {
+		children = append(children, this.window.Expressions()...)
+	}
+
+	return children
+}
+
+/*
+Map the operands of the function and the expressions of the OVER
+clause.
+*/
+func (this *WindowFunctionBase) MapChildren(mapper expression.Mapper) error {
+	operands := this.Operands()
+	for i, op := range operands {
+		if op == nil {
+			continue
+		}
+
+		expr, err := mapper.Map(op)
+		if err != nil {
+			return err
+		}
+
+		operands[i] = expr
+	}
+
+	if this.window != nil {
+		return this.window.MapExpressions(mapper)
+	}
+
+	return nil
+}
+
+/*
+Returns a copy of window, which may be nil.
+*/
+func copyWindow(window *WindowTerm) *WindowTerm {
+	if window == nil {
+		return nil
+	}
+
+	return window.Copy()
+}
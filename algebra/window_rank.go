@@ -0,0 +1,248 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package algebra
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/value"
+)
+
+/*
+This represents the window function ROW_NUMBER(). It returns the
+position of each row within its partition, starting at 1.
+*/
+type RowNumber struct {
+	WindowFunctionBase
+}
+
+func NewRowNumber(window *WindowTerm) WindowFunction {
+	rv := &RowNumber{
+		*NewWindowFunctionBase("row_number", window),
+	}
+
+	rv.SetExpr(rv)
+	return rv
+}
+
+func (this *RowNumber) Accept(visitor expression.Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *RowNumber) Type() value.Type { return value.NUMBER }
+
+func (this *RowNumber) Evaluate(item value.Value, context expression.Context) (value.Value, error) {
+	return this.evaluate(this, item, context)
+}
+
+func (this *RowNumber) MinArgs() int { return 0 }
+
+func (this *RowNumber) MaxArgs() int { return 0 }
+
+func (this *RowNumber) Constructor() expression.FunctionConstructor {
+	return func(operands ...expression.Expression) expression.Function {
+		return NewRowNumber(copyWindow(this.window))
+	}
+}
+
+func (this *RowNumber) Compute(partition *WindowPartition, context Context) (value.Values, error) {
+	rv := make(value.Values, partition.Len())
+	for i := range rv {
+		rv[i] = value.NewValue(i + 1)
+	}
+
+	return rv, nil
+}
+
+/*
+This represents the window function RANK(). It returns the position
+of the first peer of each row within its partition, starting at 1.
+Ranks are not consecutive when there are ties.
+*/
+type Rank struct {
+	WindowFunctionBase
+}
+
+func NewRank(window *WindowTerm) WindowFunction {
+	rv := &Rank{
+		*NewWindowFunctionBase("rank", window),
+	}
+
+	rv.SetExpr(rv)
+	return rv
+}
+
+func (this *Rank) Accept(visitor expression.Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *Rank) Type() value.Type { return value.NUMBER }
+
+func (this *Rank) Evaluate(item value.Value, context expression.Context) (value.Value, error) {
+	return this.evaluate(this, item, context)
+}
+
+func (this *Rank) MinArgs() int { return 0 }
+
+func (this *Rank) MaxArgs() int { return 0 }
+
+func (this *Rank) Constructor() expression.FunctionConstructor {
+	return func(operands ...expression.Expression) expression.Function {
+		return NewRank(copyWindow(this.window))
+	}
+}
+
+func (this *Rank) Compute(partition *WindowPartition, context Context) (value.Values, error) {
+	rv := make(value.Values, partition.Len())
+	for i := range rv {
+		rv[i] = value.NewValue(partition.PeerStart(i) + 1)
+	}
+
+	return rv, nil
+}
+
+/*
+This represents the window function DENSE_RANK(). It returns the
+position of the peer group of each row within its partition,
+starting at 1. Ranks are consecutive even when there are ties.
+*/
+type DenseRank struct {
+	WindowFunctionBase
+}
+
+func NewDenseRank(window *WindowTerm) WindowFunction {
+	rv := &DenseRank{
+		*NewWindowFunctionBase("dense_rank", window),
+	}
+
+	rv.SetExpr(rv)
+	return rv
+}
+
+func (this *DenseRank) Accept(visitor expression.Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *DenseRank) Type() value.Type { return value.NUMBER }
+
+func (this *DenseRank) Evaluate(item value.Value, context expression.Context) (value.Value, error) {
+	return this.evaluate(this, item, context)
+}
+
+func (this *DenseRank) MinArgs() int { return 0 }
+
+func (this *DenseRank) MaxArgs() int { return 0 }
+
+func (this *DenseRank) Constructor() expression.FunctionConstructor {
+	return func(operands ...expression.Expression) expression.Function {
+		return NewDenseRank(copyWindow(this.window))
+	}
+}
+
+func (this *DenseRank) Compute(partition *WindowPartition, context Context) (value.Values, error) {
+	rv := make(value.Values, partition.Len())
+	rank := 0
+	for i := range rv {
+		if partition.PeerStart(i) == i {
+			rank++
+		}
+
+		rv[i] = value.NewValue(rank)
+	}
+
+	return rv, nil
+}
+
+/*
+This represents the window function NTILE(n). It divides each
+partition into n buckets of nearly equal size, and returns the
+bucket of each row, starting at 1. Earlier buckets receive the extra
+rows when the partition does not divide evenly.
+*/
+type Ntile struct {
+	WindowFunctionBase
+}
+
+func NewNtile(window *WindowTerm, operand expression.Expression) WindowFunction {
+	rv := &Ntile{
+		*NewWindowFunctionBase("ntile", window, operand),
+	}
+
+	rv.SetExpr(rv)
+	return rv
+}
+
+func (this *Ntile) Accept(visitor expression.Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *Ntile) Type() value.Type { return value.NUMBER }
+
+func (this *Ntile) Evaluate(item value.Value, context expression.Context) (value.Value, error) {
+	return this.evaluate(this, item, context)
+}
+
+func (this *Ntile) MinArgs() int { return 1 }
+
+func (this *Ntile) MaxArgs() int { return 1 }
+
+func (this *Ntile) Constructor() expression.FunctionConstructor {
+	return func(operands ...expression.Expression) expression.Function {
+		return NewNtile(copyWindow(this.window), operands[0])
+	}
+}
+
+/*
+The number of buckets is evaluated against the first row of the
+partition, and must be a positive integer.
+*/
+func (this *Ntile) Compute(partition *WindowPartition, context Context) (value.Values, error) {
+	n := partition.Len()
+	rv := make(value.Values, n)
+	if n == 0 {
+		return rv, nil
+	}
+
+	bv, err := this.Operands()[0].Evaluate(partition.Rows()[0], context)
+	if err != nil {
+		return nil, err
+	}
+
+	b, ok := bv.Actual().(float64)
+	if !ok || b < 1 || b != math.Trunc(b) {
+		return nil, fmt.Errorf("NTILE requires a positive integer: %v.", bv)
+	}
+
+	buckets := int(b)
+	size := n / buckets
+	extra := n % buckets
+
+	bucket, left := 1, size
+	if extra > 0 {
+		left++
+	}
+
+	for i := range rv {
+		if left == 0 {
+			bucket++
+			left = size
+			if bucket <= extra {
+				left++
+			}
+		}
+
+		rv[i] = value.NewValue(bucket)
+		left--
+	}
+
+	return rv, nil
+}
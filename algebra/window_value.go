@@ -0,0 +1,266 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package algebra
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/value"
+)
+
+/*
+This represents the window function LAG(expr [, offset [, default]]).
+It returns expr evaluated on the row offset rows before each row in
+its partition, or default if there is no such row. The offset
+defaults to 1, and the default to NULL.
+*/
+type Lag struct {
+	WindowFunctionBase
+}
+
+func NewLag(window *WindowTerm, operands ...expression.Expression) WindowFunction {
+	rv := &Lag{
+		*NewWindowFunctionBase("lag", window, operands...),
+	}
+
+	rv.SetExpr(rv)
+	return rv
+}
+
+func (this *Lag) Accept(visitor expression.Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *Lag) Type() value.Type { return value.JSON }
+
+func (this *Lag) Evaluate(item value.Value, context expression.Context) (value.Value, error) {
+	return this.evaluate(this, item, context)
+}
+
+func (this *Lag) MinArgs() int { return 1 }
+
+func (this *Lag) MaxArgs() int { return 3 }
+
+func (this *Lag) Constructor() expression.FunctionConstructor {
+	return func(operands ...expression.Expression) expression.Function {
+		return NewLag(copyWindow(this.window), operands...)
+	}
+}
+
+func (this *Lag) Compute(partition *WindowPartition, context Context) (value.Values, error) {
+	return computeOffset(this.Operands(), partition, -1, context)
+}
+
+/*
+This represents the window function LEAD(expr [, offset [, default]]).
+It returns expr evaluated on the row offset rows after each row in
+its partition, or default if there is no such row. The offset
+defaults to 1, and the default to NULL.
+*/
+type Lead struct {
+	WindowFunctionBase
+}
+
+func NewLead(window *WindowTerm, operands ...expression.Expression) WindowFunction {
+	rv := &Lead{
+		*NewWindowFunctionBase("lead", window, operands...),
+	}
+
+	rv.SetExpr(rv)
+	return rv
+}
+
+func (this *Lead) Accept(visitor expression.Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *Lead) Type() value.Type { return value.JSON }
+
+func (this *Lead) Evaluate(item value.Value, context expression.Context) (value.Value, error) {
+	return this.evaluate(this, item, context)
+}
+
+func (this *Lead) MinArgs() int { return 1 }
+
+func (this *Lead) MaxArgs() int { return 3 }
+
+func (this *Lead) Constructor() expression.FunctionConstructor {
+	return func(operands ...expression.Expression) expression.Function {
+		return NewLead(copyWindow(this.window), operands...)
+	}
+}
+
+func (this *Lead) Compute(partition *WindowPartition, context Context) (value.Values, error) {
+	return computeOffset(this.Operands(), partition, 1, context)
+}
+
+/*
+Compute LAG (direction -1) or LEAD (direction 1). The offset and
+default are evaluated against the current row.
+*/
+func computeOffset(operands expression.Expressions, partition *WindowPartition,
+	direction int, context Context) (value.Values, error) {
+	rows := partition.Rows()
+	rv := make(value.Values, len(rows))
+
+	for i, row := range rows {
+		offset := 1
+		if len(operands) > 1 {
+			ov, err := operands[1].Evaluate(row, context)
+			if err != nil {
+				return nil, err
+			}
+
+			o, ok := ov.Actual().(float64)
+			if !ok || o < 0 || o != math.Trunc(o) {
+				return nil, fmt.Errorf("Offset must be a non-negative integer: %v.", ov)
+			}
+
+			offset = int(o)
+		}
+
+		j := i + direction*offset
+		if j >= 0 && j < len(rows) {
+			v, err := operands[0].Evaluate(rows[j], context)
+			if err != nil {
+				return nil, err
+			}
+
+			rv[i] = v
+		} else if len(operands) > 2 {
+			v, err := operands[2].Evaluate(row, context)
+			if err != nil {
+				return nil, err
+			}
+
+			rv[i] = v
+		} else {
+			rv[i] = value.NULL_VALUE
+		}
+	}
+
+	return rv, nil
+}
+
+/*
+This represents the window function FIRST_VALUE(expr). It returns
+expr evaluated on the first row of the frame of each row, or NULL if
+the frame is empty.
+*/
+type FirstValue struct {
+	WindowFunctionBase
+}
+
+func NewFirstValue(window *WindowTerm, operand expression.Expression) WindowFunction {
+	rv := &FirstValue{
+		*NewWindowFunctionBase("first_value", window, operand),
+	}
+
+	rv.SetExpr(rv)
+	return rv
+}
+
+func (this *FirstValue) Accept(visitor expression.Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *FirstValue) Type() value.Type { return value.JSON }
+
+func (this *FirstValue) Evaluate(item value.Value, context expression.Context) (value.Value, error) {
+	return this.evaluate(this, item, context)
+}
+
+func (this *FirstValue) MinArgs() int { return 1 }
+
+func (this *FirstValue) MaxArgs() int { return 1 }
+
+func (this *FirstValue) Constructor() expression.FunctionConstructor {
+	return func(operands ...expression.Expression) expression.Function {
+		return NewFirstValue(copyWindow(this.window), operands[0])
+	}
+}
+
+func (this *FirstValue) Compute(partition *WindowPartition, context Context) (value.Values, error) {
+	return computeFrameValue(this.Operands()[0], partition, true, context)
+}
+
+/*
+This represents the window function LAST_VALUE(expr). It returns
+expr evaluated on the last row of the frame of each row, or NULL if
+the frame is empty.
+*/
+type LastValue struct {
+	WindowFunctionBase
+}
+
+func NewLastValue(window *WindowTerm, operand expression.Expression) WindowFunction {
+	rv := &LastValue{
+		*NewWindowFunctionBase("last_value", window, operand),
+	}
+
+	rv.SetExpr(rv)
+	return rv
+}
+
+func (this *LastValue) Accept(visitor expression.Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *LastValue) Type() value.Type { return value.JSON }
+
+func (this *LastValue) Evaluate(item value.Value, context expression.Context) (value.Value, error) {
+	return this.evaluate(this, item, context)
+}
+
+func (this *LastValue) MinArgs() int { return 1 }
+
+func (this *LastValue) MaxArgs() int { return 1 }
+
+func (this *LastValue) Constructor() expression.FunctionConstructor {
+	return func(operands ...expression.Expression) expression.Function {
+		return NewLastValue(copyWindow(this.window), operands[0])
+	}
+}
+
+func (this *LastValue) Compute(partition *WindowPartition, context Context) (value.Values, error) {
+	return computeFrameValue(this.Operands()[0], partition, false, context)
+}
+
+func computeFrameValue(operand expression.Expression, partition *WindowPartition,
+	first bool, context Context) (value.Values, error) {
+	rows := partition.Rows()
+	rv := make(value.Values, len(rows))
+
+	for i := range rows {
+		lo, hi, err := partition.Frame(i, context)
+		if err != nil {
+			return nil, err
+		}
+
+		if lo > hi {
+			rv[i] = value.NULL_VALUE
+			continue
+		}
+
+		j := hi
+		if first {
+			j = lo
+		}
+
+		rv[i], err = operand.Evaluate(rows[j], context)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return rv, nil
+}
@@ -0,0 +1,301 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package algebra
+
+import (
+	"fmt"
+
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/value"
+)
+
+/*
+Represents the WITH clause of a select statement, which names the
+results of subqueries (common table expressions) for use in the
+rest of the statement. Type With is a struct containing the WITH
+terms, and a flag recursive for WITH RECURSIVE.
+
+The named results can be referenced in FROM and in expressions. Each
+term can reference the terms before it, and in WITH RECURSIVE, a term
+of the form (anchor UNION [ALL] step) can reference itself in step.
+*/
+type With struct {
+	recursive bool
+	terms     WithTerms
+}
+
+/*
+The function NewWith returns a pointer to the With struct by
+assigning the input attributes to the fields of the struct.
+*/
+func NewWith(recursive bool, terms WithTerms) *With {
+	return &With{
+		recursive: recursive,
+		terms:     terms,
+	}
+}
+
+/*
+Returns true for WITH RECURSIVE.
+*/
+func (this *With) Recursive() bool {
+	return this.recursive
+}
+
+/*
+Returns the WITH terms.
+*/
+func (this *With) Terms() WithTerms {
+	return this.terms
+}
+
+/*
+This method maps the queries of the WITH terms.
+*/
+func (this *With) MapExpressions(mapper expression.Mapper) (err error) {
+	for _, term := range this.terms {
+		err = term.query.MapExpressions(mapper)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+/*
+   Returns all contained Expressions.
+*/
+func (this *With) Expressions() expression.Expressions {
+	exprs := make(expression.Expressions, 0, 16)
+	for _, term := range this.terms {
+		exprs = append(exprs, term.query.Expressions()...)
+	}
+
+	return exprs
+}
+
+/*
+Returns all required privileges.
+*/
+func (this *With) Privileges() (datastore.Privileges, errors.Error) {
+	privs := datastore.NewPrivileges()
+	for _, term := range this.terms {
+		tprivs, err := term.query.Privileges()
+		if err != nil {
+			return nil, err
+		}
+
+		privs.Add(tprivs)
+	}
+
+	return privs, nil
+}
+
+/*
+   Representation as a N1QL string.
+*/
+func (this *With) String() string {
+	s := "with "
+	if this.recursive {
+		s += "recursive "
+	}
+
+	for i, term := range this.terms {
+		if i > 0 {
+			s += ", "
+		}
+
+		s += term.String()
+	}
+
+	return s
+}
+
+/*
+Qualify all identifiers of the WITH terms, and return a formalizer
+for the rest of the statement, in which the names of the terms are
+in scope. The queries of the terms can only see the names of the
+terms, and not the other aliases of the parent, so that their
+results do not depend on the current row.
+*/
+func (this *With) Formalize(parent *expression.Formalizer) (f *expression.Formalizer, err error) {
+	scope := withScope(parent)
+	allowed := value.NewScopeValue(make(map[string]interface{}, len(this.terms)), parent.Allowed)
+	names := make(map[string]bool, len(this.terms))
+
+	for _, term := range this.terms {
+		if names[term.alias] {
+			return nil, fmt.Errorf("Duplicate WITH alias %s.", term.alias)
+		}
+
+		names[term.alias] = true
+
+		if this.recursive {
+			err = term.setRecursive()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		tf := expression.NewFormalizer()
+		tf.Allowed = value.NewScopeValue(make(map[string]interface{}, 1), scope.Allowed)
+		if term.step != nil {
+			tf.Allowed.SetField(term.alias, _WITH_ALIAS)
+		}
+
+		err = term.query.FormalizeSubquery(tf)
+		if err != nil {
+			return nil, err
+		}
+
+		scope.Allowed.SetField(term.alias, _WITH_ALIAS)
+		allowed.SetField(term.alias, _WITH_ALIAS)
+	}
+
+	f = expression.NewFormalizer()
+	f.Allowed = allowed
+	f.Keyspace = parent.Keyspace
+	return f, nil
+}
+
+type WithTerms []*WithTerm
+
+/*
+Represents a term of the WITH clause, which names the results of a
+query. A recursive term is split into its anchor, which is evaluated
+once, and its step, which is evaluated repeatedly against the results
+of the previous iteration until they are empty.
+*/
+type WithTerm struct {
+	alias  string
+	query  *Select
+	anchor *Select
+	step   *Select
+	union  bool
+}
+
+/*
+The function NewWithTerm returns a pointer to the WithTerm struct
+by assigning the input attributes to the fields of the struct.
+*/
+func NewWithTerm(alias string, query *Select) *WithTerm {
+	return &WithTerm{
+		alias: alias,
+		query: query,
+	}
+}
+
+/*
+Returns the name of the term.
+*/
+func (this *WithTerm) Alias() string {
+	return this.alias
+}
+
+/*
+Returns the query of the term.
+*/
+func (this *WithTerm) Query() *Select {
+	return this.query
+}
+
+/*
+Returns true if the term is recursive.
+*/
+func (this *WithTerm) Recursive() bool {
+	return this.step != nil
+}
+
+/*
+Returns the anchor of a recursive term.
+*/
+func (this *WithTerm) Anchor() *Select {
+	return this.anchor
+}
+
+/*
+Returns the step of a recursive term.
+*/
+func (this *WithTerm) Step() *Select {
+	return this.step
+}
+
+/*
+Returns true if a recursive term is combined with UNION rather than
+UNION ALL. Duplicate results are then discarded at each step, which
+also stops cycles.
+*/
+func (this *WithTerm) Distinct() bool {
+	return this.union
+}
+
+/*
+   Representation as a N1QL string.
+*/
+func (this *WithTerm) String() string {
+	return "`" + this.alias + "` as (" + this.query.String() + ")"
+}
+
+/*
+In WITH RECURSIVE, a term of the form (anchor UNION [ALL] step) is
+recursive.
+*/
+func (this *WithTerm) setRecursive() error {
+	var first, second Subresult
+	switch sub := this.query.subresult.(type) {
+	case *Union:
+		first, second = sub.First(), sub.Second()
+		this.union = true
+	case *UnionAll:
+		first, second = sub.First(), sub.Second()
+	default:
+		return nil
+	}
+
+	if this.query.order != nil || this.query.offset != nil || this.query.limit != nil {
+		return fmt.Errorf("Recursive WITH term %s cannot have ORDER BY, OFFSET, or LIMIT.",
+			this.alias)
+	}
+
+	this.anchor = NewSelect(first, nil, nil, nil)
+	this.step = NewSelect(second, nil, nil, nil)
+	return nil
+}
+
+/*
+Marks the names of WITH terms in the scope of a formalizer.
+*/
+const _WITH_ALIAS = "#with"
+
+/*
+Returns true if alias is the name of a WITH term in scope.
+*/
+func isWithAlias(allowed value.Value, alias string) bool {
+	v, ok := allowed.Field(alias)
+	return ok && v.Actual() == _WITH_ALIAS
+}
+
+/*
+Returns a formalizer whose scope only contains the names of the WITH
+terms in the scope of parent.
+*/
+func withScope(parent *expression.Formalizer) *expression.Formalizer {
+	f := expression.NewFormalizer()
+	for name, v := range parent.Allowed.Fields() {
+		if v == _WITH_ALIAS {
+			f.Allowed.SetField(name, _WITH_ALIAS)
+		}
+	}
+
+	return f
+}
@@ -97,7 +97,7 @@ type QueryNodeOptions interface {
 	Metrics() bool           // Whether to provide Metrics
 	RequestCap() int         // Max number of queued requests
 	Threads() int            // Thread count
-	OrderLimit() int         // Max LIMIT for ORDER BY clauses
+	OrderLimit() int         // Max LIMIT plus OFFSET for ORDER BY clauses
 	UpdateLimit() int        // Max LIMIT for data modification statements
 	Http() string            // HTTP service address
 	Https() string           // HTTPS service address
@@ -80,6 +80,15 @@ type Keyspace interface {
 	Release() // Release any resources held by this object
 }
 
+// AuthorizedDeleteKeyspace is implemented by keyspaces whose deletes
+// depend on the credentials of the request, beyond the privileges
+// authorized before execution.
+type AuthorizedDeleteKeyspace interface {
+	Keyspace
+
+	DeleteAs(deletes []string, credentials Credentials) ([]string, errors.Error) // Bulk deletes on behalf of the credentials
+}
+
 // Key-value pair
 type Pair struct {
 	Key   string
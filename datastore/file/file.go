@@ -21,8 +21,10 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/couchbaselabs/query/datastore"
 	"github.com/couchbaselabs/query/errors"
@@ -125,6 +127,8 @@ type namespace struct {
 	name          string
 	keyspaces     map[string]*keyspace
 	keyspaceNames []string
+	functions     map[string]*datastore.FunctionDefinition
+	fnlock        sync.RWMutex
 }
 
 func (p *namespace) DatastoreId() string {
@@ -160,10 +164,82 @@ func (p *namespace) KeyspaceByName(name string) (b datastore.Keyspace, e errors.
 	return
 }
 
+func (p *namespace) FunctionNames() ([]string, errors.Error) {
+	p.fnlock.RLock()
+	defer p.fnlock.RUnlock()
+
+	rv := make([]string, 0, len(p.functions))
+	for _, def := range p.functions {
+		rv = append(rv, def.Name)
+	}
+
+	return rv, nil
+}
+
+func (p *namespace) FunctionByName(name string) (*datastore.FunctionDefinition, errors.Error) {
+	p.fnlock.RLock()
+	defer p.fnlock.RUnlock()
+
+	def, ok := p.functions[strings.ToUpper(name)]
+	if !ok {
+		return nil, errors.NewFileFunctionNotFoundError(nil, name)
+	}
+
+	return def, nil
+}
+
+func (p *namespace) CreateFunction(definition *datastore.FunctionDefinition, replace bool) errors.Error {
+	p.fnlock.Lock()
+	defer p.fnlock.Unlock()
+
+	nameu := strings.ToUpper(definition.Name)
+	old, ok := p.functions[nameu]
+	if ok && !replace {
+		return errors.NewFileDuplicateFunctionError(nil, definition.Name)
+	}
+
+	p.functions[nameu] = definition
+	e := p.saveFunctions()
+	if e != nil {
+		if ok {
+			p.functions[nameu] = old
+		} else {
+			delete(p.functions, nameu)
+		}
+	}
+
+	return e
+}
+
+func (p *namespace) DropFunction(name string) errors.Error {
+	p.fnlock.Lock()
+	defer p.fnlock.Unlock()
+
+	nameu := strings.ToUpper(name)
+	old, ok := p.functions[nameu]
+	if !ok {
+		return errors.NewFileFunctionNotFoundError(nil, name)
+	}
+
+	delete(p.functions, nameu)
+	e := p.saveFunctions()
+	if e != nil {
+		p.functions[nameu] = old
+	}
+
+	return e
+}
+
 func (p *namespace) path() string {
 	return filepath.Join(p.store.path, p.name)
 }
 
+// functions are stored in a single file alongside the keyspace
+// directories of the namespace.
+func (p *namespace) functionsPath() string {
+	return filepath.Join(p.path(), "functions.json")
+}
+
 // newNamespace creates a new namespace.
 func newNamespace(s *store, dir string) (p *namespace, e errors.Error) {
 	p = new(namespace)
@@ -171,9 +247,74 @@ func newNamespace(s *store, dir string) (p *namespace, e errors.Error) {
 	p.name = dir
 
 	e = p.loadKeyspaces()
+	if e != nil {
+		return
+	}
+
+	e = p.loadFunctions()
 	return
 }
 
+func (p *namespace) loadFunctions() errors.Error {
+	p.functions = make(map[string]*datastore.FunctionDefinition)
+
+	bytes, er := ioutil.ReadFile(p.functionsPath())
+	if er != nil {
+		if os.IsNotExist(er) {
+			return nil
+		}
+
+		return errors.NewFileDatastoreError(er, "")
+	}
+
+	var defs []*datastore.FunctionDefinition
+	er = json.Unmarshal(bytes, &defs)
+	if er != nil {
+		return errors.NewFileDatastoreError(er, "")
+	}
+
+	for _, def := range defs {
+		p.functions[strings.ToUpper(def.Name)] = def
+	}
+
+	return nil
+}
+
+type functionsByName []*datastore.FunctionDefinition
+
+func (this functionsByName) Len() int           { return len(this) }
+func (this functionsByName) Less(i, j int) bool { return this[i].Name < this[j].Name }
+func (this functionsByName) Swap(i, j int)      { this[i], this[j] = this[j], this[i] }
+
+func (p *namespace) saveFunctions() errors.Error {
+	if len(p.functions) == 0 {
+		er := os.Remove(p.functionsPath())
+		if er != nil && !os.IsNotExist(er) {
+			return errors.NewFileDatastoreError(er, "")
+		}
+
+		return nil
+	}
+
+	defs := make([]*datastore.FunctionDefinition, 0, len(p.functions))
+	for _, def := range p.functions {
+		defs = append(defs, def)
+	}
+
+	sort.Sort(functionsByName(defs))
+	bytes, er := json.MarshalIndent(defs, "", "    ")
+	if er != nil {
+		return errors.NewFileDatastoreError(er, "")
+	}
+
+	er = ioutil.WriteFile(p.functionsPath(), bytes, 0666)
+	if er != nil {
+		return errors.NewFileDatastoreError(er, "")
+	}
+
+	return nil
+}
+
 func (p *namespace) loadKeyspaces() (e errors.Error) {
 	dirEntries, er := ioutil.ReadDir(p.path())
 	if er != nil {
@@ -210,6 +351,9 @@ type keyspace struct {
 	name      string
 	fi        datastore.Indexer
 	fileLock  sync.Mutex
+	stats     map[string]*datastore.TermStatistics
+	statsLock sync.RWMutex
+	mutations uint64 // incremented after each mutation; accessed atomically
 }
 
 func (b *keyspace) NamespaceId() string {
@@ -315,6 +459,7 @@ func (b *keyspace) performOp(op int, kvPairs []datastore.Pair) ([]datastore.Pair
 	// this lock can be mode more granular FIXME
 	b.fileLock.Lock()
 	defer b.fileLock.Unlock()
+	defer b.mutated()
 
 	for _, kv := range kvPairs {
 		var file *os.File
@@ -382,6 +527,7 @@ func (b *keyspace) Delete(deletes []string) ([]string, errors.Error) {
 
 	var fileError []string
 	var deleted []string
+	defer b.mutated()
 	for _, key := range deletes {
 		filename := filepath.Join(b.path(), key+".json")
 		if err := os.Remove(filename); err != nil {
@@ -404,6 +550,16 @@ func (b *keyspace) Delete(deletes []string) ([]string, errors.Error) {
 func (b *keyspace) Release() {
 }
 
+// Record a mutation, so that secondary indexes compute their entries
+// again on the next scan
+func (b *keyspace) mutated() {
+	atomic.AddUint64(&b.mutations, 1)
+}
+
+func (b *keyspace) mutationCount() uint64 {
+	return atomic.LoadUint64(&b.mutations)
+}
+
 func (b *keyspace) path() string {
 	return filepath.Join(b.namespace.path(), b.name)
 }
@@ -426,10 +582,12 @@ func newKeyspace(p *namespace, dir string) (b *keyspace, e errors.Error) {
 	b.fi = newFileIndexer(b)
 	b.fi.CreatePrimaryIndex("#primary", nil)
 
+	e = b.loadStatistics()
 	return
 }
 
 type fileIndexer struct {
+	sync.RWMutex
 	keyspace *keyspace
 	indexes  map[string]datastore.Index
 	primary  datastore.PrimaryIndex
@@ -452,18 +610,18 @@ func (fi *fileIndexer) Name() datastore.IndexType {
 }
 
 func (fi *fileIndexer) IndexIds() ([]string, errors.Error) {
-	rv := make([]string, 0, len(fi.indexes))
-	for name, _ := range fi.indexes {
-		rv = append(rv, name)
-	}
-	return rv, nil
+	return fi.IndexNames()
 }
 
 func (fi *fileIndexer) IndexNames() ([]string, errors.Error) {
+	fi.RLock()
+	defer fi.RUnlock()
+
 	rv := make([]string, 0, len(fi.indexes))
 	for name, _ := range fi.indexes {
 		rv = append(rv, name)
 	}
+	sort.Strings(rv)
 	return rv, nil
 }
 
@@ -472,6 +630,9 @@ func (fi *fileIndexer) IndexById(id string) (datastore.Index, errors.Error) {
 }
 
 func (fi *fileIndexer) IndexByName(name string) (datastore.Index, errors.Error) {
+	fi.RLock()
+	defer fi.RUnlock()
+
 	index, ok := fi.indexes[name]
 	if !ok {
 		return nil, errors.NewFileIdxNotFound(nil, name)
@@ -484,11 +645,25 @@ func (fi *fileIndexer) PrimaryIndexes() ([]datastore.PrimaryIndex, errors.Error)
 }
 
 func (fi *fileIndexer) Indexes() ([]datastore.Index, errors.Error) {
-	return []datastore.Index{fi.primary}, nil
+	names, _ := fi.IndexNames()
+
+	fi.RLock()
+	defer fi.RUnlock()
+
+	rv := make([]datastore.Index, 0, len(names))
+	for _, name := range names {
+		if index, ok := fi.indexes[name]; ok {
+			rv = append(rv, index)
+		}
+	}
+	return rv, nil
 }
 
 func (fi *fileIndexer) CreatePrimaryIndex(name string, with value.Value) (
 	datastore.PrimaryIndex, errors.Error) {
+	fi.Lock()
+	defer fi.Unlock()
+
 	if fi.primary == nil {
 		pi := new(primaryIndex)
 		fi.primary = pi
@@ -500,13 +675,53 @@ func (fi *fileIndexer) CreatePrimaryIndex(name string, with value.Value) (
 	return fi.primary, nil
 }
 
-func (b *fileIndexer) CreateIndex(name string, equalKey, rangeKey expression.Expressions,
+func (fi *fileIndexer) CreateIndex(name string, equalKey, rangeKey expression.Expressions,
 	where expression.Expression, with value.Value) (datastore.Index, errors.Error) {
-	return nil, errors.NewFileNotSupported(nil, "CREATE INDEX is not supported for file-based datastore.")
+	fi.Lock()
+	defer fi.Unlock()
+
+	if _, ok := fi.indexes[name]; ok {
+		return nil, errors.NewFileDuplicateIndexError(nil, name)
+	}
+
+	si := &secondaryIndex{
+		name:     name,
+		keyspace: fi.keyspace,
+		indexer:  fi,
+		seekKey:  equalKey,
+		rangeKey: rangeKey,
+		where:    where,
+	}
+
+	fi.indexes[name] = si
+	return si, nil
+}
+
+// Secondary indexes are computed on the first scan after a mutation,
+// so they never need to be built.
+func (fi *fileIndexer) BuildIndexes(names ...string) errors.Error {
+	fi.RLock()
+	defer fi.RUnlock()
+
+	for _, name := range names {
+		if _, ok := fi.indexes[name]; !ok {
+			return errors.NewFileIdxNotFound(nil, name)
+		}
+	}
+
+	return nil
 }
 
-func (b *fileIndexer) BuildIndexes(names ...string) errors.Error {
-	return errors.NewFileNotSupported(nil, "BUILD INDEXES is not supported for file-based datastore.")
+func (fi *fileIndexer) dropIndex(name string) errors.Error {
+	fi.Lock()
+	defer fi.Unlock()
+
+	if _, ok := fi.indexes[name]; !ok {
+		return errors.NewFileIdxNotFound(nil, name)
+	}
+
+	delete(fi.indexes, name)
+	return nil
 }
 
 func (b *fileIndexer) Refresh() errors.Error {
@@ -553,7 +768,24 @@ func (pi *primaryIndex) State() (state datastore.IndexState, msg string, err err
 }
 
 func (pi *primaryIndex) Statistics(span *datastore.Span) (datastore.Statistics, errors.Error) {
-	return nil, nil
+	dirEntries, er := ioutil.ReadDir(pi.keyspace.path())
+	if er != nil {
+		return nil, errors.NewFileDatastoreError(er, "")
+	}
+
+	keys := make([]value.Values, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			continue
+		}
+
+		key := value.Values{value.NewValue(documentPathToId(dirEntry.Name()))}
+		if span.Contains(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	return datastore.NewStatistics(keys, datastore.STATISTICS_BINS), nil
 }
 
 func (pi *primaryIndex) Drop() errors.Error {
@@ -640,13 +872,19 @@ func (pi *primaryIndex) ScanEntries(limit int64, cons datastore.ScanConsistency,
 		return
 	}
 
-	for i, dirEntry := range dirEntries {
-		if limit > 0 && int64(i) > limit {
+	var n int64 = 0
+	for _, dirEntry := range dirEntries {
+		if limit > 0 && n >= limit {
 			break
 		}
 		if !dirEntry.IsDir() {
 			entry := datastore.IndexEntry{PrimaryKey: documentPathToId(dirEntry.Name())}
-			conn.EntryChannel() <- &entry
+			select {
+			case conn.EntryChannel() <- &entry:
+				n++
+			case <-conn.StopChannel():
+				return
+			}
 		}
 	}
 }
@@ -0,0 +1,305 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package file
+
+import (
+	"io/ioutil"
+	"sort"
+	"sync"
+
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/timestamp"
+	"github.com/couchbaselabs/query/value"
+)
+
+// secondaryIndex is an index on expressions of the documents of a
+// keyspace. Index definitions are kept in memory, and index entries
+// are computed from the documents when first needed and cached until
+// the keyspace is next mutated.
+type secondaryIndex struct {
+	name      string
+	keyspace  *keyspace
+	indexer   *fileIndexer
+	seekKey   expression.Expressions
+	rangeKey  expression.Expressions
+	where     expression.Expression
+	cacheLock sync.Mutex
+	cached    []*datastore.IndexEntry
+	mutations uint64 // keyspace mutation count of the cached entries
+}
+
+func (si *secondaryIndex) KeyspaceId() string {
+	return si.keyspace.Id()
+}
+
+func (si *secondaryIndex) Id() string {
+	return si.Name()
+}
+
+func (si *secondaryIndex) Name() string {
+	return si.name
+}
+
+func (si *secondaryIndex) Type() datastore.IndexType {
+	return datastore.DEFAULT
+}
+
+func (si *secondaryIndex) SeekKey() expression.Expressions {
+	return si.seekKey
+}
+
+func (si *secondaryIndex) RangeKey() expression.Expressions {
+	return si.rangeKey
+}
+
+func (si *secondaryIndex) Condition() expression.Expression {
+	return si.where
+}
+
+func (si *secondaryIndex) State() (state datastore.IndexState, msg string, err errors.Error) {
+	return datastore.ONLINE, "", nil
+}
+
+// Statistics are estimated from the statistics stored by UPDATE
+// STATISTICS for the leading index key, if any, and otherwise
+// computed from the index entries. Stored statistics describe the
+// whole keyspace, so they are not used for filtered indexes.
+func (si *secondaryIndex) Statistics(span *datastore.Span) (datastore.Statistics, errors.Error) {
+	if si.where == nil && len(si.rangeKey) > 0 && !isArrayKey(si.rangeKey[0]) {
+		stored, err := si.keyspace.StatisticsByTerm(si.rangeKey[0].String())
+		if err != nil {
+			return nil, err
+		}
+
+		if stored != nil {
+			total, err := si.keyspace.Count()
+			if err != nil {
+				return nil, err
+			}
+
+			return stored.Estimate(span, total), nil
+		}
+	}
+
+	entries, err := si.entries()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]value.Values, 0, len(entries))
+	for _, entry := range entries {
+		if span.Contains(entry.EntryKey) {
+			keys = append(keys, entry.EntryKey)
+		}
+	}
+
+	return datastore.NewStatistics(keys, datastore.STATISTICS_BINS), nil
+}
+
+func (si *secondaryIndex) Drop() errors.Error {
+	return si.indexer.dropIndex(si.name)
+}
+
+func (si *secondaryIndex) Scan(span *datastore.Span, distinct bool, limit int64,
+	cons datastore.ScanConsistency, vector timestamp.Vector, conn *datastore.IndexConnection) {
+	si.scan(span, distinct, limit, false, conn)
+}
+
+func (si *secondaryIndex) ReverseScan(span *datastore.Span, distinct bool, limit int64,
+	cons datastore.ScanConsistency, vector timestamp.Vector, conn *datastore.IndexConnection) {
+	si.scan(span, distinct, limit, true, conn)
+}
+
+func (si *secondaryIndex) scan(span *datastore.Span, distinct bool, limit int64,
+	reverse bool, conn *datastore.IndexConnection) {
+	defer close(conn.EntryChannel())
+
+	entries, err := si.entries()
+	if err != nil {
+		conn.Error(err)
+		return
+	}
+
+	var seen map[string]bool
+	if distinct {
+		seen = make(map[string]bool, len(entries))
+	}
+
+	var n int64 = 0
+	for i := range entries {
+		// The cached entries are shared, so they are read backwards
+		// rather than reversed
+		entry := entries[i]
+		if reverse {
+			entry = entries[len(entries)-1-i]
+		}
+
+		if limit > 0 && n >= limit {
+			break
+		}
+
+		if !span.Contains(entry.EntryKey) {
+			continue
+		}
+
+		if distinct {
+			if seen[entry.PrimaryKey] {
+				continue
+			}
+
+			seen[entry.PrimaryKey] = true
+		}
+
+		select {
+		case conn.EntryChannel() <- entry:
+			n++
+		case <-conn.StopChannel():
+			return
+		}
+	}
+}
+
+// Return the index entries of the keyspace, sorted by index key. The
+// entries are computed again only if the keyspace has been mutated
+// since they were cached, and must not be modified.
+func (si *secondaryIndex) entries() ([]*datastore.IndexEntry, errors.Error) {
+	si.cacheLock.Lock()
+	defer si.cacheLock.Unlock()
+
+	// Read the count before computing, so that a concurrent mutation
+	// leaves the cached entries out of date
+	mutations := si.keyspace.mutationCount()
+	if si.cached != nil && si.mutations == mutations {
+		return si.cached, nil
+	}
+
+	entries, err := si.computeEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	si.cached = entries
+	si.mutations = mutations
+	return entries, nil
+}
+
+// Compute the index entries of the keyspace, sorted by index key.
+// Documents that do not satisfy the index condition, or whose
+// leading index key is missing, are not indexed.
+func (si *secondaryIndex) computeEntries() ([]*datastore.IndexEntry, errors.Error) {
+	dirEntries, er := ioutil.ReadDir(si.keyspace.path())
+	if er != nil {
+		return nil, errors.NewFileDatastoreError(er, "")
+	}
+
+	context := expression.NewIndexContext()
+	entries := make([]*datastore.IndexEntry, 0, len(dirEntries))
+
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			continue
+		}
+
+		id := documentPathToId(dirEntry.Name())
+		doc, e := si.keyspace.fetchOne(id)
+		if e != nil {
+			return nil, e
+		}
+
+		if doc == nil {
+			continue
+		}
+
+		if si.where != nil {
+			cond, er := si.where.Evaluate(doc, context)
+			if er != nil || !cond.Truth() {
+				continue
+			}
+		}
+
+		for _, key := range evaluateKeys(si.rangeKey, doc, context) {
+			entries = append(entries, &datastore.IndexEntry{EntryKey: key, PrimaryKey: id})
+		}
+	}
+
+	sort.Sort(entriesByKey(entries))
+	return entries, nil
+}
+
+// Evaluate the index keys of a document. An array index key yields
+// an index key for each distinct element of the array, so a document
+// may have several index keys, or none if the array is empty.
+func evaluateKeys(exprs expression.Expressions, doc value.Value,
+	context expression.Context) []value.Values {
+	if len(exprs) == 0 {
+		return nil
+	}
+
+	keys := []value.Values{make(value.Values, len(exprs))}
+	for i, expr := range exprs {
+		v, err := expr.Evaluate(doc, context)
+		if err != nil {
+			return nil
+		}
+
+		if !isArrayKey(expr) || v.Type() != value.ARRAY {
+			for _, key := range keys {
+				key[i] = v
+			}
+
+			continue
+		}
+
+		elems := v.Actual().([]interface{})
+		expanded := make([]value.Values, 0, len(keys)*len(elems))
+		for _, key := range keys {
+			for _, elem := range elems {
+				ekey := make(value.Values, len(key))
+				copy(ekey, key)
+				ekey[i] = value.NewValue(elem)
+				expanded = append(expanded, ekey)
+			}
+		}
+
+		keys = expanded
+	}
+
+	if len(keys) == 0 || keys[0][0].Type() == value.MISSING {
+		return nil
+	}
+
+	return keys
+}
+
+func isArrayKey(expr expression.Expression) bool {
+	array, ok := expr.(*expression.Array)
+	return ok && array.Distinct()
+}
+
+type entriesByKey []*datastore.IndexEntry
+
+func (this entriesByKey) Len() int {
+	return len(this)
+}
+
+func (this entriesByKey) Less(i, j int) bool {
+	c := datastore.CompareKeys(this[i].EntryKey, this[j].EntryKey)
+	if c != 0 {
+		return c < 0
+	}
+
+	return this[i].PrimaryKey < this[j].PrimaryKey
+}
+
+func (this entriesByKey) Swap(i, j int) {
+	this[i], this[j] = this[j], this[i]
+}
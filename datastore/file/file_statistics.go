@@ -0,0 +1,153 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package file
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/errors"
+)
+
+func (b *keyspace) StatisticsTerms() ([]string, errors.Error) {
+	b.statsLock.RLock()
+	defer b.statsLock.RUnlock()
+
+	rv := make([]string, 0, len(b.stats))
+	for term, _ := range b.stats {
+		rv = append(rv, term)
+	}
+
+	sort.Strings(rv)
+	return rv, nil
+}
+
+func (b *keyspace) StatisticsByTerm(term string) (*datastore.TermStatistics, errors.Error) {
+	b.statsLock.RLock()
+	defer b.statsLock.RUnlock()
+
+	return b.stats[term], nil
+}
+
+func (b *keyspace) UpdateStatistics(stats []*datastore.TermStatistics) errors.Error {
+	b.statsLock.Lock()
+	defer b.statsLock.Unlock()
+
+	old := b.copyStatistics()
+	for _, s := range stats {
+		b.stats[s.Term] = s
+	}
+
+	e := b.saveStatistics()
+	if e != nil {
+		b.stats = old
+	}
+
+	return e
+}
+
+func (b *keyspace) DeleteStatistics(terms []string) errors.Error {
+	b.statsLock.Lock()
+	defer b.statsLock.Unlock()
+
+	old := b.copyStatistics()
+	if terms == nil {
+		b.stats = make(map[string]*datastore.TermStatistics)
+	}
+
+	for _, term := range terms {
+		delete(b.stats, term)
+	}
+
+	e := b.saveStatistics()
+	if e != nil {
+		b.stats = old
+	}
+
+	return e
+}
+
+// statistics are stored in a file alongside the keyspace directory,
+// so that they are not mistaken for documents.
+func (b *keyspace) statisticsPath() string {
+	return filepath.Join(b.namespace.path(), b.name+".statistics.json")
+}
+
+func (b *keyspace) copyStatistics() map[string]*datastore.TermStatistics {
+	rv := make(map[string]*datastore.TermStatistics, len(b.stats))
+	for term, s := range b.stats {
+		rv[term] = s
+	}
+
+	return rv
+}
+
+func (b *keyspace) loadStatistics() errors.Error {
+	b.stats = make(map[string]*datastore.TermStatistics)
+
+	bytes, er := ioutil.ReadFile(b.statisticsPath())
+	if er != nil {
+		if os.IsNotExist(er) {
+			return nil
+		}
+
+		return errors.NewFileDatastoreError(er, "")
+	}
+
+	var stats []*datastore.TermStatistics
+	er = json.Unmarshal(bytes, &stats)
+	if er != nil {
+		return errors.NewFileDatastoreError(er, "")
+	}
+
+	for _, s := range stats {
+		b.stats[s.Term] = s
+	}
+
+	return nil
+}
+
+func (b *keyspace) saveStatistics() errors.Error {
+	if len(b.stats) == 0 {
+		er := os.Remove(b.statisticsPath())
+		if er != nil && !os.IsNotExist(er) {
+			return errors.NewFileDatastoreError(er, "")
+		}
+
+		return nil
+	}
+
+	terms := make([]string, 0, len(b.stats))
+	for term, _ := range b.stats {
+		terms = append(terms, term)
+	}
+
+	sort.Strings(terms)
+	stats := make([]*datastore.TermStatistics, len(terms))
+	for i, term := range terms {
+		stats[i] = b.stats[term]
+	}
+
+	bytes, er := json.MarshalIndent(stats, "", "    ")
+	if er != nil {
+		return errors.NewFileDatastoreError(er, "")
+	}
+
+	er = ioutil.WriteFile(b.statisticsPath(), bytes, 0666)
+	if er != nil {
+		return errors.NewFileDatastoreError(er, "")
+	}
+
+	return nil
+}
@@ -12,10 +12,13 @@ package file
 import (
 	"fmt"
 	"math"
+	"sort"
 	"testing"
 
 	"github.com/couchbaselabs/query/datastore"
 	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/value"
 )
 
 func TestFile(t *testing.T) {
@@ -180,6 +183,245 @@ func TestFile(t *testing.T) {
 
 }
 
+func TestFileIndex(t *testing.T) {
+	keyspace, indexer := testContactsIndexer(t)
+
+	rangeKey := expression.Expressions{expression.NewIdentifier("name")}
+	index, err := indexer.CreateIndex("by_name", nil, rangeKey, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create index: %v", err)
+	}
+
+	_, err = indexer.CreateIndex("by_name", nil, rangeKey, nil, nil)
+	if err == nil {
+		t.Errorf("Duplicate index should not have been created")
+	}
+
+	span := &datastore.Span{}
+	span.Range.Low = value.Values{value.NewValue("e")}
+	span.Range.High = value.Values{value.NewValue("i")}
+	span.Range.Inclusion = datastore.BOTH
+
+	conn := datastore.NewIndexConnection(&testingContext{t})
+	go index.Scan(span, false, math.MaxInt64, datastore.UNBOUNDED, nil, conn)
+
+	var scanned []string
+	for entry := range conn.EntryChannel() {
+		scanned = append(scanned, entry.PrimaryKey)
+	}
+
+	if fmt.Sprint(scanned) != "[earl fred harry]" {
+		t.Errorf("Expected [earl fred harry], scanned %v", scanned)
+	}
+
+	stats, err := index.Statistics(span)
+	if err != nil || stats == nil {
+		t.Fatalf("failed to get statistics: %v", err)
+	}
+
+	count, _ := stats.Count()
+	if count != 3 {
+		t.Errorf("Expected count of 3, got %d", count)
+	}
+
+	min, _ := stats.Min()
+	max, _ := stats.Max()
+	if min[0].Actual() != "earl" || max[0].Actual() != "harry" {
+		t.Errorf("Expected min earl and max harry, got %v and %v", min, max)
+	}
+
+	// The entries are cached until the keyspace is mutated
+	si := index.(*secondaryIndex)
+	entries, _ := si.entries()
+	if cached, _ := si.entries(); &cached[0] != &entries[0] {
+		t.Errorf("Expected the cached entries to be reused")
+	}
+
+	// A reverse scan does not reorder the cached entries
+	conn = datastore.NewIndexConnection(&testingContext{t})
+	go si.ReverseScan(span, false, math.MaxInt64, datastore.UNBOUNDED, nil, conn)
+
+	scanned = nil
+	for entry := range conn.EntryChannel() {
+		scanned = append(scanned, entry.PrimaryKey)
+	}
+
+	if fmt.Sprint(scanned) != "[harry fred earl]" {
+		t.Errorf("Expected [harry fred earl], scanned %v", scanned)
+	}
+
+	if cached, _ := si.entries(); &cached[0] != &entries[0] || cached[0] != entries[0] {
+		t.Errorf("Expected the cached entries to be unchanged")
+	}
+
+	george := datastore.Pair{Key: "george", Value: value.NewValue(map[string]interface{}{"name": "george"})}
+	_, err = keyspace.Insert([]datastore.Pair{george})
+	if err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	stats, _ = index.Statistics(span)
+	count, _ = stats.Count()
+	if count != 4 {
+		t.Errorf("Expected count of 4 after insert, got %d", count)
+	}
+
+	_, err = keyspace.Delete([]string{"george"})
+	if err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+
+	stats, _ = index.Statistics(span)
+	count, _ = stats.Count()
+	if count != 3 {
+		t.Errorf("Expected count of 3 after delete, got %d", count)
+	}
+
+	err = index.Drop()
+	if err != nil {
+		t.Errorf("failed to drop index: %v", err)
+	}
+
+	_, err = indexer.IndexByName("by_name")
+	if err == nil {
+		t.Errorf("Index should have been dropped")
+	}
+}
+
+func TestFileArrayIndex(t *testing.T) {
+	_, indexer := testContactsIndexer(t)
+
+	rangeKey := expression.Expressions{expression.NewDistinctArray(
+		expression.NewIdentifier("h"),
+		expression.Bindings{expression.NewBinding("h", expression.NewIdentifier("hobbies"))},
+		nil)}
+	index, err := indexer.CreateIndex("by_hobby", nil, rangeKey, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create index: %v", err)
+	}
+
+	defer index.Drop()
+
+	span := &datastore.Span{}
+	span.Range.Inclusion = datastore.BOTH
+
+	// One entry for each hobby of each contact
+	conn := datastore.NewIndexConnection(&testingContext{t})
+	go index.Scan(span, false, math.MaxInt64, datastore.UNBOUNDED, nil, conn)
+
+	n := 0
+	for _ = range conn.EntryChannel() {
+		n++
+	}
+
+	if n != 7 {
+		t.Errorf("Expected 7 entries, scanned %d", n)
+	}
+
+	// One entry for each contact with a hobby
+	conn = datastore.NewIndexConnection(&testingContext{t})
+	go index.Scan(span, true, math.MaxInt64, datastore.UNBOUNDED, nil, conn)
+
+	var scanned []string
+	for entry := range conn.EntryChannel() {
+		scanned = append(scanned, entry.PrimaryKey)
+	}
+
+	sort.Strings(scanned)
+	if fmt.Sprint(scanned) != "[dave earl fred ian]" {
+		t.Errorf("Expected [dave earl fred ian], scanned %v", scanned)
+	}
+}
+
+func TestFileStatistics(t *testing.T) {
+	keyspace := testContacts(t)
+
+	skeyspace, ok := keyspace.(datastore.StatisticsKeyspace)
+	if !ok {
+		t.Fatalf("Expected keyspace to support statistics")
+	}
+
+	values := value.Values{
+		value.NewValue("fred"), value.NewValue("dave"),
+		value.NewValue("earl"), value.NewValue("dave"),
+	}
+
+	stats := datastore.NewTermStatistics("`name`", values, 4)
+	if stats.Count != 4 || stats.Distinct != 3 || stats.Min != "dave" || stats.Max != "fred" {
+		t.Errorf("Unexpected statistics %v", stats)
+	}
+
+	err := skeyspace.UpdateStatistics([]*datastore.TermStatistics{stats})
+	if err != nil {
+		t.Fatalf("failed to update statistics: %v", err)
+	}
+
+	defer skeyspace.DeleteStatistics(nil)
+
+	terms, err := skeyspace.StatisticsTerms()
+	if err != nil || fmt.Sprint(terms) != "[`name`]" {
+		t.Errorf("Expected terms [`name`], got %v %v", terms, err)
+	}
+
+	span := &datastore.Span{}
+	span.Range.Low = value.Values{value.NewValue("dave")}
+	span.Range.High = value.Values{value.NewValue("fred")}
+	span.Range.Inclusion = datastore.BOTH
+
+	count, _ := stats.Estimate(span, 8).Count()
+	if count != 8 {
+		t.Errorf("Expected estimate of 8 for whole range, got %d", count)
+	}
+
+	span.Range.High = span.Range.Low
+	count, _ = stats.Estimate(span, 4).Count()
+	if count != 1 {
+		t.Errorf("Expected estimate of 1 for equality, got %d", count)
+	}
+
+	err = skeyspace.DeleteStatistics([]string{"`name`"})
+	if err != nil {
+		t.Errorf("failed to delete statistics: %v", err)
+	}
+
+	stored, err := skeyspace.StatisticsByTerm("`name`")
+	if err != nil || stored != nil {
+		t.Errorf("Statistics should have been deleted")
+	}
+}
+
+// Return the contacts keyspace of the test store
+func testContacts(t *testing.T) datastore.Keyspace {
+	store, err := NewDatastore("../../test/json")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	namespace, err := store.NamespaceByName("default")
+	if err != nil {
+		t.Fatalf("failed to get namespace: %v", err)
+	}
+
+	keyspace, err := namespace.KeyspaceByName("contacts")
+	if err != nil {
+		t.Fatalf("failed to get keyspace by name: contacts")
+	}
+
+	return keyspace
+}
+
+// Return the contacts keyspace of the test store and its indexer
+func testContactsIndexer(t *testing.T) (datastore.Keyspace, datastore.Indexer) {
+	keyspace := testContacts(t)
+
+	indexers, err := keyspace.Indexers()
+	if err != nil {
+		t.Fatalf("failed to get indexers")
+	}
+
+	return keyspace, indexers[0]
+}
+
 type testingContext struct {
 	t *testing.T
 }
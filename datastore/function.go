@@ -0,0 +1,34 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package datastore
+
+import (
+	"github.com/couchbaselabs/query/errors"
+)
+
+// FunctionNamespace is implemented by namespaces that can persist the
+// definitions of user-defined functions.
+type FunctionNamespace interface {
+	Namespace
+
+	FunctionNames() ([]string, errors.Error)                                  // Names of the functions defined in this namespace
+	FunctionByName(name string) (*FunctionDefinition, errors.Error)           // Find a function in this namespace using the function's name
+	CreateFunction(definition *FunctionDefinition, replace bool) errors.Error // Persist a function, replacing any existing definition if replace is true
+	DropFunction(name string) errors.Error                                    // Remove a function from this namespace
+}
+
+// FunctionDefinition is the persisted definition of a user-defined
+// function. The body is the N1QL text of an expression over the
+// parameters.
+type FunctionDefinition struct {
+	Name       string   `json:"name"`
+	Parameters []string `json:"parameters"`
+	Body       string   `json:"body"`
+}
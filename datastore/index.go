@@ -78,6 +78,17 @@ type Index interface {
 		conn *IndexConnection) // Perform a scan on this index. Distinct and limit are hints.
 }
 
+/*
+ReverseIndex is implemented by indexes that can also scan in
+descending key order.
+*/
+type ReverseIndex interface {
+	Index
+
+	ReverseScan(span *Span, distinct bool, limit int64, cons ScanConsistency, vector timestamp.Vector,
+		conn *IndexConnection) // Perform a scan on this index in descending key order
+}
+
 /*
 PrimaryIndex represents primary key indexes.
 */
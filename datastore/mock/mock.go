@@ -19,6 +19,7 @@ package mock
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -384,7 +385,21 @@ func (pi *primaryIndex) State() (state datastore.IndexState, msg string, err err
 }
 
 func (pi *primaryIndex) Statistics(span *datastore.Span) (datastore.Statistics, errors.Error) {
-	return nil, nil
+	ids := make([]string, pi.keyspace.nitems)
+	for i := 0; i < pi.keyspace.nitems; i++ {
+		ids[i] = strconv.Itoa(i)
+	}
+	sort.Strings(ids)
+
+	keys := make([]value.Values, 0, len(ids))
+	for _, id := range ids {
+		key := value.Values{value.NewValue(id)}
+		if span.Contains(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	return datastore.NewStatistics(keys, datastore.STATISTICS_BINS), nil
 }
 
 func (pi *primaryIndex) Drop() errors.Error {
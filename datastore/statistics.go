@@ -0,0 +1,305 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package datastore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/value"
+)
+
+// Default number of histogram bins
+const STATISTICS_BINS = 16
+
+// Statistics computed from index keys.
+type statistics struct {
+	count    int64
+	distinct int64
+	min      value.Values
+	max      value.Values
+	bins     []Statistics
+}
+
+// NewStatistics computes the statistics of index keys, which must be
+// sorted by CompareKeys. The keys are divided into at most nbins
+// equi-depth histogram bins.
+func NewStatistics(keys []value.Values, nbins int) Statistics {
+	rv := &statistics{
+		count: int64(len(keys)),
+	}
+
+	if len(keys) == 0 {
+		return rv
+	}
+
+	rv.min = keys[0]
+	rv.max = keys[len(keys)-1]
+	rv.distinct = 1
+	for i := 1; i < len(keys); i++ {
+		if CompareKeys(keys[i-1], keys[i]) != 0 {
+			rv.distinct++
+		}
+	}
+
+	if nbins > 1 && len(keys) > nbins {
+		rv.bins = make([]Statistics, 0, nbins)
+		for i := 0; i < nbins; i++ {
+			low := i * len(keys) / nbins
+			high := (i + 1) * len(keys) / nbins
+			rv.bins = append(rv.bins, NewStatistics(keys[low:high], 0))
+		}
+	}
+
+	return rv
+}
+
+func (this *statistics) Count() (int64, errors.Error) {
+	return this.count, nil
+}
+
+func (this *statistics) Min() (value.Values, errors.Error) {
+	return this.min, nil
+}
+
+func (this *statistics) Max() (value.Values, errors.Error) {
+	return this.max, nil
+}
+
+func (this *statistics) DistinctCount() (int64, errors.Error) {
+	return this.distinct, nil
+}
+
+func (this *statistics) Bins() ([]Statistics, errors.Error) {
+	return this.bins, nil
+}
+
+// StatisticsKeyspace is implemented by keyspaces that can persist the
+// statistics collected by UPDATE STATISTICS.
+type StatisticsKeyspace interface {
+	Keyspace
+
+	StatisticsTerms() ([]string, errors.Error)                    // Terms for which statistics are stored, in sorted order
+	StatisticsByTerm(term string) (*TermStatistics, errors.Error) // Statistics stored for a term, or nil if there are none
+	UpdateStatistics(stats []*TermStatistics) errors.Error        // Persist statistics, replacing any stored for the same terms
+	DeleteStatistics(terms []string) errors.Error                 // Remove the statistics of the terms, or of all terms if terms is nil
+}
+
+// StatisticsBin summarizes a range of the values of a term.
+type StatisticsBin struct {
+	Count    int64       `json:"count"`
+	Distinct int64       `json:"distinct"`
+	Min      interface{} `json:"min"`
+	Max      interface{} `json:"max"`
+}
+
+// TermStatistics are the statistics of an expression, or term, over
+// a sample of the documents of a keyspace. Terms are identified by the
+// text of the expression, as in the keys of an index.
+type TermStatistics struct {
+	StatisticsBin
+	Term       string           `json:"term"`
+	SampleSize int64            `json:"sample_size"`
+	LastUpdate string           `json:"last_update"`
+	Bins       []*StatisticsBin `json:"bins,omitempty"`
+}
+
+// NewTermStatistics builds the statistics of a term from its values in
+// a sample of documents. The values are sorted in place.
+func NewTermStatistics(term string, values value.Values, sampleSize int64) *TermStatistics {
+	sort.Sort(valuesByCollation(values))
+
+	keys := make([]value.Values, len(values))
+	for i, v := range values {
+		keys[i] = value.Values{v}
+	}
+
+	stats := NewStatistics(keys, STATISTICS_BINS).(*statistics)
+	rv := &TermStatistics{
+		StatisticsBin: *newStatisticsBin(stats),
+		Term:          term,
+		SampleSize:    sampleSize,
+		LastUpdate:    time.Now().Format(time.RFC3339Nano),
+	}
+
+	for _, bin := range stats.bins {
+		rv.Bins = append(rv.Bins, newStatisticsBin(bin.(*statistics)))
+	}
+
+	return rv
+}
+
+func newStatisticsBin(stats *statistics) *StatisticsBin {
+	rv := &StatisticsBin{
+		Count:    stats.count,
+		Distinct: stats.distinct,
+	}
+
+	if len(stats.min) > 0 {
+		rv.Min = stats.min[0].Actual()
+		rv.Max = stats.max[0].Actual()
+	}
+
+	return rv
+}
+
+type valuesByCollation value.Values
+
+func (this valuesByCollation) Len() int           { return len(this) }
+func (this valuesByCollation) Less(i, j int) bool { return this[i].Collate(this[j]) < 0 }
+func (this valuesByCollation) Swap(i, j int)      { this[i], this[j] = this[j], this[i] }
+
+// Estimate the statistics of an index on the term for a span, given
+// the number of documents in the keyspace. Only the leading key of the
+// span is considered. Bins that fall within the span are counted in
+// full; bins that overlap the span are counted by their distinct
+// values for equality spans, and in half otherwise. The estimate is
+// scaled from the sample to the whole keyspace.
+func (this *TermStatistics) Estimate(span *Span, total int64) Statistics {
+	lead := leadingSpan(span)
+	point := len(lead.Range.Low) > 0 && len(lead.Range.High) > 0 &&
+		lead.Range.Low[0] != nil && lead.Range.High[0] != nil &&
+		lead.Range.Low[0].Equals(lead.Range.High[0])
+
+	bins := this.Bins
+	if len(bins) == 0 && this.Count > 0 {
+		bins = []*StatisticsBin{&this.StatisticsBin}
+	}
+
+	count := 0.0
+	for _, bin := range bins {
+		min := value.Values{value.NewValue(bin.Min)}
+		max := value.Values{value.NewValue(bin.Max)}
+
+		switch {
+		case lead.Contains(min) && lead.Contains(max):
+			count += float64(bin.Count)
+		case !lead.overlaps(min, max):
+		case point && bin.Distinct > 0:
+			count += float64(bin.Count) / float64(bin.Distinct)
+		default:
+			count += float64(bin.Count) / 2
+		}
+	}
+
+	if this.SampleSize > 0 && total > this.SampleSize {
+		count *= float64(total) / float64(this.SampleSize)
+	}
+
+	return &statistics{
+		count: int64(count + 0.5),
+	}
+}
+
+// Restrict a span to its leading key. Bounds on later keys are
+// dropped, which makes the leading bound inclusive.
+func leadingSpan(span *Span) *Span {
+	rv := &Span{}
+	rv.Range.Inclusion = span.Range.Inclusion
+
+	if len(span.Seek) > 0 {
+		rv.Range.Low = span.Seek[0:1]
+		rv.Range.High = span.Seek[0:1]
+		rv.Range.Inclusion = BOTH
+		return rv
+	}
+
+	if len(span.Range.Low) > 0 {
+		rv.Range.Low = span.Range.Low[0:1]
+		if len(span.Range.Low) > 1 {
+			rv.Range.Inclusion |= LOW
+		}
+	}
+
+	if len(span.Range.High) > 0 {
+		rv.Range.High = span.Range.High[0:1]
+		if len(span.Range.High) > 1 {
+			rv.Range.Inclusion |= HIGH
+		}
+	}
+
+	return rv
+}
+
+// Returns true if any key between min and max falls within the span.
+func (this *Span) overlaps(min, max value.Values) bool {
+	if len(this.Range.Low) > 0 {
+		c := compareBound(max, this.Range.Low)
+		if c < 0 || (c == 0 && this.Range.Inclusion&LOW == 0) {
+			return false
+		}
+	}
+
+	if len(this.Range.High) > 0 {
+		c := compareBound(min, this.Range.High)
+		if c > 0 || (c == 0 && this.Range.Inclusion&HIGH == 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CompareKeys compares index keys by collation, position by position.
+func CompareKeys(key1, key2 value.Values) int {
+	for i := 0; i < len(key1) && i < len(key2); i++ {
+		c := key1[i].Collate(key2[i])
+		if c != 0 {
+			return c
+		}
+	}
+
+	return len(key1) - len(key2)
+}
+
+// Contains returns true if the index key falls within the span. A
+// bound shorter than the key constrains only the leading positions
+// of the key, and a nil position in a bound is unbounded.
+func (this *Span) Contains(key value.Values) bool {
+	if len(this.Seek) > 0 && compareBound(key, this.Seek) != 0 {
+		return false
+	}
+
+	if len(this.Range.Low) > 0 {
+		c := compareBound(key, this.Range.Low)
+		if c < 0 || (c == 0 && this.Range.Inclusion&LOW == 0) {
+			return false
+		}
+	}
+
+	if len(this.Range.High) > 0 {
+		c := compareBound(key, this.Range.High)
+		if c > 0 || (c == 0 && this.Range.Inclusion&HIGH == 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func compareBound(key, bound value.Values) int {
+	for i, b := range bound {
+		if b == nil {
+			break
+		}
+
+		if i >= len(key) || key[i] == nil {
+			return -1
+		}
+
+		c := key[i].Collate(b)
+		if c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
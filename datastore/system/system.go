@@ -21,6 +21,11 @@ const KEYSPACE_NAME_NAMESPACES = "namespaces"
 const KEYSPACE_NAME_KEYSPACES = "keyspaces"
 const KEYSPACE_NAME_INDEXES = "indexes"
 const KEYSPACE_NAME_DUAL = "dual"
+const KEYSPACE_NAME_FUNCTIONS = "functions"
+const KEYSPACE_NAME_PREPAREDS = "prepareds"
+const KEYSPACE_NAME_STATISTICS = "statistics"
+const KEYSPACE_NAME_ACTIVE_REQUESTS = "active_requests"
+const KEYSPACE_NAME_COMPLETED_REQUESTS = "completed_requests"
 
 type store struct {
 	actualStore              datastore.Datastore
@@ -82,6 +87,38 @@ func NewDatastore(actualStore datastore.Datastore) (datastore.Datastore, errors.
 	return s, e
 }
 
+// The namespaces of the actual datastore that support functions
+func (s *store) functionNamespaces() ([]datastore.FunctionNamespace, errors.Error) {
+	namespaceIds, err := s.actualStore.NamespaceIds()
+	if err != nil {
+		return nil, err
+	}
+
+	rv := make([]datastore.FunctionNamespace, 0, len(namespaceIds))
+	for _, namespaceId := range namespaceIds {
+		namespace, err := s.functionNamespace(namespaceId)
+		if err != nil {
+			return nil, err
+		}
+
+		if namespace != nil {
+			rv = append(rv, namespace)
+		}
+	}
+
+	return rv, nil
+}
+
+func (s *store) functionNamespace(id string) (datastore.FunctionNamespace, errors.Error) {
+	namespace, err := s.actualStore.NamespaceById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	fnamespace, _ := namespace.(datastore.FunctionNamespace)
+	return fnamespace, nil
+}
+
 func (s *store) loadNamespace() errors.Error {
 	p, e := newNamespace(s)
 	if e != nil {
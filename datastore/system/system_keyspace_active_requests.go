@@ -0,0 +1,235 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package system
+
+import (
+	"fmt"
+
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/timestamp"
+	"github.com/couchbaselabs/query/value"
+)
+
+// The requests that are active in the query server, by request id.
+// CancelAs returns false if the request is not active, and an error
+// if the credentials do not permit cancelling it.
+type ActiveRequests interface {
+	Ids() []string
+	Entry(id string) (map[string]interface{}, bool)
+	CancelAs(id string, credentials datastore.Credentials) (bool, errors.Error)
+}
+
+var activeRequests ActiveRequests
+
+// Set the active requests listed by system:active_requests; the
+// keyspace is empty until they are set
+func SetActiveRequests(requests ActiveRequests) {
+	activeRequests = requests
+}
+
+type activeRequestsKeyspace struct {
+	namespace *namespace
+	name      string
+	indexer   datastore.Indexer
+}
+
+func (b *activeRequestsKeyspace) Release() {
+}
+
+func (b *activeRequestsKeyspace) NamespaceId() string {
+	return b.namespace.Id()
+}
+
+func (b *activeRequestsKeyspace) Id() string {
+	return b.Name()
+}
+
+func (b *activeRequestsKeyspace) Name() string {
+	return b.name
+}
+
+func (b *activeRequestsKeyspace) Count() (int64, errors.Error) {
+	if activeRequests == nil {
+		return 0, nil
+	}
+
+	return int64(len(activeRequests.Ids())), nil
+}
+
+func (b *activeRequestsKeyspace) Indexer(name datastore.IndexType) (datastore.Indexer, errors.Error) {
+	return b.indexer, nil
+}
+
+func (b *activeRequestsKeyspace) Indexers() ([]datastore.Indexer, errors.Error) {
+	return []datastore.Indexer{b.indexer}, nil
+}
+
+func (b *activeRequestsKeyspace) Fetch(keys []string) ([]datastore.AnnotatedPair, errors.Error) {
+	rv := make([]datastore.AnnotatedPair, 0, len(keys))
+	if activeRequests == nil {
+		return rv, nil
+	}
+
+	for _, k := range keys {
+		entry, ok := activeRequests.Entry(k)
+		if ok {
+			rv = append(rv, datastore.AnnotatedPair{Key: k, Value: value.NewAnnotatedValue(entry)})
+		}
+	}
+	return rv, nil
+}
+
+func (b *activeRequestsKeyspace) Insert(inserts []datastore.Pair) ([]datastore.Pair, errors.Error) {
+	// FIXME
+	return nil, errors.NewSystemNotImplementedError(nil, "")
+}
+
+func (b *activeRequestsKeyspace) Update(updates []datastore.Pair) ([]datastore.Pair, errors.Error) {
+	// FIXME
+	return nil, errors.NewSystemNotImplementedError(nil, "")
+}
+
+func (b *activeRequestsKeyspace) Upsert(upserts []datastore.Pair) ([]datastore.Pair, errors.Error) {
+	// FIXME
+	return nil, errors.NewSystemNotImplementedError(nil, "")
+}
+
+// Cancel the requests without credentials
+func (b *activeRequestsKeyspace) Delete(deletes []string) ([]string, errors.Error) {
+	return b.DeleteAs(deletes, nil)
+}
+
+// Cancel the requests that the credentials permit cancelling, and
+// return the keys of those that were active. The others are left
+// running and reported in the error.
+func (b *activeRequestsKeyspace) DeleteAs(deletes []string,
+	credentials datastore.Credentials) ([]string, errors.Error) {
+	rv := make([]string, 0, len(deletes))
+	if activeRequests == nil {
+		return rv, nil
+	}
+
+	var err errors.Error
+	for _, k := range deletes {
+		ok, e := activeRequests.CancelAs(k, credentials)
+		if e != nil {
+			err = e
+			continue
+		}
+
+		if ok {
+			rv = append(rv, k)
+		}
+	}
+	return rv, err
+}
+
+func newActiveRequestsKeyspace(p *namespace) (*activeRequestsKeyspace, errors.Error) {
+	b := new(activeRequestsKeyspace)
+	b.namespace = p
+	b.name = KEYSPACE_NAME_ACTIVE_REQUESTS
+
+	primary := &activeRequestsIndex{name: "#primary", keyspace: b}
+	b.indexer = &systemIndexer{keyspace: b, indexes: make(map[string]datastore.Index), primary: primary}
+
+	return b, nil
+}
+
+type activeRequestsIndex struct {
+	name     string
+	keyspace *activeRequestsKeyspace
+}
+
+func (pi *activeRequestsIndex) KeyspaceId() string {
+	return pi.keyspace.Id()
+}
+
+func (pi *activeRequestsIndex) Id() string {
+	return pi.Name()
+}
+
+func (pi *activeRequestsIndex) Name() string {
+	return pi.name
+}
+
+func (pi *activeRequestsIndex) Type() datastore.IndexType {
+	return datastore.DEFAULT
+}
+
+func (pi *activeRequestsIndex) SeekKey() expression.Expressions {
+	return nil
+}
+
+func (pi *activeRequestsIndex) RangeKey() expression.Expressions {
+	return nil
+}
+
+func (pi *activeRequestsIndex) Condition() expression.Expression {
+	return nil
+}
+
+func (pi *activeRequestsIndex) State() (state datastore.IndexState, msg string, err errors.Error) {
+	return datastore.ONLINE, "", nil
+}
+
+func (pi *activeRequestsIndex) Statistics(span *datastore.Span) (datastore.Statistics, errors.Error) {
+	return nil, nil
+}
+
+func (pi *activeRequestsIndex) Drop() errors.Error {
+	return errors.NewSystemIdxNoDropError(nil, "")
+}
+
+func (pi *activeRequestsIndex) Scan(span *datastore.Span, distinct bool, limit int64,
+	cons datastore.ScanConsistency, vector timestamp.Vector, conn *datastore.IndexConnection) {
+	defer close(conn.EntryChannel())
+
+	val := ""
+
+	a := span.Seek[0].Actual()
+	switch a := a.(type) {
+	case string:
+		val = a
+	default:
+		conn.Error(errors.NewSystemDatastoreError(nil, fmt.Sprintf("Invalid seek value %v of type %T.", a, a)))
+		return
+	}
+
+	if activeRequests == nil {
+		return
+	}
+
+	_, ok := activeRequests.Entry(val)
+	if ok {
+		entry := datastore.IndexEntry{PrimaryKey: val}
+		conn.EntryChannel() <- &entry
+	}
+}
+
+func (pi *activeRequestsIndex) ScanEntries(limit int64, cons datastore.ScanConsistency,
+	vector timestamp.Vector, conn *datastore.IndexConnection) {
+	defer close(conn.EntryChannel())
+
+	if activeRequests == nil {
+		return
+	}
+
+	ids := activeRequests.Ids()
+	for i, id := range ids {
+		if limit > 0 && int64(i) >= limit {
+			return
+		}
+
+		entry := datastore.IndexEntry{PrimaryKey: id}
+		conn.EntryChannel() <- &entry
+	}
+}
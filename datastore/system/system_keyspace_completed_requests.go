@@ -0,0 +1,220 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package system
+
+import (
+	"fmt"
+
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/timestamp"
+	"github.com/couchbaselabs/query/value"
+)
+
+// The log of requests that have completed in the query server, by
+// request id
+type CompletedRequests interface {
+	Ids() []string
+	Entry(id string) (map[string]interface{}, bool)
+	Delete(id string) bool
+}
+
+var completedRequests CompletedRequests
+
+// Set the completed requests listed by system:completed_requests;
+// the keyspace is empty until they are set
+func SetCompletedRequests(requests CompletedRequests) {
+	completedRequests = requests
+}
+
+type completedRequestsKeyspace struct {
+	namespace *namespace
+	name      string
+	indexer   datastore.Indexer
+}
+
+func (b *completedRequestsKeyspace) Release() {
+}
+
+func (b *completedRequestsKeyspace) NamespaceId() string {
+	return b.namespace.Id()
+}
+
+func (b *completedRequestsKeyspace) Id() string {
+	return b.Name()
+}
+
+func (b *completedRequestsKeyspace) Name() string {
+	return b.name
+}
+
+func (b *completedRequestsKeyspace) Count() (int64, errors.Error) {
+	if completedRequests == nil {
+		return 0, nil
+	}
+
+	return int64(len(completedRequests.Ids())), nil
+}
+
+func (b *completedRequestsKeyspace) Indexer(name datastore.IndexType) (datastore.Indexer, errors.Error) {
+	return b.indexer, nil
+}
+
+func (b *completedRequestsKeyspace) Indexers() ([]datastore.Indexer, errors.Error) {
+	return []datastore.Indexer{b.indexer}, nil
+}
+
+func (b *completedRequestsKeyspace) Fetch(keys []string) ([]datastore.AnnotatedPair, errors.Error) {
+	rv := make([]datastore.AnnotatedPair, 0, len(keys))
+	if completedRequests == nil {
+		return rv, nil
+	}
+
+	for _, k := range keys {
+		entry, ok := completedRequests.Entry(k)
+		if ok {
+			rv = append(rv, datastore.AnnotatedPair{Key: k, Value: value.NewAnnotatedValue(entry)})
+		}
+	}
+	return rv, nil
+}
+
+func (b *completedRequestsKeyspace) Insert(inserts []datastore.Pair) ([]datastore.Pair, errors.Error) {
+	// FIXME
+	return nil, errors.NewSystemNotImplementedError(nil, "")
+}
+
+func (b *completedRequestsKeyspace) Update(updates []datastore.Pair) ([]datastore.Pair, errors.Error) {
+	// FIXME
+	return nil, errors.NewSystemNotImplementedError(nil, "")
+}
+
+func (b *completedRequestsKeyspace) Upsert(upserts []datastore.Pair) ([]datastore.Pair, errors.Error) {
+	// FIXME
+	return nil, errors.NewSystemNotImplementedError(nil, "")
+}
+
+// Remove the requests from the log, and return the keys of those
+// that were logged.
+func (b *completedRequestsKeyspace) Delete(deletes []string) ([]string, errors.Error) {
+	rv := make([]string, 0, len(deletes))
+	if completedRequests == nil {
+		return rv, nil
+	}
+
+	for _, k := range deletes {
+		if completedRequests.Delete(k) {
+			rv = append(rv, k)
+		}
+	}
+	return rv, nil
+}
+
+func newCompletedRequestsKeyspace(p *namespace) (*completedRequestsKeyspace, errors.Error) {
+	b := new(completedRequestsKeyspace)
+	b.namespace = p
+	b.name = KEYSPACE_NAME_COMPLETED_REQUESTS
+
+	primary := &completedRequestsIndex{name: "#primary", keyspace: b}
+	b.indexer = &systemIndexer{keyspace: b, indexes: make(map[string]datastore.Index), primary: primary}
+
+	return b, nil
+}
+
+type completedRequestsIndex struct {
+	name     string
+	keyspace *completedRequestsKeyspace
+}
+
+func (pi *completedRequestsIndex) KeyspaceId() string {
+	return pi.keyspace.Id()
+}
+
+func (pi *completedRequestsIndex) Id() string {
+	return pi.Name()
+}
+
+func (pi *completedRequestsIndex) Name() string {
+	return pi.name
+}
+
+func (pi *completedRequestsIndex) Type() datastore.IndexType {
+	return datastore.DEFAULT
+}
+
+func (pi *completedRequestsIndex) SeekKey() expression.Expressions {
+	return nil
+}
+
+func (pi *completedRequestsIndex) RangeKey() expression.Expressions {
+	return nil
+}
+
+func (pi *completedRequestsIndex) Condition() expression.Expression {
+	return nil
+}
+
+func (pi *completedRequestsIndex) State() (state datastore.IndexState, msg string, err errors.Error) {
+	return datastore.ONLINE, "", nil
+}
+
+func (pi *completedRequestsIndex) Statistics(span *datastore.Span) (datastore.Statistics, errors.Error) {
+	return nil, nil
+}
+
+func (pi *completedRequestsIndex) Drop() errors.Error {
+	return errors.NewSystemIdxNoDropError(nil, "")
+}
+
+func (pi *completedRequestsIndex) Scan(span *datastore.Span, distinct bool, limit int64,
+	cons datastore.ScanConsistency, vector timestamp.Vector, conn *datastore.IndexConnection) {
+	defer close(conn.EntryChannel())
+
+	val := ""
+
+	a := span.Seek[0].Actual()
+	switch a := a.(type) {
+	case string:
+		val = a
+	default:
+		conn.Error(errors.NewSystemDatastoreError(nil, fmt.Sprintf("Invalid seek value %v of type %T.", a, a)))
+		return
+	}
+
+	if completedRequests == nil {
+		return
+	}
+
+	_, ok := completedRequests.Entry(val)
+	if ok {
+		entry := datastore.IndexEntry{PrimaryKey: val}
+		conn.EntryChannel() <- &entry
+	}
+}
+
+func (pi *completedRequestsIndex) ScanEntries(limit int64, cons datastore.ScanConsistency,
+	vector timestamp.Vector, conn *datastore.IndexConnection) {
+	defer close(conn.EntryChannel())
+
+	if completedRequests == nil {
+		return
+	}
+
+	ids := completedRequests.Ids()
+	for i, id := range ids {
+		if limit > 0 && int64(i) >= limit {
+			return
+		}
+
+		entry := datastore.IndexEntry{PrimaryKey: id}
+		conn.EntryChannel() <- &entry
+	}
+}
@@ -0,0 +1,246 @@
+//  Copyright (c) 2013 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package system
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/timestamp"
+	"github.com/couchbaselabs/query/value"
+)
+
+type functionKeyspace struct {
+	namespace *namespace
+	name      string
+	indexer   datastore.Indexer
+}
+
+func (b *functionKeyspace) Release() {
+}
+
+func (b *functionKeyspace) NamespaceId() string {
+	return b.namespace.Id()
+}
+
+func (b *functionKeyspace) Id() string {
+	return b.Name()
+}
+
+func (b *functionKeyspace) Name() string {
+	return b.name
+}
+
+func (b *functionKeyspace) Count() (int64, errors.Error) {
+	count := int64(0)
+	namespaces, excp := b.namespace.store.functionNamespaces()
+	if excp != nil {
+		return 0, errors.NewSystemDatastoreError(excp, "")
+	}
+
+	for _, namespace := range namespaces {
+		names, excp := namespace.FunctionNames()
+		if excp != nil {
+			return 0, errors.NewSystemDatastoreError(excp, "")
+		}
+
+		count += int64(len(names))
+	}
+
+	return count, nil
+}
+
+func (b *functionKeyspace) Indexer(name datastore.IndexType) (datastore.Indexer, errors.Error) {
+	return b.indexer, nil
+}
+
+func (b *functionKeyspace) Indexers() ([]datastore.Indexer, errors.Error) {
+	return []datastore.Indexer{b.indexer}, nil
+}
+
+func (b *functionKeyspace) Fetch(keys []string) ([]datastore.AnnotatedPair, errors.Error) {
+	rv := make([]datastore.AnnotatedPair, 0, len(keys))
+	for _, k := range keys {
+		item, e := b.fetchOne(k)
+		if e != nil {
+			return nil, e
+		}
+
+		if item != nil {
+			rv = append(rv, datastore.AnnotatedPair{Key: k, Value: item})
+		}
+	}
+	return rv, nil
+}
+
+func (b *functionKeyspace) fetchOne(key string) (value.AnnotatedValue, errors.Error) {
+	ids := strings.SplitN(key, "/", 2)
+	if len(ids) != 2 {
+		return nil, nil
+	}
+
+	namespace, err := b.namespace.store.functionNamespace(ids[0])
+	if namespace == nil {
+		return nil, err
+	}
+
+	def, _ := namespace.FunctionByName(ids[1])
+	if def == nil {
+		return nil, nil
+	}
+
+	doc := value.NewAnnotatedValue(map[string]interface{}{
+		"name":         def.Name,
+		"parameters":   datastoreObjectToJSONSafe(def.Parameters),
+		"body":         def.Body,
+		"namespace_id": namespace.Id(),
+		"datastore_id": b.namespace.store.actualStore.Id(),
+	})
+	return doc, nil
+}
+
+func (b *functionKeyspace) Insert(inserts []datastore.Pair) ([]datastore.Pair, errors.Error) {
+	// FIXME
+	return nil, errors.NewSystemNotImplementedError(nil, "")
+}
+
+func (b *functionKeyspace) Update(updates []datastore.Pair) ([]datastore.Pair, errors.Error) {
+	// FIXME
+	return nil, errors.NewSystemNotImplementedError(nil, "")
+}
+
+func (b *functionKeyspace) Upsert(upserts []datastore.Pair) ([]datastore.Pair, errors.Error) {
+	// FIXME
+	return nil, errors.NewSystemNotImplementedError(nil, "")
+}
+
+func (b *functionKeyspace) Delete(deletes []string) ([]string, errors.Error) {
+	// FIXME
+	return nil, errors.NewSystemNotImplementedError(nil, "")
+}
+
+func newFunctionsKeyspace(p *namespace) (*functionKeyspace, errors.Error) {
+	b := new(functionKeyspace)
+	b.namespace = p
+	b.name = KEYSPACE_NAME_FUNCTIONS
+
+	primary := &functionIndex{name: "#primary", keyspace: b}
+	b.indexer = &systemIndexer{keyspace: b, indexes: make(map[string]datastore.Index), primary: primary}
+
+	return b, nil
+}
+
+type functionIndex struct {
+	name     string
+	keyspace *functionKeyspace
+}
+
+func (pi *functionIndex) KeyspaceId() string {
+	return pi.keyspace.Id()
+}
+
+func (pi *functionIndex) Id() string {
+	return pi.Name()
+}
+
+func (pi *functionIndex) Name() string {
+	return pi.name
+}
+
+func (pi *functionIndex) Type() datastore.IndexType {
+	return datastore.DEFAULT
+}
+
+func (pi *functionIndex) SeekKey() expression.Expressions {
+	return nil
+}
+
+func (pi *functionIndex) RangeKey() expression.Expressions {
+	return nil
+}
+
+func (pi *functionIndex) Condition() expression.Expression {
+	return nil
+}
+
+func (pi *functionIndex) State() (state datastore.IndexState, msg string, err errors.Error) {
+	return datastore.ONLINE, "", nil
+}
+
+func (pi *functionIndex) Statistics(span *datastore.Span) (datastore.Statistics, errors.Error) {
+	return nil, nil
+}
+
+func (pi *functionIndex) Drop() errors.Error {
+	return errors.NewSystemIdxNoDropError(nil, "")
+}
+
+func (pi *functionIndex) Scan(span *datastore.Span, distinct bool, limit int64,
+	cons datastore.ScanConsistency, vector timestamp.Vector, conn *datastore.IndexConnection) {
+	defer close(conn.EntryChannel())
+
+	val := ""
+
+	a := span.Seek[0].Actual()
+	switch a := a.(type) {
+	case string:
+		val = a
+	default:
+		conn.Error(errors.NewSystemDatastoreError(nil, fmt.Sprintf("Invalid seek value %v of type %T.", a, a)))
+		return
+	}
+
+	ids := strings.SplitN(val, "/", 2)
+	if len(ids) != 2 {
+		return
+	}
+
+	namespace, _ := pi.keyspace.namespace.store.functionNamespace(ids[0])
+	if namespace == nil {
+		return
+	}
+
+	def, _ := namespace.FunctionByName(ids[1])
+	if def != nil {
+		entry := datastore.IndexEntry{PrimaryKey: fmt.Sprintf("%s/%s", namespace.Id(), def.Name)}
+		conn.EntryChannel() <- &entry
+	}
+}
+
+func (pi *functionIndex) ScanEntries(limit int64, cons datastore.ScanConsistency,
+	vector timestamp.Vector, conn *datastore.IndexConnection) {
+	defer close(conn.EntryChannel())
+
+	namespaces, err := pi.keyspace.namespace.store.functionNamespaces()
+	if err != nil {
+		return
+	}
+
+	i := int64(0)
+	for _, namespace := range namespaces {
+		names, err := namespace.FunctionNames()
+		if err != nil {
+			continue
+		}
+
+		for _, name := range names {
+			if limit > 0 && i >= limit {
+				return
+			}
+
+			entry := datastore.IndexEntry{PrimaryKey: fmt.Sprintf("%s/%s", namespace.Id(), name)}
+			conn.EntryChannel() <- &entry
+			i++
+		}
+	}
+}
@@ -0,0 +1,204 @@
+//  Copyright (c) 2013 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package system
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/plan"
+	"github.com/couchbaselabs/query/timestamp"
+	"github.com/couchbaselabs/query/value"
+)
+
+type preparedKeyspace struct {
+	namespace *namespace
+	name      string
+	indexer   datastore.Indexer
+}
+
+func (b *preparedKeyspace) Release() {
+}
+
+func (b *preparedKeyspace) NamespaceId() string {
+	return b.namespace.Id()
+}
+
+func (b *preparedKeyspace) Id() string {
+	return b.Name()
+}
+
+func (b *preparedKeyspace) Name() string {
+	return b.name
+}
+
+func (b *preparedKeyspace) Count() (int64, errors.Error) {
+	return int64(len(plan.PreparedCache().Names())), nil
+}
+
+func (b *preparedKeyspace) Indexer(name datastore.IndexType) (datastore.Indexer, errors.Error) {
+	return b.indexer, nil
+}
+
+func (b *preparedKeyspace) Indexers() ([]datastore.Indexer, errors.Error) {
+	return []datastore.Indexer{b.indexer}, nil
+}
+
+func (b *preparedKeyspace) Fetch(keys []string) ([]datastore.AnnotatedPair, errors.Error) {
+	rv := make([]datastore.AnnotatedPair, 0, len(keys))
+	for _, k := range keys {
+		item := b.fetchOne(k)
+		if item != nil {
+			rv = append(rv, datastore.AnnotatedPair{Key: k, Value: item})
+		}
+	}
+	return rv, nil
+}
+
+func (b *preparedKeyspace) fetchOne(key string) value.AnnotatedValue {
+	entry, ok := plan.PreparedCache().Named(key)
+	if !ok {
+		return nil
+	}
+
+	doc := map[string]interface{}{
+		"name":      entry.Prepared.Name(),
+		"statement": entry.Prepared.Text(),
+		"uses":      entry.Uses,
+	}
+
+	if entry.Uses > 0 {
+		doc["last_use"] = entry.LastUse.Format(time.RFC3339Nano)
+	}
+
+	return value.NewAnnotatedValue(doc)
+}
+
+// Prepared statements are added with PREPARE, not mutated directly
+
+func (b *preparedKeyspace) Insert(inserts []datastore.Pair) ([]datastore.Pair, errors.Error) {
+	return nil, errors.NewSystemNotSupportedError(nil, "INSERT into "+KEYSPACE_NAME_PREPAREDS+"; use PREPARE")
+}
+
+func (b *preparedKeyspace) Update(updates []datastore.Pair) ([]datastore.Pair, errors.Error) {
+	return nil, errors.NewSystemNotSupportedError(nil, "UPDATE of "+KEYSPACE_NAME_PREPAREDS+"; use PREPARE")
+}
+
+func (b *preparedKeyspace) Upsert(upserts []datastore.Pair) ([]datastore.Pair, errors.Error) {
+	return nil, errors.NewSystemNotSupportedError(nil, "UPSERT into "+KEYSPACE_NAME_PREPAREDS+"; use PREPARE")
+}
+
+// Remove the named prepared statements from the cache, and return the
+// names of those that were cached.
+func (b *preparedKeyspace) Delete(deletes []string) ([]string, errors.Error) {
+	rv := make([]string, 0, len(deletes))
+	for _, name := range deletes {
+		if _, ok := plan.PreparedCache().Named(name); ok && plan.PreparedCache().Delete(name) {
+			rv = append(rv, name)
+		}
+	}
+	return rv, nil
+}
+
+func newPreparedsKeyspace(p *namespace) (*preparedKeyspace, errors.Error) {
+	b := new(preparedKeyspace)
+	b.namespace = p
+	b.name = KEYSPACE_NAME_PREPAREDS
+
+	primary := &preparedIndex{name: "#primary", keyspace: b}
+	b.indexer = &systemIndexer{keyspace: b, indexes: make(map[string]datastore.Index), primary: primary}
+
+	return b, nil
+}
+
+type preparedIndex struct {
+	name     string
+	keyspace *preparedKeyspace
+}
+
+func (pi *preparedIndex) KeyspaceId() string {
+	return pi.keyspace.Id()
+}
+
+func (pi *preparedIndex) Id() string {
+	return pi.Name()
+}
+
+func (pi *preparedIndex) Name() string {
+	return pi.name
+}
+
+func (pi *preparedIndex) Type() datastore.IndexType {
+	return datastore.DEFAULT
+}
+
+func (pi *preparedIndex) SeekKey() expression.Expressions {
+	return nil
+}
+
+func (pi *preparedIndex) RangeKey() expression.Expressions {
+	return nil
+}
+
+func (pi *preparedIndex) Condition() expression.Expression {
+	return nil
+}
+
+func (pi *preparedIndex) State() (state datastore.IndexState, msg string, err errors.Error) {
+	return datastore.ONLINE, "", nil
+}
+
+func (pi *preparedIndex) Statistics(span *datastore.Span) (datastore.Statistics, errors.Error) {
+	return nil, nil
+}
+
+func (pi *preparedIndex) Drop() errors.Error {
+	return errors.NewSystemIdxNoDropError(nil, "")
+}
+
+func (pi *preparedIndex) Scan(span *datastore.Span, distinct bool, limit int64,
+	cons datastore.ScanConsistency, vector timestamp.Vector, conn *datastore.IndexConnection) {
+	defer close(conn.EntryChannel())
+
+	val := ""
+
+	a := span.Seek[0].Actual()
+	switch a := a.(type) {
+	case string:
+		val = a
+	default:
+		conn.Error(errors.NewSystemDatastoreError(nil, fmt.Sprintf("Invalid seek value %v of type %T.", a, a)))
+		return
+	}
+
+	_, ok := plan.PreparedCache().Named(val)
+	if ok {
+		entry := datastore.IndexEntry{PrimaryKey: val}
+		conn.EntryChannel() <- &entry
+	}
+}
+
+func (pi *preparedIndex) ScanEntries(limit int64, cons datastore.ScanConsistency,
+	vector timestamp.Vector, conn *datastore.IndexConnection) {
+	defer close(conn.EntryChannel())
+
+	names := plan.PreparedCache().Names()
+	for i, name := range names {
+		if limit > 0 && int64(i) >= limit {
+			return
+		}
+
+		entry := datastore.IndexEntry{PrimaryKey: name}
+		conn.EntryChannel() <- &entry
+	}
+}
@@ -0,0 +1,256 @@
+//  Copyright (c) 2013 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package system
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/timestamp"
+	"github.com/couchbaselabs/query/value"
+)
+
+type statisticsKeyspace struct {
+	namespace *namespace
+	name      string
+	indexer   datastore.Indexer
+}
+
+func (b *statisticsKeyspace) Release() {
+}
+
+func (b *statisticsKeyspace) NamespaceId() string {
+	return b.namespace.Id()
+}
+
+func (b *statisticsKeyspace) Id() string {
+	return b.Name()
+}
+
+func (b *statisticsKeyspace) Name() string {
+	return b.name
+}
+
+func (b *statisticsKeyspace) Count() (int64, errors.Error) {
+	keys, err := b.keys()
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(keys)), nil
+}
+
+func (b *statisticsKeyspace) Indexer(name datastore.IndexType) (datastore.Indexer, errors.Error) {
+	return b.indexer, nil
+}
+
+func (b *statisticsKeyspace) Indexers() ([]datastore.Indexer, errors.Error) {
+	return []datastore.Indexer{b.indexer}, nil
+}
+
+func (b *statisticsKeyspace) Fetch(keys []string) ([]datastore.AnnotatedPair, errors.Error) {
+	rv := make([]datastore.AnnotatedPair, 0, len(keys))
+	for _, k := range keys {
+		item, err := b.fetchOne(k)
+		if err != nil {
+			return nil, err
+		}
+
+		if item != nil {
+			rv = append(rv, datastore.AnnotatedPair{Key: k, Value: item})
+		}
+	}
+	return rv, nil
+}
+
+// Keys are of the form namespace/keyspace/term.
+func (b *statisticsKeyspace) fetchOne(key string) (value.AnnotatedValue, errors.Error) {
+	ids := strings.SplitN(key, "/", 3)
+	if len(ids) < 3 {
+		return nil, nil
+	}
+
+	actualStore := b.namespace.store.actualStore
+	namespace, err := actualStore.NamespaceById(ids[0])
+	if err != nil {
+		return nil, err
+	}
+
+	keyspace, err := namespace.KeyspaceById(ids[1])
+	if err != nil {
+		return nil, err
+	}
+
+	skeyspace, ok := keyspace.(datastore.StatisticsKeyspace)
+	if !ok {
+		return nil, nil
+	}
+
+	stats, err := skeyspace.StatisticsByTerm(ids[2])
+	if err != nil || stats == nil {
+		return nil, err
+	}
+
+	doc := value.NewAnnotatedValue(datastoreObjectToJSONSafe(stats))
+	doc.SetField("keyspace_id", keyspace.Id())
+	doc.SetField("namespace_id", namespace.Id())
+	doc.SetField("datastore_id", actualStore.Id())
+	return doc, nil
+}
+
+// Enumerate the statistics of all the keyspaces that store them.
+func (b *statisticsKeyspace) keys() ([]string, errors.Error) {
+	rv := make([]string, 0, 64)
+
+	actualStore := b.namespace.store.actualStore
+	namespaceIds, err := actualStore.NamespaceIds()
+	if err != nil {
+		return nil, errors.NewSystemDatastoreError(err, "")
+	}
+
+	for _, namespaceId := range namespaceIds {
+		namespace, err := actualStore.NamespaceById(namespaceId)
+		if err != nil {
+			return nil, errors.NewSystemDatastoreError(err, "")
+		}
+
+		keyspaceIds, err := namespace.KeyspaceIds()
+		if err != nil {
+			return nil, errors.NewSystemDatastoreError(err, "")
+		}
+
+		for _, keyspaceId := range keyspaceIds {
+			keyspace, err := namespace.KeyspaceById(keyspaceId)
+			if err != nil {
+				return nil, errors.NewSystemDatastoreError(err, "")
+			}
+
+			skeyspace, ok := keyspace.(datastore.StatisticsKeyspace)
+			if !ok {
+				continue
+			}
+
+			terms, err := skeyspace.StatisticsTerms()
+			if err != nil {
+				return nil, errors.NewSystemDatastoreError(err, "")
+			}
+
+			for _, term := range terms {
+				rv = append(rv, fmt.Sprintf("%s/%s/%s", namespaceId, keyspaceId, term))
+			}
+		}
+	}
+
+	return rv, nil
+}
+
+func (b *statisticsKeyspace) Insert(inserts []datastore.Pair) ([]datastore.Pair, errors.Error) {
+	// FIXME
+	return nil, errors.NewSystemNotImplementedError(nil, "")
+}
+
+func (b *statisticsKeyspace) Update(updates []datastore.Pair) ([]datastore.Pair, errors.Error) {
+	// FIXME
+	return nil, errors.NewSystemNotImplementedError(nil, "")
+}
+
+func (b *statisticsKeyspace) Upsert(upserts []datastore.Pair) ([]datastore.Pair, errors.Error) {
+	// FIXME
+	return nil, errors.NewSystemNotImplementedError(nil, "")
+}
+
+func (b *statisticsKeyspace) Delete(deletes []string) ([]string, errors.Error) {
+	// FIXME
+	return nil, errors.NewSystemNotImplementedError(nil, "")
+}
+
+func newStatisticsKeyspace(p *namespace) (*statisticsKeyspace, errors.Error) {
+	b := new(statisticsKeyspace)
+	b.namespace = p
+	b.name = KEYSPACE_NAME_STATISTICS
+
+	primary := &statisticsIndex{name: "#primary", keyspace: b}
+	b.indexer = &systemIndexer{keyspace: b, indexes: make(map[string]datastore.Index), primary: primary}
+
+	return b, nil
+}
+
+type statisticsIndex struct {
+	name     string
+	keyspace *statisticsKeyspace
+}
+
+func (pi *statisticsIndex) KeyspaceId() string {
+	return pi.keyspace.Id()
+}
+
+func (pi *statisticsIndex) Id() string {
+	return pi.Name()
+}
+
+func (pi *statisticsIndex) Name() string {
+	return pi.name
+}
+
+func (pi *statisticsIndex) Type() datastore.IndexType {
+	return datastore.DEFAULT
+}
+
+func (pi *statisticsIndex) SeekKey() expression.Expressions {
+	return nil
+}
+
+func (pi *statisticsIndex) RangeKey() expression.Expressions {
+	return nil
+}
+
+func (pi *statisticsIndex) Condition() expression.Expression {
+	return nil
+}
+
+func (pi *statisticsIndex) State() (state datastore.IndexState, msg string, err errors.Error) {
+	return datastore.ONLINE, "", nil
+}
+
+func (pi *statisticsIndex) Statistics(span *datastore.Span) (datastore.Statistics, errors.Error) {
+	return nil, nil
+}
+
+func (pi *statisticsIndex) Drop() errors.Error {
+	return errors.NewSystemIdxNoDropError(nil, "")
+}
+
+func (pi *statisticsIndex) Scan(span *datastore.Span, distinct bool, limit int64,
+	cons datastore.ScanConsistency, vector timestamp.Vector, conn *datastore.IndexConnection) {
+	pi.ScanEntries(limit, cons, vector, conn)
+}
+
+func (pi *statisticsIndex) ScanEntries(limit int64, cons datastore.ScanConsistency,
+	vector timestamp.Vector, conn *datastore.IndexConnection) {
+	defer close(conn.EntryChannel())
+
+	keys, err := pi.keyspace.keys()
+	if err != nil {
+		conn.Error(err)
+		return
+	}
+
+	for i, key := range keys {
+		if limit > 0 && int64(i) >= limit {
+			return
+		}
+
+		entry := datastore.IndexEntry{PrimaryKey: key}
+		conn.EntryChannel() <- &entry
+	}
+}
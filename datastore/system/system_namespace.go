@@ -107,5 +107,35 @@ func (p *namespace) loadKeyspaces() (e errors.Error) {
 	}
 	p.keyspaces[ib.Name()] = ib
 
+	fb, e := newFunctionsKeyspace(p)
+	if e != nil {
+		return e
+	}
+	p.keyspaces[fb.Name()] = fb
+
+	rb, e := newPreparedsKeyspace(p)
+	if e != nil {
+		return e
+	}
+	p.keyspaces[rb.Name()] = rb
+
+	tb, e := newStatisticsKeyspace(p)
+	if e != nil {
+		return e
+	}
+	p.keyspaces[tb.Name()] = tb
+
+	ab, e := newActiveRequestsKeyspace(p)
+	if e != nil {
+		return e
+	}
+	p.keyspaces[ab.Name()] = ab
+
+	cb, e := newCompletedRequestsKeyspace(p)
+	if e != nil {
+		return e
+	}
+	p.keyspaces[cb.Name()] = cb
+
 	return nil
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/couchbaselabs/query/datastore"
 	"github.com/couchbaselabs/query/datastore/mock"
 	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/plan"
 )
 
 func TestSystem(t *testing.T) {
@@ -125,6 +126,56 @@ func TestSystem(t *testing.T) {
 
 }
 
+func TestSystemPrepareds(t *testing.T) {
+	m, err := mock.NewDatastore("mock:")
+	if err != nil {
+		t.Fatalf("failed to create mock store: %v", err)
+	}
+
+	s, err := NewDatastore(m)
+	if err != nil {
+		t.Fatalf("failed to create system store: %v", err)
+	}
+
+	p, err := s.NamespaceByName("#system")
+	if err != nil {
+		t.Fatalf("failed to get system namespace: %v", err)
+	}
+
+	pb, err := p.KeyspaceByName("prepareds")
+	if err != nil {
+		t.Fatalf("failed to get keyspace by name %v", err)
+	}
+
+	prepared := &plan.Prepared{}
+	e := prepared.UnmarshalJSON([]byte(`{"operator": {"#operator": "DummyScan"}, "name": "sp_test",
+		"text": "PREPARE sp_test FROM SELECT 1"}`))
+	if e != nil {
+		t.Fatalf("failed to unmarshal prepared: %v", e)
+	}
+
+	e = plan.PreparedCache().AddNamed(prepared)
+	if e != nil {
+		t.Fatalf("failed to add named prepared: %v", e)
+	}
+	defer plan.PreparedCache().Delete("sp_test")
+
+	// Prepared statements are only added with PREPARE
+	_, err = pb.Insert([]datastore.Pair{datastore.Pair{Key: "sp_insert"}})
+	if err == nil || err.Code() != 11004 {
+		t.Errorf("expected insert to be not supported, got %v", err)
+	}
+
+	deleted, err := pb.Delete([]string{"sp_test", "sp_missing"})
+	if err != nil || len(deleted) != 1 || deleted[0] != "sp_test" {
+		t.Fatalf("expected to delete sp_test, got %v %v", deleted, err)
+	}
+
+	if _, ok := plan.PreparedCache().Named("sp_test"); ok {
+		t.Errorf("expected sp_test to be removed from the cache")
+	}
+}
+
 type testingContext struct {
 	t *testing.T
 }
@@ -207,6 +207,16 @@ func NewServiceErrorTypeMismatch(feature string, expected string) Error {
 		InternalMsg: fmt.Sprintf("%s has to be of type %s", feature, expected), InternalCaller: CallerN(1)}
 }
 
+func NewServiceErrorQueueFull(class string) Error {
+	return &err{level: EXCEPTION, ICode: 1080, IKey: "service.io.request.queue_full",
+		InternalMsg: fmt.Sprintf("Request queue of workload class %s is full; retry later", class), InternalCaller: CallerN(1)}
+}
+
+func NewServiceErrorCancelled() Error {
+	return &err{level: EXCEPTION, ICode: 1090, IKey: "service.io.request.cancelled",
+		InternalMsg: "Request cancelled while queued", InternalCaller: CallerN(1)}
+}
+
 func NewServiceErrorInvalidJSON(e error) Error {
 	return &err{level: EXCEPTION, ICode: 1100, IKey: "service.io.response.invalid_json", ICause: e,
 		InternalMsg: "Invalid JSON in results", InternalCaller: CallerN(1)}
@@ -223,6 +233,52 @@ func NewPlanError(e error, msg string) Error {
 	return &err{level: EXCEPTION, ICode: 4000, IKey: "plan_error", ICause: e, InternalMsg: msg, InternalCaller: CallerN(1)}
 }
 
+// Execution errors - errors that are created in the execution package when a
+// WITH term cannot be evaluated
+
+func NewWithEvaluationError(e error, alias string) Error {
+	return &err{level: EXCEPTION, ICode: 5100, IKey: "execution.with.evaluation", ICause: e,
+		InternalMsg: fmt.Sprintf("Error evaluating WITH term %s", alias), InternalCaller: CallerN(1)}
+}
+
+func NewWithDepthExceededError(alias string, depth int) Error {
+	return &err{level: EXCEPTION, ICode: 5110, IKey: "execution.with.max_depth",
+		InternalMsg: fmt.Sprintf("Recursive WITH term %s exceeded maximum depth of %d", alias, depth), InternalCaller: CallerN(1)}
+}
+
+// Execution errors - errors that are created in the execution package when a
+// request exceeds its resource limits
+
+func NewMemoryQuotaExceededError(quota int64) Error {
+	return &err{level: EXCEPTION, ICode: 5500, IKey: "execution.limit.memory_quota",
+		InternalMsg: fmt.Sprintf("Request memory quota of %d bytes exceeded", quota), InternalCaller: CallerN(1)}
+}
+
+func NewFetchLimitExceededError(limit int64) Error {
+	return &err{level: EXCEPTION, ICode: 5510, IKey: "execution.limit.max_fetch",
+		InternalMsg: fmt.Sprintf("Request maximum of %d fetched documents exceeded", limit), InternalCaller: CallerN(1)}
+}
+
+func NewResultSizeExceededError(limit int64) Error {
+	return &err{level: EXCEPTION, ICode: 5520, IKey: "execution.limit.max_result_size",
+		InternalMsg: fmt.Sprintf("Request maximum result size of %d bytes exceeded", limit), InternalCaller: CallerN(1)}
+}
+
+func NewMutationLimitExceededError(limit int64) Error {
+	return &err{level: EXCEPTION, ICode: 5530, IKey: "execution.limit.max_mutations",
+		InternalMsg: fmt.Sprintf("Request maximum of %d mutations exceeded", limit), InternalCaller: CallerN(1)}
+}
+
+func NewOrderLimitExceededError(limit, orderLimit int64) Error {
+	return &err{level: EXCEPTION, ICode: 5540, IKey: "execution.limit.order_limit", InternalMsg: fmt.Sprintf(
+		"ORDER BY LIMIT plus OFFSET %d exceeds the maximum of %d", limit, orderLimit), InternalCaller: CallerN(1)}
+}
+
+func NewUpdateLimitExceededError(limit, updateLimit int64) Error {
+	return &err{level: EXCEPTION, ICode: 5550, IKey: "execution.limit.update_limit", InternalMsg: fmt.Sprintf(
+		"DELETE or UPDATE of %d documents exceeds the maximum of %d", limit, updateLimit), InternalCaller: CallerN(1)}
+}
+
 // admin level errors - errors that are created in the clustering and accounting packages
 
 func NewAdminConnectionError(e error, msg string) Error {
@@ -285,6 +341,16 @@ func NewAdminMakeMetricError(e error, msg string) Error {
 		InternalMsg: "Error creating metric " + msg, InternalCaller: CallerN(1)}
 }
 
+func NewAdminNoPreparedError(id string) Error {
+	return &err{level: EXCEPTION, ICode: 2120, IKey: "admin.prepareds.no_such_prepared",
+		InternalMsg: "No such prepared statement " + id, InternalCaller: CallerN(1)}
+}
+
+func NewAdminAuthError(msg string) Error {
+	return &err{level: EXCEPTION, ICode: 2130, IKey: "admin.authorization_error",
+		InternalMsg: "Administrator credentials are required to " + msg, InternalCaller: CallerN(1)}
+}
+
 // Authorization Errors
 func NewDatastoreAuthorizationError(e error, msg string) Error {
 	return &err{level: EXCEPTION, ICode: 10000, IKey: "datastore.couchbase.authorization_error", ICause: e,
@@ -334,6 +400,12 @@ func NewSystemIdxNoDropError(e error, msg string) Error {
 
 }
 
+func NewSystemCancelNotPermittedError(id string) Error {
+	return &err{level: EXCEPTION, ICode: 11007, IKey: "datastore.system.cancel_not_permitted",
+		InternalMsg: "System datastore : Not permitted to cancel request " + id, InternalCaller: CallerN(1)}
+
+}
+
 // Datastore/couchbase error codes
 func NewCbConnectionError(e error, msg string) Error {
 	return &err{level: EXCEPTION, ICode: 12000, IKey: "datastore.couchbase.connection_error", ICause: e,
@@ -509,6 +581,21 @@ func NewFilePrimaryIdxNoDropError(e error, msg string) Error {
 		InternalMsg: "Primary Index cannot be dropped " + msg, InternalCaller: CallerN(1)}
 }
 
+func NewFileFunctionNotFoundError(e error, msg string) Error {
+	return &err{level: EXCEPTION, ICode: 15012, IKey: "datastore.file.function_not_found", ICause: e,
+		InternalMsg: "Function not found " + msg, InternalCaller: CallerN(1)}
+}
+
+func NewFileDuplicateFunctionError(e error, msg string) Error {
+	return &err{level: EXCEPTION, ICode: 15013, IKey: "datastore.file.duplicate_function", ICause: e,
+		InternalMsg: "Function already exists " + msg, InternalCaller: CallerN(1)}
+}
+
+func NewFileDuplicateIndexError(e error, msg string) Error {
+	return &err{level: EXCEPTION, ICode: 15014, IKey: "datastore.file.duplicate_index", ICause: e,
+		InternalMsg: "Index already exists " + msg, InternalCaller: CallerN(1)}
+}
+
 // Error codes for all other datastores, e.g Mock
 func NewOtherDatastoreError(e error, msg string) Error {
 	return &err{level: EXCEPTION, ICode: 16000, IKey: "datastore.other.datastore_generic_error", ICause: e,
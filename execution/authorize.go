@@ -52,6 +52,7 @@ func (this *Authorize) RunOnce(context *Context, parent value.Value) {
 		defer context.Recover()       // Recover from any panic
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
 
 		ds := datastore.GetDatastore()
 		if ds != nil {
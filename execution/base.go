@@ -12,6 +12,8 @@ package execution
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/couchbaselabs/query/errors"
 	"github.com/couchbaselabs/query/value"
@@ -26,6 +28,16 @@ type base struct {
 	parent      Parent
 	once        sync.Once
 	batch       []value.AnnotatedValue
+	stats       *operatorStats // Nil unless profiled; shared by copies
+}
+
+// The statistics of a profiled operator, summed over its copies
+type operatorStats struct {
+	itemsIn    int64
+	itemsOut   int64
+	runTime    int64 // Nanoseconds from start to stop
+	inputTime  int64 // Nanoseconds waiting on input
+	outputTime int64 // Nanoseconds blocked on output
 }
 
 const _ITEM_CAP = 1024
@@ -93,6 +105,7 @@ func (this *base) copy() base {
 		input:       this.input,
 		output:      this.output,
 		parent:      this.parent,
+		stats:       this.stats,
 	}
 }
 
@@ -103,10 +116,14 @@ func (this *base) sendItem(item value.AnnotatedValue) bool {
 	default:
 	}
 
+	start := this.profileTime()
+
 	select {
 	case this.output.ItemChannel() <- item:
+		this.addOutput(start, true)
 		return true
 	case <-this.stopChannel: // Never closed
+		this.addOutput(start, false)
 		return false
 	}
 }
@@ -124,6 +141,7 @@ func (this *base) runConsumer(cons consumer, context *Context, parent value.Valu
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
 		defer func() { this.batch = nil }()
+		defer this.addRunTime(this.profileTime())
 
 		if context.Readonly() && !cons.readonly() {
 			return
@@ -144,8 +162,11 @@ func (this *base) runConsumer(cons consumer, context *Context, parent value.Valu
 			default:
 			}
 
+			start := this.profileTime()
+
 			select {
 			case item, ok = <-this.input.ItemChannel():
+				this.addInput(start, ok)
 				if ok {
 					ok = cons.processItem(item, context)
 				}
@@ -203,6 +224,43 @@ func (this *base) notifyStop() {
 	}
 }
 
+// The current time if the operator is profiled, to avoid the cost of
+// timing otherwise
+func (this *base) profileTime() time.Time {
+	if this.stats == nil {
+		return time.Time{}
+	}
+
+	return time.Now()
+}
+
+// Record the running time of the operator, from the given start
+func (this *base) addRunTime(start time.Time) {
+	if this.stats != nil {
+		atomic.AddInt64(&this.stats.runTime, int64(time.Since(start)))
+	}
+}
+
+// Record the time waiting on input, and the item if one was received
+func (this *base) addInput(start time.Time, received bool) {
+	if this.stats != nil {
+		atomic.AddInt64(&this.stats.inputTime, int64(time.Since(start)))
+		if received {
+			atomic.AddInt64(&this.stats.itemsIn, 1)
+		}
+	}
+}
+
+// Record the time blocked on output, and the item if it was sent
+func (this *base) addOutput(start time.Time, sent bool) {
+	if this.stats != nil {
+		atomic.AddInt64(&this.stats.outputTime, int64(time.Since(start)))
+		if sent {
+			atomic.AddInt64(&this.stats.itemsOut, 1)
+		}
+	}
+}
+
 type batcher interface {
 	allocateBatch(n int)
 	enbatch(item value.AnnotatedValue, b batcher, context *Context) bool
@@ -54,6 +54,10 @@ func (this *builder) VisitDummyScan(plan *plan.DummyScan) (interface{}, error) {
 	return NewDummyScan(), nil
 }
 
+func (this *builder) VisitExpressionScan(plan *plan.ExpressionScan) (interface{}, error) {
+	return NewExpressionScan(plan), nil
+}
+
 func (this *builder) VisitCountScan(plan *plan.CountScan) (interface{}, error) {
 	return NewCountScan(plan), nil
 }
@@ -102,6 +106,15 @@ func (this *builder) VisitNest(plan *plan.Nest) (interface{}, error) {
 	return NewNest(plan), nil
 }
 
+func (this *builder) VisitHashJoin(plan *plan.HashJoin) (interface{}, error) {
+	child, e := plan.Child().Accept(this)
+	if e != nil {
+		return nil, e
+	}
+
+	return NewHashJoin(plan, child.(Operator)), nil
+}
+
 func (this *builder) VisitUnnest(plan *plan.Unnest) (interface{}, error) {
 	return NewUnnest(plan), nil
 }
@@ -129,6 +142,15 @@ func (this *builder) VisitFinalGroup(plan *plan.FinalGroup) (interface{}, error)
 	return NewFinalGroup(plan), nil
 }
 
+func (this *builder) VisitStreamGroup(plan *plan.StreamGroup) (interface{}, error) {
+	return NewStreamGroup(plan), nil
+}
+
+// Window
+func (this *builder) VisitWindow(plan *plan.Window) (interface{}, error) {
+	return NewWindow(plan), nil
+}
+
 // Project
 func (this *builder) VisitInitialProject(plan *plan.InitialProject) (interface{}, error) {
 	return NewInitialProject(plan), nil
@@ -143,6 +165,16 @@ func (this *builder) VisitDistinct(plan *plan.Distinct) (interface{}, error) {
 	return NewDistinct(false), nil
 }
 
+// With
+func (this *builder) VisitWith(plan *plan.With) (interface{}, error) {
+	child, err := plan.Child().Accept(this)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWith(plan, child.(Operator)), nil
+}
+
 // Set operators
 func (this *builder) VisitUnionAll(plan *plan.UnionAll) (interface{}, error) {
 	children := make([]Operator, len(plan.Children()))
@@ -349,6 +381,21 @@ func (this *builder) VisitBuildIndexes(plan *plan.BuildIndexes) (interface{}, er
 	return NewBuildIndexes(plan), nil
 }
 
+// CreateFunction
+func (this *builder) VisitCreateFunction(plan *plan.CreateFunction) (interface{}, error) {
+	return NewCreateFunction(plan), nil
+}
+
+// DropFunction
+func (this *builder) VisitDropFunction(plan *plan.DropFunction) (interface{}, error) {
+	return NewDropFunction(plan), nil
+}
+
+// UpdateStatistics
+func (this *builder) VisitUpdateStatistics(plan *plan.UpdateStatistics) (interface{}, error) {
+	return NewUpdateStatistics(plan), nil
+}
+
 // Prepare
 func (this *builder) VisitPrepare(plan *plan.Prepare) (interface{}, error) {
 	return NewPrepare(plan.Prepared()), nil
@@ -356,5 +403,5 @@ func (this *builder) VisitPrepare(plan *plan.Prepare) (interface{}, error) {
 
 // Explain
 func (this *builder) VisitExplain(plan *plan.Explain) (interface{}, error) {
-	return NewExplain(plan.Operator()), nil
+	return NewExplain(plan.Operator(), plan.Analyze(), plan.Format()), nil
 }
@@ -43,6 +43,7 @@ func (this *Channel) RunOnce(context *Context, parent value.Value) {
 		defer context.Recover()       // Recover from any panic
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
 
 		// Block until stopped
 		<-this.StopChannel()
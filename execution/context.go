@@ -14,6 +14,7 @@ import (
 	"os"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/couchbaselabs/query/algebra"
@@ -33,6 +34,23 @@ type Output interface {
 	Warning(wrn errors.Error)
 	AddMutationCount(uint64)
 	MutationCount() uint64
+	AddSortSpill(size uint64)
+	SortSpillCount() uint64
+	SortSpillSize() uint64
+}
+
+// Resource limits of a request, each zero or negative if there is no
+// limit. A request that exceeds one of them fails, except for the
+// memory budgets, beyond which operators spill to disk.
+type Limits struct {
+	MemoryQuota   int64 // Bytes held by ORDER BY, GROUP BY, DISTINCT, INTERSECT and EXCEPT
+	MaxFetch      int64 // Documents fetched
+	MaxResultSize int64 // Bytes of results, as written to the response
+	MaxMutations  int64 // Documents inserted, upserted, updated or deleted
+	OrderLimit    int64 // LIMIT plus OFFSET of ORDER BY
+	UpdateLimit   int64 // LIMIT, and mutated documents, of DELETE and UPDATE
+	SortMemory    int64 // Memory budget in bytes of each ORDER BY
+	HashMemory    int64 // Memory budget in bytes of each hash table
 }
 
 type Context struct {
@@ -46,6 +64,12 @@ type Context struct {
 	credentials    datastore.Credentials
 	consistency    datastore.ScanConsistency
 	vector         timestamp.Vector
+	limits         Limits
+	memory         int64 // Bytes held, updated atomically
+	fetchCount     int64 // Updated atomically
+	mutations      int64 // Mutations started, updated atomically
+	exceeded       int32 // Whether a limit has been exceeded
+	explainFormat  string
 	output         Output
 	subplans       *subqueryMap
 	subresults     *subqueryMap
@@ -54,7 +78,7 @@ type Context struct {
 func NewContext(datastore, systemstore datastore.Datastore, namespace string,
 	readonly bool, namedArgs map[string]value.Value, positionalArgs value.Values,
 	credentials datastore.Credentials, consistency datastore.ScanConsistency,
-	vector timestamp.Vector, output Output) *Context {
+	vector timestamp.Vector, limits Limits, explainFormat string, output Output) *Context {
 	return &Context{
 		datastore:      datastore,
 		systemstore:    systemstore,
@@ -66,6 +90,8 @@ func NewContext(datastore, systemstore datastore.Datastore, namespace string,
 		credentials:    credentials,
 		consistency:    consistency,
 		vector:         vector,
+		limits:         limits,
+		explainFormat:  explainFormat,
 		output:         output,
 		subplans:       newSubqueryMap(),
 		subresults:     newSubqueryMap(),
@@ -120,6 +146,100 @@ func (this *Context) ScanVector() timestamp.Vector {
 	return this.vector
 }
 
+// Maximum LIMIT plus OFFSET for ORDER BY; zero or negative if there
+// is no maximum
+func (this *Context) OrderLimit() int64 {
+	return this.limits.OrderLimit
+}
+
+// Maximum LIMIT, and number of mutated documents, of DELETE and
+// UPDATE; zero or negative if there is no maximum
+func (this *Context) UpdateLimit() int64 {
+	return this.limits.UpdateLimit
+}
+
+// Memory budget in bytes for each ORDER BY, beyond which sorted runs
+// are spilled to disk; zero or negative if there is no budget
+func (this *Context) SortMemory() int64 {
+	return this.limits.SortMemory
+}
+
+// Memory budget in bytes for each hash table of GROUP BY, DISTINCT,
+// INTERSECT and EXCEPT, beyond which it is partitioned to disk; zero
+// or negative if there is no budget
+func (this *Context) HashMemory() int64 {
+	return this.limits.HashMemory
+}
+
+func (this *Context) Limits() Limits {
+	return this.limits
+}
+
+// The format of EXPLAIN output, unless the statement specifies one
+func (this *Context) ExplainFormat() string {
+	return this.explainFormat
+}
+
+// Whether memory held by operators is tracked against a quota
+func (this *Context) TracksMemory() bool {
+	return this.limits.MemoryQuota > 0
+}
+
+// Track memory held by an operator. Fail the request and return false
+// if the memory quota is exceeded.
+func (this *Context) TrackMemory(size int64) bool {
+	if this.limits.MemoryQuota <= 0 {
+		return true
+	}
+
+	return this.addToLimit(&this.memory, size, this.limits.MemoryQuota,
+		errors.NewMemoryQuotaExceededError)
+}
+
+// Release memory previously tracked by TrackMemory.
+func (this *Context) ReleaseMemory(size int64) {
+	if this.limits.MemoryQuota > 0 {
+		atomic.AddInt64(&this.memory, -size)
+	}
+}
+
+// Count fetched documents. Fail the request and return false if the
+// maximum is exceeded.
+func (this *Context) AddFetchCount(i int64) bool {
+	if this.limits.MaxFetch <= 0 {
+		return true
+	}
+
+	return this.addToLimit(&this.fetchCount, i, this.limits.MaxFetch,
+		errors.NewFetchLimitExceededError)
+}
+
+// Count mutations before they are performed. Fail the request and
+// return false if the maximum is exceeded.
+func (this *Context) ReserveMutations(i int64) bool {
+	if this.limits.MaxMutations <= 0 {
+		return true
+	}
+
+	return this.addToLimit(&this.mutations, i, this.limits.MaxMutations,
+		errors.NewMutationLimitExceededError)
+}
+
+// Add to a counter. The request fails only once, when the first
+// limit is exceeded.
+func (this *Context) addToLimit(counter *int64, i, limit int64,
+	newError func(int64) errors.Error) bool {
+	if atomic.AddInt64(counter, i) <= limit {
+		return true
+	}
+
+	if atomic.CompareAndSwapInt32(&this.exceeded, 0, 1) {
+		this.Fatal(newError(limit))
+	}
+
+	return false
+}
+
 func (this *Context) AddMutationCount(i uint64) {
 	this.output.AddMutationCount(i)
 }
@@ -128,6 +248,10 @@ func (this *Context) MutationCount() uint64 {
 	return this.output.MutationCount()
 }
 
+func (this *Context) AddSortSpill(size uint64) {
+	this.output.AddSortSpill(size)
+}
+
 func (this *Context) Result(item value.Value) bool {
 	return this.output.Result(item)
 }
@@ -167,7 +291,22 @@ func (this *Context) EvaluateSubquery(query *algebra.Select, parent value.Value)
 		this.subplans.set(query, subplan)
 	}
 
-	pipeline, err := Build(subplan.(plan.Operator))
+	results, err := this.evaluatePlan(subplan.(plan.Operator), parent)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache results
+	if !planFound && !query.Subresult().IsCorrelated() {
+		this.subresults.set(query, results)
+	}
+
+	return results, nil
+}
+
+// Run a plan and collect its results
+func (this *Context) evaluatePlan(subplan plan.Operator, parent value.Value) (value.Value, error) {
+	pipeline, err := Build(subplan)
 	if err != nil {
 		return nil, err
 	}
@@ -178,41 +317,34 @@ func (this *Context) EvaluateSubquery(query *algebra.Select, parent value.Value)
 	sequence.RunOnce(this, parent)
 
 	// Await completion
-	ok = true
+	ok := true
 	for ok {
 		_, ok = <-collect.Output().ItemChannel()
 	}
 
-	results := collect.Values()
-
-	// Cache results
-	if !planFound && !query.Subresult().IsCorrelated() {
-		this.subresults.set(query, results)
-	}
-
-	return results, nil
+	return collect.Values(), nil
 }
 
 // Synchronized map
 type subqueryMap struct {
 	mutex   sync.RWMutex
-	entries map[*algebra.Select]interface{}
+	entries map[interface{}]interface{}
 }
 
 func newSubqueryMap() *subqueryMap {
 	rv := &subqueryMap{}
-	rv.entries = make(map[*algebra.Select]interface{})
+	rv.entries = make(map[interface{}]interface{})
 	return rv
 }
 
-func (this *subqueryMap) get(key *algebra.Select) (interface{}, bool) {
+func (this *subqueryMap) get(key interface{}) (interface{}, bool) {
 	this.mutex.RLock()
 	rv, ok := this.entries[key]
 	this.mutex.RUnlock()
 	return rv, ok
 }
 
-func (this *subqueryMap) set(key *algebra.Select, value interface{}) {
+func (this *subqueryMap) set(key interface{}, value interface{}) {
 	this.mutex.Lock()
 	this.entries[key] = value
 	this.mutex.Unlock()
@@ -12,6 +12,7 @@ package execution
 import (
 	"fmt"
 
+	"github.com/couchbaselabs/query/datastore"
 	"github.com/couchbaselabs/query/errors"
 	"github.com/couchbaselabs/query/plan"
 	"github.com/couchbaselabs/query/value"
@@ -21,6 +22,7 @@ type SendDelete struct {
 	base
 	plan  *plan.SendDelete
 	limit int64
+	count int64
 }
 
 func NewSendDelete(plan *plan.SendDelete) *SendDelete {
@@ -39,7 +41,7 @@ func (this *SendDelete) Accept(visitor Visitor) (interface{}, error) {
 }
 
 func (this *SendDelete) Copy() Operator {
-	return &SendDelete{this.base.copy(), this.plan, this.limit}
+	return &SendDelete{this.base.copy(), this.plan, this.limit, 0}
 }
 
 func (this *SendDelete) RunOnce(context *Context, parent value.Value) {
@@ -47,7 +49,16 @@ func (this *SendDelete) RunOnce(context *Context, parent value.Value) {
 }
 
 func (this *SendDelete) processItem(item value.AnnotatedValue, context *Context) bool {
-	rv := this.limit != 0 && this.enbatch(item, this, context)
+	if this.limit == 0 {
+		return false
+	}
+
+	this.count++
+	if !checkUpdateLimit(this.count, context) {
+		return false
+	}
+
+	rv := this.enbatch(item, this, context)
 
 	if this.limit > 0 {
 		this.limit--
@@ -75,7 +86,7 @@ func (this *SendDelete) beforeItems(context *Context, parent value.Value) bool {
 		return false
 	}
 
-	return true
+	return checkUpdateLimit(this.limit, context)
 }
 
 func (this *SendDelete) afterItems(context *Context) {
@@ -97,7 +108,18 @@ func (this *SendDelete) flushBatch(context *Context) bool {
 		keys[i] = key
 	}
 
-	deleted_keys, e := this.plan.Keyspace().Delete(keys)
+	if !context.ReserveMutations(int64(len(keys))) {
+		return false
+	}
+
+	var deleted_keys []string
+	var e errors.Error
+	keyspace := this.plan.Keyspace()
+	if authorized, ok := keyspace.(datastore.AuthorizedDeleteKeyspace); ok {
+		deleted_keys, e = authorized.DeleteAs(keys, context.Credentials())
+	} else {
+		deleted_keys, e = keyspace.Delete(keys)
+	}
 
 	// Update mutation count with number of deleted docs:
 	context.AddMutationCount(uint64(len(deleted_keys)))
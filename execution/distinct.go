@@ -16,16 +16,13 @@ import (
 // Distincting of input data.
 type Distinct struct {
 	base
-	set     *value.Set
+	set     *spillTable
 	collect bool
 }
 
-const _DISTINCT_CAP = 1024
-
 func NewDistinct(collect bool) *Distinct {
 	rv := &Distinct{
 		base:    newBase(),
-		set:     value.NewSet(_DISTINCT_CAP),
 		collect: collect,
 	}
 
@@ -40,7 +37,6 @@ func (this *Distinct) Accept(visitor Visitor) (interface{}, error) {
 func (this *Distinct) Copy() Operator {
 	return &Distinct{
 		base: this.base.copy(),
-		set:  value.NewSet(_DISTINCT_CAP),
 	}
 }
 
@@ -48,14 +44,23 @@ func (this *Distinct) RunOnce(context *Context, parent value.Value) {
 	this.runConsumer(this, context, parent)
 }
 
+func (this *Distinct) beforeItems(context *Context, parent value.Value) bool {
+	this.set = newSpillTable(context.HashMemory(), parent, false, 0)
+	return true
+}
+
 func (this *Distinct) processItem(item value.AnnotatedValue, context *Context) bool {
 	p := item.GetAttachment("projection")
 	if p == nil {
 		p = item
 	}
 
-	this.set.Put(p.(value.Value), item)
-	return true
+	key := distinctKey(p.(value.Value))
+	if this.set.get(key) != nil {
+		return true
+	}
+
+	return this.set.add(key, item, context)
 }
 
 func (this *Distinct) afterItems(context *Context) {
@@ -63,15 +68,25 @@ func (this *Distinct) afterItems(context *Context) {
 		return
 	}
 
-	for _, av := range this.set.Values() {
-		if !this.sendItem(value.NewAnnotatedValue(av)) {
-			return
-		}
+	merge := func(table *spillTable, key string, item value.AnnotatedValue) bool {
+		return table.get(key) != nil || table.add(key, item, context)
 	}
 
+	this.set.drain(merge, this.sendItem, context)
 	this.set = nil
 }
 
-func (this *Distinct) Set() *value.Set {
+func (this *Distinct) Set() *spillTable {
 	return this.set
 }
+
+// Key of a distinct value. MISSING is distinguished from NULL, which
+// has the same encoding.
+func distinctKey(val value.Value) string {
+	if val.Type() == value.MISSING {
+		return ""
+	}
+
+	bytes, _ := val.MarshalJSON()
+	return string(bytes)
+}
@@ -18,7 +18,7 @@ type ExceptAll struct {
 	first        Operator
 	second       Operator
 	childChannel StopChannel
-	set          *value.Set
+	set          *spillTable
 }
 
 func NewExceptAll(first, second Operator) *ExceptAll {
@@ -77,7 +77,12 @@ loop:
 	}
 
 	this.set = distinct.Set()
-	if this.set.Len() == 0 {
+	if this.set.empty() {
+		return false
+	}
+
+	// Once spilled, items are looked up partition by partition
+	if this.set.spilled() && !this.set.spill(context) {
 		return false
 	}
 
@@ -87,11 +92,27 @@ loop:
 }
 
 func (this *ExceptAll) processItem(item value.AnnotatedValue, context *Context) bool {
-	return this.set.Has(item) || this.sendItem(item)
+	key := distinctKey(item)
+	if this.set.spilled() {
+		return this.set.probe(key, item, context)
+	}
+
+	return this.set.get(key) != nil || this.sendItem(item)
 }
 
 func (this *ExceptAll) afterItems(context *Context) {
-	this.set = nil
+	defer func() {
+		if this.set != nil {
+			this.set.release(context)
+			this.set = nil
+		}
+	}()
+
+	if this.set != nil && this.set.spilled() {
+		this.set.drainProbes(func(item value.AnnotatedValue, found bool) bool {
+			return found || this.sendItem(item)
+		}, context)
+	}
 }
 
 func (this *ExceptAll) ChildChannel() StopChannel {
@@ -12,6 +12,7 @@ package execution
 import (
 	"encoding/json"
 
+	"github.com/couchbaselabs/query/algebra"
 	"github.com/couchbaselabs/query/errors"
 	"github.com/couchbaselabs/query/plan"
 	"github.com/couchbaselabs/query/value"
@@ -19,13 +20,17 @@ import (
 
 type Explain struct {
 	base
-	plan plan.Operator
+	plan    plan.Operator
+	analyze bool
+	format  string
 }
 
-func NewExplain(plan plan.Operator) *Explain {
+func NewExplain(plan plan.Operator, analyze bool, format string) *Explain {
 	rv := &Explain{
-		base: newBase(),
-		plan: plan,
+		base:    newBase(),
+		plan:    plan,
+		analyze: analyze,
+		format:  format,
 	}
 
 	rv.output = rv
@@ -37,7 +42,7 @@ func (this *Explain) Accept(visitor Visitor) (interface{}, error) {
 }
 
 func (this *Explain) Copy() Operator {
-	return &Explain{this.base.copy(), this.plan}
+	return &Explain{this.base.copy(), this.plan, this.analyze, this.format}
 }
 
 func (this *Explain) RunOnce(context *Context, parent value.Value) {
@@ -45,8 +50,25 @@ func (this *Explain) RunOnce(context *Context, parent value.Value) {
 		defer context.Recover()       // Recover from any panic
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
 
-		bytes, err := json.Marshal(this.plan)
+		var explain interface{}
+		var err error
+		if this.analyze {
+			explain, err = this.runPlan(context, parent)
+			if err != nil {
+				context.Fatal(errors.NewError(err, "Failed to analyze plan."))
+				return
+			}
+		} else {
+			explain, err = this.formatPlan(context)
+			if err != nil {
+				context.Fatal(errors.NewError(err, "Failed to format plan."))
+				return
+			}
+		}
+
+		bytes, err := json.Marshal(explain)
 		if err != nil {
 			context.Fatal(errors.NewError(err, "Failed to marshal JSON."))
 			return
@@ -57,3 +79,37 @@ func (this *Explain) RunOnce(context *Context, parent value.Value) {
 
 	})
 }
+
+// The plan in the format of the statement, else that of the request;
+// TEXT and DOT are rendered as a string
+func (this *Explain) formatPlan(context *Context) (interface{}, error) {
+	format := this.format
+	if format == "" {
+		format = context.ExplainFormat()
+	}
+
+	switch format {
+	case algebra.EXPLAIN_TEXT:
+		return plan.ExplainText(this.plan)
+	case algebra.EXPLAIN_DOT:
+		return plan.ExplainDot(this.plan)
+	default:
+		return this.plan, nil
+	}
+}
+
+// Run the plan, discarding its results, and return the plan annotated
+// with the statistics of its operators
+func (this *Explain) runPlan(context *Context, parent value.Value) (interface{}, error) {
+	pipeline, err := Build(this.plan)
+	if err != nil {
+		return nil, err
+	}
+
+	profiler := NewProfiler(pipeline)
+	sequence := NewSequence(pipeline, NewDiscard())
+	sequence.RunOnce(context, parent)
+	profiler.Done()
+
+	return profiler.Plan()
+}
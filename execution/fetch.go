@@ -89,6 +89,10 @@ func (this *Fetch) flushBatch(context *Context) bool {
 		return false
 	}
 
+	if !context.AddFetchCount(int64(len(pairs))) {
+		return false
+	}
+
 	// Attach meta and send
 	for i, pair := range pairs {
 		item := pair.Value
@@ -0,0 +1,88 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package execution
+
+import (
+	"fmt"
+
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/plan"
+	"github.com/couchbaselabs/query/value"
+)
+
+type CreateFunction struct {
+	base
+	plan *plan.CreateFunction
+}
+
+func NewCreateFunction(plan *plan.CreateFunction) *CreateFunction {
+	rv := &CreateFunction{
+		base: newBase(),
+		plan: plan,
+	}
+
+	rv.output = rv
+	return rv
+}
+
+func (this *CreateFunction) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitCreateFunction(this)
+}
+
+func (this *CreateFunction) Copy() Operator {
+	return &CreateFunction{this.base.copy(), this.plan}
+}
+
+func (this *CreateFunction) RunOnce(context *Context, parent value.Value) {
+	this.once.Do(func() {
+		defer context.Recover()       // Recover from any panic
+		defer close(this.itemChannel) // Broadcast that I have stopped
+		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
+
+		if context.Readonly() {
+			return
+		}
+
+		node := this.plan.Node()
+		namespace := this.plan.Namespace()
+		name := node.Function().Name()
+
+		_, ok := expression.GetUserFunction(namespace.Name(), name)
+		if ok && !node.Replace() {
+			context.Error(errors.NewError(nil, fmt.Sprintf("Function %s already exists.", name)))
+			return
+		}
+
+		err := expression.CheckUserFunctionRecursion(namespace.Name(), name, node.Body())
+		if err != nil {
+			context.Error(errors.NewError(err, ""))
+			return
+		}
+
+		// Actually create function
+		definition := &datastore.FunctionDefinition{
+			Name:       name,
+			Parameters: node.Parameters(),
+			Body:       node.Body().String(),
+		}
+
+		er := namespace.CreateFunction(definition, node.Replace())
+		if er != nil {
+			context.Error(er)
+			return
+		}
+
+		expression.RegisterUserFunction(expression.NewUserFunctionDefinition(
+			namespace.Name(), name, node.Parameters(), node.Body()))
+	})
+}
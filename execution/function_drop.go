@@ -0,0 +1,74 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package execution
+
+import (
+	"fmt"
+
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/plan"
+	"github.com/couchbaselabs/query/value"
+)
+
+type DropFunction struct {
+	base
+	plan *plan.DropFunction
+}
+
+func NewDropFunction(plan *plan.DropFunction) *DropFunction {
+	rv := &DropFunction{
+		base: newBase(),
+		plan: plan,
+	}
+
+	rv.output = rv
+	return rv
+}
+
+func (this *DropFunction) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitDropFunction(this)
+}
+
+func (this *DropFunction) Copy() Operator {
+	return &DropFunction{this.base.copy(), this.plan}
+}
+
+func (this *DropFunction) RunOnce(context *Context, parent value.Value) {
+	this.once.Do(func() {
+		defer context.Recover()       // Recover from any panic
+		defer close(this.itemChannel) // Broadcast that I have stopped
+		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
+
+		if context.Readonly() {
+			return
+		}
+
+		namespace := this.plan.Namespace()
+		name := this.plan.Node().Function().Name()
+
+		_, ok := expression.GetUserFunction(namespace.Name(), name)
+		if !ok {
+			context.Error(errors.NewError(nil, fmt.Sprintf(
+				"Function %s not found in namespace %s.", name, namespace.Name())))
+			return
+		}
+
+		// Actually drop function
+		err := namespace.DropFunction(name)
+		if err != nil {
+			context.Error(err)
+			return
+		}
+
+		expression.UnregisterUserFunction(namespace.Name(), name)
+	})
+}
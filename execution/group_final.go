@@ -20,14 +20,13 @@ import (
 type FinalGroup struct {
 	base
 	plan   *plan.FinalGroup
-	groups map[string]value.AnnotatedValue
+	groups *spillTable
 }
 
 func NewFinalGroup(plan *plan.FinalGroup) *FinalGroup {
 	rv := &FinalGroup{
-		base:   newBase(),
-		plan:   plan,
-		groups: make(map[string]value.AnnotatedValue),
+		base: newBase(),
+		plan: plan,
 	}
 
 	rv.output = rv
@@ -40,9 +39,8 @@ func (this *FinalGroup) Accept(visitor Visitor) (interface{}, error) {
 
 func (this *FinalGroup) Copy() Operator {
 	return &FinalGroup{
-		base:   this.base.copy(),
-		plan:   this.plan,
-		groups: make(map[string]value.AnnotatedValue),
+		base: this.base.copy(),
+		plan: this.plan,
 	}
 }
 
@@ -50,6 +48,11 @@ func (this *FinalGroup) RunOnce(context *Context, parent value.Value) {
 	this.runConsumer(this, context, parent)
 }
 
+func (this *FinalGroup) beforeItems(context *Context, parent value.Value) bool {
+	this.groups = newSpillTable(context.HashMemory(), parent, true, 0)
+	return true
+}
+
 func (this *FinalGroup) processItem(item value.AnnotatedValue, context *Context) bool {
 	// Generate the group key
 	var gk string
@@ -63,14 +66,13 @@ func (this *FinalGroup) processItem(item value.AnnotatedValue, context *Context)
 	}
 
 	// Get or seed the group value
-	gv := this.groups[gk]
+	gv := this.groups.get(gk)
 	if gv != nil {
 		context.Error(errors.NewError(nil, "Duplicate final GROUP."))
 		return false
 	}
 
 	gv = item
-	this.groups[gk] = gv
 
 	// Compute final aggregates
 	aggregates := gv.GetAttachment("aggregates")
@@ -87,7 +89,7 @@ func (this *FinalGroup) processItem(item value.AnnotatedValue, context *Context)
 			aggregates[agg.String()] = v
 		}
 
-		return true
+		return this.groups.add(gk, gv, context)
 	default:
 		context.Error(errors.NewError(nil, fmt.Sprintf(
 			"Invalid or missing aggregates of type %T.", aggregates)))
@@ -96,12 +98,15 @@ func (this *FinalGroup) processItem(item value.AnnotatedValue, context *Context)
 }
 
 func (this *FinalGroup) afterItems(context *Context) {
-	if len(this.groups) > 0 {
-		for _, av := range this.groups {
-			if !this.sendItem(av) {
-				return
-			}
+	defer func() { this.groups = nil }()
+
+	if !this.groups.empty() {
+		// Spilled groups are already final, and are never duplicated
+		merge := func(table *spillTable, key string, item value.AnnotatedValue) bool {
+			return table.add(key, item, context)
 		}
+
+		this.groups.drain(merge, this.sendItem, context)
 	} else if this.plan.Keys() == nil {
 		// Grouping over all inputs -- always send a result
 		av := value.NewAnnotatedValue(nil)
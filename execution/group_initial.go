@@ -19,14 +19,13 @@ import (
 type InitialGroup struct {
 	base
 	plan   *plan.InitialGroup
-	groups map[string]value.AnnotatedValue
+	groups *spillTable
 }
 
 func NewInitialGroup(plan *plan.InitialGroup) *InitialGroup {
 	rv := &InitialGroup{
-		base:   newBase(),
-		plan:   plan,
-		groups: make(map[string]value.AnnotatedValue),
+		base: newBase(),
+		plan: plan,
 	}
 
 	rv.output = rv
@@ -39,9 +38,8 @@ func (this *InitialGroup) Accept(visitor Visitor) (interface{}, error) {
 
 func (this *InitialGroup) Copy() Operator {
 	return &InitialGroup{
-		base:   this.base.copy(),
-		plan:   this.plan,
-		groups: make(map[string]value.AnnotatedValue),
+		base: this.base.copy(),
+		plan: this.plan,
 	}
 }
 
@@ -49,6 +47,11 @@ func (this *InitialGroup) RunOnce(context *Context, parent value.Value) {
 	this.runConsumer(this, context, parent)
 }
 
+func (this *InitialGroup) beforeItems(context *Context, parent value.Value) bool {
+	this.groups = newSpillTable(context.HashMemory(), parent, true, 0)
+	return true
+}
+
 func (this *InitialGroup) processItem(item value.AnnotatedValue, context *Context) bool {
 	// Generate the group key
 	var gk string
@@ -62,10 +65,10 @@ func (this *InitialGroup) processItem(item value.AnnotatedValue, context *Contex
 	}
 
 	// Get or seed the group value
-	gv := this.groups[gk]
-	if gv == nil {
+	gv := this.groups.get(gk)
+	seed := gv == nil
+	if seed {
 		gv = item
-		this.groups[gk] = gv
 
 		aggregates := make(map[string]value.Value)
 		gv.SetAttachment("aggregates", aggregates)
@@ -86,13 +89,16 @@ func (this *InitialGroup) processItem(item value.AnnotatedValue, context *Contex
 		aggregates[agg.String()] = v
 	}
 
+	// Add the group after cumulating, in case it is spilled
+	if seed {
+		return this.groups.add(gk, gv, context)
+	}
+
 	return true
 }
 
 func (this *InitialGroup) afterItems(context *Context) {
-	for _, av := range this.groups {
-		if !this.sendItem(av) {
-			return
-		}
-	}
+	defer func() { this.groups = nil }()
+
+	this.groups.drain(mergeGroups(this.plan.Aggregates(), context), this.sendItem, context)
 }
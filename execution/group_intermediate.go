@@ -10,8 +10,6 @@
 package execution
 
 import (
-	"fmt"
-
 	"github.com/couchbaselabs/query/errors"
 	"github.com/couchbaselabs/query/plan"
 	"github.com/couchbaselabs/query/value"
@@ -21,14 +19,13 @@ import (
 type IntermediateGroup struct {
 	base
 	plan   *plan.IntermediateGroup
-	groups map[string]value.AnnotatedValue
+	groups *spillTable
 }
 
 func NewIntermediateGroup(plan *plan.IntermediateGroup) *IntermediateGroup {
 	rv := &IntermediateGroup{
-		base:   newBase(),
-		plan:   plan,
-		groups: make(map[string]value.AnnotatedValue),
+		base: newBase(),
+		plan: plan,
 	}
 
 	rv.output = rv
@@ -41,9 +38,8 @@ func (this *IntermediateGroup) Accept(visitor Visitor) (interface{}, error) {
 
 func (this *IntermediateGroup) Copy() Operator {
 	return &IntermediateGroup{
-		base:   this.base.copy(),
-		plan:   this.plan,
-		groups: make(map[string]value.AnnotatedValue),
+		base: this.base.copy(),
+		plan: this.plan,
 	}
 }
 
@@ -51,6 +47,11 @@ func (this *IntermediateGroup) RunOnce(context *Context, parent value.Value) {
 	this.runConsumer(this, context, parent)
 }
 
+func (this *IntermediateGroup) beforeItems(context *Context, parent value.Value) bool {
+	this.groups = newSpillTable(context.HashMemory(), parent, true, 0)
+	return true
+}
+
 func (this *IntermediateGroup) processItem(item value.AnnotatedValue, context *Context) bool {
 	// Generate the group key
 	var gk string
@@ -64,40 +65,17 @@ func (this *IntermediateGroup) processItem(item value.AnnotatedValue, context *C
 	}
 
 	// Get or seed the group value
-	gv := this.groups[gk]
+	gv := this.groups.get(gk)
 	if gv == nil {
-		gv = item
-		this.groups[gk] = gv
-		return true
+		return this.groups.add(gk, item, context)
 	}
 
 	// Cumulate aggregates
-	aggregates := gv.GetAttachment("aggregates")
-	switch aggregates := aggregates.(type) {
-	case map[string]value.Value:
-		for _, agg := range this.plan.Aggregates() {
-			v, e := agg.CumulateIntermediate(item, aggregates[agg.String()], context)
-			if e != nil {
-				context.Error(errors.NewError(
-					e, "Error updating GROUP value."))
-				return false
-			}
-
-			aggregates[agg.String()] = v
-		}
-
-		return true
-	default:
-		context.Error(errors.NewError(nil, fmt.Sprintf(
-			"Invalid or missing aggregates of type %T.", aggregates)))
-		return false
-	}
+	return cumulateGroups(gv, item, this.plan.Aggregates(), context)
 }
 
 func (this *IntermediateGroup) afterItems(context *Context) {
-	for _, av := range this.groups {
-		if !this.sendItem(av) {
-			return
-		}
-	}
+	defer func() { this.groups = nil }()
+
+	this.groups.drain(mergeGroups(this.plan.Aggregates(), context), this.sendItem, context)
 }
@@ -0,0 +1,117 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package execution
+
+import (
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/plan"
+	"github.com/couchbaselabs/query/value"
+)
+
+// Grouping of input data that is ordered on the group keys. Only the
+// current group is kept, and it is sent as soon as its key changes.
+type StreamGroup struct {
+	base
+	plan  *plan.StreamGroup
+	key   string
+	group value.AnnotatedValue
+}
+
+func NewStreamGroup(plan *plan.StreamGroup) *StreamGroup {
+	rv := &StreamGroup{
+		base: newBase(),
+		plan: plan,
+	}
+
+	rv.output = rv
+	return rv
+}
+
+func (this *StreamGroup) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitStreamGroup(this)
+}
+
+func (this *StreamGroup) Copy() Operator {
+	return &StreamGroup{
+		base: this.base.copy(),
+		plan: this.plan,
+	}
+}
+
+func (this *StreamGroup) RunOnce(context *Context, parent value.Value) {
+	this.runConsumer(this, context, parent)
+}
+
+func (this *StreamGroup) processItem(item value.AnnotatedValue, context *Context) bool {
+	// Generate the group key
+	gk, e := groupKey(item, this.plan.Keys(), context)
+	if e != nil {
+		context.Error(errors.NewError(e, "Error evaluating GROUP key."))
+		return false
+	}
+
+	// Send the current group when the key changes
+	if this.group != nil && gk != this.key {
+		if !this.sendGroup(context) {
+			return false
+		}
+	}
+
+	// Seed the group value
+	if this.group == nil {
+		this.key = gk
+		this.group = item
+
+		aggregates := make(map[string]value.Value)
+		item.SetAttachment("aggregates", aggregates)
+		for _, agg := range this.plan.Aggregates() {
+			aggregates[agg.String()] = agg.Default()
+		}
+	}
+
+	// Cumulate aggregates
+	aggregates := this.group.GetAttachment("aggregates").(map[string]value.Value)
+	for _, agg := range this.plan.Aggregates() {
+		v, e := agg.CumulateInitial(item, aggregates[agg.String()], context)
+		if e != nil {
+			context.Error(errors.NewError(e, "Error updating GROUP value."))
+			return false
+		}
+
+		aggregates[agg.String()] = v
+	}
+
+	return true
+}
+
+func (this *StreamGroup) afterItems(context *Context) {
+	if this.group != nil {
+		this.sendGroup(context)
+	}
+}
+
+// Compute the final aggregates of the current group, and send it.
+func (this *StreamGroup) sendGroup(context *Context) bool {
+	gv := this.group
+	this.group = nil
+
+	aggregates := gv.GetAttachment("aggregates").(map[string]value.Value)
+	for _, agg := range this.plan.Aggregates() {
+		v, e := agg.ComputeFinal(aggregates[agg.String()], context)
+		if e != nil {
+			context.Error(errors.NewError(e, "Error updating GROUP value."))
+			return false
+		}
+
+		aggregates[agg.String()] = v
+	}
+
+	return this.sendItem(gv)
+}
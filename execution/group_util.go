@@ -10,6 +10,10 @@
 package execution
 
 import (
+	"fmt"
+
+	"github.com/couchbaselabs/query/algebra"
+	"github.com/couchbaselabs/query/errors"
 	"github.com/couchbaselabs/query/expression"
 	"github.com/couchbaselabs/query/value"
 )
@@ -30,3 +34,47 @@ func groupKey(item value.Value, keys expression.Expressions, context *Context) (
 	bytes, _ := value.NewValue(kvs).MarshalJSON()
 	return string(bytes), nil
 }
+
+// Cumulate the intermediate aggregates of item into the group value.
+func cumulateGroups(gv, item value.AnnotatedValue, aggs algebra.Aggregates, context *Context) bool {
+	parts, ok := item.GetAttachment("aggregates").(map[string]value.Value)
+	if !ok {
+		context.Error(errors.NewError(nil, fmt.Sprintf(
+			"Invalid or missing aggregates of type %T.", item.GetAttachment("aggregates"))))
+		return false
+	}
+
+	aggregates := gv.GetAttachment("aggregates")
+	switch aggregates := aggregates.(type) {
+	case map[string]value.Value:
+		for _, agg := range aggs {
+			v, e := agg.CumulateIntermediate(parts[agg.String()], aggregates[agg.String()], context)
+			if e != nil {
+				context.Error(errors.NewError(
+					e, "Error updating GROUP value."))
+				return false
+			}
+
+			aggregates[agg.String()] = v
+		}
+
+		return true
+	default:
+		context.Error(errors.NewError(nil, fmt.Sprintf(
+			"Invalid or missing aggregates of type %T.", aggregates)))
+		return false
+	}
+}
+
+// Merge groups read back from disk, which have intermediate
+// aggregates.
+func mergeGroups(aggs algebra.Aggregates, context *Context) spillMerge {
+	return func(table *spillTable, key string, item value.AnnotatedValue) bool {
+		gv := table.get(key)
+		if gv == nil {
+			return table.add(key, item, context)
+		}
+
+		return cumulateGroups(gv, item, aggs, context)
+	}
+}
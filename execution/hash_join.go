@@ -0,0 +1,170 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package execution
+
+import (
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/plan"
+	"github.com/couchbaselabs/query/value"
+)
+
+type HashJoin struct {
+	base
+	plan  *plan.HashJoin
+	child Operator
+	table map[string][]value.AnnotatedValue
+	size  int64
+}
+
+func NewHashJoin(plan *plan.HashJoin, child Operator) *HashJoin {
+	rv := &HashJoin{
+		base:  newBase(),
+		plan:  plan,
+		child: child,
+	}
+
+	rv.output = rv
+	return rv
+}
+
+func (this *HashJoin) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitHashJoin(this)
+}
+
+func (this *HashJoin) Copy() Operator {
+	return &HashJoin{
+		base:  this.base.copy(),
+		plan:  this.plan,
+		child: this.child.Copy(),
+	}
+}
+
+func (this *HashJoin) RunOnce(context *Context, parent value.Value) {
+	this.runConsumer(this, context, parent)
+}
+
+// Build the hash table from the child.
+func (this *HashJoin) beforeItems(context *Context, parent value.Value) bool {
+	this.table = make(map[string][]value.AnnotatedValue)
+	go this.child.RunOnce(context, parent)
+
+	for {
+		select {
+		case <-this.stopChannel: // Never closed
+			this.notifyStop()
+			notifyChildren(this.child)
+			return false
+		default:
+		}
+
+		select {
+		case item, ok := <-this.child.ItemChannel():
+			if !ok {
+				return true
+			}
+
+			key, ok, e := hashJoinKey(item, this.plan.BuildExprs(), context)
+			if e != nil {
+				context.Error(errors.NewError(e, "Error evaluating hash join build keys."))
+				notifyChildren(this.child)
+				return false
+			}
+
+			if ok {
+				this.table[key] = append(this.table[key], item)
+
+				if context.TracksMemory() {
+					size := estimateSize(item) + int64(len(key))
+					this.size += size
+					if !context.TrackMemory(size) {
+						notifyChildren(this.child)
+						return false
+					}
+				}
+			}
+		case <-this.stopChannel: // Never closed
+			this.notifyStop()
+			notifyChildren(this.child)
+			return false
+		}
+	}
+}
+
+// Probe the hash table.
+func (this *HashJoin) processItem(item value.AnnotatedValue, context *Context) bool {
+	key, ok, e := hashJoinKey(item, this.plan.ProbeExprs(), context)
+	if e != nil {
+		context.Error(errors.NewError(e, "Error evaluating hash join probe keys."))
+		return false
+	}
+
+	var matches []value.AnnotatedValue
+	if ok {
+		matches = this.table[key]
+	}
+
+	found := false
+	for _, match := range matches {
+		av := value.NewAnnotatedValue(item.Copy())
+		for _, alias := range this.plan.BuildAliases() {
+			if fv, ok := match.Field(alias); ok {
+				av.SetField(alias, fv)
+			}
+		}
+
+		on, e := this.plan.Onclause().Evaluate(av, context)
+		if e != nil {
+			context.Error(errors.NewError(e, "Error evaluating ON predicate."))
+			return false
+		}
+
+		if !on.Truth() {
+			continue
+		}
+
+		found = true
+		if !this.sendItem(av) {
+			return false
+		}
+	}
+
+	// Outer join
+	return found || !this.plan.Outer() || this.sendItem(item)
+}
+
+func (this *HashJoin) afterItems(context *Context) {
+	context.ReleaseMemory(this.size)
+	this.table = nil
+	this.size = 0
+}
+
+// The hash key of item on exprs. Returns false if any key is MISSING
+// or NULL, as such an item cannot satisfy an equality.
+func hashJoinKey(item value.Value, exprs expression.Expressions, context *Context) (
+	string, bool, error) {
+	kvs := make([]interface{}, len(exprs))
+	for i, expr := range exprs {
+		k, e := expr.Evaluate(item, context)
+		if e != nil {
+			return "", false, e
+		}
+
+		switch k.Type() {
+		case value.MISSING, value.NULL:
+			return "", false, nil
+		}
+
+		kvs[i] = k
+	}
+
+	bytes, _ := value.NewValue(kvs).MarshalJSON()
+	return string(bytes), true, nil
+}
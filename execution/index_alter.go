@@ -42,11 +42,13 @@ func (this *AlterIndex) RunOnce(context *Context, parent value.Value) {
 		defer context.Recover()       // Recover from any panic
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
 
 		if context.Readonly() {
 			return
 		}
 
 		// Actually alter index
+		invalidateKeyspace(context, this.plan.Node().Keyspace())
 	})
 }
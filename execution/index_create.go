@@ -43,6 +43,7 @@ func (this *CreateIndex) RunOnce(context *Context, parent value.Value) {
 		defer context.Recover()       // Recover from any panic
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
 
 		if context.Readonly() {
 			return
@@ -65,6 +66,10 @@ func (this *CreateIndex) RunOnce(context *Context, parent value.Value) {
 			node.Expressions(), node.Where(), node.With())
 		if err != nil {
 			context.Error(err)
+			return
 		}
+
+		keyspace := this.plan.Keyspace()
+		plan.PreparedCache().InvalidateKeyspace(keyspace.NamespaceId(), keyspace.Name())
 	})
 }
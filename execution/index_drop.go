@@ -10,6 +10,7 @@
 package execution
 
 import (
+	"github.com/couchbaselabs/query/algebra"
 	"github.com/couchbaselabs/query/plan"
 	"github.com/couchbaselabs/query/value"
 )
@@ -42,6 +43,7 @@ func (this *DropIndex) RunOnce(context *Context, parent value.Value) {
 		defer context.Recover()       // Recover from any panic
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
 
 		if context.Readonly() {
 			return
@@ -51,6 +53,19 @@ func (this *DropIndex) RunOnce(context *Context, parent value.Value) {
 		err := this.plan.Index().Drop()
 		if err != nil {
 			context.Error(err)
+			return
 		}
+
+		invalidateKeyspace(context, this.plan.Node().Keyspace())
 	})
 }
+
+// Invalidate the prepared statements that use the keyspace of an index
+func invalidateKeyspace(context *Context, ksref *algebra.KeyspaceRef) {
+	namespace := ksref.Namespace()
+	if namespace == "" {
+		namespace = context.Namespace()
+	}
+
+	plan.PreparedCache().InvalidateKeyspace(namespace, ksref.Keyspace())
+}
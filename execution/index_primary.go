@@ -42,6 +42,7 @@ func (this *CreatePrimaryIndex) RunOnce(context *Context, parent value.Value) {
 		defer context.Recover()       // Recover from any panic
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
 
 		if context.Readonly() {
 			return
@@ -58,6 +59,10 @@ func (this *CreatePrimaryIndex) RunOnce(context *Context, parent value.Value) {
 		_, err = indexer.CreatePrimaryIndex(node.Name(), node.With())
 		if err != nil {
 			context.Error(err)
+			return
 		}
+
+		keyspace := this.plan.Keyspace()
+		plan.PreparedCache().InvalidateKeyspace(keyspace.NamespaceId(), keyspace.Name())
 	})
 }
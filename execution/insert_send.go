@@ -149,6 +149,10 @@ func (this *SendInsert) flushBatch(context *Context) bool {
 	dpairs = dpairs[0:i]
 	this.batch = nil
 
+	if !context.ReserveMutations(int64(len(dpairs))) {
+		return false
+	}
+
 	// Perform the actual INSERT
 	keys, e := this.plan.Keyspace().Insert(dpairs)
 
@@ -43,6 +43,10 @@ func (this *Join) RunOnce(context *Context, parent value.Value) {
 }
 
 func (this *Join) processItem(item value.AnnotatedValue, context *Context) bool {
+	if this.plan.Onclause() != nil {
+		return this.processAnsiItem(item, context)
+	}
+
 	kv, e := this.plan.Term().Keys().Evaluate(item, context)
 	if e != nil {
 		context.Error(errors.NewError(e, "Error evaluating JOIN keys."))
@@ -121,3 +125,34 @@ func (this *Join) processItem(item value.AnnotatedValue, context *Context) bool
 
 	return found || !this.plan.Outer() || this.sendItem(item)
 }
+
+func (this *Join) processAnsiItem(item value.AnnotatedValue, context *Context) bool {
+	jvs, ok := this.ansiJoinItems(this.plan.Keyspace(), this.plan.Term(),
+		this.plan.Onclause(), this.plan.Index(), this.plan.Spans(), item, context)
+	if !ok {
+		return false
+	}
+
+	if len(jvs) == 0 {
+		// Outer join
+		return !this.plan.Outer() || this.sendItem(item)
+	}
+
+	// Attach and send
+	for i, jv := range jvs {
+		var av value.AnnotatedValue
+		if i < len(jvs)-1 {
+			av = value.NewAnnotatedValue(item.Copy())
+		} else {
+			av = item
+		}
+
+		av.SetField(this.plan.Term().Alias(), jv)
+
+		if !this.sendItem(av) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,193 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package execution
+
+import (
+	"math"
+
+	"github.com/couchbaselabs/query/algebra"
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/planner"
+	"github.com/couchbaselabs/query/value"
+)
+
+// ANSI JOIN and NEST. Find the right-hand documents that satisfy the
+// ON predicate for the given left-hand item.
+func (this *base) ansiJoinItems(keyspace datastore.Keyspace, term *algebra.KeyspaceTerm,
+	onclause expression.Expression, index datastore.Index, spans planner.Spans,
+	item value.AnnotatedValue, context *Context) ([]value.AnnotatedValue, bool) {
+	keys, ok := this.ansiJoinKeys(index, spans, item, context)
+	if !ok || len(keys) == 0 {
+		return nil, ok
+	}
+
+	// Fetch
+	pairs, err := keyspace.Fetch(keys)
+	if err != nil {
+		context.Error(err)
+		return nil, false
+	}
+
+	alias := term.Alias()
+	projection := term.Projection()
+	rvs := make([]value.AnnotatedValue, 0, len(pairs))
+
+	for _, pair := range pairs {
+		var jv value.AnnotatedValue
+
+		// Apply projection, if any
+		if projection != nil {
+			projectedItem, e := projection.Evaluate(pair.Value, context)
+			if e != nil {
+				context.Error(errors.NewError(e,
+					"Error evaluating join path."))
+				return nil, false
+			}
+
+			if projectedItem.Type() == value.MISSING {
+				continue
+			}
+
+			jv = value.NewAnnotatedValue(projectedItem)
+		} else {
+			jv = value.NewAnnotatedValue(pair.Value)
+		}
+
+		jv.SetAttachment("meta", map[string]interface{}{"id": pair.Key})
+
+		// Apply ON predicate
+		av := value.NewAnnotatedValue(item.Copy())
+		av.SetField(alias, jv)
+
+		on, e := onclause.Evaluate(av, context)
+		if e != nil {
+			context.Error(errors.NewError(e, "Error evaluating ON predicate."))
+			return nil, false
+		}
+
+		if on.Truth() {
+			rvs = append(rvs, jv)
+		}
+	}
+
+	return rvs, true
+}
+
+// Collect the distinct primary keys of candidate right-hand
+// documents. Without spans, every document in the keyspace is a
+// candidate.
+func (this *base) ansiJoinKeys(index datastore.Index, spans planner.Spans,
+	item value.AnnotatedValue, context *Context) ([]string, bool) {
+	if spans == nil {
+		primary, ok := index.(datastore.PrimaryIndex)
+		if !ok {
+			context.Error(errors.NewError(nil,
+				"Join index "+index.Name()+" is not a primary index."))
+			return nil, false
+		}
+
+		return this.scanJoinKeys(context, nil, func(conn *datastore.IndexConnection) {
+			primary.ScanEntries(math.MaxInt64, context.ScanConsistency(),
+				context.ScanVector(), conn)
+		})
+	}
+
+	var keys []string
+	var set map[string]bool
+
+	for _, ps := range spans {
+		dspan, err := evalSpan(ps, item, context)
+		if err != nil {
+			context.Error(errors.NewError(err, "Error evaluating join span."))
+			return nil, false
+		}
+
+		if !validJoinSpan(dspan) {
+			continue
+		}
+
+		if set == nil {
+			set = make(map[string]bool)
+		}
+
+		ks, ok := this.scanJoinKeys(context, set, func(conn *datastore.IndexConnection) {
+			index.Scan(dspan, false, math.MaxInt64, context.ScanConsistency(),
+				context.ScanVector(), conn)
+		})
+		if !ok {
+			return nil, false
+		}
+
+		keys = append(keys, ks...)
+	}
+
+	return keys, true
+}
+
+// Run scan on a new connection and gather its primary keys, skipping
+// those already in set.
+func (this *base) scanJoinKeys(context *Context, set map[string]bool,
+	scan func(*datastore.IndexConnection)) ([]string, bool) {
+	conn := datastore.NewIndexConnection(context)
+	defer notifyConn(conn) // Notify index that I have stopped
+
+	go func() {
+		defer context.Recover() // Recover from any panic
+		scan(conn)
+	}()
+
+	var keys []string
+	for {
+		select {
+		case <-this.stopChannel:
+			return nil, false
+		default:
+		}
+
+		select {
+		case entry, ok := <-conn.EntryChannel():
+			if !ok {
+				return keys, true
+			}
+
+			if set != nil {
+				if set[entry.PrimaryKey] {
+					continue
+				}
+
+				set[entry.PrimaryKey] = true
+			}
+
+			keys = append(keys, entry.PrimaryKey)
+		case <-this.stopChannel:
+			return nil, false
+		}
+	}
+}
+
+// A span bound that is MISSING or NULL cannot match any index entry.
+func validJoinSpan(span *datastore.Span) bool {
+	for _, bounds := range []value.Values{span.Seek, span.Range.Low, span.Range.High} {
+		for _, b := range bounds {
+			if b == nil {
+				continue
+			}
+
+			switch b.Type() {
+			case value.MISSING, value.NULL:
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -43,6 +43,10 @@ func (this *Nest) RunOnce(context *Context, parent value.Value) {
 }
 
 func (this *Nest) processItem(item value.AnnotatedValue, context *Context) bool {
+	if this.plan.Onclause() != nil {
+		return this.processAnsiItem(item, context)
+	}
+
 	kv, e := this.plan.Term().Keys().Evaluate(item, context)
 	if e != nil {
 		context.Error(errors.NewError(e, "Error evaluating NEST keys."))
@@ -118,3 +122,20 @@ func (this *Nest) processItem(item value.AnnotatedValue, context *Context) bool
 	item.SetField(this.plan.Term().Alias(), nvs)
 	return this.sendItem(item)
 }
+
+func (this *Nest) processAnsiItem(item value.AnnotatedValue, context *Context) bool {
+	nvs, ok := this.ansiJoinItems(this.plan.Keyspace(), this.plan.Term(),
+		this.plan.Onclause(), this.plan.Index(), this.plan.Spans(), item, context)
+	if !ok {
+		return false
+	}
+
+	if len(nvs) == 0 {
+		// Outer nest
+		return !this.plan.Outer() || this.sendItem(item)
+	}
+
+	// Attach and send
+	item.SetField(this.plan.Term().Alias(), nvs)
+	return this.sendItem(item)
+}
@@ -10,9 +10,14 @@
 package execution
 
 import (
+	"container/heap"
+	"fmt"
+	"math"
 	"strconv"
 
+	"github.com/couchbaselabs/query/algebra"
 	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/expression"
 	"github.com/couchbaselabs/query/plan"
 	"github.com/couchbaselabs/query/sort"
 	"github.com/couchbaselabs/query/value"
@@ -23,6 +28,9 @@ type Order struct {
 	plan    *plan.Order
 	values  value.AnnotatedValues
 	context *Context
+	limit   int64 // Number of values to keep, or -1 to keep all
+	size    int64 // Estimated size of values, if there is a memory budget or quota
+	runs    []*orderRun
 }
 
 const _ORDER_CAP = 1024
@@ -32,6 +40,7 @@ func NewOrder(plan *plan.Order) *Order {
 		base:   newBase(),
 		plan:   plan,
 		values: make(value.AnnotatedValues, 0, _ORDER_CAP),
+		limit:  -1,
 	}
 
 	rv.output = rv
@@ -47,6 +56,7 @@ func (this *Order) Copy() Operator {
 		base:   this.base.copy(),
 		plan:   this.plan,
 		values: make(value.AnnotatedValues, 0, _ORDER_CAP),
+		limit:  -1,
 	}
 }
 
@@ -54,7 +64,56 @@ func (this *Order) RunOnce(context *Context, parent value.Value) {
 	this.runConsumer(this, context, parent)
 }
 
+// With a LIMIT, keep only the first LIMIT plus OFFSET values in a
+// heap whose root is the last of them.
+func (this *Order) beforeItems(context *Context, parent value.Value) bool {
+	this.context = context
+
+	if this.plan.Limit() == nil {
+		return true
+	}
+
+	limit, ok := evalCount(this.plan.Limit(), "LIMIT", parent, context)
+	if !ok {
+		return false
+	}
+
+	if this.plan.Offset() != nil {
+		offset, ok := evalCount(this.plan.Offset(), "OFFSET", parent, context)
+		if !ok {
+			return false
+		}
+
+		if offset > 0 && limit < math.MaxInt64-offset {
+			limit += offset
+		} else if offset > 0 {
+			limit = math.MaxInt64
+		}
+	}
+
+	if limit < 0 {
+		limit = 0
+	}
+
+	orderLimit := context.OrderLimit()
+	if orderLimit > 0 && limit > orderLimit {
+		context.Error(errors.NewOrderLimitExceededError(limit, orderLimit))
+		return false
+	}
+
+	this.limit = limit
+	if limit < _ORDER_CAP {
+		this.values = make(value.AnnotatedValues, 0, limit+1)
+	}
+
+	return true
+}
+
 func (this *Order) processItem(item value.AnnotatedValue, context *Context) bool {
+	if this.limit >= 0 {
+		return this.processTop(item, context)
+	}
+
 	if len(this.values) == cap(this.values) {
 		values := make(value.AnnotatedValues, len(this.values), len(this.values)<<1)
 		copy(values, this.values)
@@ -62,13 +121,106 @@ func (this *Order) processItem(item value.AnnotatedValue, context *Context) bool
 	}
 
 	this.values = append(this.values, item)
+
+	budget := context.SortMemory()
+	if budget <= 0 && !context.TracksMemory() {
+		return true
+	}
+
+	size := estimateSize(item)
+	this.size += size
+	if !context.TrackMemory(size) {
+		return false
+	}
+
+	if budget <= 0 || this.size <= budget {
+		return true
+	}
+
+	return this.spill()
+}
+
+// Evaluate the LIMIT or OFFSET of the ORDER BY, as for the Limit and
+// Offset operators that follow it.
+func evalCount(expr expression.Expression, clause string, parent value.Value,
+	context *Context) (int64, bool) {
+	val, e := expr.Evaluate(parent, context)
+	if e != nil {
+		context.Error(errors.NewError(e, fmt.Sprintf("Error evaluating %s.", clause)))
+		return 0, false
+	}
+
+	actual := val.Actual()
+	switch actual := actual.(type) {
+	case float64:
+		if math.Trunc(actual) == actual {
+			return int64(actual), true
+		}
+	}
+
+	context.Error(errors.NewError(nil, fmt.Sprintf("Invalid %s value %v.", clause, actual)))
+	return 0, false
+}
+
+// Replace the last of the kept values if the item precedes it.
+func (this *Order) processTop(item value.AnnotatedValue, context *Context) bool {
+	if int64(len(this.values)) < this.limit {
+		heap.Push((*topOrder)(this), item)
+		return this.trackTop(item, nil, context)
+	}
+
+	if this.limit == 0 {
+		return true
+	}
+
+	this.values = append(this.values, item)
+	last := len(this.values) - 1
+	if this.Less(last, 0) {
+		replaced := this.values[0]
+		this.values[0] = item
+		this.values = this.values[0:last]
+		heap.Fix((*topOrder)(this), 0)
+		return this.trackTop(item, replaced, context)
+	} else {
+		this.values = this.values[0:last]
+	}
+
 	return true
 }
 
+// Track the memory of a value kept in the heap, and release that of
+// the value it replaced, if any.
+func (this *Order) trackTop(item, replaced value.AnnotatedValue, context *Context) bool {
+	if !context.TracksMemory() {
+		return true
+	}
+
+	if replaced != nil {
+		size := estimateSize(replaced)
+		this.size -= size
+		context.ReleaseMemory(size)
+	}
+
+	size := estimateSize(item)
+	this.size += size
+	return context.TrackMemory(size)
+}
+
 func (this *Order) afterItems(context *Context) {
-	defer func() { this.values = nil }()
+	defer func() {
+		context.ReleaseMemory(this.size)
+		this.values = nil
+		this.size = 0
+	}()
+
+	if len(this.runs) > 0 {
+		defer this.removeRuns()
+		sort.Sort(this)
+		this.merge()
+		this.context = nil
+		return
+	}
 
-	this.context = context
 	sort.Sort(this)
 	this.context = nil
 
@@ -84,45 +236,22 @@ func (this *Order) Len() int {
 }
 
 func (this *Order) Less(i, j int) bool {
-	v1 := this.values[i]
-	v2 := this.values[j]
-
-	var ev1, ev2 value.Value
-	var c int
-	var e error
+	return this.lessValues(this.values[i], this.values[j])
+}
 
+func (this *Order) lessValues(v1, v2 value.AnnotatedValue) bool {
 	for i, term := range this.plan.Terms() {
-		s := strconv.Itoa(i)
-
-		sv1 := v1.GetAttachment(s)
-		switch sv1 := sv1.(type) {
-		case value.Value:
-			ev1 = sv1
-		default:
-			ev1, e = term.Expression().Evaluate(v1, this.context)
-			if e != nil {
-				this.context.Error(errors.NewError(e, "Error evaluating ORDER BY."))
-				return false
-			}
-
-			v1.SetAttachment(s, ev1)
+		ev1, ok := this.sortValue(v1, i, term)
+		if !ok {
+			return false
 		}
 
-		sv2 := v2.GetAttachment(s)
-		switch sv2 := sv2.(type) {
-		case value.Value:
-			ev2 = sv2
-		default:
-			ev2, e = term.Expression().Evaluate(v2, this.context)
-			if e != nil {
-				this.context.Error(errors.NewError(e, "Error evaluating ORDER BY."))
-				return false
-			}
-
-			v2.SetAttachment(s, ev2)
+		ev2, ok := this.sortValue(v2, i, term)
+		if !ok {
+			return false
 		}
 
-		c = ev1.Collate(ev2)
+		c := ev1.Collate(ev2)
 
 		if c == 0 {
 			continue
@@ -136,6 +265,53 @@ func (this *Order) Less(i, j int) bool {
 	return false
 }
 
+// Evaluate the i-th sort term of an item, and cache it as an
+// attachment.
+func (this *Order) sortValue(item value.AnnotatedValue, i int, term *algebra.SortTerm) (value.Value, bool) {
+	s := strconv.Itoa(i)
+
+	sv := item.GetAttachment(s)
+	switch sv := sv.(type) {
+	case value.Value:
+		return sv, true
+	}
+
+	ev, e := term.Expression().Evaluate(item, this.context)
+	if e != nil {
+		this.context.Error(errors.NewError(e, "Error evaluating ORDER BY."))
+		return nil, false
+	}
+
+	item.SetAttachment(s, ev)
+	return ev, true
+}
+
 func (this *Order) Swap(i, j int) {
 	this.values[i], this.values[j] = this.values[j], this.values[i]
 }
+
+// A heap of ordered values whose root is the last value in order
+type topOrder Order
+
+func (this *topOrder) Len() int {
+	return len(this.values)
+}
+
+func (this *topOrder) Less(i, j int) bool {
+	return (*Order)(this).Less(j, i)
+}
+
+func (this *topOrder) Swap(i, j int) {
+	this.values[i], this.values[j] = this.values[j], this.values[i]
+}
+
+func (this *topOrder) Push(item interface{}) {
+	this.values = append(this.values, item.(value.AnnotatedValue))
+}
+
+func (this *topOrder) Pop() interface{} {
+	last := len(this.values) - 1
+	item := this.values[last]
+	this.values = this.values[0:last]
+	return item
+}
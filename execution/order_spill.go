@@ -0,0 +1,195 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package execution
+
+import (
+	"container/heap"
+
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/sort"
+	"github.com/couchbaselabs/query/value"
+)
+
+// A sorted run of values, either spilled to a temporary file or held
+// in memory
+type orderRun struct {
+	file   *spillFile
+	values value.AnnotatedValues
+	head   value.AnnotatedValue // Next value of the run, or nil if done
+}
+
+// Sort the values in memory and write them to a temporary file as a
+// sorted run. The sort keys are evaluated first, so that they are
+// spilled with the values.
+func (this *Order) spill() bool {
+	sort.Sort(this)
+
+	file, err := newSpillFile("cbq-order-")
+	if err != nil {
+		this.context.Error(errors.NewError(err, "Error creating ORDER BY spill file."))
+		return false
+	}
+
+	// Add the run first, so that its file is removed on failure
+	this.runs = append(this.runs, &orderRun{file: file})
+
+	for _, av := range this.values {
+		for i, term := range this.plan.Terms() {
+			_, ok := this.sortValue(av, i, term)
+			if !ok {
+				return false
+			}
+		}
+
+		record, err := encodeSpill(av, false)
+		if err == nil {
+			err = file.write(record)
+		}
+
+		if err != nil {
+			this.context.Error(errors.NewError(err, "Error writing ORDER BY spill file."))
+			return false
+		}
+	}
+
+	size, err := file.flush()
+	if err != nil {
+		this.context.Error(errors.NewError(err, "Error writing ORDER BY spill file."))
+		return false
+	}
+
+	this.context.AddSortSpill(uint64(size))
+
+	// Release the spilled values, and reuse their slice
+	for i := range this.values {
+		this.values[i] = nil
+	}
+
+	this.values = this.values[0:0]
+	this.context.ReleaseMemory(this.size)
+	this.size = 0
+	return true
+}
+
+// Advance the run to its next value.
+func (this *orderRun) next(context *Context) bool {
+	if this.file == nil {
+		if len(this.values) == 0 {
+			this.head = nil
+		} else {
+			this.head = this.values[0]
+			this.values = this.values[1:]
+		}
+
+		return true
+	}
+
+	var record spillRecord
+	ok, err := this.file.read(&record)
+	if err != nil {
+		context.Error(errors.NewError(err, "Error reading ORDER BY spill file."))
+		return false
+	}
+
+	if ok {
+		this.head = decodeSpill(&record, nil)
+	} else {
+		this.head = nil
+	}
+
+	return true
+}
+
+// Merge the spilled runs and the values in memory, which have been
+// sorted, and send the merged values in order.
+func (this *Order) merge() {
+	runs := make([]*orderRun, 0, len(this.runs)+1)
+	runs = append(runs, this.runs...)
+	runs = append(runs, &orderRun{values: this.values})
+
+	merge := &orderMerge{
+		order: this,
+		runs:  make([]*orderRun, 0, len(runs)),
+	}
+
+	for _, run := range runs {
+		if run.file != nil {
+			err := run.file.rewind()
+			if err != nil {
+				this.context.Error(errors.NewError(err, "Error reading ORDER BY spill file."))
+				return
+			}
+		}
+
+		if !run.next(this.context) {
+			return
+		}
+
+		if run.head != nil {
+			merge.runs = append(merge.runs, run)
+		}
+	}
+
+	heap.Init(merge)
+	for merge.Len() > 0 {
+		run := merge.runs[0]
+		if !this.sendItem(run.head) {
+			return
+		}
+
+		if !run.next(this.context) {
+			return
+		}
+
+		if run.head == nil {
+			heap.Pop(merge)
+		} else {
+			heap.Fix(merge, 0)
+		}
+	}
+}
+
+// Close and remove the spill files.
+func (this *Order) removeRuns() {
+	for _, run := range this.runs {
+		run.file.remove()
+	}
+
+	this.runs = nil
+}
+
+// A heap of runs whose root is the run with the first head in order
+type orderMerge struct {
+	order *Order
+	runs  []*orderRun
+}
+
+func (this *orderMerge) Len() int {
+	return len(this.runs)
+}
+
+func (this *orderMerge) Less(i, j int) bool {
+	return this.order.lessValues(this.runs[i].head, this.runs[j].head)
+}
+
+func (this *orderMerge) Swap(i, j int) {
+	this.runs[i], this.runs[j] = this.runs[j], this.runs[i]
+}
+
+func (this *orderMerge) Push(item interface{}) {
+	this.runs = append(this.runs, item.(*orderRun))
+}
+
+func (this *orderMerge) Pop() interface{} {
+	last := len(this.runs) - 1
+	run := this.runs[last]
+	this.runs = this.runs[0:last]
+	return run
+}
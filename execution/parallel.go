@@ -49,6 +49,7 @@ func (this *Parallel) RunOnce(context *Context, parent value.Value) {
 		defer context.Recover()       // Recover from any panic
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
 
 		this.child.SetInput(this.input)
 		this.child.SetOutput(this.output)
@@ -39,6 +39,7 @@ func (this *Prepare) RunOnce(context *Context, parent value.Value) {
 		defer context.Recover()       // Recover from any panic
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
 		value := value.NewAnnotatedValue(this.plan)
 		this.sendItem(value)
 
@@ -0,0 +1,450 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package execution
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"time"
+)
+
+// Profiling of the operators of a pipeline. Each operator counts the
+// items it receives and sends, and times its running, its waiting on
+// input and its blocking on output.
+type Profiler struct {
+	root Operator
+	done chan bool
+}
+
+// Profile a pipeline; must be called before it runs.
+func NewProfiler(root Operator) *Profiler {
+	root.Accept(&profiler{enable: true})
+
+	return &Profiler{
+		root: root,
+		done: make(chan bool),
+	}
+}
+
+// Notify that the pipeline has run; must be called exactly once.
+func (this *Profiler) Done() {
+	close(this.done)
+}
+
+// Wait until the pipeline has run, and return its plan annotated with
+// the statistics of each operator.
+func (this *Profiler) Plan() (interface{}, error) {
+	<-this.done
+	return this.root.Accept(&profiler{})
+}
+
+// Visits a pipeline to either enable profiling of its operators, or
+// to annotate their plans with their statistics.
+type profiler struct {
+	enable bool
+}
+
+// Annotate an operator that has a plan. Its children are annotated
+// separately, and replace those of the plan.
+func (this *profiler) annotatePlan(b *base, p json.Marshaler, children map[string]interface{}) (interface{}, error) {
+	if this.enable {
+		return this.annotate(b, nil, children)
+	}
+
+	bytes, err := p.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var r map[string]interface{}
+	err = json.Unmarshal(bytes, &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return this.annotate(b, r, children)
+}
+
+func (this *profiler) annotate(b *base, r map[string]interface{}, children map[string]interface{}) (interface{}, error) {
+	if this.enable {
+		if b.stats == nil {
+			b.stats = &operatorStats{}
+		}
+
+		return nil, nil
+	}
+
+	for k, child := range children {
+		r[k] = child
+	}
+
+	if b.stats != nil {
+		runTime := atomic.LoadInt64(&b.stats.runTime)
+		inputTime := atomic.LoadInt64(&b.stats.inputTime)
+		outputTime := atomic.LoadInt64(&b.stats.outputTime)
+
+		// The spans of an index scan may be blocked at the same time
+		execTime := runTime - inputTime - outputTime
+		if execTime < 0 {
+			execTime = 0
+		}
+
+		r["#stats"] = map[string]interface{}{
+			"itemsIn":    atomic.LoadInt64(&b.stats.itemsIn),
+			"itemsOut":   atomic.LoadInt64(&b.stats.itemsOut),
+			"execTime":   time.Duration(execTime).String(),
+			"inputTime":  time.Duration(inputTime).String(),
+			"outputTime": time.Duration(outputTime).String(),
+		}
+	}
+
+	return r, nil
+}
+
+// Annotate an operator that has no plan of its own.
+func (this *profiler) annotateOperator(b *base, name string, children map[string]interface{}) (interface{}, error) {
+	return this.annotate(b, map[string]interface{}{"#operator": name}, children)
+}
+
+func (this *profiler) visit(op Operator) (interface{}, error) {
+	if op == nil {
+		return nil, nil
+	}
+
+	return op.Accept(this)
+}
+
+func (this *profiler) visitAll(ops []Operator) ([]interface{}, error) {
+	rv := make([]interface{}, len(ops))
+	for i, op := range ops {
+		r, err := this.visit(op)
+		if err != nil {
+			return nil, err
+		}
+
+		rv[i] = r
+	}
+
+	return rv, nil
+}
+
+// Scan
+func (this *profiler) VisitPrimaryScan(op *PrimaryScan) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitParentScan(op *ParentScan) (interface{}, error) {
+	return this.annotateOperator(&op.base, "ParentScan", nil)
+}
+
+func (this *profiler) VisitIndexScan(op *IndexScan) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitKeyScan(op *KeyScan) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitValueScan(op *ValueScan) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitDummyScan(op *DummyScan) (interface{}, error) {
+	return this.annotateOperator(&op.base, "DummyScan", nil)
+}
+
+func (this *profiler) VisitExpressionScan(op *ExpressionScan) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitCountScan(op *CountScan) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitIntersectScan(op *IntersectScan) (interface{}, error) {
+	scans, err := this.visitAll(op.scans)
+	if err != nil {
+		return nil, err
+	}
+
+	return this.annotateOperator(&op.base, "IntersectScan", map[string]interface{}{"scans": scans})
+}
+
+func (this *profiler) VisitUnionScan(op *UnionScan) (interface{}, error) {
+	scans, err := this.visitAll(op.scans)
+	if err != nil {
+		return nil, err
+	}
+
+	return this.annotateOperator(&op.base, "UnionScan", map[string]interface{}{"scans": scans})
+}
+
+// Fetch
+func (this *profiler) VisitFetch(op *Fetch) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+// Join
+func (this *profiler) VisitJoin(op *Join) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitNest(op *Nest) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitHashJoin(op *HashJoin) (interface{}, error) {
+	child, err := this.visit(op.child)
+	if err != nil {
+		return nil, err
+	}
+
+	return this.annotatePlan(&op.base, op.plan, map[string]interface{}{"~child": child})
+}
+
+func (this *profiler) VisitUnnest(op *Unnest) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+// Let + Letting
+func (this *profiler) VisitLet(op *Let) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+// Filter
+func (this *profiler) VisitFilter(op *Filter) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+// Group
+func (this *profiler) VisitInitialGroup(op *InitialGroup) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitIntermediateGroup(op *IntermediateGroup) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitFinalGroup(op *FinalGroup) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitStreamGroup(op *StreamGroup) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+// Window
+func (this *profiler) VisitWindow(op *Window) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+// Project
+func (this *profiler) VisitInitialProject(op *InitialProject) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitFinalProject(op *FinalProject) (interface{}, error) {
+	return this.annotateOperator(&op.base, "FinalProject", nil)
+}
+
+// Distinct
+func (this *profiler) VisitDistinct(op *Distinct) (interface{}, error) {
+	return this.annotateOperator(&op.base, "Distinct", nil)
+}
+
+// With
+func (this *profiler) VisitWith(op *With) (interface{}, error) {
+	child, err := this.visit(op.child)
+	if err != nil {
+		return nil, err
+	}
+
+	return this.annotatePlan(&op.base, op.plan, map[string]interface{}{"~child": child})
+}
+
+// Set operators
+func (this *profiler) VisitUnionAll(op *UnionAll) (interface{}, error) {
+	children, err := this.visitAll(op.children)
+	if err != nil {
+		return nil, err
+	}
+
+	return this.annotateOperator(&op.base, "UnionAll", map[string]interface{}{"children": children})
+}
+
+func (this *profiler) VisitIntersectAll(op *IntersectAll) (interface{}, error) {
+	children, err := this.visitAll([]Operator{op.first, op.second})
+	if err != nil {
+		return nil, err
+	}
+
+	return this.annotateOperator(&op.base, "IntersectAll",
+		map[string]interface{}{"first": children[0], "second": children[1]})
+}
+
+func (this *profiler) VisitExceptAll(op *ExceptAll) (interface{}, error) {
+	children, err := this.visitAll([]Operator{op.first, op.second})
+	if err != nil {
+		return nil, err
+	}
+
+	return this.annotateOperator(&op.base, "ExceptAll",
+		map[string]interface{}{"first": children[0], "second": children[1]})
+}
+
+// Order
+func (this *profiler) VisitOrder(op *Order) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+// Offset
+func (this *profiler) VisitOffset(op *Offset) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitLimit(op *Limit) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+// Insert
+func (this *profiler) VisitSendInsert(op *SendInsert) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+// Upsert
+func (this *profiler) VisitSendUpsert(op *SendUpsert) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+// Delete
+func (this *profiler) VisitSendDelete(op *SendDelete) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+// Update
+func (this *profiler) VisitClone(op *Clone) (interface{}, error) {
+	return this.annotateOperator(&op.base, "Clone", nil)
+}
+
+func (this *profiler) VisitSet(op *Set) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitUnset(op *Unset) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitSendUpdate(op *SendUpdate) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+// Merge
+func (this *profiler) VisitMerge(op *Merge) (interface{}, error) {
+	children, err := this.visitAll([]Operator{op.update, op.delete, op.insert})
+	if err != nil {
+		return nil, err
+	}
+
+	return this.annotatePlan(&op.base, op.plan,
+		map[string]interface{}{"update": children[0], "delete": children[1], "insert": children[2]})
+}
+
+// Framework
+func (this *profiler) VisitAlias(op *Alias) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitAuthorize(op *Authorize) (interface{}, error) {
+	child, err := this.visit(op.child)
+	if err != nil {
+		return nil, err
+	}
+
+	return this.annotatePlan(&op.base, op.plan, map[string]interface{}{"child": child})
+}
+
+func (this *profiler) VisitParallel(op *Parallel) (interface{}, error) {
+	child, err := this.visit(op.child)
+	if err != nil {
+		return nil, err
+	}
+
+	return this.annotateOperator(&op.base, "Parallel", map[string]interface{}{"~child": child})
+}
+
+func (this *profiler) VisitSequence(op *Sequence) (interface{}, error) {
+	children, err := this.visitAll(op.children)
+	if err != nil {
+		return nil, err
+	}
+
+	return this.annotateOperator(&op.base, "Sequence", map[string]interface{}{"~children": children})
+}
+
+func (this *profiler) VisitDiscard(op *Discard) (interface{}, error) {
+	return this.annotateOperator(&op.base, "Discard", nil)
+}
+
+func (this *profiler) VisitStream(op *Stream) (interface{}, error) {
+	return this.annotateOperator(&op.base, "Stream", nil)
+}
+
+func (this *profiler) VisitCollect(op *Collect) (interface{}, error) {
+	return this.annotateOperator(&op.base, "Collect", nil)
+}
+
+func (this *profiler) VisitChannel(op *Channel) (interface{}, error) {
+	return this.annotateOperator(&op.base, "Channel", nil)
+}
+
+// Index DDL
+func (this *profiler) VisitCreatePrimaryIndex(op *CreatePrimaryIndex) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitCreateIndex(op *CreateIndex) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitDropIndex(op *DropIndex) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitAlterIndex(op *AlterIndex) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitBuildIndexes(op *BuildIndexes) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+// Function DDL
+func (this *profiler) VisitCreateFunction(op *CreateFunction) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+func (this *profiler) VisitDropFunction(op *DropFunction) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+// Statistics
+func (this *profiler) VisitUpdateStatistics(op *UpdateStatistics) (interface{}, error) {
+	return this.annotatePlan(&op.base, op.plan, nil)
+}
+
+// Explain
+func (this *profiler) VisitExplain(op *Explain) (interface{}, error) {
+	return this.annotateOperator(&op.base, "Explain", nil)
+}
+
+// Prepare
+func (this *profiler) VisitPrepare(op *Prepare) (interface{}, error) {
+	return this.annotateOperator(&op.base, "Prepare", nil)
+}
@@ -42,6 +42,7 @@ func (this *CountScan) RunOnce(context *Context, parent value.Value) {
 		defer context.Recover()       // Recover from any panic
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
 
 		count, e := this.plan.Keyspace().Count()
 		if e != nil {
@@ -41,6 +41,7 @@ func (this *DummyScan) RunOnce(context *Context, parent value.Value) {
 		defer context.Recover()       // Recover from any panic
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
 
 		cv := value.NewScopeValue(nil, parent)
 		av := value.NewAnnotatedValue(cv)
@@ -0,0 +1,73 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package execution
+
+import (
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/plan"
+	"github.com/couchbaselabs/query/value"
+)
+
+// Scan the elements of an array expression, such as the results of
+// a WITH term
+type ExpressionScan struct {
+	base
+	plan *plan.ExpressionScan
+}
+
+func NewExpressionScan(plan *plan.ExpressionScan) *ExpressionScan {
+	rv := &ExpressionScan{
+		base: newBase(),
+		plan: plan,
+	}
+
+	rv.output = rv
+	return rv
+}
+
+func (this *ExpressionScan) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitExpressionScan(this)
+}
+
+func (this *ExpressionScan) Copy() Operator {
+	return &ExpressionScan{
+		base: this.base.copy(),
+		plan: this.plan,
+	}
+}
+
+func (this *ExpressionScan) RunOnce(context *Context, parent value.Value) {
+	this.once.Do(func() {
+		defer context.Recover()       // Recover from any panic
+		defer close(this.itemChannel) // Broadcast that I have stopped
+		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
+
+		ev, e := this.plan.Expression().Evaluate(parent, context)
+		if e != nil {
+			context.Error(errors.NewError(e, "Error evaluating expression scan."))
+			return
+		}
+
+		elems, ok := ev.Actual().([]interface{})
+		if !ok {
+			return
+		}
+
+		for _, elem := range elems {
+			cv := value.NewScopeValue(make(map[string]interface{}), parent)
+			cv.SetField(this.plan.Alias(), elem)
+			av := value.NewAnnotatedValue(cv)
+			if !this.sendItem(av) {
+				return
+			}
+		}
+	})
+}
@@ -52,6 +52,7 @@ func (this *IndexScan) RunOnce(context *Context, parent value.Value) {
 		defer context.Recover()       // Recover from any panic
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
 
 		spans := this.plan.Spans()
 		n := len(spans)
@@ -101,6 +102,7 @@ func newSpanScan(parent *IndexScan, span *planner.Span) *spanScan {
 
 	rv.parent = parent
 	rv.output = parent.output
+	rv.stats = parent.stats // Items are sent on behalf of the parent
 	return rv
 }
 
@@ -123,6 +125,12 @@ func (this *spanScan) RunOnce(context *Context, parent value.Value) {
 
 		go this.scan(context, conn)
 
+		covers := this.plan.Covers()
+		keys := make([]string, len(covers))
+		for i, c := range covers {
+			keys[i] = c.String()
+		}
+
 		var entry *datastore.IndexEntry
 		ok := true
 		for ok {
@@ -138,6 +146,10 @@ func (this *spanScan) RunOnce(context *Context, parent value.Value) {
 					cv := value.NewScopeValue(make(map[string]interface{}), parent)
 					av := value.NewAnnotatedValue(cv)
 					av.SetAttachment("meta", map[string]interface{}{"id": entry.PrimaryKey})
+					if len(covers) > 0 {
+						av.SetAttachment("covers", coverValues(keys, entry))
+					}
+
 					ok = this.sendItem(av)
 				}
 			case <-this.stopChannel:
@@ -147,35 +159,65 @@ func (this *spanScan) RunOnce(context *Context, parent value.Value) {
 	})
 }
 
+// The covers are the index keys followed by the primary key
+func coverValues(keys []string, entry *datastore.IndexEntry) map[string]value.Value {
+	n := len(keys) - 1
+	rv := make(map[string]value.Value, len(keys))
+	for i := 0; i < n; i++ {
+		if i < len(entry.EntryKey) {
+			rv[keys[i]] = entry.EntryKey[i]
+		} else {
+			rv[keys[i]] = value.MISSING_VALUE
+		}
+	}
+
+	rv[keys[n]] = value.NewValue(entry.PrimaryKey)
+	return rv
+}
+
 func (this *spanScan) scan(context *Context, conn *datastore.IndexConnection) {
 	defer context.Recover() // Recover from any panic
 
-	dspan, err := evalSpan(this.span, context)
+	dspan, err := evalSpan(this.span, nil, context)
 	if err != nil {
 		context.Error(errors.NewError(err, "Error evaluating span."))
 		close(conn.EntryChannel())
 		return
 	}
 
-	this.plan.Index().Scan(dspan, this.plan.Distinct(), this.plan.Limit(),
+	if !this.plan.Reverse() {
+		this.plan.Index().Scan(dspan, this.plan.Distinct(), this.plan.Limit(),
+			context.ScanConsistency(), context.ScanVector(), conn)
+		return
+	}
+
+	index, ok := this.plan.Index().(datastore.ReverseIndex)
+	if !ok {
+		context.Error(errors.NewError(nil, fmt.Sprintf(
+			"Index %s does not support reverse scans.", this.plan.Index().Name())))
+		close(conn.EntryChannel())
+		return
+	}
+
+	index.ReverseScan(dspan, this.plan.Distinct(), this.plan.Limit(),
 		context.ScanConsistency(), context.ScanVector(), conn)
 }
 
-func evalSpan(ps *planner.Span, context *Context) (*datastore.Span, error) {
+func evalSpan(ps *planner.Span, parent value.Value, context *Context) (*datastore.Span, error) {
 	var err error
 	ds := &datastore.Span{}
 
-	ds.Seek, err = evalExprs(ps.Seek, context)
+	ds.Seek, err = evalExprs(ps.Seek, parent, context)
 	if err != nil {
 		return nil, err
 	}
 
-	ds.Range.Low, err = evalExprs(ps.Range.Low, context)
+	ds.Range.Low, err = evalExprs(ps.Range.Low, parent, context)
 	if err != nil {
 		return nil, err
 	}
 
-	ds.Range.High, err = evalExprs(ps.Range.High, context)
+	ds.Range.High, err = evalExprs(ps.Range.High, parent, context)
 	if err != nil {
 		return nil, err
 	}
@@ -184,7 +226,7 @@ func evalSpan(ps *planner.Span, context *Context) (*datastore.Span, error) {
 	return ds, nil
 }
 
-func evalExprs(exprs expression.Expressions, context *Context) (value.Values, error) {
+func evalExprs(exprs expression.Expressions, parent value.Value, context *Context) (value.Values, error) {
 	if exprs == nil {
 		return nil, nil
 	}
@@ -197,7 +239,7 @@ func evalExprs(exprs expression.Expressions, context *Context) (value.Values, er
 			continue
 		}
 
-		values[i], err = expr.Evaluate(nil, context)
+		values[i], err = expr.Evaluate(parent, context)
 		if err != nil {
 			return nil, err
 		}
@@ -57,6 +57,7 @@ func (this *IntersectScan) RunOnce(context *Context, parent value.Value) {
 		defer context.Recover()       // Recover from any panic
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
 		defer func() { this.counts = nil }()
 		defer func() { this.values = nil }()
 
@@ -89,8 +90,13 @@ func (this *IntersectScan) RunOnce(context *Context, parent value.Value) {
 					ok = this.processKey(item, context)
 				}
 			case <-this.childChannel:
+				// A key may still be found by the other scans,
+				// so continue until every scan has stopped
 				n--
-				break loop
+				if n == 0 {
+					this.drainKeys(channel, context)
+					break loop
+				}
 			case <-this.stopChannel:
 				this.values = nil
 				break loop
@@ -149,6 +155,20 @@ func (this *IntersectScan) processKey(item value.AnnotatedValue, context *Contex
 	return true
 }
 
+// Process the keys sent by the scans before they stopped.
+func (this *IntersectScan) drainKeys(channel *Channel, context *Context) {
+	for {
+		select {
+		case item, ok := <-channel.ItemChannel():
+			if !ok || !this.processKey(item, context) {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
 func (this *IntersectScan) sendItems() {
 	n := len(this.scans)
 	for key, av := range this.values {
@@ -46,6 +46,7 @@ func (this *KeyScan) RunOnce(context *Context, parent value.Value) {
 		defer context.Recover()       // Recover from any panic
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
 
 		keys, e := this.plan.Keys().Evaluate(parent, context)
 		if e != nil {
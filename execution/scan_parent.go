@@ -39,6 +39,7 @@ func (this *ParentScan) RunOnce(context *Context, parent value.Value) {
 		defer context.Recover()       // Recover from any panic
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
 
 		// Shallow copy of the parent includes
 		// correlated and annotated aspects
@@ -10,8 +10,6 @@
 package execution
 
 import (
-	"math"
-
 	"github.com/couchbaselabs/query/datastore"
 	"github.com/couchbaselabs/query/plan"
 	"github.com/couchbaselabs/query/value"
@@ -45,6 +43,7 @@ func (this *PrimaryScan) RunOnce(context *Context, parent value.Value) {
 		defer context.Recover()       // Recover from any panic
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
 
 		this.scanPrimary(context, parent)
 	})
@@ -81,5 +80,5 @@ func (this *PrimaryScan) scanPrimary(context *Context, parent value.Value) {
 
 func (this *PrimaryScan) scanEntries(context *Context, conn *datastore.IndexConnection) {
 	defer context.Recover() // Recover from any panic
-	this.plan.Index().ScanEntries(math.MaxInt64, context.ScanConsistency(), context.ScanVector(), conn)
+	this.plan.Index().ScanEntries(this.plan.Limit(), context.ScanConsistency(), context.ScanVector(), conn)
 }
@@ -56,6 +56,7 @@ func (this *UnionScan) RunOnce(context *Context, parent value.Value) {
 		defer context.Recover()       // Recover from any panic
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
 		defer func() { this.values = nil }()
 
 		this.values = make(map[string]value.AnnotatedValue, 1024)
@@ -86,8 +87,12 @@ func (this *UnionScan) RunOnce(context *Context, parent value.Value) {
 					ok = this.processKey(item, context)
 				}
 			case <-this.childChannel:
+				// Continue until every scan has stopped
 				n--
-				break loop
+				if n == 0 {
+					this.drainKeys(channel, context)
+					break loop
+				}
 			case <-this.stopChannel:
 				this.values = nil
 				break loop
@@ -137,6 +142,20 @@ func (this *UnionScan) processKey(item value.AnnotatedValue, context *Context) b
 	return this.sendItem(item)
 }
 
+// Process the keys sent by the scans before they stopped.
+func (this *UnionScan) drainKeys(channel *Channel, context *Context) {
+	for {
+		select {
+		case item, ok := <-channel.ItemChannel():
+			if !ok || !this.processKey(item, context) {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
 func (this *UnionScan) notifyScans() {
 	for _, s := range this.scans {
 		select {
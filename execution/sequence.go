@@ -53,6 +53,7 @@ func (this *Sequence) RunOnce(context *Context, parent value.Value) {
 		defer context.Recover()       // Recover from any panic
 		defer close(this.itemChannel) // Broadcast that I have stopped
 		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
 
 		first_child := this.children[0]
 		first_child.SetInput(this.input)
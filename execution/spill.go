@@ -0,0 +1,276 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package execution
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/couchbaselabs/query/value"
+)
+
+// A temporary file of annotated values spilled to disk
+type spillFile struct {
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *json.Encoder
+	decoder *json.Decoder
+}
+
+func newSpillFile(prefix string) (*spillFile, error) {
+	file, err := ioutil.TempFile("", prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := bufio.NewWriter(file)
+	return &spillFile{
+		file:    file,
+		writer:  writer,
+		encoder: json.NewEncoder(writer),
+	}, nil
+}
+
+func (this *spillFile) write(record *spillRecord) error {
+	return this.encoder.Encode(record)
+}
+
+// Flush the writes, and return the size of the file.
+func (this *spillFile) flush() (int64, error) {
+	err := this.writer.Flush()
+	if err != nil {
+		return 0, err
+	}
+
+	return this.file.Seek(0, io.SeekCurrent)
+}
+
+// Flush the writes, and read from the start of the file.
+func (this *spillFile) rewind() error {
+	err := this.writer.Flush()
+	if err != nil {
+		return err
+	}
+
+	_, err = this.file.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	this.decoder = json.NewDecoder(bufio.NewReader(this.file))
+	return nil
+}
+
+// Read the next record; return false at the end of the file.
+func (this *spillFile) read(record *spillRecord) (bool, error) {
+	err := this.decoder.Decode(record)
+	if err == io.EOF {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (this *spillFile) remove() {
+	this.file.Close()
+	os.Remove(this.file.Name())
+}
+
+// An annotated value as encoded in a spill file. Attachments that are
+// values, such as projections and sort keys, or maps of values, such
+// as aggregates and covers, are kept. Other attachments are kept if
+// they can be encoded as JSON, and are dropped otherwise.
+type spillRecord struct {
+	Key         string                           `json:"key,omitempty"`
+	Value       spillValue                       `json:"value"`
+	Values      map[string]spillValue            `json:"values,omitempty"`
+	ValueMaps   map[string]map[string]spillValue `json:"value_maps,omitempty"`
+	Attachments map[string]json.RawMessage       `json:"attachments,omitempty"`
+}
+
+// A value as encoded in a spill file, which distinguishes MISSING
+// from NULL. The set of a DISTINCT aggregate, which is attached to
+// its value, is encoded with it; such a set is never empty.
+type spillValue struct {
+	Value   json.RawMessage `json:"v,omitempty"`
+	Missing bool            `json:"m,omitempty"`
+	Set     []spillValue    `json:"s,omitempty"`
+}
+
+func encodeSpillValue(val value.Value) (spillValue, error) {
+	if val.Type() == value.MISSING {
+		return spillValue{Missing: true}, nil
+	}
+
+	bytes, err := json.Marshal(val)
+	if err != nil {
+		return spillValue{}, err
+	}
+
+	sv := spillValue{Value: bytes}
+
+	if av, ok := val.(value.AnnotatedValue); ok {
+		if set, ok := av.GetAttachment("set").(*value.Set); ok {
+			values := set.Values()
+			sv.Set = make([]spillValue, len(values))
+			for i, v := range values {
+				sv.Set[i], err = encodeSpillValue(v)
+				if err != nil {
+					return spillValue{}, err
+				}
+			}
+		}
+	}
+
+	return sv, nil
+}
+
+func decodeSpillValue(sv spillValue) value.Value {
+	if sv.Missing {
+		return value.MISSING_VALUE
+	}
+
+	var val value.Value = value.NULL_VALUE
+	if len(sv.Value) > 0 {
+		val = value.NewValue([]byte(sv.Value))
+	}
+
+	if sv.Set != nil {
+		set := value.NewSet(len(sv.Set))
+		for _, v := range sv.Set {
+			set.Add(decodeSpillValue(v))
+		}
+
+		av := value.NewAnnotatedValue(val)
+		av.SetAttachment("set", set)
+		return av
+	}
+
+	return val
+}
+
+// Encode an item for spilling. If flatten is true, the fields of
+// enclosing scopes are included, so that expressions can still be
+// evaluated on the decoded item.
+func encodeSpill(item value.AnnotatedValue, flatten bool) (*spillRecord, error) {
+	var val value.Value = item.GetValue()
+	if flatten && val.Type() == value.OBJECT {
+		val = value.NewValue(item.Fields())
+	}
+
+	sv, err := encodeSpillValue(val)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &spillRecord{Value: sv}
+
+	for key, atmt := range item.Attachments() {
+		switch atmt := atmt.(type) {
+		case value.Value:
+			sv, err = encodeSpillValue(atmt)
+			if err != nil {
+				return nil, err
+			}
+
+			if record.Values == nil {
+				record.Values = make(map[string]spillValue)
+			}
+
+			record.Values[key] = sv
+		case map[string]value.Value:
+			m := make(map[string]spillValue, len(atmt))
+			for k, v := range atmt {
+				m[k], err = encodeSpillValue(v)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if record.ValueMaps == nil {
+				record.ValueMaps = make(map[string]map[string]spillValue)
+			}
+
+			record.ValueMaps[key] = m
+		default:
+			bytes, err := json.Marshal(atmt)
+			if err != nil {
+				continue
+			}
+
+			if record.Attachments == nil {
+				record.Attachments = make(map[string]json.RawMessage)
+			}
+
+			record.Attachments[key] = bytes
+		}
+	}
+
+	return record, nil
+}
+
+// Decode a spilled item. If parent is not nil, the item is scoped
+// within it.
+func decodeSpill(record *spillRecord, parent value.Value) value.AnnotatedValue {
+	val := decodeSpillValue(record.Value)
+	if parent != nil && val.Type() == value.OBJECT {
+		val = value.NewScopeValue(val, parent)
+	}
+
+	av := value.NewAnnotatedValue(val)
+
+	for key, sv := range record.Values {
+		av.SetAttachment(key, decodeSpillValue(sv))
+	}
+
+	for key, m := range record.ValueMaps {
+		vm := make(map[string]value.Value, len(m))
+		for k, sv := range m {
+			vm[k] = decodeSpillValue(sv)
+		}
+
+		av.SetAttachment(key, vm)
+	}
+
+	for key, bytes := range record.Attachments {
+		var atmt interface{}
+		err := json.Unmarshal(bytes, &atmt)
+		if err == nil {
+			av.SetAttachment(key, atmt)
+		}
+	}
+
+	return av
+}
+
+// Estimate the memory used by an item, using the size of its encoding.
+func estimateSize(item value.AnnotatedValue) int64 {
+	size := 0
+
+	bytes, err := json.Marshal(item.GetValue())
+	if err == nil {
+		size += len(bytes)
+	}
+
+	switch pv := item.GetAttachment("projection").(type) {
+	case value.Value:
+		bytes, err = json.Marshal(pv)
+		if err == nil {
+			size += len(bytes)
+		}
+	}
+
+	return int64(size)
+}
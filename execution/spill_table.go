@@ -0,0 +1,287 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package execution
+
+import (
+	"hash/fnv"
+
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/value"
+)
+
+const _SPILL_PARTITIONS = 16
+
+// Maximum depth of repartitioning, beyond which a partition is kept
+// in memory
+const _SPILL_DEPTH = 4
+
+// A hash table of items by key, as used for GROUP BY, DISTINCT,
+// INTERSECT and EXCEPT. When its estimated size exceeds the memory
+// budget, its entries are written to partitions on disk by hash of
+// key. The partitions are then processed one by one, and are
+// themselves repartitioned if they exceed the budget.
+type spillTable struct {
+	budget     int64
+	parent     value.Value
+	flatten    bool // Whether to include enclosing scopes when spilling
+	depth      int
+	entries    map[string]value.AnnotatedValue
+	size       int64 // Estimated size of entries, if there is a memory budget or quota
+	partitions []*spillFile
+	probes     []*spillFile // Items to look up in the partitions
+}
+
+// A function that combines a spilled item into a table when
+// processing a partition
+type spillMerge func(table *spillTable, key string, item value.AnnotatedValue) bool
+
+func newSpillTable(budget int64, parent value.Value, flatten bool, depth int) *spillTable {
+	return &spillTable{
+		budget:  budget,
+		parent:  parent,
+		flatten: flatten,
+		depth:   depth,
+		entries: make(map[string]value.AnnotatedValue),
+	}
+}
+
+func (this *spillTable) spilled() bool {
+	return this.partitions != nil
+}
+
+// Whether the table has no entries in memory or on disk
+func (this *spillTable) empty() bool {
+	return len(this.entries) == 0 && this.partitions == nil
+}
+
+func (this *spillTable) get(key string) value.AnnotatedValue {
+	return this.entries[key]
+}
+
+// Add a new entry, and spill the table if it exceeds the budget.
+func (this *spillTable) add(key string, item value.AnnotatedValue, context *Context) bool {
+	this.entries[key] = item
+
+	if this.budget <= 0 && !context.TracksMemory() {
+		return true
+	}
+
+	size := estimateSize(item)
+	this.size += size
+	if !context.TrackMemory(size) {
+		return false
+	}
+
+	if this.budget <= 0 || this.depth >= _SPILL_DEPTH || this.size <= this.budget {
+		return true
+	}
+
+	return this.spill(context)
+}
+
+// Write the entries in memory to their partitions, and release them.
+func (this *spillTable) spill(context *Context) bool {
+	if this.partitions == nil {
+		this.partitions = make([]*spillFile, _SPILL_PARTITIONS)
+	}
+
+	for key, item := range this.entries {
+		p := this.partition(key)
+		if this.partitions[p] == nil {
+			file, err := newSpillFile("cbq-hash-")
+			if err != nil {
+				context.Error(errors.NewError(err, "Error creating hash table spill file."))
+				return false
+			}
+
+			this.partitions[p] = file
+		}
+
+		record, err := encodeSpill(item, this.flatten)
+		if err == nil {
+			record.Key = key
+			err = this.partitions[p].write(record)
+		}
+
+		if err != nil {
+			context.Error(errors.NewError(err, "Error writing hash table spill file."))
+			return false
+		}
+	}
+
+	this.entries = make(map[string]value.AnnotatedValue)
+	this.release(context)
+	return true
+}
+
+// Release the memory tracked for the entries in memory.
+func (this *spillTable) release(context *Context) {
+	context.ReleaseMemory(this.size)
+	this.size = 0
+}
+
+// Write an item to the probe partition of its key, for lookup after
+// the table has been spilled.
+func (this *spillTable) probe(key string, item value.AnnotatedValue, context *Context) bool {
+	if this.probes == nil {
+		this.probes = make([]*spillFile, _SPILL_PARTITIONS)
+	}
+
+	p := this.partition(key)
+	if this.probes[p] == nil {
+		file, err := newSpillFile("cbq-hash-")
+		if err != nil {
+			context.Error(errors.NewError(err, "Error creating hash table spill file."))
+			return false
+		}
+
+		this.probes[p] = file
+	}
+
+	record, err := encodeSpill(item, false)
+	if err == nil {
+		record.Key = key
+		err = this.probes[p].write(record)
+	}
+
+	if err != nil {
+		context.Error(errors.NewError(err, "Error writing hash table spill file."))
+		return false
+	}
+
+	return true
+}
+
+// Hash the key, seeded by depth so that repartitioning splits it.
+func (this *spillTable) partition(key string) int {
+	h := fnv.New32a()
+	h.Write([]byte{byte(this.depth)})
+	h.Write([]byte(key))
+	return int(h.Sum32() % _SPILL_PARTITIONS)
+}
+
+// Send every entry. If the table has been spilled, each partition is
+// read into a new table using merge, and that table is sent in turn.
+func (this *spillTable) drain(merge spillMerge, send func(value.AnnotatedValue) bool,
+	context *Context) bool {
+	if this.partitions == nil {
+		defer this.release(context)
+
+		for _, av := range this.entries {
+			if !send(av) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	defer this.remove()
+
+	if !this.spill(context) {
+		return false
+	}
+
+	for _, file := range this.partitions {
+		if file == nil {
+			continue
+		}
+
+		table := newSpillTable(this.budget, this.parent, this.flatten, this.depth+1)
+		ok := this.readPartition(file, func(key string, item value.AnnotatedValue) bool {
+			return merge(table, key, item)
+		}, context)
+
+		if !ok || !table.drain(merge, send, context) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Send each probed item, and whether its key is in the table. The
+// table must have been spilled.
+func (this *spillTable) drainProbes(send func(value.AnnotatedValue, bool) bool,
+	context *Context) bool {
+	defer this.remove()
+
+	for p, file := range this.probes {
+		if file == nil {
+			continue
+		}
+
+		keys := make(map[string]bool)
+		if this.partitions[p] != nil {
+			ok := this.readPartition(this.partitions[p], func(key string, item value.AnnotatedValue) bool {
+				keys[key] = true
+				return true
+			}, context)
+
+			if !ok {
+				return false
+			}
+		}
+
+		ok := this.readPartition(file, func(key string, item value.AnnotatedValue) bool {
+			return send(item, keys[key])
+		}, context)
+
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (this *spillTable) readPartition(file *spillFile,
+	process func(string, value.AnnotatedValue) bool, context *Context) bool {
+	err := file.rewind()
+	if err != nil {
+		context.Error(errors.NewError(err, "Error reading hash table spill file."))
+		return false
+	}
+
+	for {
+		var record spillRecord
+		ok, err := file.read(&record)
+		if err != nil {
+			context.Error(errors.NewError(err, "Error reading hash table spill file."))
+			return false
+		}
+
+		if !ok {
+			return true
+		}
+
+		if !process(record.Key, decodeSpill(&record, this.parent)) {
+			return false
+		}
+	}
+}
+
+// Close and remove the spill files.
+func (this *spillTable) remove() {
+	for _, file := range this.partitions {
+		if file != nil {
+			file.remove()
+		}
+	}
+
+	for _, file := range this.probes {
+		if file != nil {
+			file.remove()
+		}
+	}
+
+	this.partitions = nil
+	this.probes = nil
+}
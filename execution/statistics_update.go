@@ -0,0 +1,224 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package execution
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/plan"
+	"github.com/couchbaselabs/query/value"
+)
+
+// Number of documents sampled if no sample_size is given
+const _STATISTICS_SAMPLE_SIZE = 10000
+
+type UpdateStatistics struct {
+	base
+	plan *plan.UpdateStatistics
+}
+
+func NewUpdateStatistics(plan *plan.UpdateStatistics) *UpdateStatistics {
+	rv := &UpdateStatistics{
+		base: newBase(),
+		plan: plan,
+	}
+
+	rv.output = rv
+	return rv
+}
+
+func (this *UpdateStatistics) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitUpdateStatistics(this)
+}
+
+func (this *UpdateStatistics) Copy() Operator {
+	return &UpdateStatistics{this.base.copy(), this.plan}
+}
+
+func (this *UpdateStatistics) RunOnce(context *Context, parent value.Value) {
+	this.once.Do(func() {
+		defer context.Recover()       // Recover from any panic
+		defer close(this.itemChannel) // Broadcast that I have stopped
+		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
+
+		if context.Readonly() {
+			return
+		}
+
+		keyspace := this.plan.Keyspace()
+		node := this.plan.Node()
+
+		if node.Delete() {
+			var terms []string
+			if node.Terms() != nil {
+				terms = make([]string, len(node.Terms()))
+				for i, term := range node.Terms() {
+					terms[i] = term.String()
+				}
+			}
+
+			err := keyspace.DeleteStatistics(terms)
+			if err != nil {
+				context.Error(err)
+				return
+			}
+
+			invalidateKeyspace(context, node.Keyspace())
+			return
+		}
+
+		sampleSize, err := statisticsSampleSize(node.With())
+		if err != nil {
+			context.Error(err)
+			return
+		}
+
+		stats, err := this.sample(context, keyspace, node.Terms(), sampleSize)
+		if err != nil {
+			context.Error(err)
+			return
+		}
+
+		if stats == nil {
+			// Stopped
+			return
+		}
+
+		err = keyspace.UpdateStatistics(stats)
+		if err != nil {
+			context.Error(err)
+			return
+		}
+
+		// Plans may change with the statistics
+		invalidateKeyspace(context, node.Keyspace())
+	})
+}
+
+// Sample documents of the keyspace uniformly at random, and compute
+// the statistics of each term over the sampled documents. The whole
+// primary index is scanned, keeping a reservoir of sampleSize keys,
+// so that the sample is not biased towards the lowest keys.
+func (this *UpdateStatistics) sample(context *Context, keyspace datastore.Keyspace,
+	terms expression.Expressions, sampleSize int64) ([]*datastore.TermStatistics, errors.Error) {
+	primary, err := onlinePrimaryIndex(keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := datastore.NewIndexConnection(context)
+	defer notifyConn(conn) // Notify index that I have stopped
+
+	go primary.ScanEntries(math.MaxInt64, context.ScanConsistency(), context.ScanVector(), conn)
+
+	keys := make([]string, 0, 256)
+	var scanned int64 = 0
+	ok := true
+	for ok {
+		var entry *datastore.IndexEntry
+
+		select {
+		case entry, ok = <-conn.EntryChannel():
+			if !ok {
+				break
+			}
+
+			// Each scanned key replaces a sampled key with
+			// probability sampleSize / scanned
+			if scanned < sampleSize {
+				keys = append(keys, entry.PrimaryKey)
+			} else if i := rand.Int63n(scanned + 1); i < sampleSize {
+				keys[i] = entry.PrimaryKey
+			}
+
+			scanned++
+		case <-this.stopChannel:
+			return nil, nil
+		}
+	}
+
+	pairs, err := keyspace.Fetch(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]value.Values, len(terms))
+	for _, pair := range pairs {
+		for i, term := range terms {
+			v, e := term.Evaluate(pair.Value, context)
+			if e != nil {
+				return nil, errors.NewError(e, "Error evaluating statistics term.")
+			}
+
+			if v.Type() != value.MISSING {
+				values[i] = append(values[i], v)
+			}
+		}
+	}
+
+	rv := make([]*datastore.TermStatistics, len(terms))
+	for i, term := range terms {
+		rv[i] = datastore.NewTermStatistics(term.String(), values[i], int64(len(pairs)))
+	}
+
+	return rv, nil
+}
+
+func onlinePrimaryIndex(keyspace datastore.Keyspace) (datastore.PrimaryIndex, errors.Error) {
+	indexers, err := keyspace.Indexers()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, indexer := range indexers {
+		indexes, err := indexer.PrimaryIndexes()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, index := range indexes {
+			state, _, err := index.State()
+			if err != nil {
+				return nil, err
+			}
+
+			if state == datastore.ONLINE {
+				return index, nil
+			}
+		}
+	}
+
+	return nil, errors.NewError(nil, fmt.Sprintf(
+		"No online primary index on keyspace %s. Use CREATE PRIMARY INDEX to create one.",
+		keyspace.Name()))
+}
+
+func statisticsSampleSize(with value.Value) (int64, errors.Error) {
+	if with == nil {
+		return _STATISTICS_SAMPLE_SIZE, nil
+	}
+
+	size, ok := with.Field("sample_size")
+	if !ok {
+		return _STATISTICS_SAMPLE_SIZE, nil
+	}
+
+	if size.Type() != value.NUMBER || size.Actual().(float64) < 1 {
+		return 0, errors.NewError(nil, "UPDATE STATISTICS sample_size must be a positive number.")
+	}
+
+	return int64(size.Actual().(float64)), nil
+}
@@ -23,6 +23,7 @@ type SendUpdate struct {
 	base
 	plan  *plan.SendUpdate
 	limit int64
+	count int64
 }
 
 func NewSendUpdate(plan *plan.SendUpdate) *SendUpdate {
@@ -41,7 +42,7 @@ func (this *SendUpdate) Accept(visitor Visitor) (interface{}, error) {
 }
 
 func (this *SendUpdate) Copy() Operator {
-	return &SendUpdate{this.base.copy(), this.plan, this.limit}
+	return &SendUpdate{this.base.copy(), this.plan, this.limit, 0}
 }
 
 func (this *SendUpdate) RunOnce(context *Context, parent value.Value) {
@@ -49,7 +50,16 @@ func (this *SendUpdate) RunOnce(context *Context, parent value.Value) {
 }
 
 func (this *SendUpdate) processItem(item value.AnnotatedValue, context *Context) bool {
-	rv := this.limit != 0 && this.enbatch(item, this, context)
+	if this.limit == 0 {
+		return false
+	}
+
+	this.count++
+	if !checkUpdateLimit(this.count, context) {
+		return false
+	}
+
+	rv := this.enbatch(item, this, context)
 
 	if this.limit > 0 {
 		this.limit--
@@ -77,7 +87,7 @@ func (this *SendUpdate) beforeItems(context *Context, parent value.Value) bool {
 		return false
 	}
 
-	return true
+	return checkUpdateLimit(this.limit, context)
 }
 
 func (this *SendUpdate) afterItems(context *Context) {
@@ -109,6 +119,10 @@ func (this *SendUpdate) flushBatch(context *Context) bool {
 		}
 	}
 
+	if !context.ReserveMutations(int64(len(pairs))) {
+		return false
+	}
+
 	pairs, e := this.plan.Keyspace().Update(pairs)
 
 	// Update mutation count with number of updated docs
@@ -119,6 +119,10 @@ func (this *SendUpsert) flushBatch(context *Context) bool {
 	dpairs = dpairs[0:i]
 	this.batch = nil
 
+	if !context.ReserveMutations(int64(len(dpairs))) {
+		return false
+	}
+
 	// Perform the actual UPSERT
 	keys, e := this.plan.Keyspace().Upsert(dpairs)
 
@@ -9,6 +9,10 @@
 
 package execution
 
+import (
+	"github.com/couchbaselabs/query/errors"
+)
+
 func notifyChildren(children ...Operator) {
 	for _, child := range children {
 		if child != nil {
@@ -27,3 +31,15 @@ func copyOperator(op Operator) Operator {
 		return op.Copy()
 	}
 }
+
+// Fail the request if a DELETE or UPDATE would mutate more documents
+// than the update limit
+func checkUpdateLimit(limit int64, context *Context) bool {
+	updateLimit := context.UpdateLimit()
+	if updateLimit > 0 && limit > updateLimit {
+		context.Error(errors.NewUpdateLimitExceededError(limit, updateLimit))
+		return false
+	}
+
+	return true
+}
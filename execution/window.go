@@ -0,0 +1,128 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package execution
+
+import (
+	"github.com/couchbaselabs/query/algebra"
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/plan"
+	"github.com/couchbaselabs/query/value"
+)
+
+// Computes window functions over all the input items, and attaches
+// the results to the items, which are then sent in their input order.
+type Window struct {
+	base
+	plan   *plan.Window
+	values value.AnnotatedValues
+	size   int64
+}
+
+const _WINDOW_CAP = 1024
+
+func NewWindow(plan *plan.Window) *Window {
+	rv := &Window{
+		base:   newBase(),
+		plan:   plan,
+		values: make(value.AnnotatedValues, 0, _WINDOW_CAP),
+	}
+
+	rv.output = rv
+	return rv
+}
+
+func (this *Window) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitWindow(this)
+}
+
+func (this *Window) Copy() Operator {
+	return &Window{
+		base:   this.base.copy(),
+		plan:   this.plan,
+		values: make(value.AnnotatedValues, 0, _WINDOW_CAP),
+	}
+}
+
+func (this *Window) RunOnce(context *Context, parent value.Value) {
+	this.runConsumer(this, context, parent)
+}
+
+func (this *Window) processItem(item value.AnnotatedValue, context *Context) bool {
+	if len(this.values) == cap(this.values) {
+		values := make(value.AnnotatedValues, len(this.values), len(this.values)<<1)
+		copy(values, this.values)
+		this.values = values
+	}
+
+	this.values = append(this.values, item)
+
+	if !context.TracksMemory() {
+		return true
+	}
+
+	size := estimateSize(item)
+	this.size += size
+	return context.TrackMemory(size)
+}
+
+func (this *Window) afterItems(context *Context) {
+	defer func() {
+		context.ReleaseMemory(this.size)
+		this.values = nil
+		this.size = 0
+	}()
+
+	for _, av := range this.values {
+		av.SetAttachment("windows", make(map[string]value.Value, len(this.plan.Functions())))
+	}
+
+	// Partition once for all the functions that share an OVER clause
+	groups := make(map[string]algebra.WindowFunctions, len(this.plan.Functions()))
+	order := make([]string, 0, len(this.plan.Functions()))
+	for _, fn := range this.plan.Functions() {
+		over := fn.OverClause()
+		if _, ok := groups[over]; !ok {
+			order = append(order, over)
+		}
+
+		groups[over] = append(groups[over], fn)
+	}
+
+	for _, over := range order {
+		fns := groups[over]
+		partitions, err := fns[0].Window().Partition(this.values, context)
+		if err != nil {
+			context.Error(errors.NewError(err, "Error partitioning window."))
+			return
+		}
+
+		for _, fn := range fns {
+			name := fn.String()
+			for _, partition := range partitions {
+				results, err := fn.Compute(partition, context)
+				if err != nil {
+					context.Error(errors.NewError(err, "Error computing window function."))
+					return
+				}
+
+				for i, row := range partition.Rows() {
+					windows := row.GetAttachment("windows").(map[string]value.Value)
+					windows[name] = results[i]
+				}
+			}
+		}
+	}
+
+	for _, av := range this.values {
+		if !this.sendItem(av) {
+			return
+		}
+	}
+}
@@ -0,0 +1,188 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package execution
+
+import (
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/plan"
+	"github.com/couchbaselabs/query/value"
+)
+
+// Maximum number of iterations of a recursive WITH term
+const _MAX_WITH_DEPTH = 100
+
+// Materialize the results of the WITH terms, and run the child with
+// the results in scope
+type With struct {
+	base
+	plan         *plan.With
+	child        Operator
+	childChannel StopChannel
+}
+
+func NewWith(plan *plan.With, child Operator) *With {
+	rv := &With{
+		base:         newBase(),
+		plan:         plan,
+		child:        child,
+		childChannel: make(StopChannel, 1),
+	}
+
+	rv.output = rv
+	return rv
+}
+
+func (this *With) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitWith(this)
+}
+
+func (this *With) Copy() Operator {
+	return &With{
+		base:         this.base.copy(),
+		plan:         this.plan,
+		child:        this.child.Copy(),
+		childChannel: make(StopChannel, 1),
+	}
+}
+
+func (this *With) RunOnce(context *Context, parent value.Value) {
+	this.once.Do(func() {
+		defer context.Recover()       // Recover from any panic
+		defer close(this.itemChannel) // Broadcast that I have stopped
+		defer this.notify()           // Notify that I have stopped
+		defer this.addRunTime(this.profileTime())
+
+		cv := value.NewScopeValue(make(map[string]interface{}, len(this.plan.Terms())), parent)
+		for _, term := range this.plan.Terms() {
+			results, ok := this.evaluateTerm(term, context, cv)
+			if !ok {
+				return
+			}
+
+			cv.SetField(term.Alias(), results)
+		}
+
+		this.child.SetInput(this.input)
+		this.child.SetOutput(this.output)
+		this.child.SetStop(nil)
+		this.child.SetParent(this)
+
+		go this.child.RunOnce(context, cv)
+
+		for {
+			select {
+			case <-this.childChannel: // Never closed
+				// Wait for child
+				return
+			case <-this.stopChannel: // Never closed
+				this.notifyStop()
+				notifyChildren(this.child)
+			}
+		}
+	})
+}
+
+func (this *With) ChildChannel() StopChannel {
+	return this.childChannel
+}
+
+// Evaluate a WITH term once per request
+func (this *With) evaluateTerm(term *plan.WithTerm, context *Context, parent value.Value) (
+	value.Value, bool) {
+	subresult, ok := context.subresults.get(term)
+	if ok {
+		return subresult.(value.Value), true
+	}
+
+	results, e := context.evaluatePlan(term.Query(), parent)
+	if e != nil {
+		context.Error(errors.NewWithEvaluationError(e, term.Alias()))
+		return nil, false
+	}
+
+	if term.Step() != nil {
+		results, ok = this.evaluateRecursive(term, results, context, parent)
+		if !ok {
+			return nil, false
+		}
+	}
+
+	context.subresults.set(term, results)
+	return results, true
+}
+
+// Evaluate the step of a recursive term against the results of the
+// previous iteration, until there are no new results
+func (this *With) evaluateRecursive(term *plan.WithTerm, anchor value.Value, context *Context,
+	parent value.Value) (value.Value, bool) {
+	var set *value.Set
+	if term.Distinct() {
+		set = value.NewSet(_COLLECT_CAP)
+	}
+
+	results, ok := this.accumulate(nil, anchor, set, context)
+	if !ok {
+		return nil, false
+	}
+
+	delta := results
+
+	for depth := 0; len(delta) > 0; depth++ {
+		if depth >= _MAX_WITH_DEPTH {
+			context.Error(errors.NewWithDepthExceededError(term.Alias(), _MAX_WITH_DEPTH))
+			return nil, false
+		}
+
+		scope := value.NewScopeValue(make(map[string]interface{}, 1), parent)
+		scope.SetField(term.Alias(), delta)
+
+		next, e := context.evaluatePlan(term.Step(), scope)
+		if e != nil {
+			context.Error(errors.NewWithEvaluationError(e, term.Alias()))
+			return nil, false
+		}
+
+		n := len(results)
+		results, ok = this.accumulate(results, next, set, context)
+		if !ok {
+			return nil, false
+		}
+
+		delta = results[n:len(results):len(results)]
+	}
+
+	return value.NewValue(results), true
+}
+
+// Append the elements of values to results, skipping duplicates if
+// set is not nil. The working set is held for the rest of the
+// request, so its memory is tracked but not released.
+func (this *With) accumulate(results []interface{}, values value.Value, set *value.Set,
+	context *Context) ([]interface{}, bool) {
+	elems, _ := values.Actual().([]interface{})
+	for _, elem := range elems {
+		v := value.NewValue(elem)
+		if set != nil {
+			if set.Has(v) {
+				continue
+			}
+
+			set.Add(v)
+		}
+
+		results = append(results, elem)
+
+		if context.TracksMemory() && !context.TrackMemory(estimateSize(value.NewAnnotatedValue(v))) {
+			return nil, false
+		}
+	}
+
+	return results, true
+}
@@ -21,6 +21,7 @@ Array is a struct that implements collMap.
 */
 type Array struct {
 	collMap
+	distinct bool
 }
 
 /*
@@ -41,6 +42,18 @@ func NewArray(mapping Expression, bindings Bindings, when Expression) Expression
 	return rv
 }
 
+/*
+This method returns an Array that removes duplicate values from
+its result. DISTINCT ARRAY is only allowed as an index key, where
+it defines an array index with one entry for each distinct
+element.
+*/
+func NewDistinctArray(mapping Expression, bindings Bindings, when Expression) Expression {
+	rv := NewArray(mapping, bindings, when).(*Array)
+	rv.distinct = true
+	return rv
+}
+
 /*
 It calls the VisitArray method by passing in the receiver to
 and returns the interface. It is a visitor pattern.
@@ -136,9 +149,53 @@ func (this *Array) Evaluate(item value.Value, context Context) (value.Value, err
 		}
 	}
 
+	if this.distinct {
+		rv = distinctValues(rv)
+	}
+
 	return value.NewValue(rv), nil
 }
 
+func distinctValues(vals []interface{}) []interface{} {
+	rv := vals[:0]
+	for _, v := range vals {
+		found := false
+		for _, d := range rv {
+			if v.(value.Value).Equals(d.(value.Value)) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			rv = append(rv, v)
+		}
+	}
+
+	return rv
+}
+
+/*
+An array is equivalent only to another array with the same
+distinctness, so that an array index key is never used for an
+ordinary ARRAY expression.
+*/
+func (this *Array) EquivalentTo(other Expression) bool {
+	o, ok := other.(*Array)
+	return ok && this.distinct == o.distinct && this.collMap.EquivalentTo(other)
+}
+
+/*
+Returns true for DISTINCT ARRAY.
+*/
+func (this *Array) Distinct() bool {
+	return this.distinct
+}
+
 func (this *Array) Copy() Expression {
+	if this.distinct {
+		return NewDistinctArray(this.mapping.Copy(), this.bindings.Copy(), Copy(this.when))
+	}
+
 	return NewArray(this.mapping.Copy(), this.bindings.Copy(), Copy(this.when))
 }
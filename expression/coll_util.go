@@ -77,6 +77,20 @@ func (this *collMap) Bindings() Bindings {
 	return this.bindings
 }
 
+/*
+Return the mapping expression.
+*/
+func (this *collMap) Mapping() Expression {
+	return this.mapping
+}
+
+/*
+Return the when condition, or nil.
+*/
+func (this *collMap) When() Expression {
+	return this.when
+}
+
 /*
 Type collPred represents a struct that implements ExpressionBase.
 It refers to the fields or attributes of a collection or map
@@ -127,3 +141,10 @@ Return receiver bindings.
 func (this *collPred) Bindings() Bindings {
 	return this.bindings
 }
+
+/*
+Return the satisfies condition.
+*/
+func (this *collPred) Satisfies() Expression {
+	return this.satisfies
+}
@@ -94,6 +94,82 @@ func (this *Base64) Constructor() FunctionConstructor {
 	}
 }
 
+///////////////////////////////////////////////////
+//
+// Cover
+//
+///////////////////////////////////////////////////
+
+/*
+This represents the Meta function COVER(expr). It marks an
+expression whose value is provided by an index entry, so that
+the document need not be fetched. Index scans attach the values
+of covered expressions to each item under the attachment
+"covers", keyed by the text of the expression. Type Cover is a
+struct that implements UnaryFunctionBase.
+*/
+type Cover struct {
+	UnaryFunctionBase
+	text string
+}
+
+/*
+The function NewCover takes as input an expression and returns
+a pointer to the Cover struct that calls NewUnaryFunctionBase to
+create a function named COVER with an input operand as the
+expression.
+*/
+func NewCover(operand Expression) Function {
+	rv := &Cover{
+		*NewUnaryFunctionBase("cover", operand),
+		operand.String(),
+	}
+
+	rv.expr = rv
+	return rv
+}
+
+/*
+It calls the VisitFunction method by passing in the receiver to
+and returns the interface. It is a visitor pattern.
+*/
+func (this *Cover) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+/*
+It returns the type of the covered expression.
+*/
+func (this *Cover) Type() value.Type { return this.operands[0].Type() }
+
+/*
+Return the covered value attached to the item. If the item
+carries no value for the expression, evaluate the expression
+itself.
+*/
+func (this *Cover) Evaluate(item value.Value, context Context) (value.Value, error) {
+	if av, ok := item.(value.AnnotatedValue); ok {
+		covers, ok := av.GetAttachment("covers").(map[string]value.Value)
+		if ok {
+			if v, ok := covers[this.text]; ok {
+				return v, nil
+			}
+		}
+	}
+
+	return this.operands[0].Evaluate(item, context)
+}
+
+/*
+The constructor returns a NewCover with an operand cast to a
+Function as the FunctionConstructor.
+*/
+func (this *Cover) Constructor() FunctionConstructor {
+	return func(operands ...Expression) Function {
+		return NewCover(operands[0])
+	}
+}
+
 ///////////////////////////////////////////////////
 //
 // Meta
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/couchbaselabs/query/util"
+	"github.com/couchbaselabs/query/value"
 )
 
 // Define the pattern for UUIDs - RFC 4122, version 4
@@ -35,3 +36,25 @@ func TestNewV4_eval(t *testing.T) {
 	fmt.Printf("\t UUID:  %v \n", u.Actual())
 
 }
+
+func TestCover(t *testing.T) {
+	key := NewField(NewIdentifier("k"), NewFieldName("a"))
+	cover := NewCover(key.Copy())
+
+	doc := value.NewValue(map[string]interface{}{
+		"k": map[string]interface{}{"a": "doc"},
+	})
+	av := value.NewAnnotatedValue(doc)
+
+	v, err := cover.Evaluate(av, nil)
+	if err != nil || v.Actual() != "doc" {
+		t.Errorf("Expected uncovered value doc, got %v, %v", v, err)
+	}
+
+	av.SetAttachment("covers", map[string]value.Value{key.String(): value.NewValue("index")})
+
+	v, err = cover.Evaluate(av, nil)
+	if err != nil || v.Actual() != "index" {
+		t.Errorf("Expected covered value index, got %v, %v", v, err)
+	}
+}
@@ -19,11 +19,21 @@ Based on the input string name it looks through a map and
 retrieves the function that corresponds to it. If the
 function exists it returns true and the function. While
 looking into the map, convert the string name to lower
-case.
+case. Built-in functions take precedence over user-defined
+functions.
 */
 func GetFunction(name string) (Function, bool) {
 	rv, ok := _FUNCTIONS[strings.ToLower(name)]
-	return rv, ok
+	if ok {
+		return rv, ok
+	}
+
+	defs := userFunctionDefinitions(name)
+	if len(defs) > 0 {
+		return NewUserFunction(defs[0].Name()), true
+	}
+
+	return nil, false
 }
 
 /*
@@ -215,6 +225,7 @@ var _FUNCTIONS = map[string]Function{
 
 	// Meta
 	"base64": &Base64{},
+	"cover":  &Cover{},
 	"meta":   &Meta{},
 	"self":   &Self{},
 	"uuid":   &Uuid{},
@@ -0,0 +1,408 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/couchbaselabs/query/value"
+)
+
+/*
+Represents the definition of a user-defined function, created
+with CREATE FUNCTION. The body is an expression over the
+parameters.
+*/
+type UserFunctionDefinition struct {
+	namespace  string
+	name       string
+	parameters []string
+	body       Expression
+}
+
+/*
+The function NewUserFunctionDefinition returns a pointer to the
+UserFunctionDefinition struct by assigning the input attributes
+to the fields of the struct.
+*/
+func NewUserFunctionDefinition(namespace, name string, parameters []string,
+	body Expression) *UserFunctionDefinition {
+	return &UserFunctionDefinition{
+		namespace:  namespace,
+		name:       name,
+		parameters: parameters,
+		body:       body,
+	}
+}
+
+/*
+Returns the namespace in which the function is defined.
+*/
+func (this *UserFunctionDefinition) Namespace() string {
+	return this.namespace
+}
+
+/*
+Returns the name of the function.
+*/
+func (this *UserFunctionDefinition) Name() string {
+	return this.name
+}
+
+/*
+Returns the names of the parameters.
+*/
+func (this *UserFunctionDefinition) Parameters() []string {
+	return this.parameters
+}
+
+/*
+Returns the body of the function.
+*/
+func (this *UserFunctionDefinition) Body() Expression {
+	return this.body
+}
+
+/*
+The registry of user-defined functions, keyed by namespace and then
+by lower case name. User-defined functions share a single name space
+with the built-in functions, so that the parser can resolve them; a
+call is bound to a namespace when it is evaluated.
+*/
+var _USER_FUNCTIONS = struct {
+	sync.RWMutex
+	namespaces map[string]map[string]*UserFunctionDefinition
+}{
+	namespaces: make(map[string]map[string]*UserFunctionDefinition),
+}
+
+/*
+Adds or replaces a user-defined function. Calls in the body of the
+function are bound to the namespace of the function.
+*/
+func RegisterUserFunction(definition *UserFunctionDefinition) {
+	bindUserFunctions(definition.namespace, definition.body)
+
+	_USER_FUNCTIONS.Lock()
+	defer _USER_FUNCTIONS.Unlock()
+	functions, ok := _USER_FUNCTIONS.namespaces[definition.namespace]
+	if !ok {
+		functions = make(map[string]*UserFunctionDefinition)
+		_USER_FUNCTIONS.namespaces[definition.namespace] = functions
+	}
+
+	functions[strings.ToLower(definition.name)] = definition
+}
+
+/*
+Removes a user-defined function from a namespace.
+*/
+func UnregisterUserFunction(namespace, name string) {
+	_USER_FUNCTIONS.Lock()
+	defer _USER_FUNCTIONS.Unlock()
+	functions, ok := _USER_FUNCTIONS.namespaces[namespace]
+	if !ok {
+		return
+	}
+
+	delete(functions, strings.ToLower(name))
+	if len(functions) == 0 {
+		delete(_USER_FUNCTIONS.namespaces, namespace)
+	}
+}
+
+/*
+Returns the definition of a user-defined function in a namespace.
+*/
+func GetUserFunction(namespace, name string) (*UserFunctionDefinition, bool) {
+	_USER_FUNCTIONS.RLock()
+	defer _USER_FUNCTIONS.RUnlock()
+	rv, ok := _USER_FUNCTIONS.namespaces[namespace][strings.ToLower(name)]
+	return rv, ok
+}
+
+/*
+Returns the definitions of a user-defined function in all namespaces,
+in namespace order.
+*/
+func userFunctionDefinitions(name string) []*UserFunctionDefinition {
+	_USER_FUNCTIONS.RLock()
+	defer _USER_FUNCTIONS.RUnlock()
+
+	name = strings.ToLower(name)
+	rv := make([]*UserFunctionDefinition, 0, len(_USER_FUNCTIONS.namespaces))
+	for _, functions := range _USER_FUNCTIONS.namespaces {
+		if def, ok := functions[name]; ok {
+			rv = append(rv, def)
+		}
+	}
+
+	sort.Sort(definitionsByNamespace(rv))
+	return rv
+}
+
+type definitionsByNamespace []*UserFunctionDefinition
+
+func (this definitionsByNamespace) Len() int {
+	return len(this)
+}
+
+func (this definitionsByNamespace) Less(i, j int) bool {
+	return this[i].namespace < this[j].namespace
+}
+
+func (this definitionsByNamespace) Swap(i, j int) {
+	this[i], this[j] = this[j], this[i]
+}
+
+/*
+Binds the calls of user-defined functions in expr that are not bound
+yet to the given namespace.
+*/
+func bindUserFunctions(namespace string, expr Expression) {
+	if expr == nil {
+		return
+	}
+
+	if call, ok := expr.(*UserFunction); ok && call.namespace == "" {
+		call.namespace = namespace
+	}
+
+	for _, child := range expr.Children() {
+		bindUserFunctions(namespace, child)
+	}
+}
+
+/*
+Returns true if name is the name of a built-in function.
+*/
+func IsBuiltinFunction(name string) bool {
+	_, ok := _FUNCTIONS[strings.ToLower(name)]
+	return ok
+}
+
+/*
+Returns an error if a function of a namespace, with the given name
+and body, would call itself, directly or through other user-defined
+functions.
+*/
+func CheckUserFunctionRecursion(namespace, name string, body Expression) error {
+	return checkRecursion(namespace, strings.ToLower(name), namespace, body, make(map[string]bool))
+}
+
+func checkRecursion(namespace, name, caller string, expr Expression, visited map[string]bool) error {
+	if expr == nil {
+		return nil
+	}
+
+	if call, ok := expr.(*UserFunction); ok {
+		callee := strings.ToLower(call.Name())
+		calleeNamespace := call.namespace
+		if calleeNamespace == "" {
+			calleeNamespace = caller
+		}
+
+		if callee == name && calleeNamespace == namespace {
+			return fmt.Errorf("Recursive definition of function %s.", name)
+		}
+
+		key := calleeNamespace + ":" + callee
+		if !visited[key] {
+			visited[key] = true
+			def, ok := GetUserFunction(calleeNamespace, callee)
+			if ok {
+				err := checkRecursion(namespace, name, calleeNamespace, def.body, visited)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, child := range expr.Children() {
+		err := checkRecursion(namespace, name, caller, child, visited)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+A context that names the namespace in which calls of user-defined
+functions are resolved, such as the default namespace of a request.
+*/
+type NamespaceContext interface {
+	Context
+	Namespace() string
+}
+
+/*
+This represents a call of a user-defined function. The definition
+is looked up when the call is evaluated, so that calls see the
+latest definition of the function. Calls in queries are resolved in
+the namespace of the context, and calls in the body of a function
+are bound to the namespace of that function.
+*/
+type UserFunction struct {
+	FunctionBase
+	namespace string
+}
+
+/*
+The function NewUserFunction calls NewFunctionBase to create a
+call of the named user-defined function.
+*/
+func NewUserFunction(name string, operands ...Expression) Function {
+	rv := &UserFunction{
+		FunctionBase: *NewFunctionBase(name, operands...),
+	}
+
+	rv.expr = rv
+	return rv
+}
+
+/*
+It calls the VisitFunction method by passing in the receiver to
+and returns the interface. It is a visitor pattern.
+*/
+func (this *UserFunction) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+/*
+It returns a value type JSON.
+*/
+func (this *UserFunction) Type() value.Type { return value.JSON }
+
+/*
+Calls the Eval method for the receiver and passes in the
+receiver, current item and current context.
+*/
+func (this *UserFunction) Evaluate(item value.Value, context Context) (value.Value, error) {
+	return this.Eval(this, item, context)
+}
+
+/*
+Evaluates the body of the function, with the parameters bound to
+the arguments.
+*/
+func (this *UserFunction) Apply(context Context, args ...value.Value) (value.Value, error) {
+	def, err := this.definition(context)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) != len(def.parameters) {
+		return nil, fmt.Errorf("Wrong number of arguments to function %s.", this.name)
+	}
+
+	scope := value.NewScopeValue(make(map[string]interface{}, len(args)), nil)
+	for i, param := range def.parameters {
+		scope.SetField(param, args[i])
+	}
+
+	return def.body.Evaluate(scope, context)
+}
+
+/*
+Minimum input arguments required is the number of parameters. An
+unbound call accepts the number of parameters of the function in
+any namespace; the number is checked again when it is evaluated.
+*/
+func (this *UserFunction) MinArgs() int {
+	min, _ := this.arity()
+	return min
+}
+
+/*
+Maximum input arguments allowed is the number of parameters.
+*/
+func (this *UserFunction) MaxArgs() int {
+	_, max := this.arity()
+	return max
+}
+
+/*
+Return a constructor of calls of the same function, bound to the
+same namespace.
+*/
+func (this *UserFunction) Constructor() FunctionConstructor {
+	name, namespace := this.name, this.namespace
+	return func(operands ...Expression) Function {
+		rv := NewUserFunction(name, operands...).(*UserFunction)
+		rv.namespace = namespace
+		return rv
+	}
+}
+
+/*
+Returns the definition of the function in its namespace. An unbound
+call is resolved in the namespace of the context, or else in the
+only namespace that defines the function.
+*/
+func (this *UserFunction) definition(context Context) (*UserFunctionDefinition, error) {
+	var def *UserFunctionDefinition
+	ok := false
+
+	if this.namespace != "" {
+		def, ok = GetUserFunction(this.namespace, this.name)
+	} else {
+		if nc, isNamespace := context.(NamespaceContext); isNamespace {
+			def, ok = GetUserFunction(nc.Namespace(), this.name)
+		}
+
+		if !ok {
+			defs := userFunctionDefinitions(this.name)
+			if len(defs) > 1 {
+				return nil, fmt.Errorf("Function %s is defined in several namespaces.", this.name)
+			}
+
+			if len(defs) == 1 {
+				def, ok = defs[0], true
+			}
+		}
+	}
+
+	if !ok || def.body == nil {
+		return nil, fmt.Errorf("Function %s not found.", this.name)
+	}
+
+	return def, nil
+}
+
+func (this *UserFunction) arity() (min, max int) {
+	if this.namespace != "" {
+		def, ok := GetUserFunction(this.namespace, this.name)
+		if !ok {
+			return 0, 0
+		}
+
+		return len(def.parameters), len(def.parameters)
+	}
+
+	defs := userFunctionDefinitions(this.name)
+	for i, def := range defs {
+		n := len(def.parameters)
+		if i == 0 || n < min {
+			min = n
+		}
+
+		if i == 0 || n > max {
+			max = n
+		}
+	}
+
+	return min, max
+}
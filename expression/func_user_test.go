@@ -0,0 +1,133 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package expression
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/query/value"
+)
+
+type namespaceContext string
+
+func (this namespaceContext) Now() time.Time {
+	return time.Now()
+}
+
+func (this namespaceContext) Namespace() string {
+	return string(this)
+}
+
+func evaluateCall(t *testing.T, call Expression, namespace string) interface{} {
+	rv, err := call.Evaluate(nil, namespaceContext(namespace))
+	if err != nil {
+		t.Fatalf("failed to evaluate %v in %s: %v", call, namespace, err)
+	}
+
+	return rv.Actual()
+}
+
+func TestUserFunctionNamespaces(t *testing.T) {
+	// f(x) is x + 1 in ns1 and x + 2 in ns2
+	RegisterUserFunction(NewUserFunctionDefinition("ns1", "f", []string{"x"},
+		NewAdd(NewIdentifier("x"), NewConstant(1))))
+	RegisterUserFunction(NewUserFunctionDefinition("ns2", "f", []string{"x"},
+		NewAdd(NewIdentifier("x"), NewConstant(2))))
+	defer UnregisterUserFunction("ns1", "f")
+	defer UnregisterUserFunction("ns2", "f")
+
+	call := NewUserFunction("f", NewConstant(1))
+	if rv := evaluateCall(t, call, "ns1"); rv != 2.0 {
+		t.Errorf("expected 2 in ns1, got %v", rv)
+	}
+
+	if rv := evaluateCall(t, call, "ns2"); rv != 3.0 {
+		t.Errorf("expected 3 in ns2, got %v", rv)
+	}
+
+	// Defined in several namespaces, but not in ns3
+	_, err := call.Evaluate(nil, namespaceContext("ns3"))
+	if err == nil {
+		t.Errorf("expected an error resolving f in ns3")
+	}
+
+	// Calls in the body of g are bound to the namespace of g
+	RegisterUserFunction(NewUserFunctionDefinition("ns2", "g", []string{"x"},
+		NewUserFunction("f", NewIdentifier("x"))))
+	defer UnregisterUserFunction("ns2", "g")
+
+	if rv := evaluateCall(t, NewUserFunction("g", NewConstant(1)), "ns1"); rv != 3.0 {
+		t.Errorf("expected g to call f of ns2, got %v", rv)
+	}
+
+	UnregisterUserFunction("ns1", "f")
+	if _, ok := GetUserFunction("ns1", "f"); ok {
+		t.Errorf("expected f to be removed from ns1")
+	}
+
+	// Defined in ns2 only, so the call falls back to ns2
+	if rv := evaluateCall(t, call, "ns1"); rv != 3.0 {
+		t.Errorf("expected 3 from ns2, got %v", rv)
+	}
+}
+
+func TestUserFunctionArity(t *testing.T) {
+	RegisterUserFunction(NewUserFunctionDefinition("ns1", "h", []string{"x"},
+		NewIdentifier("x")))
+	RegisterUserFunction(NewUserFunctionDefinition("ns2", "h", []string{"x", "y"},
+		NewIdentifier("y")))
+	defer UnregisterUserFunction("ns1", "h")
+	defer UnregisterUserFunction("ns2", "h")
+
+	call := NewUserFunction("h").(*UserFunction)
+	if call.MinArgs() != 1 || call.MaxArgs() != 2 {
+		t.Errorf("expected 1 to 2 arguments, got %d to %d", call.MinArgs(), call.MaxArgs())
+	}
+
+	call.namespace = "ns2"
+	if call.MinArgs() != 2 || call.MaxArgs() != 2 {
+		t.Errorf("expected 2 arguments in ns2, got %d to %d", call.MinArgs(), call.MaxArgs())
+	}
+
+	// The arguments are checked against the definition that is used
+	_, err := NewUserFunction("h", NewConstant(1), NewConstant(2)).Evaluate(nil, namespaceContext("ns1"))
+	if err == nil {
+		t.Errorf("expected an error calling h of ns1 with 2 arguments")
+	}
+
+	rv, err := NewUserFunction("h", NewConstant(1), NewConstant(2)).Evaluate(nil, namespaceContext("ns2"))
+	if err != nil || !rv.Equals(value.NewValue(2)) {
+		t.Errorf("expected 2 from h of ns2, got %v %v", rv, err)
+	}
+}
+
+func TestUserFunctionRecursion(t *testing.T) {
+	// a of ns1 calls b, which is defined in ns1 only
+	RegisterUserFunction(NewUserFunctionDefinition("ns1", "b", []string{"x"},
+		NewUserFunction("a", NewIdentifier("x"))))
+	defer UnregisterUserFunction("ns1", "b")
+
+	err := CheckUserFunctionRecursion("ns1", "a", NewUserFunction("b", NewConstant(1)))
+	if err == nil {
+		t.Errorf("expected a of ns1 to be recursive through b")
+	}
+
+	// a of ns2 calls b of ns2, which does not exist
+	err = CheckUserFunctionRecursion("ns2", "a", NewUserFunction("b", NewConstant(1)))
+	if err != nil {
+		t.Errorf("expected a of ns2 not to be recursive, got %v", err)
+	}
+
+	err = CheckUserFunctionRecursion("ns2", "a", NewUserFunction("a", NewConstant(1)))
+	if err == nil {
+		t.Errorf("expected a calling itself to be recursive")
+	}
+}
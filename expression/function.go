@@ -67,6 +67,23 @@ type Function interface {
 	Constructor() FunctionConstructor
 }
 
+/*
+A window function is a function followed by an OVER clause. The
+clause is defined outside this package, and is included in the
+string representation of the function.
+*/
+type WindowFunction interface {
+	/*
+	   Inherits from Function.
+	*/
+	Function
+
+	/*
+	   Returns the contents of the OVER clause as a N1QL string.
+	*/
+	OverClause() string
+}
+
 /*
 FunctionConstructor enables dynamic construction of functions.
 It represents a function that takes input expressions as
@@ -235,3 +235,12 @@ Return the name of the Field as its Alias.
 func (this *FieldName) Alias() string {
 	return this.name
 }
+
+/*
+Field names are immutable, so return the receiver. Copy is
+defined here so that a copy remains a FieldName rather than the
+embedded Constant.
+*/
+func (this *FieldName) Copy() Expression {
+	return this
+}
@@ -165,6 +165,10 @@ func (this *Stringer) VisitAny(expr *Any) (interface{}, error) {
 
 func (this *Stringer) VisitArray(expr *Array) (interface{}, error) {
 	var buf bytes.Buffer
+	if expr.distinct {
+		buf.WriteString("distinct ")
+	}
+
 	buf.WriteString("array ")
 	buf.WriteString(this.Visit(expr.mapping))
 	buf.WriteString(" for ")
@@ -532,6 +536,13 @@ func (this *Stringer) VisitFunction(expr Function) (interface{}, error) {
 	}
 
 	buf.WriteString(")")
+
+	if window, ok := expr.(WindowFunction); ok {
+		buf.WriteString(" over (")
+		buf.WriteString(window.OverClause())
+		buf.WriteString(")")
+	}
+
 	return buf.String(), nil
 }
 
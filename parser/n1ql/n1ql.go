@@ -30,9 +30,14 @@ func ParseStatement(input string) (algebra.Statement, error) {
 		err := lex.stmt.Formalize()
 		if err != nil {
 			return nil, err
-		} else {
-			return lex.stmt, nil
 		}
+
+		prepare, ok := lex.stmt.(*algebra.Prepare)
+		if ok {
+			prepare.SetText(input)
+		}
+
+		return lex.stmt, nil
 	}
 }
 
@@ -5626,6 +5626,146 @@ func(r rune) int {
 },
 }, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1, -1, -1,},nil},
 
+// [cC][uU][rR][rR][eE][nN][tT]
+{[]bool{false, false, false, false, false, false, false, true}, []func(rune) int{  // Transitions
+func(r rune) int {
+	switch(r) {
+		case 99: return 1
+		case 67: return 1
+		case 117: return -1
+		case 85: return -1
+		case 114: return -1
+		case 82: return -1
+		case 101: return -1
+		case 69: return -1
+		case 110: return -1
+		case 78: return -1
+		case 116: return -1
+		case 84: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 99: return -1
+		case 67: return -1
+		case 117: return 2
+		case 85: return 2
+		case 114: return -1
+		case 82: return -1
+		case 101: return -1
+		case 69: return -1
+		case 110: return -1
+		case 78: return -1
+		case 116: return -1
+		case 84: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 99: return -1
+		case 67: return -1
+		case 117: return -1
+		case 85: return -1
+		case 114: return 3
+		case 82: return 3
+		case 101: return -1
+		case 69: return -1
+		case 110: return -1
+		case 78: return -1
+		case 116: return -1
+		case 84: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 99: return -1
+		case 67: return -1
+		case 117: return -1
+		case 85: return -1
+		case 114: return 4
+		case 82: return 4
+		case 101: return -1
+		case 69: return -1
+		case 110: return -1
+		case 78: return -1
+		case 116: return -1
+		case 84: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 99: return -1
+		case 67: return -1
+		case 117: return -1
+		case 85: return -1
+		case 114: return -1
+		case 82: return -1
+		case 101: return 5
+		case 69: return 5
+		case 110: return -1
+		case 78: return -1
+		case 116: return -1
+		case 84: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 99: return -1
+		case 67: return -1
+		case 117: return -1
+		case 85: return -1
+		case 114: return -1
+		case 82: return -1
+		case 101: return -1
+		case 69: return -1
+		case 110: return 6
+		case 78: return 6
+		case 116: return -1
+		case 84: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 99: return -1
+		case 67: return -1
+		case 117: return -1
+		case 85: return -1
+		case 114: return -1
+		case 82: return -1
+		case 101: return -1
+		case 69: return -1
+		case 110: return -1
+		case 78: return -1
+		case 116: return 7
+		case 84: return 7
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 99: return -1
+		case 67: return -1
+		case 117: return -1
+		case 85: return -1
+		case 114: return -1
+		case 82: return -1
+		case 101: return -1
+		case 69: return -1
+		case 110: return -1
+		case 78: return -1
+		case 116: return -1
+		case 84: return -1
+	}
+	return -1
+},
+}, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1, -1, -1, -1,},nil},
+
 // [dD][aA][tT][aA][bB][aA][sS][eE]
 {[]bool{false, false, false, false, false, false, false, false, true}, []func(rune) int{  // Transitions
 func(r rune) int {
@@ -8503,16 +8643,24 @@ func(r rune) int {
 },
 }, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1, -1, -1, -1,},nil},
 
-// [fF][oO][rR]
-{[]bool{false, false, false, true}, []func(rune) int{  // Transitions
+// [fF][oO][lL][lL][oO][wW][iI][nN][gG]
+{[]bool{false, false, false, false, false, false, false, false, false, true}, []func(rune) int{  // Transitions
 func(r rune) int {
 	switch(r) {
 		case 102: return 1
 		case 70: return 1
 		case 111: return -1
 		case 79: return -1
-		case 114: return -1
-		case 82: return -1
+		case 108: return -1
+		case 76: return -1
+		case 119: return -1
+		case 87: return -1
+		case 105: return -1
+		case 73: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
 	}
 	return -1
 },
@@ -8522,8 +8670,16 @@ func(r rune) int {
 		case 70: return -1
 		case 111: return 2
 		case 79: return 2
-		case 114: return -1
-		case 82: return -1
+		case 108: return -1
+		case 76: return -1
+		case 119: return -1
+		case 87: return -1
+		case 105: return -1
+		case 73: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
 	}
 	return -1
 },
@@ -8533,8 +8689,16 @@ func(r rune) int {
 		case 70: return -1
 		case 111: return -1
 		case 79: return -1
-		case 114: return 3
-		case 82: return 3
+		case 108: return 3
+		case 76: return 3
+		case 119: return -1
+		case 87: return -1
+		case 105: return -1
+		case 73: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
 	}
 	return -1
 },
@@ -8544,25 +8708,16 @@ func(r rune) int {
 		case 70: return -1
 		case 111: return -1
 		case 79: return -1
-		case 114: return -1
-		case 82: return -1
-	}
-	return -1
-},
-}, []int{  /* Start-of-input transitions */  -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1,},nil},
-
-// [fF][rR][oO][mM]
-{[]bool{false, false, false, false, true}, []func(rune) int{  // Transitions
-func(r rune) int {
-	switch(r) {
-		case 102: return 1
-		case 70: return 1
-		case 114: return -1
-		case 82: return -1
-		case 111: return -1
-		case 79: return -1
-		case 109: return -1
-		case 77: return -1
+		case 108: return 4
+		case 76: return 4
+		case 119: return -1
+		case 87: return -1
+		case 105: return -1
+		case 73: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
 	}
 	return -1
 },
@@ -8570,12 +8725,18 @@ func(r rune) int {
 	switch(r) {
 		case 102: return -1
 		case 70: return -1
-		case 114: return 2
-		case 82: return 2
-		case 111: return -1
-		case 79: return -1
-		case 109: return -1
-		case 77: return -1
+		case 111: return 5
+		case 79: return 5
+		case 108: return -1
+		case 76: return -1
+		case 119: return -1
+		case 87: return -1
+		case 105: return -1
+		case 73: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
 	}
 	return -1
 },
@@ -8583,12 +8744,18 @@ func(r rune) int {
 	switch(r) {
 		case 102: return -1
 		case 70: return -1
-		case 114: return -1
-		case 82: return -1
-		case 111: return 3
-		case 79: return 3
-		case 109: return -1
-		case 77: return -1
+		case 111: return -1
+		case 79: return -1
+		case 108: return -1
+		case 76: return -1
+		case 119: return 6
+		case 87: return 6
+		case 105: return -1
+		case 73: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
 	}
 	return -1
 },
@@ -8596,12 +8763,18 @@ func(r rune) int {
 	switch(r) {
 		case 102: return -1
 		case 70: return -1
-		case 114: return -1
-		case 82: return -1
 		case 111: return -1
 		case 79: return -1
-		case 109: return 4
-		case 77: return 4
+		case 108: return -1
+		case 76: return -1
+		case 119: return -1
+		case 87: return -1
+		case 105: return 7
+		case 73: return 7
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
 	}
 	return -1
 },
@@ -8609,7 +8782,168 @@ func(r rune) int {
 	switch(r) {
 		case 102: return -1
 		case 70: return -1
-		case 114: return -1
+		case 111: return -1
+		case 79: return -1
+		case 108: return -1
+		case 76: return -1
+		case 119: return -1
+		case 87: return -1
+		case 105: return -1
+		case 73: return -1
+		case 110: return 8
+		case 78: return 8
+		case 103: return -1
+		case 71: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 102: return -1
+		case 70: return -1
+		case 111: return -1
+		case 79: return -1
+		case 108: return -1
+		case 76: return -1
+		case 119: return -1
+		case 87: return -1
+		case 105: return -1
+		case 73: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return 9
+		case 71: return 9
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 102: return -1
+		case 70: return -1
+		case 111: return -1
+		case 79: return -1
+		case 108: return -1
+		case 76: return -1
+		case 119: return -1
+		case 87: return -1
+		case 105: return -1
+		case 73: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
+	}
+	return -1
+},
+}, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,},nil},
+
+// [fF][oO][rR]
+{[]bool{false, false, false, true}, []func(rune) int{  // Transitions
+func(r rune) int {
+	switch(r) {
+		case 102: return 1
+		case 70: return 1
+		case 111: return -1
+		case 79: return -1
+		case 114: return -1
+		case 82: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 102: return -1
+		case 70: return -1
+		case 111: return 2
+		case 79: return 2
+		case 114: return -1
+		case 82: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 102: return -1
+		case 70: return -1
+		case 111: return -1
+		case 79: return -1
+		case 114: return 3
+		case 82: return 3
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 102: return -1
+		case 70: return -1
+		case 111: return -1
+		case 79: return -1
+		case 114: return -1
+		case 82: return -1
+	}
+	return -1
+},
+}, []int{  /* Start-of-input transitions */  -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1,},nil},
+
+// [fF][rR][oO][mM]
+{[]bool{false, false, false, false, true}, []func(rune) int{  // Transitions
+func(r rune) int {
+	switch(r) {
+		case 102: return 1
+		case 70: return 1
+		case 114: return -1
+		case 82: return -1
+		case 111: return -1
+		case 79: return -1
+		case 109: return -1
+		case 77: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 102: return -1
+		case 70: return -1
+		case 114: return 2
+		case 82: return 2
+		case 111: return -1
+		case 79: return -1
+		case 109: return -1
+		case 77: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 102: return -1
+		case 70: return -1
+		case 114: return -1
+		case 82: return -1
+		case 111: return 3
+		case 79: return 3
+		case 109: return -1
+		case 77: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 102: return -1
+		case 70: return -1
+		case 114: return -1
+		case 82: return -1
+		case 111: return -1
+		case 79: return -1
+		case 109: return 4
+		case 77: return 4
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 102: return -1
+		case 70: return -1
+		case 114: return -1
 		case 82: return -1
 		case 111: return -1
 		case 79: return -1
@@ -9031,6 +9365,65 @@ func(r rune) int {
 },
 }, []int{  /* Start-of-input transitions */  -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1,},nil},
 
+// [hH][aA][sS][hH]
+{[]bool{false, false, false, false, true}, []func(rune) int{  // Transitions
+func(r rune) int {
+	switch(r) {
+		case 104: return 1
+		case 72: return 1
+		case 97: return -1
+		case 65: return -1
+		case 115: return -1
+		case 83: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 104: return -1
+		case 72: return -1
+		case 97: return 2
+		case 65: return 2
+		case 115: return -1
+		case 83: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 104: return -1
+		case 72: return -1
+		case 97: return -1
+		case 65: return -1
+		case 115: return 3
+		case 83: return 3
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 104: return 4
+		case 72: return 4
+		case 97: return -1
+		case 65: return -1
+		case 115: return -1
+		case 83: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 104: return -1
+		case 72: return -1
+		case 97: return -1
+		case 65: return -1
+		case 115: return -1
+		case 83: return -1
+	}
+	return -1
+},
+}, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1,},nil},
+
 // [hH][aA][vV][iI][nN][gG]
 {[]bool{false, false, false, false, false, false, true}, []func(rune) int{  // Transitions
 func(r rune) int {
@@ -13718,8 +14111,8 @@ func(r rune) int {
 },
 }, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1,},nil},
 
-// [pP][rR][eE][pP][aA][rR][eE]
-{[]bool{false, false, false, false, false, false, false, true}, []func(rune) int{  // Transitions
+// [pP][rR][eE][cC][eE][dD][iI][nN][gG]
+{[]bool{false, false, false, false, false, false, false, false, false, true}, []func(rune) int{  // Transitions
 func(r rune) int {
 	switch(r) {
 		case 112: return 1
@@ -13728,8 +14121,16 @@ func(r rune) int {
 		case 82: return -1
 		case 101: return -1
 		case 69: return -1
-		case 97: return -1
-		case 65: return -1
+		case 99: return -1
+		case 67: return -1
+		case 100: return -1
+		case 68: return -1
+		case 105: return -1
+		case 73: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
 	}
 	return -1
 },
@@ -13741,8 +14142,16 @@ func(r rune) int {
 		case 82: return 2
 		case 101: return -1
 		case 69: return -1
-		case 97: return -1
-		case 65: return -1
+		case 99: return -1
+		case 67: return -1
+		case 100: return -1
+		case 68: return -1
+		case 105: return -1
+		case 73: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
 	}
 	return -1
 },
@@ -13754,15 +14163,213 @@ func(r rune) int {
 		case 82: return -1
 		case 101: return 3
 		case 69: return 3
-		case 97: return -1
-		case 65: return -1
-	}
-	return -1
-},
-func(r rune) int {
-	switch(r) {
-		case 112: return 4
-		case 80: return 4
+		case 99: return -1
+		case 67: return -1
+		case 100: return -1
+		case 68: return -1
+		case 105: return -1
+		case 73: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 112: return -1
+		case 80: return -1
+		case 114: return -1
+		case 82: return -1
+		case 101: return -1
+		case 69: return -1
+		case 99: return 4
+		case 67: return 4
+		case 100: return -1
+		case 68: return -1
+		case 105: return -1
+		case 73: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 112: return -1
+		case 80: return -1
+		case 114: return -1
+		case 82: return -1
+		case 101: return 5
+		case 69: return 5
+		case 99: return -1
+		case 67: return -1
+		case 100: return -1
+		case 68: return -1
+		case 105: return -1
+		case 73: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 112: return -1
+		case 80: return -1
+		case 114: return -1
+		case 82: return -1
+		case 101: return -1
+		case 69: return -1
+		case 99: return -1
+		case 67: return -1
+		case 100: return 6
+		case 68: return 6
+		case 105: return -1
+		case 73: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 112: return -1
+		case 80: return -1
+		case 114: return -1
+		case 82: return -1
+		case 101: return -1
+		case 69: return -1
+		case 99: return -1
+		case 67: return -1
+		case 100: return -1
+		case 68: return -1
+		case 105: return 7
+		case 73: return 7
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 112: return -1
+		case 80: return -1
+		case 114: return -1
+		case 82: return -1
+		case 101: return -1
+		case 69: return -1
+		case 99: return -1
+		case 67: return -1
+		case 100: return -1
+		case 68: return -1
+		case 105: return -1
+		case 73: return -1
+		case 110: return 8
+		case 78: return 8
+		case 103: return -1
+		case 71: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 112: return -1
+		case 80: return -1
+		case 114: return -1
+		case 82: return -1
+		case 101: return -1
+		case 69: return -1
+		case 99: return -1
+		case 67: return -1
+		case 100: return -1
+		case 68: return -1
+		case 105: return -1
+		case 73: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return 9
+		case 71: return 9
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 112: return -1
+		case 80: return -1
+		case 114: return -1
+		case 82: return -1
+		case 101: return -1
+		case 69: return -1
+		case 99: return -1
+		case 67: return -1
+		case 100: return -1
+		case 68: return -1
+		case 105: return -1
+		case 73: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
+	}
+	return -1
+},
+}, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,},nil},
+
+// [pP][rR][eE][pP][aA][rR][eE]
+{[]bool{false, false, false, false, false, false, false, true}, []func(rune) int{  // Transitions
+func(r rune) int {
+	switch(r) {
+		case 112: return 1
+		case 80: return 1
+		case 114: return -1
+		case 82: return -1
+		case 101: return -1
+		case 69: return -1
+		case 97: return -1
+		case 65: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 112: return -1
+		case 80: return -1
+		case 114: return 2
+		case 82: return 2
+		case 101: return -1
+		case 69: return -1
+		case 97: return -1
+		case 65: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 112: return -1
+		case 80: return -1
+		case 114: return -1
+		case 82: return -1
+		case 101: return 3
+		case 69: return 3
+		case 97: return -1
+		case 65: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 112: return 4
+		case 80: return 4
 		case 114: return -1
 		case 82: return -1
 		case 101: return -1
@@ -14316,22 +14923,19 @@ func(r rune) int {
 },
 }, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,},nil},
 
-// [pP][rR][oO][cC][eE][dE][uU][rR][eE]
-{[]bool{false, false, false, false, false, false, false, false, false, true}, []func(rune) int{  // Transitions
+// [pP][rR][oO][bB][eE]
+{[]bool{false, false, false, false, false, true}, []func(rune) int{  // Transitions
 func(r rune) int {
 	switch(r) {
+		case 112: return 1
 		case 80: return 1
 		case 114: return -1
 		case 82: return -1
+		case 111: return -1
 		case 79: return -1
-		case 99: return -1
+		case 98: return -1
+		case 66: return -1
 		case 101: return -1
-		case 100: return -1
-		case 117: return -1
-		case 85: return -1
-		case 112: return 1
-		case 111: return -1
-		case 67: return -1
 		case 69: return -1
 	}
 	return -1
@@ -14339,35 +14943,29 @@ func(r rune) int {
 func(r rune) int {
 	switch(r) {
 		case 112: return -1
-		case 111: return -1
-		case 67: return -1
-		case 69: return -1
 		case 80: return -1
 		case 114: return 2
 		case 82: return 2
+		case 111: return -1
 		case 79: return -1
-		case 99: return -1
+		case 98: return -1
+		case 66: return -1
 		case 101: return -1
-		case 100: return -1
-		case 117: return -1
-		case 85: return -1
+		case 69: return -1
 	}
 	return -1
 },
 func(r rune) int {
 	switch(r) {
+		case 112: return -1
 		case 80: return -1
 		case 114: return -1
 		case 82: return -1
+		case 111: return 3
 		case 79: return 3
-		case 99: return -1
+		case 98: return -1
+		case 66: return -1
 		case 101: return -1
-		case 100: return -1
-		case 117: return -1
-		case 85: return -1
-		case 112: return -1
-		case 111: return 3
-		case 67: return -1
 		case 69: return -1
 	}
 	return -1
@@ -14375,18 +14973,121 @@ func(r rune) int {
 func(r rune) int {
 	switch(r) {
 		case 112: return -1
-		case 111: return -1
-		case 67: return 4
-		case 69: return -1
 		case 80: return -1
 		case 114: return -1
 		case 82: return -1
+		case 111: return -1
 		case 79: return -1
-		case 99: return 4
+		case 98: return 4
+		case 66: return 4
 		case 101: return -1
-		case 100: return -1
-		case 117: return -1
-		case 85: return -1
+		case 69: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 112: return -1
+		case 80: return -1
+		case 114: return -1
+		case 82: return -1
+		case 111: return -1
+		case 79: return -1
+		case 98: return -1
+		case 66: return -1
+		case 101: return 5
+		case 69: return 5
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 112: return -1
+		case 80: return -1
+		case 114: return -1
+		case 82: return -1
+		case 111: return -1
+		case 79: return -1
+		case 98: return -1
+		case 66: return -1
+		case 101: return -1
+		case 69: return -1
+	}
+	return -1
+},
+}, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1, -1,},nil},
+
+// [pP][rR][oO][cC][eE][dE][uU][rR][eE]
+{[]bool{false, false, false, false, false, false, false, false, false, true}, []func(rune) int{  // Transitions
+func(r rune) int {
+	switch(r) {
+		case 80: return 1
+		case 114: return -1
+		case 82: return -1
+		case 79: return -1
+		case 99: return -1
+		case 101: return -1
+		case 100: return -1
+		case 117: return -1
+		case 85: return -1
+		case 112: return 1
+		case 111: return -1
+		case 67: return -1
+		case 69: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 112: return -1
+		case 111: return -1
+		case 67: return -1
+		case 69: return -1
+		case 80: return -1
+		case 114: return 2
+		case 82: return 2
+		case 79: return -1
+		case 99: return -1
+		case 101: return -1
+		case 100: return -1
+		case 117: return -1
+		case 85: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 80: return -1
+		case 114: return -1
+		case 82: return -1
+		case 79: return 3
+		case 99: return -1
+		case 101: return -1
+		case 100: return -1
+		case 117: return -1
+		case 85: return -1
+		case 112: return -1
+		case 111: return 3
+		case 67: return -1
+		case 69: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 112: return -1
+		case 111: return -1
+		case 67: return 4
+		case 69: return -1
+		case 80: return -1
+		case 114: return -1
+		case 82: return -1
+		case 79: return -1
+		case 99: return 4
+		case 101: return -1
+		case 100: return -1
+		case 117: return -1
+		case 85: return -1
 	}
 	return -1
 },
@@ -14623,6 +15324,100 @@ func(r rune) int {
 },
 }, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1, -1, -1,},nil},
 
+// [rR][aA][nN][gG][eE]
+{[]bool{false, false, false, false, false, true}, []func(rune) int{  // Transitions
+func(r rune) int {
+	switch(r) {
+		case 114: return 1
+		case 82: return 1
+		case 97: return -1
+		case 65: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
+		case 101: return -1
+		case 69: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 114: return -1
+		case 82: return -1
+		case 97: return 2
+		case 65: return 2
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
+		case 101: return -1
+		case 69: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 114: return -1
+		case 82: return -1
+		case 97: return -1
+		case 65: return -1
+		case 110: return 3
+		case 78: return 3
+		case 103: return -1
+		case 71: return -1
+		case 101: return -1
+		case 69: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 114: return -1
+		case 82: return -1
+		case 97: return -1
+		case 65: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return 4
+		case 71: return 4
+		case 101: return -1
+		case 69: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 114: return -1
+		case 82: return -1
+		case 97: return -1
+		case 65: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
+		case 101: return 5
+		case 69: return 5
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 114: return -1
+		case 82: return -1
+		case 97: return -1
+		case 65: return -1
+		case 110: return -1
+		case 78: return -1
+		case 103: return -1
+		case 71: return -1
+		case 101: return -1
+		case 69: return -1
+	}
+	return -1
+},
+}, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1, -1,},nil},
+
 // [rR][aA][wW]
 {[]bool{false, false, false, true}, []func(rune) int{  // Transitions
 func(r rune) int {
@@ -14765,63 +15560,257 @@ func(r rune) int {
 },
 }, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1, -1,},nil},
 
-// [rR][eE][dD][uU][cC][eE]
-{[]bool{false, false, false, false, false, false, true}, []func(rune) int{  // Transitions
+// [rR][eE][cC][uU][rR][sS][iI][vV][eE]
+{[]bool{false, false, false, false, false, false, false, false, false, true}, []func(rune) int{  // Transitions
 func(r rune) int {
 	switch(r) {
 		case 114: return 1
+		case 82: return 1
 		case 101: return -1
 		case 69: return -1
-		case 68: return -1
-		case 117: return -1
-		case 85: return -1
-		case 82: return 1
-		case 100: return -1
 		case 99: return -1
 		case 67: return -1
+		case 117: return -1
+		case 85: return -1
+		case 115: return -1
+		case 83: return -1
+		case 105: return -1
+		case 73: return -1
+		case 118: return -1
+		case 86: return -1
 	}
 	return -1
 },
 func(r rune) int {
 	switch(r) {
 		case 114: return -1
+		case 82: return -1
 		case 101: return 2
 		case 69: return 2
-		case 68: return -1
-		case 117: return -1
-		case 85: return -1
-		case 82: return -1
-		case 100: return -1
 		case 99: return -1
 		case 67: return -1
+		case 117: return -1
+		case 85: return -1
+		case 115: return -1
+		case 83: return -1
+		case 105: return -1
+		case 73: return -1
+		case 118: return -1
+		case 86: return -1
 	}
 	return -1
 },
 func(r rune) int {
 	switch(r) {
-		case 82: return -1
-		case 100: return 3
-		case 99: return -1
-		case 67: return -1
 		case 114: return -1
+		case 82: return -1
 		case 101: return -1
 		case 69: return -1
-		case 68: return 3
+		case 99: return 3
+		case 67: return 3
 		case 117: return -1
 		case 85: return -1
+		case 115: return -1
+		case 83: return -1
+		case 105: return -1
+		case 73: return -1
+		case 118: return -1
+		case 86: return -1
 	}
 	return -1
 },
 func(r rune) int {
 	switch(r) {
 		case 114: return -1
+		case 82: return -1
 		case 101: return -1
 		case 69: return -1
-		case 68: return -1
-		case 117: return 4
-		case 85: return 4
-		case 82: return -1
-		case 100: return -1
+		case 99: return -1
+		case 67: return -1
+		case 117: return 4
+		case 85: return 4
+		case 115: return -1
+		case 83: return -1
+		case 105: return -1
+		case 73: return -1
+		case 118: return -1
+		case 86: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 114: return 5
+		case 82: return 5
+		case 101: return -1
+		case 69: return -1
+		case 99: return -1
+		case 67: return -1
+		case 117: return -1
+		case 85: return -1
+		case 115: return -1
+		case 83: return -1
+		case 105: return -1
+		case 73: return -1
+		case 118: return -1
+		case 86: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 114: return -1
+		case 82: return -1
+		case 101: return -1
+		case 69: return -1
+		case 99: return -1
+		case 67: return -1
+		case 117: return -1
+		case 85: return -1
+		case 115: return 6
+		case 83: return 6
+		case 105: return -1
+		case 73: return -1
+		case 118: return -1
+		case 86: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 114: return -1
+		case 82: return -1
+		case 101: return -1
+		case 69: return -1
+		case 99: return -1
+		case 67: return -1
+		case 117: return -1
+		case 85: return -1
+		case 115: return -1
+		case 83: return -1
+		case 105: return 7
+		case 73: return 7
+		case 118: return -1
+		case 86: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 114: return -1
+		case 82: return -1
+		case 101: return -1
+		case 69: return -1
+		case 99: return -1
+		case 67: return -1
+		case 117: return -1
+		case 85: return -1
+		case 115: return -1
+		case 83: return -1
+		case 105: return -1
+		case 73: return -1
+		case 118: return 8
+		case 86: return 8
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 114: return -1
+		case 82: return -1
+		case 101: return 9
+		case 69: return 9
+		case 99: return -1
+		case 67: return -1
+		case 117: return -1
+		case 85: return -1
+		case 115: return -1
+		case 83: return -1
+		case 105: return -1
+		case 73: return -1
+		case 118: return -1
+		case 86: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 114: return -1
+		case 82: return -1
+		case 101: return -1
+		case 69: return -1
+		case 99: return -1
+		case 67: return -1
+		case 117: return -1
+		case 85: return -1
+		case 115: return -1
+		case 83: return -1
+		case 105: return -1
+		case 73: return -1
+		case 118: return -1
+		case 86: return -1
+	}
+	return -1
+},
+}, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,},nil},
+
+// [rR][eE][dD][uU][cC][eE]
+{[]bool{false, false, false, false, false, false, true}, []func(rune) int{  // Transitions
+func(r rune) int {
+	switch(r) {
+		case 114: return 1
+		case 101: return -1
+		case 69: return -1
+		case 68: return -1
+		case 117: return -1
+		case 85: return -1
+		case 82: return 1
+		case 100: return -1
+		case 99: return -1
+		case 67: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 114: return -1
+		case 101: return 2
+		case 69: return 2
+		case 68: return -1
+		case 117: return -1
+		case 85: return -1
+		case 82: return -1
+		case 100: return -1
+		case 99: return -1
+		case 67: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 82: return -1
+		case 100: return 3
+		case 99: return -1
+		case 67: return -1
+		case 114: return -1
+		case 101: return -1
+		case 69: return -1
+		case 68: return 3
+		case 117: return -1
+		case 85: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 114: return -1
+		case 101: return -1
+		case 69: return -1
+		case 68: return -1
+		case 117: return 4
+		case 85: return 4
+		case 82: return -1
+		case 100: return -1
 		case 99: return -1
 		case 67: return -1
 	}
@@ -15733,80 +16722,197 @@ func(r rune) int {
 },
 }, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1, -1, -1, -1, -1,},nil},
 
-// [sS][aA][tT][iI][sS][fF][iI][eE][sS]
-{[]bool{false, false, false, false, false, false, false, false, false, true}, []func(rune) int{  // Transitions
+// [rR][oO][wW]
+{[]bool{false, false, false, true}, []func(rune) int{  // Transitions
 func(r rune) int {
 	switch(r) {
-		case 83: return 1
-		case 97: return -1
-		case 65: return -1
-		case 102: return -1
-		case 70: return -1
-		case 101: return -1
-		case 69: return -1
-		case 115: return 1
-		case 116: return -1
-		case 84: return -1
-		case 105: return -1
-		case 73: return -1
+		case 114: return 1
+		case 82: return 1
+		case 111: return -1
+		case 79: return -1
+		case 119: return -1
+		case 87: return -1
 	}
 	return -1
 },
 func(r rune) int {
 	switch(r) {
-		case 83: return -1
-		case 97: return 2
-		case 65: return 2
-		case 102: return -1
-		case 70: return -1
-		case 101: return -1
-		case 69: return -1
-		case 115: return -1
-		case 116: return -1
-		case 84: return -1
-		case 105: return -1
-		case 73: return -1
+		case 114: return -1
+		case 82: return -1
+		case 111: return 2
+		case 79: return 2
+		case 119: return -1
+		case 87: return -1
 	}
 	return -1
 },
 func(r rune) int {
 	switch(r) {
-		case 115: return -1
-		case 116: return 3
-		case 84: return 3
-		case 105: return -1
-		case 73: return -1
-		case 83: return -1
-		case 97: return -1
-		case 65: return -1
-		case 102: return -1
-		case 70: return -1
-		case 101: return -1
-		case 69: return -1
+		case 114: return -1
+		case 82: return -1
+		case 111: return -1
+		case 79: return -1
+		case 119: return 3
+		case 87: return 3
 	}
 	return -1
 },
 func(r rune) int {
 	switch(r) {
-		case 115: return -1
-		case 116: return -1
-		case 84: return -1
-		case 105: return 4
-		case 73: return 4
-		case 83: return -1
-		case 97: return -1
-		case 65: return -1
-		case 102: return -1
-		case 70: return -1
-		case 101: return -1
-		case 69: return -1
+		case 114: return -1
+		case 82: return -1
+		case 111: return -1
+		case 79: return -1
+		case 119: return -1
+		case 87: return -1
 	}
 	return -1
 },
+}, []int{  /* Start-of-input transitions */  -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1,},nil},
+
+// [rR][oO][wW][sS]
+{[]bool{false, false, false, false, true}, []func(rune) int{  // Transitions
 func(r rune) int {
 	switch(r) {
-		case 83: return 5
-		case 97: return -1
+		case 114: return 1
+		case 82: return 1
+		case 111: return -1
+		case 79: return -1
+		case 119: return -1
+		case 87: return -1
+		case 115: return -1
+		case 83: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 114: return -1
+		case 82: return -1
+		case 111: return 2
+		case 79: return 2
+		case 119: return -1
+		case 87: return -1
+		case 115: return -1
+		case 83: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 114: return -1
+		case 82: return -1
+		case 111: return -1
+		case 79: return -1
+		case 119: return 3
+		case 87: return 3
+		case 115: return -1
+		case 83: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 114: return -1
+		case 82: return -1
+		case 111: return -1
+		case 79: return -1
+		case 119: return -1
+		case 87: return -1
+		case 115: return 4
+		case 83: return 4
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 114: return -1
+		case 82: return -1
+		case 111: return -1
+		case 79: return -1
+		case 119: return -1
+		case 87: return -1
+		case 115: return -1
+		case 83: return -1
+	}
+	return -1
+},
+}, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1,},nil},
+
+// [sS][aA][tT][iI][sS][fF][iI][eE][sS]
+{[]bool{false, false, false, false, false, false, false, false, false, true}, []func(rune) int{  // Transitions
+func(r rune) int {
+	switch(r) {
+		case 83: return 1
+		case 97: return -1
+		case 65: return -1
+		case 102: return -1
+		case 70: return -1
+		case 101: return -1
+		case 69: return -1
+		case 115: return 1
+		case 116: return -1
+		case 84: return -1
+		case 105: return -1
+		case 73: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 83: return -1
+		case 97: return 2
+		case 65: return 2
+		case 102: return -1
+		case 70: return -1
+		case 101: return -1
+		case 69: return -1
+		case 115: return -1
+		case 116: return -1
+		case 84: return -1
+		case 105: return -1
+		case 73: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 115: return -1
+		case 116: return 3
+		case 84: return 3
+		case 105: return -1
+		case 73: return -1
+		case 83: return -1
+		case 97: return -1
+		case 65: return -1
+		case 102: return -1
+		case 70: return -1
+		case 101: return -1
+		case 69: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 115: return -1
+		case 116: return -1
+		case 84: return -1
+		case 105: return 4
+		case 73: return 4
+		case 83: return -1
+		case 97: return -1
+		case 65: return -1
+		case 102: return -1
+		case 70: return -1
+		case 101: return -1
+		case 69: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 83: return 5
+		case 97: return -1
 		case 65: return -1
 		case 102: return -1
 		case 70: return -1
@@ -17601,112 +18707,124 @@ func(r rune) int {
 },
 }, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1, -1, -1, -1, -1,},nil},
 
-// [uU][nN][dD][eE][rR]
-{[]bool{false, false, false, false, false, true}, []func(rune) int{  // Transitions
+// [uU][nN][bB][oO][uU][nN][dD][eE][dD]
+{[]bool{false, false, false, false, false, false, false, false, false, true}, []func(rune) int{  // Transitions
 func(r rune) int {
 	switch(r) {
 		case 117: return 1
-		case 110: return -1
-		case 100: return -1
-		case 101: return -1
-		case 114: return -1
 		case 85: return 1
+		case 110: return -1
 		case 78: return -1
+		case 98: return -1
+		case 66: return -1
+		case 111: return -1
+		case 79: return -1
+		case 100: return -1
 		case 68: return -1
+		case 101: return -1
 		case 69: return -1
-		case 82: return -1
 	}
 	return -1
 },
 func(r rune) int {
 	switch(r) {
 		case 117: return -1
-		case 110: return 2
-		case 100: return -1
-		case 101: return -1
-		case 114: return -1
 		case 85: return -1
+		case 110: return 2
 		case 78: return 2
+		case 98: return -1
+		case 66: return -1
+		case 111: return -1
+		case 79: return -1
+		case 100: return -1
 		case 68: return -1
+		case 101: return -1
 		case 69: return -1
-		case 82: return -1
 	}
 	return -1
 },
 func(r rune) int {
 	switch(r) {
 		case 117: return -1
-		case 110: return -1
-		case 100: return 3
-		case 101: return -1
-		case 114: return -1
 		case 85: return -1
+		case 110: return -1
 		case 78: return -1
-		case 68: return 3
+		case 98: return 3
+		case 66: return 3
+		case 111: return -1
+		case 79: return -1
+		case 100: return -1
+		case 68: return -1
+		case 101: return -1
 		case 69: return -1
-		case 82: return -1
 	}
 	return -1
 },
 func(r rune) int {
 	switch(r) {
-		case 85: return -1
-		case 78: return -1
-		case 68: return -1
-		case 69: return 4
-		case 82: return -1
 		case 117: return -1
+		case 85: return -1
 		case 110: return -1
+		case 78: return -1
+		case 98: return -1
+		case 66: return -1
+		case 111: return 4
+		case 79: return 4
 		case 100: return -1
-		case 101: return 4
-		case 114: return -1
+		case 68: return -1
+		case 101: return -1
+		case 69: return -1
 	}
 	return -1
 },
 func(r rune) int {
 	switch(r) {
-		case 117: return -1
+		case 117: return 5
+		case 85: return 5
 		case 110: return -1
-		case 100: return -1
-		case 101: return -1
-		case 114: return 5
-		case 85: return -1
 		case 78: return -1
+		case 98: return -1
+		case 66: return -1
+		case 111: return -1
+		case 79: return -1
+		case 100: return -1
 		case 68: return -1
+		case 101: return -1
 		case 69: return -1
-		case 82: return 5
 	}
 	return -1
 },
 func(r rune) int {
 	switch(r) {
-		case 85: return -1
-		case 78: return -1
-		case 68: return -1
-		case 69: return -1
-		case 82: return -1
 		case 117: return -1
-		case 110: return -1
+		case 85: return -1
+		case 110: return 6
+		case 78: return 6
+		case 98: return -1
+		case 66: return -1
+		case 111: return -1
+		case 79: return -1
 		case 100: return -1
+		case 68: return -1
 		case 101: return -1
-		case 114: return -1
+		case 69: return -1
 	}
 	return -1
 },
-}, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1, -1,},nil},
-
-// [uU][nN][iI][oO][nN]
-{[]bool{false, false, false, false, false, true}, []func(rune) int{  // Transitions
 func(r rune) int {
 	switch(r) {
-		case 117: return 1
-		case 85: return 1
+		case 117: return -1
+		case 85: return -1
 		case 110: return -1
 		case 78: return -1
-		case 105: return -1
-		case 73: return -1
+		case 98: return -1
+		case 66: return -1
 		case 111: return -1
 		case 79: return -1
+		case 100: return 7
+		case 68: return 7
+		case 101: return -1
+		case 69: return -1
 	}
 	return -1
 },
@@ -17714,12 +18832,16 @@ func(r rune) int {
 	switch(r) {
 		case 117: return -1
 		case 85: return -1
-		case 110: return 2
-		case 78: return 2
-		case 105: return -1
-		case 73: return -1
+		case 110: return -1
+		case 78: return -1
+		case 98: return -1
+		case 66: return -1
 		case 111: return -1
 		case 79: return -1
+		case 100: return -1
+		case 68: return -1
+		case 101: return 8
+		case 69: return 8
 	}
 	return -1
 },
@@ -17729,8 +18851,166 @@ func(r rune) int {
 		case 85: return -1
 		case 110: return -1
 		case 78: return -1
-		case 105: return 3
-		case 73: return 3
+		case 98: return -1
+		case 66: return -1
+		case 111: return -1
+		case 79: return -1
+		case 100: return 9
+		case 68: return 9
+		case 101: return -1
+		case 69: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 117: return -1
+		case 85: return -1
+		case 110: return -1
+		case 78: return -1
+		case 98: return -1
+		case 66: return -1
+		case 111: return -1
+		case 79: return -1
+		case 100: return -1
+		case 68: return -1
+		case 101: return -1
+		case 69: return -1
+	}
+	return -1
+},
+}, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,},nil},
+
+// [uU][nN][dD][eE][rR]
+{[]bool{false, false, false, false, false, true}, []func(rune) int{  // Transitions
+func(r rune) int {
+	switch(r) {
+		case 117: return 1
+		case 110: return -1
+		case 100: return -1
+		case 101: return -1
+		case 114: return -1
+		case 85: return 1
+		case 78: return -1
+		case 68: return -1
+		case 69: return -1
+		case 82: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 117: return -1
+		case 110: return 2
+		case 100: return -1
+		case 101: return -1
+		case 114: return -1
+		case 85: return -1
+		case 78: return 2
+		case 68: return -1
+		case 69: return -1
+		case 82: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 117: return -1
+		case 110: return -1
+		case 100: return 3
+		case 101: return -1
+		case 114: return -1
+		case 85: return -1
+		case 78: return -1
+		case 68: return 3
+		case 69: return -1
+		case 82: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 85: return -1
+		case 78: return -1
+		case 68: return -1
+		case 69: return 4
+		case 82: return -1
+		case 117: return -1
+		case 110: return -1
+		case 100: return -1
+		case 101: return 4
+		case 114: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 117: return -1
+		case 110: return -1
+		case 100: return -1
+		case 101: return -1
+		case 114: return 5
+		case 85: return -1
+		case 78: return -1
+		case 68: return -1
+		case 69: return -1
+		case 82: return 5
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 85: return -1
+		case 78: return -1
+		case 68: return -1
+		case 69: return -1
+		case 82: return -1
+		case 117: return -1
+		case 110: return -1
+		case 100: return -1
+		case 101: return -1
+		case 114: return -1
+	}
+	return -1
+},
+}, []int{  /* Start-of-input transitions */  -1, -1, -1, -1, -1, -1,}, []int{  /* End-of-input transitions */  -1, -1, -1, -1, -1, -1,},nil},
+
+// [uU][nN][iI][oO][nN]
+{[]bool{false, false, false, false, false, true}, []func(rune) int{  // Transitions
+func(r rune) int {
+	switch(r) {
+		case 117: return 1
+		case 85: return 1
+		case 110: return -1
+		case 78: return -1
+		case 105: return -1
+		case 73: return -1
+		case 111: return -1
+		case 79: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 117: return -1
+		case 85: return -1
+		case 110: return 2
+		case 78: return 2
+		case 105: return -1
+		case 73: return -1
+		case 111: return -1
+		case 79: return -1
+	}
+	return -1
+},
+func(r rune) int {
+	switch(r) {
+		case 117: return -1
+		case 85: return -1
+		case 110: return -1
+		case 78: return -1
+		case 105: return 3
+		case 73: return 3
 		case 111: return -1
 		case 79: return -1
 	}
@@ -19891,423 +21171,453 @@ func (yylex *Lexer) Lex(lval *yySymType) int {
 			{ logToken("CREATE"); return CREATE }
 			continue
 		case 60:
-			{ logToken("DATABASE"); return DATABASE }
+			{ logToken("CURRENT"); return CURRENT }
 			continue
 		case 61:
-			{ logToken("DATASET"); return DATASET }
+			{ logToken("DATABASE"); return DATABASE }
 			continue
 		case 62:
-			{ logToken("DATASTORE"); return DATASTORE }
+			{ logToken("DATASET"); return DATASET }
 			continue
 		case 63:
-			{ logToken("DECLARE"); return DECLARE }
+			{ logToken("DATASTORE"); return DATASTORE }
 			continue
 		case 64:
-			{ logToken("DECREMENT"); return DECREMENT }
+			{ logToken("DECLARE"); return DECLARE }
 			continue
 		case 65:
-			{ logToken("DELETE"); return DELETE }
+			{ logToken("DECREMENT"); return DECREMENT }
 			continue
 		case 66:
-			{ logToken("DERIVED"); return DERIVED }
+			{ logToken("DELETE"); return DELETE }
 			continue
 		case 67:
-			{ logToken("DESC"); return DESC }
+			{ logToken("DERIVED"); return DERIVED }
 			continue
 		case 68:
-			{ logToken("DESCRIBE"); return DESCRIBE }
+			{ logToken("DESC"); return DESC }
 			continue
 		case 69:
-			{ logToken("DISTINCT"); return DISTINCT }
+			{ logToken("DESCRIBE"); return DESCRIBE }
 			continue
 		case 70:
-			{ logToken("DO"); return DO }
+			{ logToken("DISTINCT"); return DISTINCT }
 			continue
 		case 71:
-			{ logToken("DROP"); return DROP }
+			{ logToken("DO"); return DO }
 			continue
 		case 72:
-			{ logToken("EACH"); return EACH }
+			{ logToken("DROP"); return DROP }
 			continue
 		case 73:
-			{ logToken("ELEMENT"); return ELEMENT }
+			{ logToken("EACH"); return EACH }
 			continue
 		case 74:
-			{ logToken("ELSE"); return ELSE }
+			{ logToken("ELEMENT"); return ELEMENT }
 			continue
 		case 75:
-			{ logToken("END"); return END }
+			{ logToken("ELSE"); return ELSE }
 			continue
 		case 76:
-			{ logToken("EVERY"); return EVERY }
+			{ logToken("END"); return END }
 			continue
 		case 77:
-			{ logToken("EXCEPT"); return EXCEPT }
+			{ logToken("EVERY"); return EVERY }
 			continue
 		case 78:
-			{ logToken("EXCLUDE"); return EXCLUDE }
+			{ logToken("EXCEPT"); return EXCEPT }
 			continue
 		case 79:
-			{ logToken("EXECUTE"); return EXECUTE }
+			{ logToken("EXCLUDE"); return EXCLUDE }
 			continue
 		case 80:
-			{ logToken("EXISTS"); return EXISTS }
+			{ logToken("EXECUTE"); return EXECUTE }
 			continue
 		case 81:
-			{ logToken("EXPLAIN"); return EXPLAIN }
+			{ logToken("EXISTS"); return EXISTS }
 			continue
 		case 82:
-			{ logToken("FALSE"); return FALSE }
+			{ logToken("EXPLAIN"); return EXPLAIN }
 			continue
 		case 83:
-			{ logToken("FIRST"); return FIRST }
+			{ logToken("FALSE"); return FALSE }
 			continue
 		case 84:
-			{ logToken("FLATTEN"); return FLATTEN }
+			{ logToken("FIRST"); return FIRST }
 			continue
 		case 85:
-			{ logToken("FOR"); return FOR }
+			{ logToken("FLATTEN"); return FLATTEN }
 			continue
 		case 86:
-			{ logToken("FROM"); return FROM }
+			{ logToken("FOLLOWING"); return FOLLOWING }
 			continue
 		case 87:
-			{ logToken("FUNCTION"); return FUNCTION }
+			{ logToken("FOR"); return FOR }
 			continue
 		case 88:
-			{ logToken("GRANT"); return GRANT }
+			{ logToken("FROM"); return FROM }
 			continue
 		case 89:
-			{ logToken("GROUP"); return GROUP }
+			{ logToken("FUNCTION"); return FUNCTION }
 			continue
 		case 90:
-			{ logToken("GSI"); return GSI }
+			{ logToken("GRANT"); return GRANT }
 			continue
 		case 91:
-			{ logToken("HAVING"); return HAVING }
+			{ logToken("GROUP"); return GROUP }
 			continue
 		case 92:
-			{ logToken("IF"); return IF }
+			{ logToken("GSI"); return GSI }
 			continue
 		case 93:
-			{ logToken("IN"); return IN }
+			{ logToken("HASH"); return HASH }
 			continue
 		case 94:
-			{ logToken("INCLUDE"); return INCLUDE }
+			{ logToken("HAVING"); return HAVING }
 			continue
 		case 95:
-			{ logToken("INCREMENT"); return INCREMENT }
+			{ logToken("IF"); return IF }
 			continue
 		case 96:
-			{ logToken("INDEX"); return INDEX }
+			{ logToken("IN"); return IN }
 			continue
 		case 97:
-			{ logToken("INLINE"); return INLINE }
+			{ logToken("INCLUDE"); return INCLUDE }
 			continue
 		case 98:
-			{ logToken("INNER"); return INNER }
+			{ logToken("INCREMENT"); return INCREMENT }
 			continue
 		case 99:
-			{ logToken("INSERT"); return INSERT }
+			{ logToken("INDEX"); return INDEX }
 			continue
 		case 100:
-			{ logToken("INTERSECT"); return INTERSECT }
+			{ logToken("INLINE"); return INLINE }
 			continue
 		case 101:
-			{ logToken("INTO"); return INTO }
+			{ logToken("INNER"); return INNER }
 			continue
 		case 102:
-			{ logToken("IS"); return IS }
+			{ logToken("INSERT"); return INSERT }
 			continue
 		case 103:
-			{ logToken("JOIN"); return JOIN }
+			{ logToken("INTERSECT"); return INTERSECT }
 			continue
 		case 104:
-			{ logToken("KEY"); return KEY }
+			{ logToken("INTO"); return INTO }
 			continue
 		case 105:
-			{ logToken("KEYS"); return KEYS }
+			{ logToken("IS"); return IS }
 			continue
 		case 106:
-			{ logToken("KEYSPACE"); return KEYSPACE }
+			{ logToken("JOIN"); return JOIN }
 			continue
 		case 107:
-			{ logToken("LAST"); return LAST }
+			{ logToken("KEY"); return KEY }
 			continue
 		case 108:
-			{ logToken("LEFT"); return LEFT }
+			{ logToken("KEYS"); return KEYS }
 			continue
 		case 109:
-			{ logToken("LET"); return LET }
+			{ logToken("KEYSPACE"); return KEYSPACE }
 			continue
 		case 110:
-			{ logToken("LETTING"); return LETTING }
+			{ logToken("LAST"); return LAST }
 			continue
 		case 111:
-			{ logToken("LIKE"); return LIKE }
+			{ logToken("LEFT"); return LEFT }
 			continue
 		case 112:
-			{ logToken("LIMIT"); return LIMIT }
+			{ logToken("LET"); return LET }
 			continue
 		case 113:
-			{ logToken("LSM"); return LSM }
+			{ logToken("LETTING"); return LETTING }
 			continue
 		case 114:
-			{ logToken("MAP"); return MAP }
+			{ logToken("LIKE"); return LIKE }
 			continue
 		case 115:
-			{ logToken("MAPPING"); return MAPPING }
+			{ logToken("LIMIT"); return LIMIT }
 			continue
 		case 116:
-			{ logToken("MATCHED"); return MATCHED }
+			{ logToken("LSM"); return LSM }
 			continue
 		case 117:
-			{ logToken("MATERIALIZED"); return MATERIALIZED }
+			{ logToken("MAP"); return MAP }
 			continue
 		case 118:
-			{ logToken("MERGE"); return MERGE }
+			{ logToken("MAPPING"); return MAPPING }
 			continue
 		case 119:
-			{ logToken("MINUS"); return MINUS }
+			{ logToken("MATCHED"); return MATCHED }
 			continue
 		case 120:
-			{ logToken("MISSING"); return MISSING }
+			{ logToken("MATERIALIZED"); return MATERIALIZED }
 			continue
 		case 121:
-			{ logToken("NAMESPACE"); return NAMESPACE }
+			{ logToken("MERGE"); return MERGE }
 			continue
 		case 122:
-			{ logToken("NEST"); return NEST }
+			{ logToken("MINUS"); return MINUS }
 			continue
 		case 123:
-			{ logToken("NOT"); return NOT }
+			{ logToken("MISSING"); return MISSING }
 			continue
 		case 124:
-			{ logToken("NULL"); return NULL }
+			{ logToken("NAMESPACE"); return NAMESPACE }
 			continue
 		case 125:
-			{ logToken("NUMBER"); return NUMBER }
+			{ logToken("NEST"); return NEST }
 			continue
 		case 126:
-			{ logToken("OBJECT"); return OBJECT }
+			{ logToken("NOT"); return NOT }
 			continue
 		case 127:
-			{ logToken("OFFSET"); return OFFSET }
+			{ logToken("NULL"); return NULL }
 			continue
 		case 128:
-			{ logToken("ON"); return ON }
+			{ logToken("NUMBER"); return NUMBER }
 			continue
 		case 129:
-			{ logToken("OPTION"); return OPTION }
+			{ logToken("OBJECT"); return OBJECT }
 			continue
 		case 130:
-			{ logToken("OR"); return OR }
+			{ logToken("OFFSET"); return OFFSET }
 			continue
 		case 131:
-			{ logToken("ORDER"); return ORDER }
+			{ logToken("ON"); return ON }
 			continue
 		case 132:
-			{ logToken("OUTER"); return OUTER }
+			{ logToken("OPTION"); return OPTION }
 			continue
 		case 133:
-			{ logToken("OVER"); return OVER }
+			{ logToken("OR"); return OR }
 			continue
 		case 134:
-			{ logToken("PARTITION"); return PARTITION }
+			{ logToken("ORDER"); return ORDER }
 			continue
 		case 135:
-			{ logToken("PASSWORD"); return PASSWORD }
+			{ logToken("OUTER"); return OUTER }
 			continue
 		case 136:
-			{ logToken("PATH"); return PATH }
+			{ logToken("OVER"); return OVER }
 			continue
 		case 137:
-			{ logToken("POOL"); return POOL }
+			{ logToken("PARTITION"); return PARTITION }
 			continue
 		case 138:
-			{ logToken("PREPARE"); return PREPARE }
+			{ logToken("PASSWORD"); return PASSWORD }
 			continue
 		case 139:
-			{ logToken("PRIMARY"); return PRIMARY }
+			{ logToken("PATH"); return PATH }
 			continue
 		case 140:
-			{ logToken("PRIVATE"); return PRIVATE }
+			{ logToken("POOL"); return POOL }
 			continue
 		case 141:
-			{ logToken("PRIVILEGE"); return PRIVILEGE }
+			{ logToken("PRECEDING"); return PRECEDING }
 			continue
 		case 142:
-			{ logToken("PROCEDURE"); return PROCEDURE }
+			{ logToken("PREPARE"); return PREPARE }
 			continue
 		case 143:
-			{ logToken("PUBLIC"); return PUBLIC }
+			{ logToken("PRIMARY"); return PRIMARY }
 			continue
 		case 144:
-			{ logToken("RAW"); return RAW }
+			{ logToken("PRIVATE"); return PRIVATE }
 			continue
 		case 145:
-			{ logToken("REALM"); return REALM }
+			{ logToken("PRIVILEGE"); return PRIVILEGE }
 			continue
 		case 146:
-			{ logToken("REDUCE"); return REDUCE }
+			{ logToken("PROBE"); return PROBE }
 			continue
 		case 147:
-			{ logToken("RENAME"); return RENAME }
+			{ logToken("PROCEDURE"); return PROCEDURE }
 			continue
 		case 148:
-			{ logToken("RETURN"); return RETURN }
+			{ logToken("PUBLIC"); return PUBLIC }
 			continue
 		case 149:
-			{ logToken("RETURNING"); return RETURNING }
+			{ logToken("RANGE"); return RANGE }
 			continue
 		case 150:
-			{ logToken("REVOKE"); return REVOKE }
+			{ logToken("RAW"); return RAW }
 			continue
 		case 151:
-			{ logToken("RIGHT"); return RIGHT }
+			{ logToken("REALM"); return REALM }
 			continue
 		case 152:
-			{ logToken("ROLE"); return ROLE }
+			{ logToken("RECURSIVE"); return RECURSIVE }
 			continue
 		case 153:
-			{ logToken("ROLLBACK"); return ROLLBACK }
+			{ logToken("REDUCE"); return REDUCE }
 			continue
 		case 154:
-			{ logToken("SATISFIES"); return SATISFIES }
+			{ logToken("RENAME"); return RENAME }
 			continue
 		case 155:
-			{ logToken("SCHEMA"); return SCHEMA }
+			{ logToken("RETURN"); return RETURN }
 			continue
 		case 156:
-			{ logToken("SELECT"); return SELECT }
+			{ logToken("RETURNING"); return RETURNING }
 			continue
 		case 157:
-			{ logToken("SELF"); return SELF }
+			{ logToken("REVOKE"); return REVOKE }
 			continue
 		case 158:
-			{ logToken("SET"); return SET }
+			{ logToken("RIGHT"); return RIGHT }
 			continue
 		case 159:
-			{ logToken("SHOW"); return SHOW }
+			{ logToken("ROLE"); return ROLE }
 			continue
 		case 160:
-			{ logToken("SOME"); return SOME }
+			{ logToken("ROLLBACK"); return ROLLBACK }
 			continue
 		case 161:
-			{ logToken("START"); return START }
+			{ logToken("ROW"); return ROW }
 			continue
 		case 162:
-			{ logToken("STATISTICS"); return STATISTICS }
+			{ logToken("ROWS"); return ROWS }
 			continue
 		case 163:
-			{ logToken("STRING"); return STRING }
+			{ logToken("SATISFIES"); return SATISFIES }
 			continue
 		case 164:
-			{ logToken("SYSTEM"); return SYSTEM }
+			{ logToken("SCHEMA"); return SCHEMA }
 			continue
 		case 165:
-			{ logToken("THEN"); return THEN }
+			{ logToken("SELECT"); return SELECT }
 			continue
 		case 166:
-			{ logToken("TO"); return TO }
+			{ logToken("SELF"); return SELF }
 			continue
 		case 167:
-			{ logToken("TRANSACTION"); return TRANSACTION }
+			{ logToken("SET"); return SET }
 			continue
 		case 168:
-			{ logToken("TRIGGER"); return TRIGGER }
+			{ logToken("SHOW"); return SHOW }
 			continue
 		case 169:
-			{ logToken("TRUE"); return TRUE }
+			{ logToken("SOME"); return SOME }
 			continue
 		case 170:
-			{ logToken("TRUNCATE"); return TRUNCATE }
+			{ logToken("START"); return START }
 			continue
 		case 171:
-			{ logToken("UNDER"); return UNDER }
+			{ logToken("STATISTICS"); return STATISTICS }
 			continue
 		case 172:
-			{ logToken("UNION"); return UNION }
+			{ logToken("STRING"); return STRING }
 			continue
 		case 173:
-			{ logToken("UNIQUE"); return UNIQUE }
+			{ logToken("SYSTEM"); return SYSTEM }
 			continue
 		case 174:
-			{ logToken("UNNEST"); return UNNEST }
+			{ logToken("THEN"); return THEN }
 			continue
 		case 175:
-			{ logToken("UNSET"); return UNSET }
+			{ logToken("TO"); return TO }
 			continue
 		case 176:
-			{ logToken("UPDATE"); return UPDATE }
+			{ logToken("TRANSACTION"); return TRANSACTION }
 			continue
 		case 177:
-			{ logToken("UPSERT"); return UPSERT }
+			{ logToken("TRIGGER"); return TRIGGER }
 			continue
 		case 178:
-			{ logToken("USE"); return USE }
+			{ logToken("TRUE"); return TRUE }
 			continue
 		case 179:
-			{ logToken("USER"); return USER }
+			{ logToken("TRUNCATE"); return TRUNCATE }
 			continue
 		case 180:
-			{ logToken("USING"); return USING }
+			{ logToken("UNBOUNDED"); return UNBOUNDED }
 			continue
 		case 181:
-			{ logToken("VALUE"); return VALUE }
+			{ logToken("UNDER"); return UNDER }
 			continue
 		case 182:
-			{ logToken("VALUED"); return VALUED }
+			{ logToken("UNION"); return UNION }
 			continue
 		case 183:
-			{ logToken("VALUES"); return VALUES }
+			{ logToken("UNIQUE"); return UNIQUE }
 			continue
 		case 184:
-			{ logToken("VIEW"); return VIEW }
+			{ logToken("UNNEST"); return UNNEST }
 			continue
 		case 185:
-			{ logToken("WHEN"); return WHEN }
+			{ logToken("UNSET"); return UNSET }
 			continue
 		case 186:
-			{ logToken("WHERE"); return WHERE }
+			{ logToken("UPDATE"); return UPDATE }
 			continue
 		case 187:
-			{ logToken("WHILE"); return WHILE }
+			{ logToken("UPSERT"); return UPSERT }
 			continue
 		case 188:
-			{ logToken("WITH"); return WITH }
+			{ logToken("USE"); return USE }
 			continue
 		case 189:
-			{ logToken("WITHIN"); return WITHIN }
+			{ logToken("USER"); return USER }
 			continue
 		case 190:
-			{ logToken("WORK"); return WORK }
+			{ logToken("USING"); return USING }
 			continue
 		case 191:
-			{ logToken("XOR"); return XOR }
+			{ logToken("VALUE"); return VALUE }
 			continue
 		case 192:
+			{ logToken("VALUED"); return VALUED }
+			continue
+		case 193:
+			{ logToken("VALUES"); return VALUES }
+			continue
+		case 194:
+			{ logToken("VIEW"); return VIEW }
+			continue
+		case 195:
+			{ logToken("WHEN"); return WHEN }
+			continue
+		case 196:
+			{ logToken("WHERE"); return WHERE }
+			continue
+		case 197:
+			{ logToken("WHILE"); return WHILE }
+			continue
+		case 198:
+			{ logToken("WITH"); return WITH }
+			continue
+		case 199:
+			{ logToken("WITHIN"); return WITHIN }
+			continue
+		case 200:
+			{ logToken("WORK"); return WORK }
+			continue
+		case 201:
+			{ logToken("XOR"); return XOR }
+			continue
+		case 202:
 			{
 		    lval.s = yylex.Text()
 		    logToken("IDENTIFIER - %s", lval.s)
 		    return IDENTIFIER
 		  }
 			continue
-		case 193:
+		case 203:
 			{
 		    lval.s = yylex.Text()[1:]
 		    logToken("NAMED_PARAM - %s", lval.s)
 		    return NAMED_PARAM
 		  }
 			continue
-		case 194:
+		case 204:
 			{
 		    lval.n, _ = strconv.Atoi(yylex.Text()[1:])
 		    logToken("POSITIONAL_PARAM - %d", lval.n)
 		    return POSITIONAL_PARAM
 		  }
 			continue
-		case 195:
+		case 205:
 			{
 		    lval.n = 0 // Handled by parser
 		    logToken("NEXT_PARAM - ?")
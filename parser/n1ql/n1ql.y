@@ -32,10 +32,14 @@ node             algebra.Node
 statement        algebra.Statement
 
 fullselect       *algebra.Select
+with             *algebra.With
+withTerm         *algebra.WithTerm
+withTerms        algebra.WithTerms
 subresult        algebra.Subresult
 subselect        *algebra.Subselect
 fromTerm         algebra.FromTerm
 keyspaceTerm     *algebra.KeyspaceTerm
+joinHint         algebra.JoinHint
 subqueryTerm     *algebra.SubqueryTerm
 path             expression.Path
 group            *algebra.Group
@@ -45,8 +49,12 @@ projection       *algebra.Projection
 order            *algebra.Order
 sortTerm         *algebra.SortTerm
 sortTerms        algebra.SortTerms
+windowTerm       *algebra.WindowTerm
+windowFrame      *algebra.WindowFrame
+windowFrameBound *algebra.WindowFrameBound
 
 keyspaceRef      *algebra.KeyspaceRef
+functionRef      *algebra.FunctionRef
 
 pairs            algebra.Pairs
 set              *algebra.Set
@@ -94,6 +102,7 @@ val              value.Value
 %token CONNECT
 %token CONTINUE
 %token CREATE
+%token CURRENT
 %token DATABASE
 %token DATASET
 %token DATASTORE
@@ -119,12 +128,14 @@ val              value.Value
 %token FALSE
 %token FIRST
 %token FLATTEN
+%token FOLLOWING
 %token FOR
 %token FROM
 %token FUNCTION
 %token GRANT
 %token GROUP
 %token GSI
+%token HASH
 %token HAVING
 %token IF
 %token IN
@@ -172,14 +183,18 @@ val              value.Value
 %token PASSWORD
 %token PATH
 %token POOL
+%token PRECEDING
 %token PREPARE
 %token PRIMARY
 %token PRIVATE
 %token PRIVILEGE
+%token PROBE
 %token PROCEDURE
 %token PUBLIC
+%token RANGE
 %token RAW
 %token REALM
+%token RECURSIVE
 %token REDUCE
 %token RENAME
 %token RETURN
@@ -188,6 +203,8 @@ val              value.Value
 %token RIGHT
 %token ROLE
 %token ROLLBACK
+%token ROW
+%token ROWS
 %token SATISFIES
 %token SCHEMA
 %token SELECT
@@ -205,6 +222,7 @@ val              value.Value
 %token TRIGGER
 %token TRUE
 %token TRUNCATE
+%token UNBOUNDED
 %token UNDER
 %token UNION
 %token UNIQUE
@@ -287,10 +305,20 @@ val              value.Value
 
 %type <expr>             function_expr
 %type <s>                function_name
+%type <windowTerm>       window_spec
+%type <exprs>            opt_window_partition
+%type <sortTerms>        opt_window_order
+%type <windowFrame>      opt_window_frame window_frame
+%type <b>                window_frame_unit
+%type <windowFrameBound> window_frame_bound
 
 %type <expr>             paren_or_subquery_expr paren_or_subquery
 
-%type <fullselect>       fullselect
+%type <fullselect>       fullselect select_body
+%type <with>             with
+%type <withTerm>         with_term
+%type <withTerms>        with_terms
+%type <b>                opt_recursive
 %type <subresult>        subselects
 %type <subselect>        subselect
 %type <subselect>        select_from
@@ -299,6 +327,7 @@ val              value.Value
 %type <keyspaceTerm>     keyspace_term join_term
 %type <subqueryTerm>     subquery_term
 %type <b>                opt_join_type
+%type <joinHint>         opt_use_hash
 %type <path>             path opt_subpath
 %type <s>                namespace_name keyspace_name
 %type <expr>             use_keys opt_use_keys on_keys
@@ -318,8 +347,14 @@ val              value.Value
 %type <b>                dir opt_dir
 
 %type <statement>        stmt explain prepare execute select_stmt dml_stmt ddl_stmt
+%type <s>                opt_explain_format
 %type <statement>        insert upsert delete update merge
 %type <statement>        index_stmt create_index drop_index alter_index build_index
+%type <statement>        function_stmt create_function drop_function
+%type <statement>        update_statistics
+%type <functionRef>      function_ref
+%type <b>                opt_or_replace
+%type <ss>               opt_parameters parameters
 
 %type <keyspaceRef>      keyspace_ref
 %type <pairs>            values values_list
@@ -349,7 +384,8 @@ val              value.Value
 %type <indexType>        index_using opt_index_using
 %type <val>              index_with opt_index_with
 %type <s>                rename
-%type <expr>             index_expr index_where
+%type <expr>             index_expr index_term index_where
+%type <expr>             opt_execute_using
 %type <exprs>            index_exprs
 
 %start input
@@ -383,23 +419,69 @@ ddl_stmt
 ;
 
 explain:
-EXPLAIN stmt
+EXPLAIN opt_explain_format stmt
 {
-    $$ = algebra.NewExplain($2)
+    $$ = algebra.NewExplain($3, false, $2)
+}
+|
+EXPLAIN ANALYZE stmt
+{
+    $$ = algebra.NewExplain($3, true, "")
+}
+;
+
+opt_explain_format:
+/* empty */
+{
+    $$ = ""
+}
+|
+IDENTIFIER IDENTIFIER
+{
+    if strings.ToLower($1) != "format" {
+        yylex.Error(fmt.Sprintf("Invalid EXPLAIN %s.", $1));
+    }
+
+    $$ = strings.ToLower($2)
+    if !algebra.IsExplainFormat($$) {
+        yylex.Error(fmt.Sprintf("Invalid EXPLAIN FORMAT %s.", $2));
+    }
 }
 ;
 
 prepare:
 PREPARE stmt
 {
-    $$ = algebra.NewPrepare($2)
+    $$ = algebra.NewPrepare("", $2)
+}
+|
+PREPARE IDENTIFIER FROM stmt
+{
+    $$ = algebra.NewPrepare($2, $4)
 }
 ;
 
 execute:
-EXECUTE object
+EXECUTE object opt_execute_using
 {
-    $$ = algebra.NewExecute($2)
+    $$ = algebra.NewExecute($2, $3)
+}
+|
+EXECUTE IDENTIFIER opt_execute_using
+{
+    $$ = algebra.NewExecute(expression.NewConstant($2), $3)
+}
+;
+
+opt_execute_using:
+/* empty */
+{
+    $$ = nil
+}
+|
+USING expr
+{
+    $$ = $2
 }
 ;
 
@@ -424,6 +506,10 @@ merge
 
 ddl_stmt:
 index_stmt
+|
+function_stmt
+|
+update_statistics
 ;
 
 index_stmt:
@@ -436,7 +522,61 @@ alter_index
 build_index
 ;
 
+function_stmt:
+create_function
+|
+drop_function
+;
+
 fullselect:
+select_body
+|
+with select_body
+{
+    $2.SetWith($1)
+    $$ = $2
+}
+;
+
+with:
+WITH opt_recursive with_terms
+{
+    $$ = algebra.NewWith($2, $3)
+}
+;
+
+opt_recursive:
+/* empty */
+{
+    $$ = false
+}
+|
+RECURSIVE
+{
+    $$ = true
+}
+;
+
+with_terms:
+with_term
+{
+    $$ = algebra.WithTerms{$1}
+}
+|
+with_terms COMMA with_term
+{
+    $$ = append($1, $3)
+}
+;
+
+with_term:
+alias AS LPAREN fullselect RPAREN
+{
+    $$ = algebra.NewWithTerm($1, $4)
+}
+;
+
+select_body:
 subselects opt_order_by
 {
     $$ = algebra.NewSelect($1, $2, nil, nil) /* OFFSET precedes LIMIT */
@@ -643,14 +783,38 @@ subquery_term
     $$ = $1
 }
 |
-from_term opt_join_type JOIN join_term
+from_term opt_join_type JOIN join_term on_keys
 {
-    $$ = algebra.NewJoin($1, $2, $4)
+    if $4.JoinHint() != algebra.JOIN_HINT_NONE {
+        yylex.Error("USE HASH requires an ON clause.")
+    } else {
+        $4.SetKeys($5)
+        $$ = algebra.NewJoin($1, $2, $4)
+    }
 }
 |
-from_term opt_join_type NEST join_term
+from_term opt_join_type JOIN join_term ON expr
 {
-    $$ = algebra.NewNest($1, $2, $4)
+    $$ = algebra.NewAnsiJoin($1, $2, $4, $6)
+}
+|
+from_term opt_join_type NEST join_term on_keys
+{
+    if $4.JoinHint() != algebra.JOIN_HINT_NONE {
+        yylex.Error("USE HASH requires an ON clause.")
+    } else {
+        $4.SetKeys($5)
+        $$ = algebra.NewNest($1, $2, $4)
+    }
+}
+|
+from_term opt_join_type NEST join_term ON expr
+{
+    if $4.JoinHint() != algebra.JOIN_HINT_NONE {
+        yylex.Error("USE HASH is not supported for NEST.")
+    } else {
+        $$ = algebra.NewAnsiNest($1, $2, $4, $6)
+    }
 }
 |
 from_term opt_join_type unnest expr opt_as_alias
@@ -694,19 +858,22 @@ LPAREN fullselect RPAREN opt_as_alias
 ;
 
 join_term:
-keyspace_name opt_subpath opt_as_alias on_keys
+keyspace_name opt_subpath opt_as_alias opt_use_hash
 {
-    $$ = algebra.NewKeyspaceTerm("", $1, $2, $3, $4)
+    $$ = algebra.NewKeyspaceTerm("", $1, $2, $3, nil)
+    $$.SetJoinHint($4)
 }
 |
-namespace_name COLON keyspace_name opt_subpath opt_as_alias on_keys
+namespace_name COLON keyspace_name opt_subpath opt_as_alias opt_use_hash
 {
-    $$ = algebra.NewKeyspaceTerm($1, $3, $4, $5, $6)
+    $$ = algebra.NewKeyspaceTerm($1, $3, $4, $5, nil)
+    $$.SetJoinHint($6)
 }
 |
-SYSTEM COLON keyspace_name opt_subpath opt_as_alias on_keys
+SYSTEM COLON keyspace_name opt_subpath opt_as_alias opt_use_hash
 {
-    $$ = algebra.NewKeyspaceTerm("#system", $3, $4, $5, $6)
+    $$ = algebra.NewKeyspaceTerm("#system", $3, $4, $5, nil)
+    $$.SetJoinHint($6)
 }
 ;
 
@@ -716,6 +883,12 @@ IDENTIFIER
 
 keyspace_name:
 IDENTIFIER
+|
+STATISTICS
+{
+    /* Allow system:statistics */
+    $$ = "statistics"
+}
 ;
 
 opt_subpath:
@@ -777,6 +950,23 @@ opt_outer:
 OUTER
 ;
 
+opt_use_hash:
+/* empty */
+{
+    $$ = algebra.JOIN_HINT_NONE
+}
+|
+USE HASH LPAREN BUILD RPAREN
+{
+    $$ = algebra.USE_HASH_BUILD
+}
+|
+USE HASH LPAREN PROBE RPAREN
+{
+    $$ = algebra.USE_HASH_PROBE
+}
+;
+
 on_keys:
 ON opt_primary KEYS expr
 {
@@ -1055,6 +1245,11 @@ namespace_name COLON keyspace_name opt_as_alias
     $$ = algebra.NewKeyspaceRef($1, $3, $4)
 }
 |
+SYSTEM COLON keyspace_name opt_as_alias
+{
+    $$ = algebra.NewKeyspaceRef("#system", $3, $4)
+}
+|
 keyspace_name opt_as_alias
 {
     $$ = algebra.NewKeyspaceRef("", $1, $2)
@@ -1510,17 +1705,31 @@ WITH expr
 ;
 
 index_exprs:
-index_expr
+index_term
 {
     $$ = expression.Expressions{$1}
 }
 |
-index_exprs COMMA index_expr
+index_exprs COMMA index_term
 {
     $$ = append($1, $3)
 }
 ;
 
+index_term:
+index_expr
+|
+DISTINCT ARRAY expr FOR coll_bindings opt_when END
+{
+    exp := expression.NewDistinctArray($3, $5, $6)
+    if !exp.Indexable() {
+        yylex.Error(fmt.Sprintf("Expression not indexable: %s", exp.String()))
+    }
+
+    $$ = exp
+}
+;
+
 index_expr:
 expr
 {
@@ -1613,6 +1822,106 @@ index_names COMMA index_name
 ;
 
 
+/*************************************************
+ *
+ * CREATE FUNCTION
+ *
+ *************************************************/
+
+create_function:
+CREATE opt_or_replace FUNCTION function_ref LPAREN opt_parameters RPAREN LBRACE expr RBRACE
+{
+    $$ = algebra.NewCreateFunction($4, $6, $9, $2)
+}
+;
+
+opt_or_replace:
+/* empty */
+{
+    $$ = false
+}
+|
+OR IDENTIFIER
+{
+    if strings.ToLower($2) != "replace" {
+        yylex.Error(fmt.Sprintf("Invalid CREATE OR %s.", $2));
+    }
+    $$ = true
+}
+;
+
+function_ref:
+IDENTIFIER
+{
+    $$ = algebra.NewFunctionRef("", $1)
+}
+|
+namespace_name COLON IDENTIFIER
+{
+    $$ = algebra.NewFunctionRef($1, $3)
+}
+;
+
+opt_parameters:
+/* empty */
+{
+    $$ = nil
+}
+|
+parameters
+;
+
+parameters:
+IDENTIFIER
+{
+    $$ = []string{$1}
+}
+|
+parameters COMMA IDENTIFIER
+{
+    $$ = append($1, $3)
+}
+;
+
+
+/*************************************************
+ *
+ * DROP FUNCTION
+ *
+ *************************************************/
+
+drop_function:
+DROP FUNCTION function_ref
+{
+    $$ = algebra.NewDropFunction($3)
+}
+;
+
+
+/*************************************************
+ *
+ * UPDATE STATISTICS
+ *
+ *************************************************/
+
+update_statistics:
+UPDATE STATISTICS FOR named_keyspace_ref LPAREN index_exprs RPAREN opt_index_with
+{
+    $$ = algebra.NewUpdateStatistics($4, $6, $8)
+}
+|
+UPDATE STATISTICS FOR named_keyspace_ref DELETE LPAREN index_exprs RPAREN
+{
+    $$ = algebra.NewDeleteStatistics($4, $7)
+}
+|
+UPDATE STATISTICS FOR named_keyspace_ref DELETE ALL
+{
+    $$ = algebra.NewDeleteStatistics($4, nil)
+}
+;
+
+
 /*************************************************
  *
  * Path
@@ -2282,12 +2591,168 @@ function_name LPAREN STAR RPAREN
         }
     }
 }
+|
+function_name LPAREN opt_exprs RPAREN OVER LPAREN window_spec RPAREN
+{
+    $$ = nil;
+    if !yylex.(*lexer).parsingStatement() {
+        yylex.Error("Cannot use window function as an inline expression.");
+    } else {
+        f, ok := algebra.GetWindowFunction($1, false);
+        if ok {
+            if len($3) < f.MinArgs() || len($3) > f.MaxArgs() {
+                yylex.Error(fmt.Sprintf("Wrong number of arguments to function %s.", $1));
+            } else {
+                w := f.Constructor()($3...).(algebra.WindowFunction);
+                w.SetWindow($7);
+                $$ = w;
+            }
+        } else {
+            yylex.Error(fmt.Sprintf("Invalid window function %s.", $1));
+        }
+    }
+}
+|
+function_name LPAREN DISTINCT expr RPAREN OVER LPAREN window_spec RPAREN
+{
+    $$ = nil;
+    if !yylex.(*lexer).parsingStatement() {
+        yylex.Error("Cannot use window function as an inline expression.");
+    } else {
+        f, ok := algebra.GetWindowFunction($1, true);
+        if ok {
+            w := f.Constructor()($4).(algebra.WindowFunction);
+            w.SetWindow($8);
+            $$ = w;
+        } else {
+            yylex.Error(fmt.Sprintf("Invalid window function %s.", $1));
+        }
+    }
+}
+|
+function_name LPAREN STAR RPAREN OVER LPAREN window_spec RPAREN
+{
+    $$ = nil;
+    if !yylex.(*lexer).parsingStatement() {
+        yylex.Error("Cannot use window function as an inline expression.");
+    } else {
+        if strings.ToLower($1) != "count" {
+            yylex.Error(fmt.Sprintf("Invalid window function %s(*).", $1));
+        } else {
+            f, ok := algebra.GetWindowFunction($1, false);
+            if ok {
+                w := f.Constructor()(nil).(algebra.WindowFunction);
+                w.SetWindow($7);
+                $$ = w;
+            } else {
+                yylex.Error(fmt.Sprintf("Invalid window function %s.", $1));
+            }
+        }
+    }
+}
 ;
 
 function_name:
 IDENTIFIER
 ;
 
+window_spec:
+opt_window_partition opt_window_order opt_window_frame
+{
+    $$ = algebra.NewWindowTerm($1, $2, $3);
+}
+;
+
+opt_window_partition:
+/* empty */
+{
+    $$ = nil;
+}
+|
+PARTITION BY exprs
+{
+    $$ = $3;
+}
+;
+
+opt_window_order:
+/* empty */
+{
+    $$ = nil;
+}
+|
+ORDER BY sort_terms
+{
+    $$ = $3;
+}
+;
+
+opt_window_frame:
+/* empty */
+{
+    $$ = nil;
+}
+|
+window_frame
+{
+    $$ = $1;
+    if err := $$.Validate(); err != nil {
+        yylex.Error(err.Error());
+    }
+}
+;
+
+window_frame:
+window_frame_unit window_frame_bound
+{
+    $$ = algebra.NewWindowFrame($1, $2, algebra.NewWindowFrameBound(algebra.CURRENT_ROW, nil));
+}
+|
+window_frame_unit BETWEEN window_frame_bound AND window_frame_bound
+{
+    $$ = algebra.NewWindowFrame($1, $3, $5);
+}
+;
+
+window_frame_unit:
+ROWS
+{
+    $$ = true;
+}
+|
+RANGE
+{
+    $$ = false;
+}
+;
+
+window_frame_bound:
+UNBOUNDED PRECEDING
+{
+    $$ = algebra.NewWindowFrameBound(algebra.UNBOUNDED_PRECEDING, nil);
+}
+|
+UNBOUNDED FOLLOWING
+{
+    $$ = algebra.NewWindowFrameBound(algebra.UNBOUNDED_FOLLOWING, nil);
+}
+|
+CURRENT ROW
+{
+    $$ = algebra.NewWindowFrameBound(algebra.CURRENT_ROW, nil);
+}
+|
+b_expr PRECEDING
+{
+    $$ = algebra.NewWindowFrameBound(algebra.PRECEDING, $1);
+}
+|
+b_expr FOLLOWING
+{
+    $$ = algebra.NewWindowFrameBound(algebra.FOLLOWING, $1);
+}
+;
+
 
 /*************************************************
  *
@@ -35,22 +35,30 @@ type yySymType struct {
 	node      algebra.Node
 	statement algebra.Statement
 
-	fullselect   *algebra.Select
-	subresult    algebra.Subresult
-	subselect    *algebra.Subselect
-	fromTerm     algebra.FromTerm
-	keyspaceTerm *algebra.KeyspaceTerm
-	subqueryTerm *algebra.SubqueryTerm
-	path         expression.Path
-	group        *algebra.Group
-	resultTerm   *algebra.ResultTerm
-	resultTerms  algebra.ResultTerms
-	projection   *algebra.Projection
-	order        *algebra.Order
-	sortTerm     *algebra.SortTerm
-	sortTerms    algebra.SortTerms
+	fullselect       *algebra.Select
+	with             *algebra.With
+	withTerm         *algebra.WithTerm
+	withTerms        algebra.WithTerms
+	subresult        algebra.Subresult
+	subselect        *algebra.Subselect
+	fromTerm         algebra.FromTerm
+	keyspaceTerm     *algebra.KeyspaceTerm
+	joinHint         algebra.JoinHint
+	subqueryTerm     *algebra.SubqueryTerm
+	path             expression.Path
+	group            *algebra.Group
+	resultTerm       *algebra.ResultTerm
+	resultTerms      algebra.ResultTerms
+	projection       *algebra.Projection
+	order            *algebra.Order
+	sortTerm         *algebra.SortTerm
+	sortTerms        algebra.SortTerms
+	windowTerm       *algebra.WindowTerm
+	windowFrame      *algebra.WindowFrame
+	windowFrameBound *algebra.WindowFrameBound
 
 	keyspaceRef *algebra.KeyspaceRef
+	functionRef *algebra.FunctionRef
 
 	pairs        algebra.Pairs
 	set          *algebra.Set
@@ -98,168 +106,178 @@ const COMMIT = 57368
 const CONNECT = 57369
 const CONTINUE = 57370
 const CREATE = 57371
-const DATABASE = 57372
-const DATASET = 57373
-const DATASTORE = 57374
-const DECLARE = 57375
-const DECREMENT = 57376
-const DELETE = 57377
-const DERIVED = 57378
-const DESC = 57379
-const DESCRIBE = 57380
-const DISTINCT = 57381
-const DO = 57382
-const DROP = 57383
-const EACH = 57384
-const ELEMENT = 57385
-const ELSE = 57386
-const END = 57387
-const EVERY = 57388
-const EXCEPT = 57389
-const EXCLUDE = 57390
-const EXECUTE = 57391
-const EXISTS = 57392
-const EXPLAIN = 57393
-const FALSE = 57394
-const FIRST = 57395
-const FLATTEN = 57396
-const FOR = 57397
-const FROM = 57398
-const FUNCTION = 57399
-const GRANT = 57400
-const GROUP = 57401
-const GSI = 57402
-const HAVING = 57403
-const IF = 57404
-const IN = 57405
-const INCLUDE = 57406
-const INCREMENT = 57407
-const INDEX = 57408
-const INLINE = 57409
-const INNER = 57410
-const INSERT = 57411
-const INTERSECT = 57412
-const INTO = 57413
-const IS = 57414
-const JOIN = 57415
-const KEY = 57416
-const KEYS = 57417
-const KEYSPACE = 57418
-const LAST = 57419
-const LEFT = 57420
-const LET = 57421
-const LETTING = 57422
-const LIKE = 57423
-const LIMIT = 57424
-const LSM = 57425
-const MAP = 57426
-const MAPPING = 57427
-const MATCHED = 57428
-const MATERIALIZED = 57429
-const MERGE = 57430
-const MINUS = 57431
-const MISSING = 57432
-const NAMESPACE = 57433
-const NEST = 57434
-const NOT = 57435
-const NULL = 57436
-const NUMBER = 57437
-const OBJECT = 57438
-const OFFSET = 57439
-const ON = 57440
-const OPTION = 57441
-const OR = 57442
-const ORDER = 57443
-const OUTER = 57444
-const OVER = 57445
-const PARTITION = 57446
-const PASSWORD = 57447
-const PATH = 57448
-const POOL = 57449
-const PREPARE = 57450
-const PRIMARY = 57451
-const PRIVATE = 57452
-const PRIVILEGE = 57453
-const PROCEDURE = 57454
-const PUBLIC = 57455
-const RAW = 57456
-const REALM = 57457
-const REDUCE = 57458
-const RENAME = 57459
-const RETURN = 57460
-const RETURNING = 57461
-const REVOKE = 57462
-const RIGHT = 57463
-const ROLE = 57464
-const ROLLBACK = 57465
-const SATISFIES = 57466
-const SCHEMA = 57467
-const SELECT = 57468
-const SELF = 57469
-const SET = 57470
-const SHOW = 57471
-const SOME = 57472
-const START = 57473
-const STATISTICS = 57474
-const STRING = 57475
-const SYSTEM = 57476
-const THEN = 57477
-const TO = 57478
-const TRANSACTION = 57479
-const TRIGGER = 57480
-const TRUE = 57481
-const TRUNCATE = 57482
-const UNDER = 57483
-const UNION = 57484
-const UNIQUE = 57485
-const UNNEST = 57486
-const UNSET = 57487
-const UPDATE = 57488
-const UPSERT = 57489
-const USE = 57490
-const USER = 57491
-const USING = 57492
-const VALUE = 57493
-const VALUED = 57494
-const VALUES = 57495
-const VIEW = 57496
-const WHEN = 57497
-const WHERE = 57498
-const WHILE = 57499
-const WITH = 57500
-const WITHIN = 57501
-const WORK = 57502
-const XOR = 57503
-const INT = 57504
-const IDENTIFIER = 57505
-const IDENTIFIER_ICASE = 57506
-const NAMED_PARAM = 57507
-const POSITIONAL_PARAM = 57508
-const NEXT_PARAM = 57509
-const LPAREN = 57510
-const RPAREN = 57511
-const LBRACE = 57512
-const RBRACE = 57513
-const LBRACKET = 57514
-const RBRACKET = 57515
-const RBRACKET_ICASE = 57516
-const COMMA = 57517
-const COLON = 57518
-const INTERESECT = 57519
-const EQ = 57520
-const DEQ = 57521
-const NE = 57522
-const LT = 57523
-const GT = 57524
-const LE = 57525
-const GE = 57526
-const CONCAT = 57527
-const PLUS = 57528
-const STAR = 57529
-const DIV = 57530
-const MOD = 57531
-const UMINUS = 57532
-const DOT = 57533
+const CURRENT = 57372
+const DATABASE = 57373
+const DATASET = 57374
+const DATASTORE = 57375
+const DECLARE = 57376
+const DECREMENT = 57377
+const DELETE = 57378
+const DERIVED = 57379
+const DESC = 57380
+const DESCRIBE = 57381
+const DISTINCT = 57382
+const DO = 57383
+const DROP = 57384
+const EACH = 57385
+const ELEMENT = 57386
+const ELSE = 57387
+const END = 57388
+const EVERY = 57389
+const EXCEPT = 57390
+const EXCLUDE = 57391
+const EXECUTE = 57392
+const EXISTS = 57393
+const EXPLAIN = 57394
+const FALSE = 57395
+const FIRST = 57396
+const FLATTEN = 57397
+const FOLLOWING = 57398
+const FOR = 57399
+const FROM = 57400
+const FUNCTION = 57401
+const GRANT = 57402
+const GROUP = 57403
+const GSI = 57404
+const HASH = 57405
+const HAVING = 57406
+const IF = 57407
+const IN = 57408
+const INCLUDE = 57409
+const INCREMENT = 57410
+const INDEX = 57411
+const INLINE = 57412
+const INNER = 57413
+const INSERT = 57414
+const INTERSECT = 57415
+const INTO = 57416
+const IS = 57417
+const JOIN = 57418
+const KEY = 57419
+const KEYS = 57420
+const KEYSPACE = 57421
+const LAST = 57422
+const LEFT = 57423
+const LET = 57424
+const LETTING = 57425
+const LIKE = 57426
+const LIMIT = 57427
+const LSM = 57428
+const MAP = 57429
+const MAPPING = 57430
+const MATCHED = 57431
+const MATERIALIZED = 57432
+const MERGE = 57433
+const MINUS = 57434
+const MISSING = 57435
+const NAMESPACE = 57436
+const NEST = 57437
+const NOT = 57438
+const NULL = 57439
+const NUMBER = 57440
+const OBJECT = 57441
+const OFFSET = 57442
+const ON = 57443
+const OPTION = 57444
+const OR = 57445
+const ORDER = 57446
+const OUTER = 57447
+const OVER = 57448
+const PARTITION = 57449
+const PASSWORD = 57450
+const PATH = 57451
+const POOL = 57452
+const PRECEDING = 57453
+const PREPARE = 57454
+const PRIMARY = 57455
+const PRIVATE = 57456
+const PRIVILEGE = 57457
+const PROBE = 57458
+const PROCEDURE = 57459
+const PUBLIC = 57460
+const RANGE = 57461
+const RAW = 57462
+const REALM = 57463
+const RECURSIVE = 57464
+const REDUCE = 57465
+const RENAME = 57466
+const RETURN = 57467
+const RETURNING = 57468
+const REVOKE = 57469
+const RIGHT = 57470
+const ROLE = 57471
+const ROLLBACK = 57472
+const ROW = 57473
+const ROWS = 57474
+const SATISFIES = 57475
+const SCHEMA = 57476
+const SELECT = 57477
+const SELF = 57478
+const SET = 57479
+const SHOW = 57480
+const SOME = 57481
+const START = 57482
+const STATISTICS = 57483
+const STRING = 57484
+const SYSTEM = 57485
+const THEN = 57486
+const TO = 57487
+const TRANSACTION = 57488
+const TRIGGER = 57489
+const TRUE = 57490
+const TRUNCATE = 57491
+const UNBOUNDED = 57492
+const UNDER = 57493
+const UNION = 57494
+const UNIQUE = 57495
+const UNNEST = 57496
+const UNSET = 57497
+const UPDATE = 57498
+const UPSERT = 57499
+const USE = 57500
+const USER = 57501
+const USING = 57502
+const VALUE = 57503
+const VALUED = 57504
+const VALUES = 57505
+const VIEW = 57506
+const WHEN = 57507
+const WHERE = 57508
+const WHILE = 57509
+const WITH = 57510
+const WITHIN = 57511
+const WORK = 57512
+const XOR = 57513
+const INT = 57514
+const IDENTIFIER = 57515
+const IDENTIFIER_ICASE = 57516
+const NAMED_PARAM = 57517
+const POSITIONAL_PARAM = 57518
+const NEXT_PARAM = 57519
+const LPAREN = 57520
+const RPAREN = 57521
+const LBRACE = 57522
+const RBRACE = 57523
+const LBRACKET = 57524
+const RBRACKET = 57525
+const RBRACKET_ICASE = 57526
+const COMMA = 57527
+const COLON = 57528
+const INTERESECT = 57529
+const EQ = 57530
+const DEQ = 57531
+const NE = 57532
+const LT = 57533
+const GT = 57534
+const LE = 57535
+const GE = 57536
+const CONCAT = 57537
+const PLUS = 57538
+const STAR = 57539
+const DIV = 57540
+const MOD = 57541
+const UMINUS = 57542
+const DOT = 57543
 
 var yyToknames = []string{
 	"ALL",
@@ -288,6 +306,7 @@ var yyToknames = []string{
 	"CONNECT",
 	"CONTINUE",
 	"CREATE",
+	"CURRENT",
 	"DATABASE",
 	"DATASET",
 	"DATASTORE",
@@ -313,12 +332,14 @@ var yyToknames = []string{
 	"FALSE",
 	"FIRST",
 	"FLATTEN",
+	"FOLLOWING",
 	"FOR",
 	"FROM",
 	"FUNCTION",
 	"GRANT",
 	"GROUP",
 	"GSI",
+	"HASH",
 	"HAVING",
 	"IF",
 	"IN",
@@ -366,14 +387,18 @@ var yyToknames = []string{
 	"PASSWORD",
 	"PATH",
 	"POOL",
+	"PRECEDING",
 	"PREPARE",
 	"PRIMARY",
 	"PRIVATE",
 	"PRIVILEGE",
+	"PROBE",
 	"PROCEDURE",
 	"PUBLIC",
+	"RANGE",
 	"RAW",
 	"REALM",
+	"RECURSIVE",
 	"REDUCE",
 	"RENAME",
 	"RETURN",
@@ -382,6 +407,8 @@ var yyToknames = []string{
 	"RIGHT",
 	"ROLE",
 	"ROLLBACK",
+	"ROW",
+	"ROWS",
 	"SATISFIES",
 	"SCHEMA",
 	"SELECT",
@@ -399,6 +426,7 @@ var yyToknames = []string{
 	"TRIGGER",
 	"TRUE",
 	"TRUNCATE",
+	"UNBOUNDED",
 	"UNDER",
 	"UNION",
 	"UNIQUE",
@@ -462,714 +490,802 @@ var yyExca = []int{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 25,
-	168, 326,
-	-2, 271,
-	-1, 119,
-	176, 72,
-	-2, 73,
-	-1, 157,
-	54, 81,
-	73, 81,
-	92, 81,
-	144, 81,
-	-2, 57,
-	-1, 186,
-	178, 0,
-	179, 0,
-	180, 0,
-	-2, 235,
-	-1, 187,
-	178, 0,
-	179, 0,
-	180, 0,
-	-2, 236,
-	-1, 188,
-	178, 0,
-	179, 0,
-	180, 0,
-	-2, 237,
-	-1, 189,
-	181, 0,
-	182, 0,
-	183, 0,
-	184, 0,
-	-2, 238,
-	-1, 190,
-	181, 0,
-	182, 0,
-	183, 0,
-	184, 0,
-	-2, 239,
-	-1, 191,
-	181, 0,
-	182, 0,
-	183, 0,
-	184, 0,
-	-2, 240,
-	-1, 192,
-	181, 0,
-	182, 0,
-	183, 0,
-	184, 0,
-	-2, 241,
-	-1, 199,
-	81, 0,
-	-2, 244,
-	-1, 200,
-	63, 0,
-	159, 0,
-	-2, 246,
-	-1, 201,
-	63, 0,
-	159, 0,
-	-2, 248,
-	-1, 304,
-	81, 0,
+	-1, 27,
+	178, 370,
+	-2, 312,
+	-1, 127,
+	186, 93,
+	-2, 94,
+	-1, 176,
+	55, 103,
+	76, 103,
+	95, 103,
+	154, 103,
+	-2, 76,
+	-1, 206,
+	188, 0,
+	189, 0,
+	190, 0,
+	-2, 276,
+	-1, 207,
+	188, 0,
+	189, 0,
+	190, 0,
+	-2, 277,
+	-1, 208,
+	188, 0,
+	189, 0,
+	190, 0,
+	-2, 278,
+	-1, 209,
+	191, 0,
+	192, 0,
+	193, 0,
+	194, 0,
+	-2, 279,
+	-1, 210,
+	191, 0,
+	192, 0,
+	193, 0,
+	194, 0,
+	-2, 280,
+	-1, 211,
+	191, 0,
+	192, 0,
+	193, 0,
+	194, 0,
+	-2, 281,
+	-1, 212,
+	191, 0,
+	192, 0,
+	193, 0,
+	194, 0,
+	-2, 282,
+	-1, 219,
+	84, 0,
+	-2, 285,
+	-1, 220,
+	66, 0,
+	169, 0,
+	-2, 287,
+	-1, 221,
+	66, 0,
+	169, 0,
+	-2, 289,
+	-1, 288,
+	186, 93,
 	-2, 245,
-	-1, 305,
-	63, 0,
-	159, 0,
-	-2, 247,
-	-1, 306,
-	63, 0,
-	159, 0,
-	-2, 249,
+	-1, 341,
+	84, 0,
+	-2, 286,
+	-1, 342,
+	66, 0,
+	169, 0,
+	-2, 288,
+	-1, 343,
+	66, 0,
+	169, 0,
+	-2, 290,
 }
 
-const yyNprod = 342
+const yyNprod = 402
 const yyPrivate = 57344
 
 var yyTokenNames []string
 var yyStates []string
 
-const yyLast = 3130
+const yyLast = 3472
 
 var yyAct = []int{
 
-	173, 3, 661, 649, 478, 659, 650, 590, 334, 318,
-	333, 506, 101, 102, 556, 601, 611, 337, 432, 420,
-	230, 586, 247, 146, 548, 231, 497, 373, 450, 226,
-	106, 279, 434, 431, 418, 165, 142, 16, 168, 483,
-	250, 326, 459, 273, 272, 158, 370, 75, 169, 417,
-	144, 232, 145, 139, 328, 242, 60, 125, 213, 296,
-	129, 280, 356, 354, 499, 374, 10, 143, 252, 377,
-	519, 150, 151, 95, 299, 300, 301, 518, 295, 355,
-	177, 178, 179, 180, 181, 182, 183, 184, 185, 186,
-	187, 188, 189, 190, 191, 192, 467, 130, 199, 200,
-	201, 451, 495, 593, 282, 467, 117, 193, 481, 594,
-	79, 296, 298, 251, 79, 466, 451, 281, 284, 148,
-	149, 583, 143, 98, 466, 82, 83, 84, 244, 78,
-	295, 562, 100, 78, 174, 175, 261, 563, 258, 257,
-	262, 97, 229, 176, 538, 161, 162, 376, 496, 81,
-	494, 118, 484, 485, 412, 259, 256, 255, 395, 269,
-	259, 65, 194, 607, 479, 216, 218, 220, 577, 288,
-	401, 402, 553, 539, 535, 119, 253, 291, 505, 403,
-	439, 467, 117, 117, 117, 233, 391, 174, 175, 117,
-	160, 261, 162, 482, 290, 296, 176, 304, 305, 306,
-	466, 283, 285, 287, 345, 286, 274, 100, 302, 297,
-	299, 300, 301, 343, 295, 320, 321, 245, 449, 99,
-	331, 119, 329, 327, 81, 121, 163, 118, 118, 118,
-	298, 248, 79, 388, 118, 521, 522, 462, 344, 147,
-	340, 119, 347, 234, 348, 85, 80, 82, 83, 84,
-	584, 78, 263, 592, 271, 332, 336, 633, 359, 152,
-	360, 660, 194, 363, 364, 365, 119, 271, 655, 351,
-	243, 317, 375, 81, 322, 587, 323, 419, 324, 330,
-	559, 195, 378, 578, 537, 341, 508, 393, 536, 342,
-	228, 260, 639, 595, 399, 357, 346, 404, 335, 387,
-	386, 674, 673, 303, 669, 140, 640, 79, 629, 394,
-	358, 221, 77, 296, 362, 336, 76, 481, 368, 369,
-	85, 80, 82, 83, 84, 141, 78, 297, 299, 300,
-	301, 197, 295, 392, 561, 110, 338, 126, 234, 426,
-	428, 429, 427, 339, 620, 219, 585, 134, 264, 196,
-	442, 425, 551, 319, 385, 435, 79, 421, 109, 116,
-	437, 194, 602, 76, 194, 194, 194, 194, 194, 194,
-	80, 82, 83, 84, 558, 78, 215, 446, 457, 448,
-	424, 241, 464, 217, 411, 617, 77, 552, 438, 112,
-	133, 501, 422, 444, 445, 389, 390, 76, 452, 443,
-	353, 132, 400, 638, 473, 405, 406, 407, 408, 409,
-	410, 161, 470, 327, 471, 275, 465, 352, 453, 455,
-	456, 468, 469, 463, 487, 458, 274, 198, 274, 488,
-	108, 254, 667, 77, 491, 76, 490, 500, 492, 493,
-	265, 266, 503, 383, 131, 313, 664, 236, 461, 461,
-	315, 310, 514, 665, 477, 143, 160, 671, 509, 670,
-	510, 212, 379, 240, 214, 489, 298, 77, 523, 447,
-	630, 154, 512, 223, 224, 225, 529, 502, 194, 215,
-	235, 380, 214, 534, 504, 520, 635, 372, 436, 524,
-	525, 516, 517, 460, 460, 540, 545, 542, 543, 588,
-	277, 541, 615, 486, 120, 77, 557, 294, 374, 616,
-	278, 74, 114, 113, 530, 435, 554, 532, 677, 476,
-	533, 550, 676, 549, 651, 249, 308, 246, 544, 546,
-	307, 311, 314, 382, 573, 136, 135, 576, 566, 600,
-	76, 115, 609, 515, 565, 672, 580, 95, 513, 296,
-	367, 366, 361, 239, 570, 571, 634, 454, 222, 423,
-	384, 381, 302, 297, 299, 300, 301, 2, 295, 312,
-	1, 555, 581, 575, 596, 605, 582, 579, 50, 589,
-	606, 103, 104, 632, 608, 560, 597, 156, 309, 298,
-	591, 507, 619, 618, 105, 557, 614, 98, 511, 603,
-	604, 623, 613, 612, 612, 350, 100, 549, 610, 621,
-	86, 646, 654, 498, 433, 430, 95, 547, 622, 628,
-	624, 480, 531, 81, 625, 626, 42, 41, 40, 39,
-	22, 21, 567, 568, 557, 644, 645, 631, 20, 19,
-	18, 636, 637, 17, 642, 9, 8, 648, 643, 647,
-	653, 652, 662, 641, 656, 658, 657, 663, 7, 86,
-	6, 5, 233, 4, 666, 95, 98, 413, 414, 668,
-	316, 325, 296, 107, 111, 100, 675, 662, 662, 679,
-	680, 678, 164, 599, 97, 302, 297, 299, 300, 301,
-	598, 295, 81, 99, 564, 371, 96, 270, 153, 227,
-	276, 159, 155, 87, 157, 72, 79, 73, 33, 128,
-	32, 55, 28, 58, 57, 98, 31, 124, 123, 85,
-	80, 82, 83, 84, 100, 78, 122, 30, 137, 138,
-	27, 51, 24, 97, 23, 67, 70, 0, 0, 0,
-	0, 81, 0, 0, 0, 96, 0, 0, 56, 0,
-	0, 0, 87, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 99, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 69, 0, 79, 526, 527, 0, 45,
-	71, 88, 89, 90, 91, 92, 93, 94, 85, 80,
-	82, 83, 84, 86, 78, 0, 209, 0, 0, 95,
-	0, 211, 206, 0, 0, 0, 0, 0, 0, 0,
-	0, 99, 0, 0, 0, 234, 29, 44, 0, 0,
-	0, 43, 47, 0, 79, 0, 0, 0, 0, 0,
-	88, 89, 90, 91, 92, 93, 94, 85, 80, 82,
-	83, 84, 86, 78, 0, 0, 415, 0, 95, 98,
-	0, 0, 0, 0, 26, 0, 0, 68, 100, 0,
-	49, 0, 0, 0, 0, 0, 46, 97, 0, 0,
-	0, 0, 416, 0, 0, 81, 0, 204, 0, 96,
-	203, 202, 207, 210, 0, 0, 87, 0, 0, 48,
-	25, 0, 52, 53, 54, 59, 0, 65, 98, 66,
-	0, 0, 0, 0, 0, 0, 0, 100, 0, 0,
-	0, 0, 0, 0, 0, 0, 97, 0, 0, 0,
-	208, 0, 0, 0, 81, 0, 0, 0, 96, 0,
-	0, 0, 0, 0, 0, 87, 0, 0, 0, 205,
-	0, 0, 0, 0, 0, 99, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 79, 474,
-	0, 0, 475, 0, 88, 89, 90, 91, 92, 93,
-	94, 85, 80, 82, 83, 84, 86, 78, 0, 0,
-	0, 0, 95, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 99, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 79, 0, 0,
-	0, 0, 0, 88, 89, 90, 91, 92, 93, 94,
-	85, 80, 82, 83, 84, 86, 78, 0, 0, 0,
-	0, 95, 98, 0, 0, 0, 0, 0, 0, 0,
-	0, 100, 63, 0, 0, 0, 0, 0, 0, 0,
-	97, 0, 0, 0, 0, 64, 0, 0, 81, 0,
-	0, 0, 96, 0, 0, 0, 61, 0, 0, 87,
-	0, 0, 36, 0, 0, 0, 0, 0, 62, 0,
-	0, 98, 0, 0, 0, 0, 15, 0, 13, 0,
-	100, 0, 0, 76, 0, 0, 0, 0, 0, 97,
-	0, 0, 0, 0, 0, 0, 34, 81, 0, 0,
-	0, 96, 0, 0, 0, 0, 0, 0, 87, 0,
-	0, 0, 0, 0, 0, 38, 0, 0, 99, 0,
+	193, 3, 788, 773, 531, 786, 774, 672, 10, 740,
+	361, 360, 108, 109, 574, 556, 381, 677, 504, 698,
+	388, 460, 708, 161, 316, 249, 165, 279, 630, 555,
+	620, 472, 117, 163, 248, 550, 418, 554, 16, 474,
+	471, 458, 285, 242, 585, 185, 389, 188, 415, 536,
+	284, 457, 276, 744, 513, 189, 309, 310, 177, 164,
+	158, 265, 133, 649, 260, 137, 82, 102, 287, 355,
+	250, 286, 317, 162, 401, 398, 335, 169, 170, 422,
+	688, 589, 552, 126, 588, 419, 622, 197, 198, 199,
+	200, 201, 202, 203, 204, 205, 206, 207, 208, 209,
+	210, 211, 212, 138, 78, 219, 220, 221, 167, 168,
+	400, 214, 124, 534, 521, 399, 521, 319, 333, 2,
+	105, 318, 548, 456, 333, 86, 179, 294, 295, 107,
+	162, 763, 86, 520, 113, 520, 262, 332, 104, 336,
+	337, 338, 299, 332, 85, 505, 88, 89, 90, 91,
+	505, 85, 194, 195, 676, 180, 213, 107, 247, 246,
+	622, 196, 126, 126, 126, 298, 333, 335, 488, 644,
+	668, 126, 532, 296, 88, 645, 440, 321, 306, 339,
+	334, 336, 337, 338, 623, 332, 762, 296, 639, 325,
+	622, 124, 124, 124, 298, 610, 421, 328, 549, 547,
+	124, 537, 538, 391, 293, 290, 689, 521, 335, 331,
+	292, 446, 447, 194, 195, 780, 277, 341, 342, 343,
+	448, 320, 196, 106, 214, 289, 520, 779, 327, 724,
+	232, 233, 322, 324, 323, 116, 86, 704, 354, 686,
+	681, 560, 71, 662, 311, 268, 270, 272, 638, 92,
+	87, 89, 90, 91, 478, 85, 373, 333, 635, 629,
+	376, 750, 377, 611, 86, 607, 436, 383, 384, 340,
+	339, 334, 336, 337, 338, 390, 332, 92, 87, 89,
+	90, 91, 374, 85, 183, 359, 181, 372, 263, 367,
+	369, 633, 570, 565, 335, 404, 366, 405, 333, 563,
+	408, 409, 410, 535, 503, 497, 394, 494, 364, 420,
+	357, 339, 334, 336, 337, 338, 127, 332, 358, 423,
+	356, 482, 370, 375, 438, 129, 380, 183, 251, 181,
+	335, 444, 593, 594, 449, 111, 171, 183, 413, 414,
+	214, 166, 402, 214, 214, 214, 214, 214, 214, 432,
+	396, 431, 280, 289, 403, 353, 433, 690, 407, 127,
+	439, 434, 435, 573, 182, 252, 466, 468, 469, 368,
+	501, 467, 288, 183, 385, 516, 386, 437, 387, 487,
+	465, 183, 282, 125, 333, 445, 183, 234, 450, 451,
+	452, 453, 454, 455, 88, 475, 477, 339, 334, 336,
+	337, 338, 479, 332, 480, 127, 123, 297, 125, 464,
+	215, 674, 308, 127, 179, 559, 669, 83, 127, 747,
+	333, 300, 261, 511, 787, 782, 499, 518, 678, 502,
+	481, 459, 641, 506, 334, 336, 337, 338, 127, 332,
+	363, 663, 500, 180, 493, 609, 496, 608, 498, 527,
+	576, 308, 371, 237, 699, 244, 691, 755, 802, 522,
+	523, 801, 277, 217, 540, 214, 797, 514, 514, 541,
+	362, 524, 512, 525, 544, 519, 509, 553, 558, 510,
+	517, 216, 507, 543, 86, 545, 546, 566, 363, 390,
+	311, 252, 311, 756, 84, 737, 515, 515, 87, 89,
+	90, 91, 112, 85, 428, 159, 582, 382, 83, 162,
+	530, 312, 142, 84, 577, 542, 761, 578, 273, 670,
+	134, 562, 595, 643, 301, 424, 160, 66, 534, 146,
+	601, 580, 584, 568, 640, 567, 606, 141, 767, 587,
+	236, 271, 259, 721, 425, 153, 569, 269, 612, 617,
+	614, 615, 148, 592, 627, 152, 720, 596, 597, 122,
+	558, 613, 590, 726, 461, 365, 218, 632, 144, 760,
+	430, 558, 83, 564, 602, 302, 303, 754, 485, 621,
+	604, 475, 605, 626, 636, 84, 150, 648, 616, 618,
+	628, 483, 653, 683, 634, 83, 147, 625, 716, 151,
+	462, 83, 591, 427, 586, 561, 658, 495, 637, 661,
+	397, 395, 291, 266, 795, 267, 140, 266, 665, 792,
+	714, 647, 650, 558, 759, 675, 793, 715, 267, 799,
+	798, 651, 652, 738, 81, 314, 768, 93, 667, 417,
+	655, 656, 173, 102, 687, 315, 660, 264, 697, 84,
+	664, 476, 671, 666, 258, 680, 254, 238, 679, 692,
+	702, 419, 685, 539, 128, 703, 120, 119, 805, 705,
+	804, 775, 84, 693, 283, 711, 700, 701, 84, 239,
+	240, 241, 278, 155, 154, 162, 696, 731, 253, 235,
+	727, 281, 83, 710, 713, 621, 105, 729, 730, 707,
+	121, 709, 709, 245, 769, 107, 706, 583, 723, 581,
+	412, 411, 406, 257, 104, 800, 748, 175, 736, 728,
+	722, 35, 88, 390, 684, 508, 103, 274, 214, 55,
+	392, 624, 102, 94, 777, 732, 733, 463, 739, 429,
+	426, 1, 746, 745, 642, 115, 735, 673, 558, 766,
+	214, 758, 751, 752, 753, 575, 725, 757, 118, 579,
+	393, 764, 770, 765, 781, 772, 771, 551, 473, 470,
+	619, 533, 778, 603, 776, 572, 789, 93, 783, 785,
+	251, 790, 784, 102, 571, 105, 214, 791, 149, 24,
+	47, 794, 229, 46, 107, 23, 796, 231, 226, 106,
+	45, 44, 43, 42, 803, 789, 789, 807, 808, 806,
+	93, 88, 86, 598, 599, 22, 102, 21, 95, 96,
+	97, 98, 99, 100, 101, 92, 87, 89, 90, 91,
+	20, 85, 19, 18, 17, 110, 105, 9, 8, 7,
+	6, 5, 4, 489, 93, 107, 490, 379, 491, 139,
+	102, 143, 184, 695, 104, 694, 646, 416, 307, 172,
+	243, 313, 88, 178, 174, 176, 103, 79, 80, 105,
+	65, 145, 275, 94, 36, 492, 224, 136, 107, 223,
+	222, 227, 230, 34, 719, 718, 717, 104, 106, 682,
+	631, 350, 60, 30, 63, 88, 352, 347, 62, 103,
+	33, 86, 132, 105, 131, 130, 94, 32, 156, 157,
+	29, 56, 107, 26, 92, 87, 89, 90, 91, 25,
+	85, 104, 0, 0, 0, 228, 0, 0, 0, 88,
+	0, 0, 0, 103, 0, 0, 0, 0, 0, 106,
+	94, 0, 0, 252, 0, 225, 0, 0, 0, 0,
+	0, 0, 86, 0, 0, 0, 0, 0, 95, 96,
+	97, 98, 99, 100, 101, 92, 87, 89, 90, 91,
+	0, 85, 106, 0, 0, 345, 0, 0, 93, 344,
+	348, 351, 0, 0, 102, 86, 528, 0, 0, 529,
+	0, 95, 96, 97, 98, 99, 100, 101, 92, 87,
+	89, 90, 91, 0, 85, 0, 106, 0, 0, 93,
+	0, 0, 0, 0, 0, 102, 0, 0, 0, 86,
+	0, 0, 0, 0, 349, 95, 96, 97, 98, 99,
+	100, 101, 92, 87, 89, 90, 91, 105, 85, 0,
+	0, 0, 93, 0, 346, 251, 107, 0, 102, 0,
+	0, 0, 0, 0, 0, 104, 0, 0, 0, 0,
+	0, 0, 0, 88, 0, 0, 0, 103, 105, 0,
+	0, 0, 0, 0, 94, 0, 0, 107, 0, 102,
+	0, 0, 0, 0, 0, 0, 104, 0, 0, 0,
+	0, 0, 0, 0, 88, 0, 0, 0, 103, 0,
+	0, 105, 0, 0, 0, 94, 0, 0, 0, 0,
+	107, 0, 0, 0, 0, 0, 0, 0, 0, 104,
+	0, 0, 0, 0, 0, 0, 0, 88, 0, 0,
+	0, 103, 105, 0, 0, 0, 0, 0, 94, 0,
+	106, 107, 0, 0, 0, 0, 0, 0, 0, 0,
+	104, 0, 0, 86, 441, 442, 0, 0, 88, 95,
+	96, 97, 98, 99, 100, 101, 92, 87, 89, 90,
+	91, 106, 85, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 86, 329, 0, 0, 330, 0,
+	95, 96, 97, 98, 99, 100, 101, 92, 87, 89,
+	90, 91, 187, 85, 106, 0, 73, 76, 252, 0,
+	0, 0, 0, 0, 0, 0, 0, 86, 0, 61,
+	0, 0, 0, 95, 96, 97, 98, 99, 100, 101,
+	92, 87, 89, 90, 91, 106, 326, 0, 186, 0,
+	93, 0, 191, 0, 0, 75, 102, 0, 86, 12,
+	0, 50, 77, 0, 0, 0, 0, 98, 99, 100,
+	101, 92, 87, 89, 90, 91, 0, 85, 0, 0,
+	0, 93, 0, 0, 0, 0, 0, 102, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 79, 396, 397, 0, 14, 0, 88, 89, 90,
-	91, 92, 93, 94, 85, 80, 82, 83, 84, 86,
-	78, 0, 233, 77, 0, 95, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 99, 0, 0,
-	0, 0, 0, 37, 35, 0, 0, 0, 0, 0,
-	79, 292, 0, 0, 293, 0, 88, 89, 90, 91,
-	92, 93, 94, 85, 80, 82, 83, 84, 0, 78,
-	0, 0, 0, 0, 0, 98, 0, 0, 0, 0,
-	0, 0, 0, 0, 100, 0, 0, 0, 0, 0,
-	0, 0, 167, 97, 0, 0, 67, 70, 0, 0,
-	0, 81, 0, 0, 0, 96, 0, 0, 0, 56,
-	0, 0, 87, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 166, 0, 0,
-	0, 171, 0, 0, 69, 0, 0, 0, 12, 0,
-	45, 71, 0, 0, 0, 0, 0, 0, 86, 0,
-	0, 0, 0, 0, 95, 0, 0, 0, 0, 0,
+	31, 49, 0, 0, 11, 48, 52, 0, 0, 105,
+	0, 0, 0, 0, 93, 0, 0, 0, 107, 0,
+	102, 0, 0, 0, 0, 0, 0, 104, 190, 0,
+	0, 0, 0, 0, 0, 88, 0, 0, 0, 103,
+	105, 0, 0, 0, 28, 0, 94, 74, 0, 107,
+	54, 0, 0, 0, 0, 0, 51, 0, 104, 0,
+	0, 0, 0, 0, 0, 0, 88, 0, 0, 0,
+	103, 0, 0, 105, 0, 0, 0, 94, 0, 0,
+	53, 27, 107, 57, 58, 59, 64, 0, 71, 0,
+	72, 104, 0, 0, 0, 0, 0, 0, 0, 88,
+	0, 0, 0, 103, 0, 192, 0, 0, 0, 308,
+	94, 0, 106, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 86, 0, 0, 0, 0,
+	0, 95, 96, 97, 98, 99, 100, 101, 92, 87,
+	89, 90, 91, 106, 85, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 749, 86, 0, 0, 0,
+	0, 0, 95, 96, 97, 98, 99, 100, 101, 92,
+	87, 89, 90, 91, 93, 85, 106, 0, 0, 0,
+	102, 0, 0, 0, 0, 0, 734, 0, 0, 86,
+	0, 0, 0, 0, 0, 95, 96, 97, 98, 99,
+	100, 101, 92, 87, 89, 90, 91, 93, 85, 0,
+	0, 0, 0, 102, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 712, 0, 0, 0, 0, 0,
+	0, 0, 0, 105, 0, 0, 0, 0, 93, 0,
+	0, 0, 107, 0, 102, 0, 0, 0, 0, 0,
+	0, 104, 0, 0, 0, 0, 0, 552, 0, 88,
+	0, 0, 0, 103, 0, 0, 105, 0, 0, 0,
+	94, 0, 0, 0, 0, 107, 0, 0, 0, 0,
+	0, 0, 0, 0, 104, 0, 0, 0, 0, 0,
+	0, 0, 88, 0, 0, 0, 103, 105, 0, 0,
+	0, 0, 0, 94, 0, 0, 107, 0, 0, 0,
+	0, 0, 0, 0, 0, 104, 0, 0, 0, 0,
+	0, 0, 0, 88, 0, 0, 0, 103, 0, 0,
+	0, 0, 0, 0, 94, 0, 106, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 86,
+	0, 0, 0, 0, 0, 95, 96, 97, 98, 99,
+	100, 101, 92, 87, 89, 90, 91, 0, 85, 106,
+	0, 0, 93, 0, 0, 0, 0, 0, 102, 0,
+	0, 0, 86, 0, 0, 0, 0, 0, 95, 96,
+	97, 98, 99, 100, 101, 92, 87, 89, 90, 91,
+	106, 85, 0, 93, 0, 0, 0, 0, 0, 102,
+	0, 0, 0, 86, 0, 0, 659, 0, 0, 95,
+	96, 97, 98, 99, 100, 101, 92, 87, 89, 90,
+	91, 105, 85, 0, 0, 0, 93, 0, 0, 0,
+	107, 0, 102, 0, 0, 0, 0, 0, 0, 104,
+	0, 0, 0, 0, 0, 0, 0, 88, 0, 0,
+	0, 103, 105, 0, 0, 0, 0, 0, 94, 0,
+	0, 107, 0, 0, 0, 0, 0, 0, 0, 0,
+	104, 0, 0, 0, 0, 0, 0, 0, 88, 0,
+	0, 0, 103, 0, 0, 105, 0, 0, 0, 94,
+	0, 0, 0, 0, 107, 0, 0, 0, 0, 0,
+	0, 0, 0, 104, 0, 0, 0, 0, 0, 0,
+	0, 88, 0, 0, 0, 103, 0, 0, 0, 0,
+	0, 0, 94, 0, 106, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 86, 657, 0,
+	0, 0, 0, 95, 96, 97, 98, 99, 100, 101,
+	92, 87, 89, 90, 91, 106, 85, 0, 0, 0,
+	93, 0, 0, 0, 0, 0, 102, 0, 86, 654,
+	0, 0, 0, 0, 95, 96, 97, 98, 99, 100,
+	101, 92, 87, 89, 90, 91, 0, 85, 106, 0,
+	0, 93, 0, 0, 0, 0, 0, 102, 0, 0,
+	0, 86, 526, 0, 0, 0, 0, 95, 96, 97,
+	98, 99, 100, 101, 92, 87, 89, 90, 91, 105,
+	85, 0, 0, 0, 93, 0, 0, 0, 107, 0,
+	102, 0, 0, 0, 0, 0, 0, 104, 0, 0,
+	0, 0, 0, 0, 0, 88, 0, 0, 0, 103,
+	105, 0, 0, 0, 0, 0, 94, 0, 0, 107,
+	0, 0, 0, 0, 0, 0, 0, 0, 104, 0,
+	0, 0, 0, 0, 0, 0, 88, 0, 0, 0,
+	103, 0, 0, 105, 0, 0, 0, 94, 0, 0,
+	0, 0, 107, 0, 0, 0, 0, 486, 0, 0,
+	0, 104, 0, 0, 0, 0, 0, 0, 0, 88,
+	0, 0, 0, 103, 0, 0, 0, 0, 0, 0,
+	94, 0, 106, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 86, 0, 0, 0, 0,
+	0, 95, 96, 97, 98, 99, 100, 101, 92, 87,
+	89, 90, 91, 106, 85, 0, 0, 0, 0, 0,
+	0, 378, 0, 484, 0, 0, 86, 0, 0, 0,
+	0, 0, 95, 96, 97, 98, 99, 100, 101, 92,
+	87, 89, 90, 91, 93, 85, 106, 0, 0, 0,
+	102, 0, 0, 0, 0, 0, 0, 0, 0, 86,
+	0, 0, 0, 0, 0, 95, 96, 97, 98, 99,
+	100, 101, 92, 87, 89, 90, 91, 93, 85, 0,
+	0, 0, 0, 102, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 305, 0, 0, 0, 0, 0,
+	0, 0, 0, 105, 0, 0, 0, 0, 0, 0,
+	0, 0, 107, 0, 0, 0, 93, 0, 0, 0,
+	0, 104, 102, 0, 0, 0, 0, 304, 0, 88,
+	0, 0, 0, 103, 0, 0, 105, 0, 0, 0,
+	94, 0, 0, 0, 0, 107, 0, 0, 0, 0,
+	0, 0, 0, 0, 104, 0, 0, 0, 0, 0,
+	0, 0, 88, 0, 0, 0, 103, 0, 0, 0,
+	0, 0, 0, 94, 0, 105, 0, 0, 0, 0,
+	0, 0, 0, 0, 107, 0, 0, 0, 0, 0,
+	0, 0, 0, 104, 0, 0, 0, 0, 0, 0,
+	0, 88, 0, 0, 0, 103, 106, 0, 0, 0,
+	0, 0, 94, 0, 0, 0, 0, 0, 0, 86,
+	0, 0, 0, 0, 0, 95, 96, 97, 98, 99,
+	100, 101, 92, 87, 89, 90, 91, 0, 85, 106,
+	0, 73, 76, 0, 0, 0, 0, 0, 0, 0,
+	93, 0, 86, 0, 61, 0, 102, 0, 95, 96,
+	97, 98, 99, 100, 101, 92, 87, 89, 90, 91,
+	0, 85, 0, 0, 135, 0, 0, 191, 106, 0,
+	75, 0, 0, 0, 12, 0, 50, 77, 93, 0,
+	0, 86, 0, 0, 102, 0, 0, 95, 96, 97,
+	98, 99, 100, 101, 92, 87, 89, 90, 91, 105,
+	85, 0, 0, 0, 0, 0, 0, 0, 107, 0,
+	0, 0, 0, 0, 0, 31, 49, 104, 0, 11,
+	48, 52, 0, 0, 0, 88, 0, 0, 0, 103,
+	0, 0, 0, 0, 0, 0, 94, 105, 0, 0,
+	0, 0, 0, 190, 0, 0, 107, 0, 0, 0,
+	0, 0, 0, 0, 0, 104, 0, 0, 0, 28,
+	0, 0, 74, 88, 0, 54, 0, 103, 0, 0,
+	0, 51, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 99, 0, 0, 0, 234, 0, 29, 44, 0,
-	0, 11, 43, 47, 79, 0, 0, 0, 0, 0,
-	88, 89, 90, 91, 92, 93, 94, 85, 80, 82,
-	83, 84, 170, 289, 98, 86, 0, 0, 0, 0,
-	0, 95, 0, 100, 0, 26, 0, 0, 68, 0,
-	0, 49, 97, 0, 0, 0, 0, 46, 0, 0,
-	81, 0, 0, 0, 96, 0, 0, 0, 0, 0,
-	0, 87, 0, 0, 0, 0, 0, 0, 0, 0,
-	48, 25, 0, 52, 53, 54, 59, 0, 65, 0,
-	66, 98, 0, 0, 0, 0, 0, 0, 0, 0,
-	100, 0, 0, 0, 0, 172, 0, 0, 86, 97,
-	0, 0, 0, 0, 95, 0, 0, 81, 0, 0,
-	0, 96, 0, 0, 0, 0, 0, 271, 87, 0,
-	99, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 79, 0, 0, 0, 0, 0, 88,
-	89, 90, 91, 92, 93, 94, 85, 80, 82, 83,
-	84, 0, 78, 0, 98, 0, 0, 0, 0, 0,
-	86, 0, 0, 100, 0, 0, 95, 0, 0, 0,
-	0, 0, 97, 0, 0, 0, 0, 99, 0, 0,
-	81, 0, 0, 0, 96, 0, 0, 627, 0, 0,
-	79, 87, 0, 0, 0, 0, 88, 89, 90, 91,
-	92, 93, 94, 85, 80, 82, 83, 84, 499, 78,
-	0, 0, 0, 0, 0, 0, 98, 0, 0, 0,
-	0, 0, 0, 0, 0, 100, 0, 86, 0, 0,
-	0, 0, 0, 95, 97, 0, 0, 0, 0, 0,
-	0, 0, 81, 0, 0, 0, 96, 0, 0, 0,
-	99, 0, 0, 87, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 79, 0, 0, 574, 0, 0, 88,
-	89, 90, 91, 92, 93, 94, 85, 80, 82, 83,
-	84, 0, 78, 98, 0, 0, 0, 86, 0, 0,
-	0, 0, 100, 95, 0, 0, 0, 0, 0, 0,
-	0, 97, 0, 0, 0, 0, 0, 0, 0, 81,
-	0, 0, 99, 96, 0, 0, 0, 0, 0, 0,
-	87, 0, 0, 0, 0, 79, 0, 0, 0, 0,
-	0, 88, 89, 90, 91, 92, 93, 94, 85, 80,
-	82, 83, 84, 98, 78, 0, 0, 0, 0, 0,
-	0, 0, 100, 0, 86, 0, 0, 0, 0, 0,
-	95, 97, 0, 0, 0, 0, 0, 0, 0, 81,
-	0, 0, 0, 96, 0, 0, 0, 0, 0, 99,
-	87, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 79, 572, 0, 0, 0, 0, 88, 89,
-	90, 91, 92, 93, 94, 85, 80, 82, 83, 84,
-	98, 78, 0, 0, 86, 0, 0, 0, 0, 100,
-	95, 0, 0, 0, 0, 0, 0, 0, 97, 0,
-	0, 0, 0, 0, 0, 0, 81, 0, 0, 99,
-	96, 0, 0, 0, 0, 0, 0, 87, 0, 0,
-	0, 0, 79, 569, 0, 0, 0, 0, 88, 89,
-	90, 91, 92, 93, 94, 85, 80, 82, 83, 84,
-	98, 78, 0, 0, 0, 0, 0, 0, 0, 100,
-	0, 86, 0, 0, 0, 0, 0, 95, 97, 0,
-	0, 0, 0, 0, 0, 0, 81, 0, 0, 0,
-	96, 0, 0, 0, 0, 0, 99, 87, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 79,
-	472, 0, 0, 0, 0, 88, 89, 90, 91, 92,
-	93, 94, 85, 80, 82, 83, 84, 98, 78, 0,
-	0, 86, 441, 0, 0, 0, 100, 95, 0, 0,
-	0, 0, 0, 0, 0, 97, 0, 0, 0, 0,
-	0, 0, 0, 81, 0, 0, 99, 96, 0, 0,
-	0, 0, 0, 0, 87, 0, 0, 0, 0, 79,
-	0, 0, 0, 0, 0, 88, 89, 90, 91, 92,
-	93, 94, 85, 80, 82, 83, 84, 98, 78, 0,
-	0, 0, 0, 0, 0, 0, 100, 0, 0, 0,
-	0, 0, 0, 0, 0, 97, 86, 0, 0, 0,
-	0, 0, 95, 81, 0, 0, 0, 96, 0, 0,
-	0, 0, 0, 99, 87, 0, 0, 0, 0, 0,
-	0, 0, 0, 440, 0, 0, 79, 0, 0, 0,
-	0, 0, 88, 89, 90, 91, 92, 93, 94, 85,
-	80, 82, 83, 84, 268, 78, 0, 0, 0, 349,
-	0, 0, 98, 0, 0, 0, 86, 0, 0, 0,
-	0, 100, 95, 0, 0, 0, 0, 0, 0, 0,
-	97, 0, 0, 99, 0, 0, 0, 0, 81, 0,
-	0, 0, 96, 0, 0, 0, 79, 0, 0, 87,
-	0, 0, 88, 89, 90, 91, 92, 93, 94, 85,
-	80, 82, 83, 84, 267, 78, 0, 0, 0, 0,
-	0, 0, 98, 0, 0, 0, 0, 0, 0, 0,
-	0, 100, 0, 86, 0, 0, 0, 0, 0, 95,
-	97, 0, 0, 0, 0, 0, 0, 0, 81, 0,
-	0, 0, 96, 0, 0, 0, 0, 0, 99, 87,
+	0, 0, 0, 0, 0, 53, 27, 0, 57, 58,
+	59, 64, 106, 71, 0, 72, 73, 76, 0, 0,
+	0, 0, 0, 0, 0, 86, 0, 0, 0, 61,
+	192, 95, 96, 97, 98, 99, 100, 101, 92, 87,
+	89, 90, 91, 0, 85, 0, 0, 0, 255, 0,
+	106, 0, 0, 0, 0, 75, 102, 0, 0, 12,
+	0, 50, 77, 86, 0, 0, 0, 0, 0, 95,
+	96, 97, 98, 99, 100, 101, 92, 87, 89, 90,
+	91, 0, 85, 73, 76, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 61, 0, 0, 0,
+	31, 49, 0, 0, 11, 48, 52, 0, 0, 105,
+	0, 0, 0, 0, 0, 0, 0, 0, 107, 0,
+	0, 0, 75, 0, 0, 0, 12, 104, 50, 77,
+	0, 0, 0, 0, 0, 88, 0, 0, 0, 103,
+	0, 0, 0, 0, 28, 0, 0, 74, 0, 0,
+	54, 0, 0, 0, 0, 0, 51, 0, 0, 0,
+	0, 0, 0, 69, 0, 0, 0, 31, 49, 0,
+	0, 11, 48, 52, 0, 0, 70, 0, 0, 0,
+	53, 27, 0, 57, 58, 59, 64, 67, 71, 0,
+	72, 0, 0, 0, 39, 0, 0, 0, 0, 0,
+	68, 0, 0, 0, 0, 256, 0, 0, 15, 0,
+	13, 28, 106, 0, 74, 0, 83, 54, 0, 0,
+	0, 0, 0, 51, 0, 86, 0, 0, 0, 0,
+	37, 95, 96, 97, 98, 99, 100, 101, 92, 87,
+	89, 90, 91, 0, 85, 0, 0, 53, 27, 41,
+	57, 58, 59, 64, 0, 71, 0, 72, 69, 0,
+	0, 73, 76, 0, 0, 0, 0, 0, 0, 0,
+	14, 70, 192, 0, 61, 0, 0, 0, 0, 0,
+	0, 0, 67, 0, 73, 76, 0, 0, 0, 39,
+	0, 0, 0, 84, 0, 68, 0, 61, 0, 0,
+	75, 0, 0, 15, 12, 13, 50, 77, 0, 0,
+	0, 83, 0, 0, 40, 38, 0, 0, 0, 0,
+	0, 0, 0, 75, 0, 37, 66, 12, 0, 50,
+	77, 114, 0, 0, 83, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 41, 31, 49, 0, 0, 11,
+	48, 52, 0, 0, 0, 0, 0, 73, 76, 0,
+	0, 0, 0, 0, 0, 14, 0, 0, 31, 49,
+	61, 0, 11, 48, 52, 0, 0, 73, 76, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 84, 28,
+	61, 0, 74, 0, 0, 54, 75, 0, 0, 0,
+	12, 51, 50, 77, 0, 0, 0, 0, 0, 40,
+	38, 84, 28, 0, 0, 74, 75, 0, 54, 0,
+	12, 66, 50, 77, 51, 53, 27, 0, 57, 58,
+	59, 64, 0, 71, 0, 72, 0, 0, 0, 0,
+	0, 31, 49, 0, 66, 11, 48, 52, 53, 27,
+	0, 57, 58, 59, 64, 0, 71, 0, 72, 0,
+	0, 31, 49, 0, 0, 11, 48, 52, 0, 0,
+	73, 76, 0, 0, 0, 741, 0, 0, 0, 0,
+	0, 0, 0, 61, 0, 28, 0, 0, 74, 0,
+	0, 54, 743, 0, 0, 0, 0, 51, 0, 0,
+	0, 0, 0, 0, 0, 28, 0, 0, 74, 75,
+	0, 54, 0, 0, 0, 50, 77, 51, 0, 0,
+	0, 53, 27, 0, 57, 58, 59, 64, 0, 71,
+	0, 72, 600, 0, 0, 73, 76, 0, 0, 0,
+	0, 53, 27, 0, 57, 58, 59, 64, 61, 71,
+	0, 72, 443, 0, 31, 49, 0, 0, 0, 48,
+	52, 0, 0, 0, 0, 0, 0, 557, 0, 0,
+	0, 0, 0, 0, 75, 0, 0, 0, 12, 0,
+	50, 77, 0, 73, 76, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 61, 0, 28, 0,
+	0, 74, 73, 76, 54, 0, 0, 0, 0, 0,
+	51, 0, 742, 0, 0, 61, 0, 0, 0, 31,
+	49, 0, 75, 11, 48, 52, 12, 0, 50, 77,
+	0, 0, 0, 0, 53, 27, 0, 57, 58, 59,
+	64, 75, 71, 0, 72, 12, 0, 50, 77, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 79, 0, 0, 0, 0, 0, 88, 89, 90,
-	91, 92, 93, 94, 85, 80, 82, 83, 84, 98,
-	78, 0, 0, 86, 0, 0, 0, 0, 100, 95,
-	0, 0, 0, 0, 0, 0, 0, 97, 0, 0,
-	0, 0, 0, 0, 0, 81, 0, 0, 99, 96,
-	0, 0, 0, 0, 0, 0, 87, 0, 0, 0,
-	0, 79, 0, 0, 0, 0, 0, 88, 89, 90,
-	91, 92, 93, 94, 85, 80, 82, 83, 84, 98,
-	78, 0, 0, 0, 0, 0, 0, 0, 100, 0,
-	0, 0, 0, 0, 0, 0, 0, 97, 0, 0,
-	0, 0, 0, 0, 0, 81, 0, 0, 0, 96,
-	0, 127, 0, 0, 0, 99, 87, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 79, 0,
-	0, 0, 0, 0, 88, 89, 90, 91, 92, 93,
-	94, 85, 80, 82, 83, 84, 0, 78, 67, 70,
-	0, 0, 0, 0, 0, 0, 0, 86, 0, 0,
-	0, 56, 0, 95, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 99, 0, 0, 0, 0,
-	0, 0, 0, 171, 0, 0, 69, 0, 79, 0,
-	12, 0, 45, 71, 88, 89, 90, 91, 92, 93,
-	94, 85, 80, 82, 83, 84, 0, 78, 0, 0,
-	0, 0, 0, 98, 0, 95, 0, 0, 0, 0,
-	0, 0, 100, 0, 0, 0, 0, 0, 0, 29,
-	44, 97, 0, 11, 43, 47, 0, 0, 0, 81,
-	0, 0, 0, 96, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 170, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 98, 0, 26, 0, 0,
-	68, 0, 0, 49, 100, 0, 0, 0, 0, 46,
-	0, 0, 0, 97, 0, 0, 0, 0, 0, 0,
-	0, 81, 0, 0, 0, 96, 0, 0, 0, 0,
-	0, 0, 48, 25, 0, 52, 53, 54, 59, 99,
-	65, 0, 66, 67, 70, 0, 0, 0, 0, 0,
-	0, 0, 79, 0, 0, 0, 56, 172, 88, 89,
-	90, 91, 92, 93, 94, 85, 80, 82, 83, 84,
-	0, 78, 0, 0, 237, 0, 0, 0, 0, 0,
-	0, 69, 0, 0, 0, 12, 0, 45, 71, 0,
-	0, 99, 0, 0, 67, 70, 0, 0, 0, 0,
-	0, 0, 0, 0, 79, 0, 0, 56, 0, 0,
-	88, 89, 90, 91, 92, 93, 94, 85, 80, 82,
-	83, 84, 0, 78, 29, 44, 0, 0, 11, 43,
-	47, 0, 69, 0, 0, 0, 12, 0, 45, 71,
+	0, 0, 0, 28, 0, 0, 74, 31, 49, 54,
+	0, 11, 48, 52, 0, 51, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 31, 49, 365, 0,
+	11, 48, 52, 0, 0, 73, 76, 0, 0, 53,
+	27, 0, 57, 58, 59, 64, 0, 71, 61, 72,
+	0, 28, 0, 0, 74, 73, 76, 54, 0, 0,
+	0, 0, 0, 51, 0, 0, 0, 0, 61, 0,
+	28, 0, 0, 74, 75, 0, 54, 743, 12, 0,
+	50, 77, 51, 0, 0, 0, 0, 53, 27, 0,
+	57, 58, 59, 64, 75, 71, 0, 72, 0, 135,
+	50, 77, 0, 0, 0, 0, 53, 27, 0, 57,
+	58, 59, 64, 0, 71, 0, 72, 0, 0, 31,
+	49, 0, 0, 11, 48, 52, 0, 0, 73, 76,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 31,
+	49, 61, 0, 0, 48, 52, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 28, 0, 0, 74, 75, 0, 54,
+	0, 0, 0, 50, 77, 51, 0, 0, 0, 0,
+	0, 0, 0, 28, 0, 0, 74, 0, 0, 54,
+	0, 0, 0, 0, 0, 51, 0, 742, 0, 53,
+	27, 0, 57, 58, 59, 64, 0, 71, 69, 72,
+	0, 0, 31, 49, 0, 0, 0, 48, 52, 53,
+	27, 70, 57, 58, 59, 64, 0, 71, 0, 72,
+	0, 0, 67, 0, 0, 0, 0, 0, 0, 39,
+	0, 0, 0, 0, 0, 68, 0, 0, 0, 0,
+	0, 0, 0, 15, 0, 13, 28, 0, 0, 74,
+	0, 83, 54, 0, 0, 0, 0, 0, 51, 0,
+	0, 0, 0, 0, 0, 37, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 26, 0, 0, 68, 0, 0, 49, 0,
-	0, 0, 0, 0, 46, 29, 44, 0, 0, 11,
-	43, 47, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 48, 25, 0,
-	52, 53, 54, 59, 0, 65, 0, 66, 0, 0,
-	0, 0, 0, 26, 0, 0, 68, 0, 0, 49,
-	0, 0, 238, 0, 0, 46, 0, 0, 0, 0,
-	0, 63, 0, 0, 67, 70, 0, 0, 0, 0,
-	0, 95, 0, 0, 64, 0, 0, 56, 48, 25,
-	0, 52, 53, 54, 59, 61, 65, 0, 66, 0,
-	0, 36, 0, 0, 0, 0, 0, 62, 0, 0,
-	0, 0, 69, 172, 0, 15, 12, 13, 45, 71,
-	0, 0, 76, 0, 0, 0, 0, 0, 0, 0,
-	0, 98, 0, 0, 0, 34, 0, 0, 0, 0,
-	100, 0, 0, 0, 0, 0, 0, 0, 0, 97,
-	0, 0, 0, 0, 38, 29, 44, 81, 0, 11,
-	43, 47, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 14, 0, 0, 0, 0, 0,
-	0, 67, 70, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 77, 26, 56, 0, 68, 67, 70, 49,
-	0, 0, 0, 0, 0, 46, 0, 0, 0, 0,
-	56, 0, 37, 35, 0, 0, 0, 0, 0, 69,
-	0, 0, 0, 12, 0, 45, 71, 99, 48, 25,
-	0, 52, 53, 54, 59, 69, 65, 0, 66, 12,
-	79, 45, 71, 0, 0, 0, 0, 0, 0, 91,
-	92, 93, 94, 85, 80, 82, 83, 84, 0, 78,
-	0, 0, 29, 44, 0, 0, 11, 43, 47, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 29, 44,
-	0, 0, 11, 43, 47, 67, 70, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 56, 0,
-	26, 0, 0, 68, 0, 0, 49, 0, 0, 0,
-	0, 0, 46, 0, 0, 0, 26, 0, 0, 68,
-	0, 0, 49, 69, 0, 0, 0, 12, 46, 45,
-	71, 0, 0, 76, 0, 48, 25, 0, 52, 53,
-	54, 59, 0, 65, 0, 66, 528, 67, 70, 0,
-	0, 48, 25, 0, 52, 53, 54, 59, 0, 65,
-	56, 66, 398, 0, 0, 0, 29, 44, 0, 0,
-	11, 43, 47, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 69, 0, 0, 0, 12,
-	0, 45, 71, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 77, 26, 0, 0, 68, 0, 0,
-	49, 0, 0, 0, 0, 0, 46, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 29, 44,
-	0, 0, 11, 43, 47, 67, 70, 0, 0, 48,
-	25, 0, 52, 53, 54, 59, 0, 65, 56, 66,
+	0, 0, 53, 27, 41, 57, 58, 59, 64, 0,
+	71, 0, 72, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 14, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 26, 0, 0, 68,
-	0, 0, 49, 69, 0, 0, 0, 12, 46, 45,
-	71, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 127, 0, 0, 0, 0, 0,
-	0, 48, 25, 0, 52, 53, 54, 59, 0, 65,
-	0, 66, 0, 0, 0, 0, 29, 44, 0, 0,
-	11, 43, 47, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 84, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 26, 0, 0, 68, 0, 0,
-	49, 0, 0, 0, 0, 0, 46, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 48,
-	25, 0, 52, 53, 54, 59, 0, 65, 0, 66,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 40,
+	38, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 66,
 }
 var yyPact = []int{
 
-	2596, -1000, -1000, 2116, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, 2957, 2957, 1037, 1037, -9, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 2957,
-	-1000, -1000, -1000, 288, 442, 441, 485, 78, 433, -1000,
+	2693, -1000, -1000, 2283, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, 3127, 3127, 329, 2598, 62, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, 57, 2869, -1000, -1000, 2807,
-	-1000, 335, 281, 470, 469, 172, 2957, 76, 76, 76,
-	2957, 2957, -1000, -1000, 392, 484, 58, 1228, 24, 2957,
-	2957, 2957, 2957, 2957, 2957, 2957, 2957, 2957, 2957, 2957,
-	2957, 2957, 2957, 2957, 2957, 727, 268, 2957, 2957, 2957,
-	787, 2302, 135, -1000, -1000, -1000, -58, 382, 379, 341,
-	307, -1000, 539, 78, 78, 78, 142, -34, 175, -1000,
-	78, 2405, 508, -1000, -1000, 2056, 226, 2957, 48, 2116,
-	-1000, 461, 68, 459, 78, 78, 333, -14, -19, -1000,
-	-37, -35, -20, 2116, 16, -1000, 189, -1000, 16, 16,
-	1989, 1929, 98, -1000, 80, 392, -1000, 432, -1000, -1000,
-	-130, -59, -72, 260, -1000, -57, 2240, 2456, 2957, -1000,
-	-1000, -1000, -1000, 1152, -1000, -1000, 2957, 1018, -62, -62,
-	-58, -58, -58, 184, 2302, 2250, 2598, 2598, 2598, 60,
-	60, 60, 60, 500, -1000, 727, 2957, 2957, 2957, 534,
-	135, 135, -1000, 436, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, 279, 400, 2957, 2957, -1000, 260, -1000, 260,
-	-1000, 260, 2957, 54, 52, 142, 170, -1000, 227, 77,
-	-1000, -1000, -1000, 80, -1000, 139, 44, 2957, 35, -1000,
-	226, 2957, -1000, 2957, 1854, -1000, 68, 319, -1000, 302,
-	-128, -1000, -97, -129, 78, -1000, 172, 2957, -1000, 2957,
-	507, 76, 2957, 2957, 2957, 506, 505, 76, 76, 428,
-	-1000, 2957, -28, -1000, -109, 98, 389, -1000, 252, 175,
-	70, 77, 77, 17, 2456, -57, 2957, -57, 652, -29,
-	-1000, 969, -1000, 2719, 727, 7, 2957, 727, 727, 727,
-	727, 727, 727, 377, 534, 135, 135, -1000, -1000, -1000,
+	-1000, 3127, -1000, -1000, -1000, -1000, 450, 593, 592, 642,
+	265, 590, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	2116, 2116, -1000, -1000, -1000, -21, -1000, 835, 124, 283,
-	124, 283, 98, 111, 98, 70, 70, 413, -1000, 175,
-	-1000, -1000, 12, -1000, 1794, -1000, -1000, 1727, 2116, 2957,
-	301, -1000, 78, 78, 68, 77, 68, 50, -1000, 2116,
-	2116, -1000, -1000, 2116, 2116, 2116, -1000, -1000, -39, -39,
-	186, -1000, 538, -1000, 80, 2116, 80, 2957, 428, 103,
-	103, 2957, -1000, -1000, -1000, -1000, 142, -67, -1000, -130,
-	-130, 175, -1000, 652, -1000, -1000, -1000, -1000, -1000, 1667,
-	23, -1000, -1000, 2957, 786, -113, -113, -61, -61, -61,
-	141, 727, 2957, -1000, -1000, -1000, -1000, -11, -1000, 25,
-	-23, -22, 429, 2957, -11, -23, 400, 98, 400, 400,
-	-25, -1000, -76, -27, -1000, 9, 2957, -1000, 293, 260,
-	-1000, 2957, 2116, 78, 10, 136, 136, -1000, 136, 68,
-	503, 2957, 498, -1000, 2957, -28, -1000, 2116, -1000, -1000,
-	-130, -99, -106, -1000, 652, -1000, 72, 2957, 175, 175,
-	-1000, -1000, -1000, 603, -1000, 2703, 23, -1000, -1000, 124,
-	-1000, 2240, 2957, 5, 137, 133, -31, 2116, -1000, 4,
-	198, 400, 198, 198, 70, 2957, 70, -1000, -1000, 76,
-	2116, 278, 3, 2116, 136, 2957, -1000, -1000, 220, -1000,
-	217, -38, -1000, -1000, 2116, -1000, -15, 175, 77, 77,
-	-1000, -1000, -1000, 1600, 142, 142, -1000, -1000, -1000, 1540,
-	-1000, -1000, -57, 2957, 1411, 260, 2957, -1, 132, 260,
-	-1000, 198, -1000, -1000, -1000, 1473, -1000, -54, -1000, 187,
-	120, -1000, 425, 175, 95, -66, -1000, 2116, -1000, -1000,
-	-1000, 157, 136, 68, 478, -1000, 264, -130, -130, -1000,
-	-1000, -1000, -1000, 2116, 2957, 198, 2116, -1000, -6, 198,
-	-1000, -1000, 497, 76, 70, 70, 400, 416, -1000, 287,
-	-1000, -1000, 2957, 240, 2957, 68, -1000, -1000, -1000, -1000,
-	2957, -1000, 227, 175, 175, 1338, -1000, -1000, -1000, -1000,
-	-1000, -1000, -67, -1000, 198, 173, 384, 278, 2116, 101,
-	537, -1000, -1000, 2116, 411, 264, 264, -1000, -1000, 257,
-	171, 120, 136, 2957, 2957, 2957, -1000, -1000, 170, 98,
-	455, 400, 95, -1000, 2116, 2116, 113, 111, 98, 106,
-	-1000, 2957, 198, -1000, -1000, 360, -1000, 98, -1000, -1000,
-	339, -1000, 1281, -1000, 169, 373, -1000, 371, -1000, 510,
-	167, 166, 98, 453, 449, 106, 2957, 2957, -1000, -1000,
-	-1000,
+	147, 3034, -1000, -1000, 2716, 464, 407, 483, 486, 615,
+	614, 363, 3127, 168, 168, 168, 3127, 3127, -1000, -1000,
+	-1000, 560, 634, 186, 1198, 40, 3127, 3127, 3127, 3127,
+	3127, 3127, 3127, 3127, 3127, 3127, 3127, 3127, 3127, 3127,
+	3127, 3127, 3220, 397, 3127, 3127, 3127, 783, 2483, 82,
+	3303, 3303, 214, -1000, 631, 293, 293, -57, -1000, 240,
+	240, 240, 297, 646, -27, -28, 318, -1000, 240, 2448,
+	667, -1000, -1000, 2149, 377, 3127, 109, 2283, -1000, 528,
+	543, 537, 514, -1000, 708, 192, -1000, 613, 179, 632,
+	209, 605, 245, 199, 245, 511, 29, 19, -1000, -59,
+	-55, -12, 2283, 9, -1000, 355, -1000, 9, 9, 2110,
+	2077, 246, -1000, 192, 560, -1000, 564, -1000, -1000, -129,
+	-65, -69, 359, -1000, -1000, -8, 2273, 2515, 3127, -1000,
+	-1000, -1000, -1000, 1035, -1000, -1000, 3127, 1002, -50, -50,
+	-57, -57, -57, 302, 2483, 2321, 1066, 1066, 1066, 54,
+	54, 54, 54, 202, -1000, 3220, 3127, 3127, 3127, 719,
+	82, 82, -1000, 882, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, 3303, -1000, 3127, -1000, 142,
+	140, 297, 333, -1000, 452, 245, 196, 196, -1000, -1000,
+	-1000, 192, -1000, 292, 108, 3127, 103, -1000, 377, 3127,
+	-1000, 3127, 1917, -1000, 515, 532, 3127, 3127, -1000, 450,
+	-1000, 450, -1000, 450, 3127, 18, -1000, 720, 179, 510,
+	-1000, 199, -1000, 509, -126, -1000, -71, -1000, -1000, -76,
+	-127, 245, -1000, 363, 3127, -1000, 3127, 666, 168, 3127,
+	3127, 3127, 665, 664, 168, 168, 578, -1000, 3127, 11,
+	-1000, -109, 246, 449, -1000, 465, 318, 183, 196, 196,
+	87, 2515, -8, 3127, -8, 770, -21, -1000, 971, -1000,
+	2809, 3220, 38, 3127, 3220, 3220, 3220, 3220, 3220, 3220,
+	116, 719, 82, 82, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, 2283, 268, 487, 268, 487, 246,
+	285, 246, 183, 183, 573, -1000, 218, 318, -1000, 318,
+	-1000, 143, 485, 1884, 472, -1000, 1853, 2283, 3127, -1000,
+	-1000, -1000, -1000, 2283, 2283, -1000, -1000, -1000, -17, -1000,
+	837, 192, 129, 506, -1000, 245, 127, 245, 179, 196,
+	197, 179, 126, -1000, 2283, 2283, -1000, -1000, 2283, 2283,
+	2283, -1000, -1000, -20, -20, 378, -1000, 706, -1000, 192,
+	2283, 192, 3127, 578, 232, 232, 3127, -1000, -1000, -1000,
+	-1000, 297, -68, -1000, -129, -129, 318, -1000, 770, -1000,
+	-1000, -1000, -1000, -1000, 1719, -16, -1000, -1000, 3127, 803,
+	-58, -58, -64, -64, -64, 238, 3220, -13, -1000, 125,
+	16, 17, 586, 3127, -13, 16, 532, 246, 532, 532,
+	14, -1000, -66, 13, -1000, 25, 3127, 2967, 237, -1000,
+	-1000, 504, 359, 121, 467, 115, 3127, 2283, 3127, -1000,
+	-1000, -1000, -1000, -1000, 359, 245, 114, 190, 290, 290,
+	-1000, -1000, 290, 179, 663, 3127, 661, -1000, 3127, 11,
+	-1000, 2283, -1000, 503, -129, -102, -105, 501, 770, -1000,
+	159, 3127, 318, 318, -1000, -1000, -1000, 630, -1000, 2789,
+	-16, -1000, 268, -1000, 2273, 3127, 86, 286, 284, 10,
+	2283, -1000, 84, 402, 532, 402, 402, 183, 3127, 183,
+	-1000, -1000, 168, 2283, 5, -1000, -1000, 722, 2283, 2967,
+	-1000, 477, 80, 460, 113, 460, 2283, -1000, 79, 290,
+	2967, 69, 3, -1000, -1000, -1000, 370, -1000, 399, -10,
+	-1000, -1000, 2283, -1000, 2, -1000, 3015, 318, 196, 196,
+	-1000, 3015, -1000, -1000, -1000, 1686, 297, 297, -1000, -1000,
+	-1000, 1655, -1000, -1000, -8, 3127, 1521, 359, 3127, 64,
+	280, 359, -1000, 402, -1000, -1000, -1000, 1490, -1000, -15,
+	-1000, 350, 2967, 243, 3127, -25, 263, -1000, 581, 318,
+	61, 489, 705, 460, 60, -1000, 243, -99, 26, 184,
+	-1000, -1000, -1000, 311, 290, 179, 622, -1000, 2283, 570,
+	296, -129, -129, 2283, -1000, -1000, -1000, -1000, 2283, 3127,
+	402, 2283, -1000, 58, 402, -1000, -1000, 660, 168, 183,
+	183, -1000, -1000, -1000, 3127, 1457, -1000, 532, 531, -1000,
+	497, -1000, 424, 701, 3127, 50, -1000, -1000, 456, 3127,
+	-1000, 179, -1000, -1000, -1000, -1000, 3127, 3127, -1000, 624,
+	318, 318, 1297, -1000, -1000, -1000, -1000, -1000, -1000, -68,
+	-1000, 2283, 168, 402, 351, 544, 477, -1000, -1000, 2902,
+	-1000, -1000, 3127, -12, -1000, 253, 697, 1264, -1000, 2283,
+	2283, 83, 296, 296, -1000, -20, -1000, 421, 349, 263,
+	-1000, 3147, 513, 385, 75, -17, 290, 3127, 3127, -1000,
+	520, -1000, -1000, 658, 333, 246, 599, 532, 727, -1000,
+	-1000, -1000, -1000, -1000, 243, -1000, 2283, 48, 36, -1000,
+	260, 285, 246, 259, -1000, 3127, 402, 3147, -1000, -1000,
+	-1000, -1000, 530, -1000, 246, -1000, -1000, 518, -1000, 1233,
+	-1000, -1000, 322, 541, -1000, 540, -1000, 679, 317, 314,
+	246, 598, 596, 259, 3127, 3127, -1000, -1000, -1000,
 }
 var yyPgo = []int{
 
-	0, 734, 732, 578, 731, 730, 53, 729, 728, 0,
-	66, 107, 36, 325, 43, 44, 51, 25, 20, 23,
-	727, 726, 718, 717, 55, 337, 716, 714, 713, 52,
-	50, 291, 28, 712, 711, 710, 709, 37, 708, 56,
-	707, 705, 704, 511, 702, 45, 42, 701, 700, 18,
-	31, 68, 113, 699, 29, 15, 259, 698, 6, 697,
-	46, 695, 694, 27, 690, 683, 48, 35, 682, 47,
-	674, 673, 41, 671, 353, 9, 58, 670, 668, 667,
-	567, 663, 661, 660, 658, 646, 645, 643, 640, 639,
-	638, 631, 630, 629, 628, 627, 626, 359, 34, 49,
-	19, 39, 622, 621, 4, 24, 617, 16, 10, 33,
-	615, 8, 32, 614, 613, 26, 21, 612, 611, 3,
-	2, 5, 22, 605, 598, 40, 592, 591, 11, 590,
-	7, 585, 14, 583, 571, 570, 38, 561, 17, 560,
-	54, 559,
+	0, 919, 913, 729, 911, 910, 60, 909, 908, 0,
+	8, 53, 23, 526, 57, 56, 70, 25, 34, 26,
+	907, 905, 904, 902, 64, 520, 900, 898, 894, 59,
+	33, 407, 18, 893, 892, 28, 890, 889, 886, 885,
+	884, 9, 883, 877, 38, 721, 874, 52, 872, 871,
+	870, 104, 868, 867, 865, 634, 864, 58, 54, 863,
+	861, 19, 31, 24, 71, 42, 860, 43, 44, 336,
+	859, 6, 858, 48, 857, 856, 36, 855, 853, 55,
+	45, 852, 66, 851, 849, 46, 20, 507, 16, 61,
+	847, 846, 843, 119, 842, 841, 840, 839, 838, 837,
+	835, 834, 833, 832, 830, 817, 815, 803, 802, 801,
+	800, 795, 793, 790, 789, 68, 788, 784, 775, 559,
+	41, 51, 21, 49, 773, 771, 4, 30, 770, 22,
+	11, 40, 769, 10, 39, 768, 767, 35, 17, 764,
+	762, 3, 2, 5, 27, 760, 759, 50, 756, 755,
+	14, 747, 7, 744, 15, 29, 742, 540, 37, 741,
+	47, 740, 63, 739, 69, 737,
 }
 var yyR1 = []int{
 
-	0, 135, 135, 80, 80, 80, 80, 80, 80, 81,
-	82, 83, 84, 85, 85, 85, 85, 85, 86, 92,
-	92, 92, 92, 37, 37, 37, 38, 38, 38, 38,
-	38, 38, 38, 39, 39, 41, 40, 69, 68, 68,
-	68, 68, 68, 136, 136, 67, 67, 66, 66, 66,
-	18, 18, 17, 17, 16, 44, 44, 43, 42, 42,
-	42, 42, 42, 137, 137, 45, 45, 45, 47, 46,
-	46, 46, 51, 52, 50, 50, 54, 54, 53, 138,
-	138, 48, 48, 48, 139, 139, 55, 56, 56, 57,
-	15, 15, 14, 58, 58, 59, 60, 60, 61, 61,
-	12, 12, 62, 62, 63, 64, 64, 65, 71, 71,
-	70, 73, 73, 72, 79, 79, 78, 78, 75, 75,
-	74, 77, 77, 76, 87, 87, 97, 97, 140, 140,
-	140, 141, 141, 99, 99, 98, 104, 104, 103, 102,
-	102, 100, 101, 101, 88, 88, 89, 90, 90, 90,
-	108, 110, 110, 109, 115, 115, 114, 106, 106, 105,
-	105, 19, 107, 32, 32, 111, 113, 113, 112, 91,
-	91, 116, 116, 116, 116, 117, 117, 117, 121, 121,
-	118, 118, 118, 119, 120, 93, 93, 123, 123, 122,
-	125, 125, 126, 126, 128, 128, 127, 127, 130, 130,
-	129, 134, 134, 132, 133, 133, 94, 94, 95, 131,
-	131, 96, 124, 124, 49, 49, 49, 49, 9, 9,
+	0, 159, 159, 93, 93, 93, 93, 93, 93, 94,
+	94, 100, 100, 95, 95, 96, 96, 157, 157, 97,
+	98, 98, 98, 98, 98, 99, 99, 99, 106, 106,
+	106, 106, 111, 111, 44, 44, 46, 49, 49, 48,
+	48, 47, 45, 45, 45, 50, 50, 50, 50, 50,
+	50, 50, 51, 51, 53, 52, 82, 81, 81, 81,
+	81, 81, 160, 160, 80, 80, 79, 79, 79, 18,
+	18, 17, 17, 16, 56, 56, 55, 54, 54, 54,
+	54, 54, 54, 54, 161, 161, 57, 57, 57, 59,
+	58, 58, 58, 64, 65, 65, 63, 63, 67, 67,
+	66, 162, 162, 60, 60, 60, 163, 163, 61, 61,
+	61, 68, 69, 69, 70, 15, 15, 14, 71, 71,
+	72, 73, 73, 74, 74, 12, 12, 75, 75, 76,
+	77, 77, 78, 84, 84, 83, 86, 86, 85, 92,
+	92, 91, 91, 88, 88, 87, 90, 90, 89, 101,
+	101, 119, 119, 119, 164, 164, 164, 165, 165, 121,
+	121, 120, 126, 126, 125, 124, 124, 122, 123, 123,
+	102, 102, 103, 104, 104, 104, 130, 132, 132, 131,
+	137, 137, 136, 128, 128, 127, 127, 19, 129, 32,
+	32, 133, 135, 135, 134, 105, 105, 138, 138, 138,
+	138, 139, 139, 139, 143, 143, 140, 140, 140, 141,
+	142, 107, 107, 145, 145, 144, 147, 147, 148, 148,
+	150, 150, 149, 149, 152, 152, 151, 158, 158, 155,
+	155, 154, 156, 156, 108, 108, 109, 153, 153, 110,
+	146, 146, 112, 116, 116, 115, 115, 117, 117, 118,
+	118, 113, 114, 114, 114, 62, 62, 62, 62, 9,
 	9, 9, 9, 9, 9, 9, 9, 9, 9, 9,
 	9, 9, 9, 9, 9, 9, 9, 9, 9, 9,
 	9, 9, 9, 9, 9, 9, 9, 9, 9, 9,
 	9, 9, 9, 9, 9, 9, 9, 9, 9, 9,
-	9, 9, 9, 9, 9, 9, 9, 9, 9, 10,
-	10, 10, 10, 10, 10, 10, 10, 10, 10, 11,
+	9, 9, 9, 9, 9, 9, 9, 9, 9, 9,
+	10, 10, 10, 10, 10, 10, 10, 10, 10, 10,
 	11, 11, 11, 11, 11, 11, 11, 11, 11, 11,
-	11, 11, 11, 1, 1, 1, 1, 1, 1, 1,
-	2, 2, 3, 8, 8, 7, 7, 6, 4, 13,
-	13, 5, 5, 5, 20, 21, 21, 22, 25, 25,
-	23, 24, 24, 33, 33, 33, 34, 26, 26, 27,
-	27, 27, 30, 30, 29, 29, 31, 28, 28, 35,
-	36, 36,
+	11, 11, 11, 11, 1, 1, 1, 1, 1, 1,
+	1, 2, 2, 3, 8, 8, 7, 7, 6, 4,
+	13, 13, 5, 5, 5, 20, 21, 21, 22, 25,
+	25, 23, 24, 24, 33, 33, 33, 33, 33, 33,
+	34, 35, 36, 36, 37, 37, 38, 38, 39, 39,
+	40, 40, 41, 41, 41, 41, 41, 26, 26, 27,
+	27, 27, 30, 30, 29, 29, 31, 28, 28, 42,
+	43, 43,
 }
 var yyR2 = []int{
 
-	0, 1, 1, 1, 1, 1, 1, 1, 1, 2,
-	2, 2, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 2, 4, 4, 1, 3, 4, 3,
-	4, 3, 4, 1, 1, 5, 5, 2, 1, 2,
-	2, 3, 4, 1, 1, 1, 3, 1, 3, 2,
-	0, 1, 1, 2, 1, 0, 1, 2, 1, 1,
-	4, 4, 5, 1, 1, 4, 6, 6, 4, 4,
-	6, 6, 1, 1, 0, 2, 0, 1, 4, 0,
-	1, 0, 1, 2, 0, 1, 4, 0, 1, 2,
-	1, 3, 3, 0, 1, 2, 0, 1, 5, 1,
-	1, 3, 0, 1, 2, 0, 1, 2, 0, 1,
-	3, 1, 3, 2, 0, 1, 1, 1, 0, 1,
-	2, 0, 1, 2, 6, 9, 4, 2, 0, 5,
-	6, 1, 2, 1, 3, 6, 0, 1, 2, 1,
-	2, 2, 0, 3, 6, 9, 7, 8, 7, 7,
-	2, 1, 3, 4, 0, 1, 4, 1, 3, 3,
-	3, 1, 1, 0, 2, 2, 1, 3, 2, 10,
-	13, 0, 6, 6, 6, 0, 6, 6, 0, 6,
-	2, 3, 2, 1, 2, 8, 12, 0, 1, 1,
-	1, 3, 0, 3, 0, 1, 2, 2, 0, 1,
-	2, 1, 3, 1, 0, 2, 6, 6, 7, 0,
-	3, 8, 1, 3, 1, 3, 3, 4, 1, 3,
-	3, 5, 5, 4, 5, 6, 3, 3, 3, 3,
-	3, 3, 3, 3, 2, 3, 3, 3, 3, 3,
-	3, 3, 5, 6, 3, 4, 3, 4, 3, 4,
-	3, 4, 3, 4, 3, 4, 3, 4, 3, 4,
-	3, 4, 3, 4, 3, 4, 3, 4, 2, 1,
-	1, 1, 1, 1, 1, 2, 1, 1, 1, 1,
+	0, 1, 1, 1, 1, 1, 1, 1, 1, 3,
+	3, 0, 2, 2, 4, 3, 3, 0, 2, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 2, 3, 0, 1, 1,
+	3, 5, 2, 4, 4, 1, 3, 4, 3, 4,
+	3, 4, 1, 1, 5, 5, 2, 1, 2, 2,
+	3, 4, 1, 1, 1, 3, 1, 3, 2, 0,
+	1, 1, 2, 1, 0, 1, 2, 1, 1, 5,
+	6, 5, 6, 5, 1, 1, 4, 6, 6, 4,
+	4, 6, 6, 1, 1, 1, 0, 2, 0, 1,
+	4, 0, 1, 0, 1, 2, 0, 1, 0, 5,
+	5, 4, 0, 1, 2, 1, 3, 3, 0, 1,
+	2, 0, 1, 5, 1, 1, 3, 0, 1, 2,
+	0, 1, 2, 0, 1, 3, 1, 3, 2, 0,
+	1, 1, 1, 0, 1, 2, 0, 1, 2, 6,
+	9, 4, 4, 2, 0, 5, 6, 1, 2, 1,
+	3, 6, 0, 1, 2, 1, 2, 2, 0, 3,
+	6, 9, 7, 8, 7, 7, 2, 1, 3, 4,
+	0, 1, 4, 1, 3, 3, 3, 1, 1, 0,
+	2, 2, 1, 3, 2, 10, 13, 0, 6, 6,
+	6, 0, 6, 6, 0, 6, 2, 3, 2, 1,
+	2, 8, 12, 0, 1, 1, 1, 3, 0, 3,
+	0, 1, 2, 2, 0, 1, 2, 1, 3, 1,
+	7, 1, 0, 2, 6, 6, 7, 0, 3, 8,
+	1, 3, 10, 0, 2, 1, 3, 0, 1, 1,
+	3, 3, 8, 8, 6, 1, 3, 3, 4, 1,
 	3, 3, 5, 5, 4, 5, 6, 3, 3, 3,
-	3, 3, 3, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 3, 0, 1, 1, 3, 3, 3, 0,
-	1, 1, 1, 1, 3, 1, 1, 3, 4, 5,
-	2, 0, 2, 4, 5, 4, 1, 1, 1, 4,
+	3, 3, 3, 3, 3, 2, 3, 3, 3, 3,
+	3, 3, 3, 5, 6, 3, 4, 3, 4, 3,
+	4, 3, 4, 3, 4, 3, 4, 3, 4, 3,
+	4, 3, 4, 3, 4, 3, 4, 3, 4, 2,
+	1, 1, 1, 1, 1, 1, 2, 1, 1, 1,
+	1, 3, 3, 5, 5, 4, 5, 6, 3, 3,
+	3, 3, 3, 3, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 3, 0, 1, 1, 3, 3, 3,
+	0, 1, 1, 1, 1, 3, 1, 1, 3, 4,
+	5, 2, 0, 2, 4, 5, 4, 8, 9, 8,
+	1, 3, 0, 3, 0, 3, 0, 1, 2, 5,
+	1, 1, 2, 2, 2, 2, 2, 1, 1, 4,
 	4, 4, 1, 3, 3, 3, 2, 6, 6, 3,
 	1, 1,
 }
 var yyChk = []int{
 
-	-1000, -135, -80, -9, -81, -82, -83, -84, -85, -86,
-	-10, 93, 50, 51, 108, 49, -37, -87, -88, -89,
-	-90, -91, -92, -1, -2, 163, 127, -5, -33, 89,
-	-20, -26, -35, -38, 69, 147, 35, 146, 88, -93,
-	-94, -95, -96, 94, 90, 52, 139, 95, 162, 133,
-	-3, -4, 165, 166, 167, -34, 21, -27, -28, 168,
-	-39, 29, 41, 5, 18, 170, 172, 8, 130, 46,
-	9, 53, -41, -40, -43, -69, 56, 126, 191, 172,
-	186, 89, 187, 188, 189, 185, 7, 100, 178, 179,
-	180, 181, 182, 183, 184, 13, 93, 81, 63, 159,
-	72, -9, -9, -80, -80, -3, -9, -71, 142, 70,
-	47, -70, 101, 71, 71, 56, -97, -51, -52, 163,
-	71, 168, -21, -22, -23, -9, -25, 155, -36, -9,
-	-37, 109, 66, 109, 66, 66, 66, -8, -7, -6,
-	133, -13, -12, -9, -30, -29, -19, 163, -30, -30,
-	-9, -9, -56, -57, 79, -44, -43, -42, -45, -47,
-	-52, -51, 134, 168, -68, -67, 39, 4, -136, -66,
-	114, 43, 187, -9, 163, 164, 172, -9, -9, -9,
+	-1000, -159, -93, -9, -94, -95, -96, -97, -98, -99,
+	-10, 96, 51, 52, 112, 50, -44, -101, -102, -103,
+	-104, -105, -106, -111, -114, -1, -2, 173, 136, -5,
+	-33, 92, -20, -26, -42, -45, -46, 72, 157, 36,
+	156, 91, -107, -108, -109, -110, -112, -113, 97, 93,
+	53, 148, 98, 172, 142, -3, -4, 175, 176, 177,
+	-34, 21, -27, -28, 178, -50, 168, 29, 42, 5,
+	18, 180, 182, 8, 139, 47, 9, 54, -51, -53,
+	-52, -55, -82, 58, 135, 201, 182, 196, 92, 197,
+	198, 199, 195, 7, 103, 188, 189, 190, 191, 192,
+	193, 194, 13, 96, 84, 66, 169, 75, -9, -9,
+	-100, 6, 173, -93, 173, -3, 173, -9, -45, 74,
+	74, 58, -119, 141, -64, 143, -65, 173, 74, 178,
+	-21, -22, -23, -9, -25, 165, -43, -9, -44, -84,
+	152, 73, 48, -83, 104, -49, 122, 113, 69, -116,
+	103, 113, 69, 59, 69, 69, -8, -7, -6, 142,
+	-13, -12, -9, -30, -29, -19, 173, -30, -30, -9,
+	-9, -69, -70, 82, -56, -55, -54, -57, -59, -65,
+	-64, 143, 178, 141, -81, -80, 40, 4, -160, -79,
+	120, 44, 197, -9, 173, 174, 182, -9, -9, -9,
 	-9, -9, -9, -9, -9, -9, -9, -9, -9, -9,
-	-9, -9, -9, -11, -10, 13, 81, 63, 159, -9,
-	-9, -9, 94, 93, 90, 152, 15, 95, 133, 9,
-	96, 14, -74, -76, 82, 97, -39, 4, -39, 4,
-	-39, 4, 19, -97, -97, -97, -54, -53, 148, 176,
-	-18, -17, -16, 10, 163, -97, -13, 39, 187, 45,
-	-25, 155, -24, 44, -9, 169, 66, -122, 163, 66,
-	-125, -52, -51, -125, 98, 171, 175, 176, 173, 175,
-	-31, 175, 124, 63, 159, -31, -31, 55, 55, -58,
-	-59, 156, -15, -14, -16, -56, -48, 68, 78, -50,
-	191, 176, 176, -37, 175, -67, -136, -67, -9, 191,
-	-18, -9, 173, 176, 7, 191, 172, 186, 89, 187,
-	188, 189, 185, -11, -9, -9, -9, 94, 90, 152,
-	15, 95, 133, 9, 96, 14, -77, -76, -75, -74,
-	-9, -9, -39, -39, -39, -73, -72, -9, -140, 168,
-	-140, 168, -54, -108, -111, 128, 145, -138, 109, -52,
-	163, -16, 150, 169, -9, 169, -24, -9, -9, 135,
-	-123, -122, 98, 98, 191, 176, 191, -125, -6, -9,
-	-9, 45, -29, -9, -9, -9, 45, 45, -30, -30,
-	-60, -61, 59, -63, 80, -9, 175, 178, -58, 73,
-	92, -137, 144, 54, -139, 102, -18, -49, 163, -52,
-	-52, 169, -66, -9, -18, 187, 173, 174, 173, -9,
-	-11, 163, 164, 172, -9, -11, -11, -11, -11, -11,
-	-11, 7, 175, -79, -78, 11, 37, -99, -98, 153,
-	-100, 74, 109, -141, -99, -100, -58, -111, -58, -58,
-	-110, -109, -49, -113, -112, -49, 75, -18, -45, 168,
-	169, 135, -9, 98, -125, -125, -122, -52, -122, 168,
-	-32, 155, -32, -69, 19, -15, -14, -9, -60, -46,
-	-52, -51, 134, -46, -9, -54, 191, 172, -50, -50,
-	-18, -18, 173, -9, 173, 176, -11, -72, -104, 175,
-	-103, 119, 168, -101, 175, 175, 74, -9, -104, -101,
-	-75, -58, -75, -75, 175, 178, 175, -115, -114, 55,
-	-9, 98, -37, -9, -125, 168, -128, -127, 150, -128,
-	-128, -124, -122, 45, -9, 45, -12, -50, 176, 176,
-	-18, 163, 164, -9, -18, -18, 173, 174, 173, -9,
-	-98, -102, -67, -136, -9, 169, 151, 151, 175, 169,
-	-104, -75, -104, -104, -109, -9, -112, -106, -105, -19,
-	-100, 74, 109, 169, -128, -134, -132, -9, 154, 60,
-	-131, 117, 169, 175, -62, -63, -18, -52, -52, 173,
-	-54, -54, 173, -9, 175, -37, -9, 169, 151, -37,
-	-104, -115, -32, 175, 63, 159, -116, 155, 74, -17,
-	-130, -129, 158, 169, 175, 136, -128, -122, -64, -65,
-	61, -55, 98, -50, -50, -9, -104, 169, -104, 45,
-	-105, -107, -49, -107, -75, 86, 93, 98, -9, -126,
-	104, -132, -122, -9, -138, -18, -18, 169, -104, 135,
-	86, -100, -133, 156, 19, 75, -55, -55, 146, 35,
-	135, -116, -128, -132, -9, -9, -118, -108, -111, -119,
-	-58, 69, -75, -130, -117, 155, -58, -111, -58, -121,
-	155, -120, -9, -104, 86, 93, -58, 93, -58, 135,
-	86, 86, 35, 135, 135, -119, 69, 69, -121, -120,
-	-120,
+	-9, -9, -9, -11, -10, 13, 84, 66, 169, -9,
+	-9, -9, 97, 96, 93, 162, 15, 98, 142, 9,
+	99, 14, -93, -93, 173, 58, -157, 160, -157, -119,
+	-119, -119, -67, -66, 158, 57, 186, 186, -18, -17,
+	-16, 10, 173, -119, -13, 40, 197, 46, -25, 165,
+	-24, 45, -9, 179, -87, -89, 85, 100, -51, 4,
+	-51, 4, -51, 4, 19, -48, -47, -16, 69, -144,
+	173, 59, 173, 69, -147, -65, -64, -115, 173, -64,
+	-147, 101, 181, 185, 186, 183, 185, -31, 185, 133,
+	66, 169, -31, -31, 57, 57, -71, -72, 166, -15,
+	-14, -16, -69, -60, 71, 81, -63, 201, 186, 186,
+	-44, 185, -80, -160, -80, -9, 201, -18, -9, 183,
+	186, 7, 201, 182, 196, 92, 197, 198, 199, 195,
+	-11, -9, -9, -9, 97, 93, 162, 15, 98, 142,
+	9, 99, 14, -93, -9, -164, 178, -164, 178, -67,
+	-130, -133, 137, 155, -162, 113, -147, -65, 173, -65,
+	-16, 160, 179, -9, 179, -24, -9, -9, 144, -90,
+	-89, -88, -87, -9, -9, -51, -51, -51, -86, -85,
+	-9, 185, 10, -145, -144, 101, -115, 101, 201, 186,
+	186, 201, -147, -6, -9, -9, 46, -29, -9, -9,
+	-9, 46, 46, -30, -30, -73, -74, 61, -76, 83,
+	-9, 185, 188, -71, 76, 95, -161, 154, 55, -163,
+	105, -18, -62, 173, -65, -65, 179, -79, -9, -18,
+	197, 183, 184, 183, -9, -11, 173, 174, 182, -9,
+	-11, -11, -11, -11, -11, -11, 7, -121, -120, 163,
+	-122, 77, 113, -165, -121, -122, -71, -133, -71, -71,
+	-132, -131, -62, -135, -134, -62, 78, 178, 36, -18,
+	-18, -57, 178, 106, 179, 106, 144, -9, 185, -92,
+	-91, 11, 38, -47, 178, 101, -147, 178, -147, -144,
+	-65, 173, -144, 178, -32, 165, -32, -82, 19, -15,
+	-14, -9, -73, -58, -65, -64, 143, -58, -9, -67,
+	201, 182, -63, -63, -18, -18, 183, -9, 183, 186,
+	-11, -126, 185, -125, 126, 178, -123, 185, 185, 77,
+	-9, -126, -123, -88, -71, -88, -88, 185, 188, 185,
+	-137, -136, 57, -9, -158, -155, -154, 40, -9, 178,
+	4, 101, -44, 178, 106, 178, -9, -85, -44, -147,
+	178, -117, -118, 173, -150, -149, 160, -150, -150, -146,
+	-144, 46, -9, 46, -12, -68, 101, -63, 186, 186,
+	-68, 101, -18, 173, 174, -9, -18, -18, 183, 184,
+	183, -9, -120, -124, -80, -160, -9, 179, 161, 161,
+	185, 179, -126, -88, -126, -126, -131, -9, -134, -128,
+	-127, -19, 185, 179, 9, -158, -122, 77, 113, 179,
+	-35, -36, 107, 178, -35, 179, -150, -158, 179, 185,
+	164, 62, -153, 124, 179, 185, -75, -76, -9, -162,
+	-18, -65, -65, -9, 183, -67, -67, 183, -9, 185,
+	-44, -9, 179, 161, -44, -126, -137, -32, 185, 66,
+	169, -155, -152, -151, 168, -9, 179, -138, 165, 77,
+	-17, 179, -37, 104, 19, -35, 179, -152, 179, 180,
+	173, 145, -150, -144, -77, -78, 64, 78, -61, 158,
+	-63, -63, -9, -126, 179, -126, 46, -127, -129, -62,
+	-129, -9, 57, -88, 89, 96, 101, -38, -39, -40,
+	132, 119, 19, -12, 179, -148, 107, -9, -144, -9,
+	-9, 63, -18, -18, 179, -30, -126, 144, 89, -122,
+	-41, 13, 150, 30, -11, -86, -156, 166, 19, 181,
+	178, -61, -61, -32, 156, 36, 144, -138, -41, 111,
+	56, 131, 111, 56, -150, -154, -9, 18, 116, 46,
+	-140, -130, -133, -141, -71, 72, -88, 7, -152, 179,
+	179, -139, 165, -71, -133, -71, -143, 165, -142, -9,
+	-126, -41, 89, 96, -71, 96, -71, 144, 89, 89,
+	36, 144, 144, -141, 72, 72, -143, -142, -142,
 }
 var yyDef = []int{
 
 	0, -2, 1, 2, 3, 4, 5, 6, 7, 8,
-	218, 0, 0, 0, 0, 0, 12, 13, 14, 15,
-	16, 17, 18, 269, 270, -2, 272, 273, 274, 0,
-	276, 277, 278, 108, 0, 0, 0, 0, 0, 19,
-	20, 21, 22, 293, 294, 295, 296, 297, 298, 299,
-	300, 301, 311, 312, 313, 0, 0, 327, 328, 0,
-	26, 0, 0, 0, 0, 303, 309, 0, 0, 0,
-	0, 0, 33, 34, 87, 55, 0, 0, 0, 0,
+	259, 0, 0, 11, 0, 0, 19, 20, 21, 22,
+	23, 24, 25, 26, 27, 310, 311, -2, 313, 314,
+	315, 0, 317, 318, 319, 34, 0, 0, 0, 0,
+	0, 0, 28, 29, 30, 31, 32, 33, 334, 335,
+	336, 337, 338, 339, 340, 341, 342, 352, 353, 354,
+	0, 0, 387, 388, 0, 133, 37, 243, 0, 0,
+	0, 344, 350, 0, 0, 0, 0, 0, 45, 52,
+	53, 112, 74, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 275, 309,
+	0, 0, 0, 13, 0, 17, 17, 316, 35, 0,
+	0, 0, 98, 95, 0, 0, 69, -2, 0, 350,
+	0, 356, 357, 0, 362, 0, 0, 400, 401, 42,
+	0, 0, 0, 134, 0, 0, 38, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 345, 346, 0,
+	0, 351, 125, 0, 392, 0, 187, 0, 0, 0,
+	0, 118, 113, 0, 112, 75, -2, 77, 78, 96,
+	0, 0, 0, 95, 56, 57, 0, 0, 0, 64,
+	62, 63, 66, 69, 260, 261, 0, 0, 267, 268,
+	269, 270, 271, 272, 273, 274, -2, -2, -2, -2,
+	-2, -2, -2, 0, 320, 0, 0, 0, 0, -2,
+	-2, -2, 291, 0, 293, 295, 297, 299, 301, 303,
+	305, 307, 9, 10, 12, 0, 15, 0, 16, 154,
+	154, 98, 0, 99, 101, 0, 0, 0, 153, 70,
+	71, 0, 73, 0, 0, 0, 0, 355, 362, 0,
+	361, 0, 0, 399, 146, 143, 0, 0, 46, 0,
+	48, 0, 50, 0, 0, 36, 39, 0, 213, 0,
+	215, 0, 244, 0, 0, 216, 0, 251, -2, 0,
+	0, 0, 343, 0, 0, 349, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 121, 119, 0, 114,
+	115, 0, 118, 0, 104, 106, 69, 0, 0, 0,
+	0, 0, 58, 0, 59, 69, 0, 68, 0, 264,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 234, 268, 9, 10, 11, 275, 23, 0, 0,
-	0, 109, 0, 0, 0, 0, 76, 0, 50, -2,
-	0, 309, 0, 315, 316, 0, 321, 0, 0, 340,
-	341, 0, 0, 0, 0, 0, 0, 0, 304, 305,
-	0, 0, 310, 100, 0, 332, 0, 161, 0, 0,
-	0, 0, 93, 88, 0, 87, 56, -2, 58, 59,
-	74, 0, 0, 0, 37, 38, 0, 0, 0, 45,
-	43, 44, 47, 50, 219, 220, 0, 0, 226, 227,
-	228, 229, 230, 231, 232, 233, -2, -2, -2, -2,
-	-2, -2, -2, 0, 279, 0, 0, 0, 0, -2,
-	-2, -2, 250, 0, 252, 254, 256, 258, 260, 262,
-	264, 266, 121, 118, 0, 0, 27, 0, 29, 0,
-	31, 0, 0, 128, 128, 76, 0, 77, 79, 0,
-	127, 51, 52, 0, 54, 0, 0, 0, 0, 314,
-	321, 0, 320, 0, 0, 339, 187, 0, 189, 0,
-	0, 190, 0, 0, 0, 302, 0, 0, 308, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 96,
-	94, 0, 89, 90, 0, 93, 0, 82, 84, 50,
-	0, 0, 0, 0, 0, 39, 0, 40, 50, 0,
-	49, 0, 223, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, -2, -2, -2, 251, 253, 255,
-	257, 259, 261, 263, 265, 267, 24, 122, 25, 119,
-	120, 123, 28, 30, 32, 110, 111, 114, 0, 0,
-	0, 0, 93, 93, 93, 0, 0, 0, 80, 50,
-	73, 53, 0, 323, 0, 325, 317, 0, 322, 0,
-	0, 188, 0, 0, 0, 0, 0, 0, 306, 307,
-	101, 329, 333, 336, 334, 335, 330, 331, 163, 163,
-	0, 97, 0, 99, 0, 95, 0, 0, 96, 0,
-	0, 0, 63, 64, 83, 85, 76, 75, 214, 74,
-	74, 50, 46, 50, 41, 48, 221, 222, 224, 0,
-	242, 280, 281, 0, 0, 287, 288, 289, 290, 291,
-	292, 0, 0, 113, 115, 116, 117, 136, 133, 0,
-	142, 131, 0, 0, 136, 142, 118, 93, 118, 118,
-	150, 151, 0, 165, 166, 154, 0, 126, 0, 0,
-	324, 0, 318, 0, 0, 194, 194, 191, 194, 0,
-	0, 0, 0, 35, 0, 104, 91, 92, 36, 60,
-	74, 0, 0, 61, 50, 65, 0, 0, 50, 50,
-	68, 42, 225, 0, 284, 0, 243, 112, 124, 0,
-	137, 0, 0, 0, 0, 0, 132, 141, 144, 0,
-	136, 118, 136, 136, 0, 0, 0, 168, 155, 0,
-	78, 0, 0, 319, 194, 0, 206, 195, 0, 207,
-	209, 0, 212, 337, 164, 338, 102, 50, 0, 0,
-	62, 215, 216, 0, 76, 76, 282, 283, 285, 0,
-	134, 138, 139, 0, 0, 0, 0, 0, 0, 0,
-	146, 136, 148, 149, 152, 154, 167, 163, 157, 0,
-	171, 131, 0, 0, 198, 0, 201, 203, 196, 197,
-	208, 0, 194, 0, 105, 103, 0, 74, 74, 217,
-	66, 67, 286, 140, 0, 136, 143, 129, 0, 136,
-	147, 153, 0, 0, 0, 0, 118, 0, 132, 0,
-	185, 199, 0, 192, 0, 0, 211, 213, 98, 106,
-	0, 69, 79, 50, 50, 0, 125, 130, 145, 156,
-	158, 159, 162, 160, 136, 0, 0, 0, 200, 204,
-	0, 202, 210, 107, 0, 0, 0, 135, 169, 0,
-	0, 171, 194, 0, 0, 0, 70, 71, 0, 93,
-	0, 118, 198, 205, 193, 86, 175, 93, 93, 178,
-	183, 0, 136, 186, 172, 0, 180, 93, 182, 173,
-	0, 174, 93, 170, 0, 0, 181, 0, 184, 0,
-	0, 0, 93, 0, 0, 178, 0, 0, 176, 177,
-	179,
+	0, -2, -2, -2, 292, 294, 296, 298, 300, 302,
+	304, 306, 308, 14, 18, 0, 0, 0, 0, 118,
+	118, 118, 0, 0, 0, 102, 0, 69, 94, 69,
+	72, 0, 364, 0, 366, 358, 0, 363, 0, 43,
+	147, 44, 144, 145, 148, 47, 49, 51, 135, 136,
+	139, 0, 0, 0, 214, 0, 0, 0, 0, 0,
+	0, 0, 0, 347, 348, 126, 389, 393, 396, 394,
+	395, 390, 391, 189, 189, 0, 122, 0, 124, 0,
+	120, 0, 0, 121, 0, 0, 0, 84, 85, 105,
+	107, 98, 97, 255, 96, 96, 69, 65, 69, 60,
+	67, 262, 263, 265, 0, 283, 321, 322, 0, 0,
+	328, 329, 330, 331, 332, 333, 0, 162, 159, 0,
+	168, 157, 0, 0, 162, 168, 143, 118, 143, 143,
+	176, 177, 0, 191, 192, 180, 0, 0, 0, 151,
+	152, 0, 0, 0, 365, 0, 0, 359, 0, 138,
+	140, 141, 142, 40, 0, 0, 0, 247, 220, 220,
+	217, 246, 220, 0, 0, 0, 0, 54, 0, 129,
+	116, 117, 55, 0, 96, 0, 0, 0, 69, 86,
+	0, 0, 69, 69, 89, 61, 266, 0, 325, 0,
+	284, 149, 0, 163, 0, 0, 0, 0, 0, 158,
+	167, 170, 0, 162, 143, 162, 162, 0, 0, 0,
+	194, 181, 0, 100, 0, 227, 229, 0, 231, 0,
+	254, 0, 0, 372, 0, 372, 360, 137, 0, 220,
+	0, 0, 248, 249, 234, 221, 0, 235, 237, 0,
+	240, 397, 190, 398, 127, 79, 101, 69, 0, 0,
+	81, 101, 83, 256, 257, 0, 98, 98, 323, 324,
+	326, 0, 160, 164, 165, 0, 0, 0, 0, 0,
+	0, 0, 172, 162, 174, 175, 178, 180, 193, 189,
+	183, 0, 0, 224, 0, 0, 197, 157, 0, 0,
+	0, 374, 0, 372, 0, 41, 224, 0, 0, 0,
+	222, 223, 236, 0, 220, 0, 130, 128, 80, 0,
+	108, 96, 96, 82, 258, 87, 88, 327, 166, 0,
+	162, 169, 155, 0, 162, 173, 179, 0, 0, 0,
+	0, 228, 252, 225, 0, 0, 253, 143, 0, 158,
+	0, 367, 376, 0, 0, 0, 369, 211, 218, 0,
+	250, 0, 239, 241, 123, 131, 0, 0, 90, 0,
+	69, 69, 0, 150, 156, 171, 182, 184, 185, 188,
+	186, 226, 0, 162, 0, 0, 0, 371, 377, 0,
+	380, 381, 0, 373, 368, 232, 0, 0, 238, 132,
+	111, 0, 108, 108, 161, 189, 195, 0, 0, 197,
+	378, 0, 0, 0, 0, 375, 220, 0, 0, 242,
+	0, 91, 92, 0, 0, 118, 0, 143, 0, 382,
+	383, 384, 385, 386, 224, 233, 219, 0, 0, 230,
+	201, 118, 118, 204, 209, 0, 162, 0, 212, 109,
+	110, 198, 0, 206, 118, 208, 199, 0, 200, 118,
+	196, 379, 0, 0, 207, 0, 210, 0, 0, 0,
+	118, 0, 0, 204, 0, 0, 202, 203, 205,
 }
 var yyTok1 = []int{
 
@@ -1196,6 +1312,7 @@ var yyTok2 = []int{
 	162, 163, 164, 165, 166, 167, 168, 169, 170, 171,
 	172, 173, 174, 175, 176, 177, 178, 179, 180, 181,
 	182, 183, 184, 185, 186, 187, 188, 189, 190, 191,
+	192, 193, 194, 195, 196, 197, 198, 199, 200, 201,
 }
 var yyTok3 = []int{
 	0,
@@ -1427,12 +1544,12 @@ yydefault:
 	switch yynt {
 
 	case 1:
-		//line n1ql.y:361
+		//line n1ql.y:397
 		{
 			yylex.(*lexer).setStatement(yyS[yypt-0].statement)
 		}
 	case 2:
-		//line n1ql.y:366
+		//line n1ql.y:402
 		{
 			yylex.(*lexer).setExpression(yyS[yypt-0].expr)
 		}
@@ -1449,39 +1566,67 @@ yydefault:
 	case 8:
 		yyVAL.statement = yyS[yypt-0].statement
 	case 9:
-		//line n1ql.y:387
+		//line n1ql.y:423
 		{
-			yyVAL.statement = algebra.NewExplain(yyS[yypt-0].statement)
+			yyVAL.statement = algebra.NewExplain(yyS[yypt-0].statement, false, yyS[yypt-1].s)
 		}
 	case 10:
-		//line n1ql.y:394
+		//line n1ql.y:428
 		{
-			yyVAL.statement = algebra.NewPrepare(yyS[yypt-0].statement)
+			yyVAL.statement = algebra.NewExplain(yyS[yypt-0].statement, true, "")
 		}
 	case 11:
-		//line n1ql.y:401
+		//line n1ql.y:435
 		{
-			yyVAL.statement = algebra.NewExecute(yyS[yypt-0].expr)
+			yyVAL.s = ""
 		}
 	case 12:
-		//line n1ql.y:408
+		//line n1ql.y:440
 		{
-			yyVAL.statement = yyS[yypt-0].fullselect
+			if strings.ToLower(yyS[yypt-1].s) != "format" {
+				yylex.Error(fmt.Sprintf("Invalid EXPLAIN %s.", yyS[yypt-1].s))
+			}
+
+			yyVAL.s = strings.ToLower(yyS[yypt-0].s)
+			if !algebra.IsExplainFormat(yyVAL.s) {
+				yylex.Error(fmt.Sprintf("Invalid EXPLAIN FORMAT %s.", yyS[yypt-0].s))
+			}
 		}
 	case 13:
-		yyVAL.statement = yyS[yypt-0].statement
+		//line n1ql.y:454
+		{
+			yyVAL.statement = algebra.NewPrepare("", yyS[yypt-0].statement)
+		}
 	case 14:
-		yyVAL.statement = yyS[yypt-0].statement
+		//line n1ql.y:459
+		{
+			yyVAL.statement = algebra.NewPrepare(yyS[yypt-2].s, yyS[yypt-0].statement)
+		}
 	case 15:
-		yyVAL.statement = yyS[yypt-0].statement
+		//line n1ql.y:466
+		{
+			yyVAL.statement = algebra.NewExecute(yyS[yypt-1].expr, yyS[yypt-0].expr)
+		}
 	case 16:
-		yyVAL.statement = yyS[yypt-0].statement
+		//line n1ql.y:471
+		{
+			yyVAL.statement = algebra.NewExecute(expression.NewConstant(yyS[yypt-1].s), yyS[yypt-0].expr)
+		}
 	case 17:
-		yyVAL.statement = yyS[yypt-0].statement
+		//line n1ql.y:478
+		{
+			yyVAL.expr = nil
+		}
 	case 18:
-		yyVAL.statement = yyS[yypt-0].statement
+		//line n1ql.y:483
+		{
+			yyVAL.expr = yyS[yypt-0].expr
+		}
 	case 19:
-		yyVAL.statement = yyS[yypt-0].statement
+		//line n1ql.y:490
+		{
+			yyVAL.statement = yyS[yypt-0].fullselect
+		}
 	case 20:
 		yyVAL.statement = yyS[yypt-0].statement
 	case 21:
@@ -1489,194 +1634,278 @@ yydefault:
 	case 22:
 		yyVAL.statement = yyS[yypt-0].statement
 	case 23:
-		//line n1ql.y:441
+		yyVAL.statement = yyS[yypt-0].statement
+	case 24:
+		yyVAL.statement = yyS[yypt-0].statement
+	case 25:
+		yyVAL.statement = yyS[yypt-0].statement
+	case 26:
+		yyVAL.statement = yyS[yypt-0].statement
+	case 27:
+		yyVAL.statement = yyS[yypt-0].statement
+	case 28:
+		yyVAL.statement = yyS[yypt-0].statement
+	case 29:
+		yyVAL.statement = yyS[yypt-0].statement
+	case 30:
+		yyVAL.statement = yyS[yypt-0].statement
+	case 31:
+		yyVAL.statement = yyS[yypt-0].statement
+	case 32:
+		yyVAL.statement = yyS[yypt-0].statement
+	case 33:
+		yyVAL.statement = yyS[yypt-0].statement
+	case 34:
+		yyVAL.fullselect = yyS[yypt-0].fullselect
+	case 35:
+		//line n1ql.y:535
+		{
+			yyS[yypt-0].fullselect.SetWith(yyS[yypt-1].with)
+			yyVAL.fullselect = yyS[yypt-0].fullselect
+		}
+	case 36:
+		//line n1ql.y:543
+		{
+			yyVAL.with = algebra.NewWith(yyS[yypt-1].b, yyS[yypt-0].withTerms)
+		}
+	case 37:
+		//line n1ql.y:550
+		{
+			yyVAL.b = false
+		}
+	case 38:
+		//line n1ql.y:555
+		{
+			yyVAL.b = true
+		}
+	case 39:
+		//line n1ql.y:562
+		{
+			yyVAL.withTerms = algebra.WithTerms{yyS[yypt-0].withTerm}
+		}
+	case 40:
+		//line n1ql.y:567
+		{
+			yyVAL.withTerms = append(yyS[yypt-2].withTerms, yyS[yypt-0].withTerm)
+		}
+	case 41:
+		//line n1ql.y:574
+		{
+			yyVAL.withTerm = algebra.NewWithTerm(yyS[yypt-4].s, yyS[yypt-1].fullselect)
+		}
+	case 42:
+		//line n1ql.y:581
 		{
 			yyVAL.fullselect = algebra.NewSelect(yyS[yypt-1].subresult, yyS[yypt-0].order, nil, nil) /* OFFSET precedes LIMIT */
 		}
-	case 24:
-		//line n1ql.y:445
+	case 43:
+		//line n1ql.y:585
 		{
 			yyVAL.fullselect = algebra.NewSelect(yyS[yypt-3].subresult, yyS[yypt-2].order, yyS[yypt-0].expr, yyS[yypt-1].expr) /* OFFSET precedes LIMIT */
 		}
-	case 25:
-		//line n1ql.y:449
+	case 44:
+		//line n1ql.y:589
 		{
 			yyVAL.fullselect = algebra.NewSelect(yyS[yypt-3].subresult, yyS[yypt-2].order, yyS[yypt-1].expr, yyS[yypt-0].expr) /* OFFSET precedes LIMIT */
 		}
-	case 26:
-		//line n1ql.y:455
+	case 45:
+		//line n1ql.y:595
 		{
 			yyVAL.subresult = yyS[yypt-0].subselect
 		}
-	case 27:
-		//line n1ql.y:460
+	case 46:
+		//line n1ql.y:600
 		{
 			yyVAL.subresult = algebra.NewUnion(yyS[yypt-2].subresult, yyS[yypt-0].subselect)
 		}
-	case 28:
-		//line n1ql.y:465
+	case 47:
+		//line n1ql.y:605
 		{
 			yyVAL.subresult = algebra.NewUnionAll(yyS[yypt-3].subresult, yyS[yypt-0].subselect)
 		}
-	case 29:
-		//line n1ql.y:470
+	case 48:
+		//line n1ql.y:610
 		{
 			yyVAL.subresult = algebra.NewIntersect(yyS[yypt-2].subresult, yyS[yypt-0].subselect)
 		}
-	case 30:
-		//line n1ql.y:475
+	case 49:
+		//line n1ql.y:615
 		{
 			yyVAL.subresult = algebra.NewIntersectAll(yyS[yypt-3].subresult, yyS[yypt-0].subselect)
 		}
-	case 31:
-		//line n1ql.y:480
+	case 50:
+		//line n1ql.y:620
 		{
 			yyVAL.subresult = algebra.NewExcept(yyS[yypt-2].subresult, yyS[yypt-0].subselect)
 		}
-	case 32:
-		//line n1ql.y:485
+	case 51:
+		//line n1ql.y:625
 		{
 			yyVAL.subresult = algebra.NewExceptAll(yyS[yypt-3].subresult, yyS[yypt-0].subselect)
 		}
-	case 33:
+	case 52:
 		yyVAL.subselect = yyS[yypt-0].subselect
-	case 34:
+	case 53:
 		yyVAL.subselect = yyS[yypt-0].subselect
-	case 35:
-		//line n1ql.y:498
+	case 54:
+		//line n1ql.y:638
 		{
 			yyVAL.subselect = algebra.NewSubselect(yyS[yypt-4].fromTerm, yyS[yypt-3].bindings, yyS[yypt-2].expr, yyS[yypt-1].group, yyS[yypt-0].projection)
 		}
-	case 36:
-		//line n1ql.y:505
+	case 55:
+		//line n1ql.y:645
 		{
 			yyVAL.subselect = algebra.NewSubselect(yyS[yypt-3].fromTerm, yyS[yypt-2].bindings, yyS[yypt-1].expr, yyS[yypt-0].group, yyS[yypt-4].projection)
 		}
-	case 37:
-		//line n1ql.y:520
+	case 56:
+		//line n1ql.y:660
 		{
 			yyVAL.projection = yyS[yypt-0].projection
 		}
-	case 38:
-		//line n1ql.y:527
+	case 57:
+		//line n1ql.y:667
 		{
 			yyVAL.projection = algebra.NewProjection(false, yyS[yypt-0].resultTerms)
 		}
-	case 39:
-		//line n1ql.y:532
+	case 58:
+		//line n1ql.y:672
 		{
 			yyVAL.projection = algebra.NewProjection(true, yyS[yypt-0].resultTerms)
 		}
-	case 40:
-		//line n1ql.y:537
+	case 59:
+		//line n1ql.y:677
 		{
 			yyVAL.projection = algebra.NewProjection(false, yyS[yypt-0].resultTerms)
 		}
-	case 41:
-		//line n1ql.y:542
+	case 60:
+		//line n1ql.y:682
 		{
 			yyVAL.projection = algebra.NewRawProjection(false, yyS[yypt-1].expr, yyS[yypt-0].s)
 		}
-	case 42:
-		//line n1ql.y:547
+	case 61:
+		//line n1ql.y:687
 		{
 			yyVAL.projection = algebra.NewRawProjection(true, yyS[yypt-1].expr, yyS[yypt-0].s)
 		}
-	case 45:
-		//line n1ql.y:560
+	case 64:
+		//line n1ql.y:700
 		{
 			yyVAL.resultTerms = algebra.ResultTerms{yyS[yypt-0].resultTerm}
 		}
-	case 46:
-		//line n1ql.y:565
+	case 65:
+		//line n1ql.y:705
 		{
 			yyVAL.resultTerms = append(yyS[yypt-2].resultTerms, yyS[yypt-0].resultTerm)
 		}
-	case 47:
-		//line n1ql.y:572
+	case 66:
+		//line n1ql.y:712
 		{
 			yyVAL.resultTerm = algebra.NewResultTerm(nil, true, "")
 		}
-	case 48:
-		//line n1ql.y:577
+	case 67:
+		//line n1ql.y:717
 		{
 			yyVAL.resultTerm = algebra.NewResultTerm(yyS[yypt-2].expr, true, "")
 		}
-	case 49:
-		//line n1ql.y:582
+	case 68:
+		//line n1ql.y:722
 		{
 			yyVAL.resultTerm = algebra.NewResultTerm(yyS[yypt-1].expr, false, yyS[yypt-0].s)
 		}
-	case 50:
-		//line n1ql.y:589
+	case 69:
+		//line n1ql.y:729
 		{
 			yyVAL.s = ""
 		}
-	case 51:
+	case 70:
 		yyVAL.s = yyS[yypt-0].s
-	case 52:
+	case 71:
 		yyVAL.s = yyS[yypt-0].s
-	case 53:
-		//line n1ql.y:600
+	case 72:
+		//line n1ql.y:740
 		{
 			yyVAL.s = yyS[yypt-0].s
 		}
-	case 54:
+	case 73:
 		yyVAL.s = yyS[yypt-0].s
-	case 55:
-		//line n1ql.y:618
+	case 74:
+		//line n1ql.y:758
 		{
 			yyVAL.fromTerm = nil
 		}
-	case 56:
+	case 75:
 		yyVAL.fromTerm = yyS[yypt-0].fromTerm
-	case 57:
-		//line n1ql.y:627
+	case 76:
+		//line n1ql.y:767
 		{
 			yyVAL.fromTerm = yyS[yypt-0].fromTerm
 		}
-	case 58:
-		//line n1ql.y:634
+	case 77:
+		//line n1ql.y:774
 		{
 			yyVAL.fromTerm = yyS[yypt-0].keyspaceTerm
 		}
-	case 59:
-		//line n1ql.y:639
+	case 78:
+		//line n1ql.y:779
 		{
 			yyVAL.fromTerm = yyS[yypt-0].subqueryTerm
 		}
-	case 60:
-		//line n1ql.y:644
+	case 79:
+		//line n1ql.y:784
+		{
+			if yyS[yypt-1].keyspaceTerm.JoinHint() != algebra.JOIN_HINT_NONE {
+				yylex.Error("USE HASH requires an ON clause.")
+			} else {
+				yyS[yypt-1].keyspaceTerm.SetKeys(yyS[yypt-0].expr)
+				yyVAL.fromTerm = algebra.NewJoin(yyS[yypt-4].fromTerm, yyS[yypt-3].b, yyS[yypt-1].keyspaceTerm)
+			}
+		}
+	case 80:
+		//line n1ql.y:794
 		{
-			yyVAL.fromTerm = algebra.NewJoin(yyS[yypt-3].fromTerm, yyS[yypt-2].b, yyS[yypt-0].keyspaceTerm)
+			yyVAL.fromTerm = algebra.NewAnsiJoin(yyS[yypt-5].fromTerm, yyS[yypt-4].b, yyS[yypt-2].keyspaceTerm, yyS[yypt-0].expr)
 		}
-	case 61:
-		//line n1ql.y:649
+	case 81:
+		//line n1ql.y:799
+		{
+			if yyS[yypt-1].keyspaceTerm.JoinHint() != algebra.JOIN_HINT_NONE {
+				yylex.Error("USE HASH requires an ON clause.")
+			} else {
+				yyS[yypt-1].keyspaceTerm.SetKeys(yyS[yypt-0].expr)
+				yyVAL.fromTerm = algebra.NewNest(yyS[yypt-4].fromTerm, yyS[yypt-3].b, yyS[yypt-1].keyspaceTerm)
+			}
+		}
+	case 82:
+		//line n1ql.y:809
 		{
-			yyVAL.fromTerm = algebra.NewNest(yyS[yypt-3].fromTerm, yyS[yypt-2].b, yyS[yypt-0].keyspaceTerm)
+			if yyS[yypt-2].keyspaceTerm.JoinHint() != algebra.JOIN_HINT_NONE {
+				yylex.Error("USE HASH is not supported for NEST.")
+			} else {
+				yyVAL.fromTerm = algebra.NewAnsiNest(yyS[yypt-5].fromTerm, yyS[yypt-4].b, yyS[yypt-2].keyspaceTerm, yyS[yypt-0].expr)
+			}
 		}
-	case 62:
-		//line n1ql.y:654
+	case 83:
+		//line n1ql.y:818
 		{
 			yyVAL.fromTerm = algebra.NewUnnest(yyS[yypt-4].fromTerm, yyS[yypt-3].b, yyS[yypt-1].expr, yyS[yypt-0].s)
 		}
-	case 65:
-		//line n1ql.y:667
+	case 86:
+		//line n1ql.y:831
 		{
 			yyVAL.keyspaceTerm = algebra.NewKeyspaceTerm("", yyS[yypt-3].s, yyS[yypt-2].path, yyS[yypt-1].s, yyS[yypt-0].expr)
 		}
-	case 66:
-		//line n1ql.y:672
+	case 87:
+		//line n1ql.y:836
 		{
 			yyVAL.keyspaceTerm = algebra.NewKeyspaceTerm(yyS[yypt-5].s, yyS[yypt-3].s, yyS[yypt-2].path, yyS[yypt-1].s, yyS[yypt-0].expr)
 		}
-	case 67:
-		//line n1ql.y:677
+	case 88:
+		//line n1ql.y:841
 		{
 			yyVAL.keyspaceTerm = algebra.NewKeyspaceTerm("#system", yyS[yypt-3].s, yyS[yypt-2].path, yyS[yypt-1].s, yyS[yypt-0].expr)
 		}
-	case 68:
-		//line n1ql.y:684
+	case 89:
+		//line n1ql.y:848
 		{
 			if yyS[yypt-0].s == "" {
 				yylex.Error("Subquery in FROM clause must have an alias.")
@@ -1684,582 +1913,623 @@ yydefault:
 				yyVAL.subqueryTerm = algebra.NewSubqueryTerm(yyS[yypt-2].fullselect, yyS[yypt-0].s)
 			}
 		}
-	case 69:
-		//line n1ql.y:695
+	case 90:
+		//line n1ql.y:859
 		{
-			yyVAL.keyspaceTerm = algebra.NewKeyspaceTerm("", yyS[yypt-3].s, yyS[yypt-2].path, yyS[yypt-1].s, yyS[yypt-0].expr)
+			yyVAL.keyspaceTerm = algebra.NewKeyspaceTerm("", yyS[yypt-3].s, yyS[yypt-2].path, yyS[yypt-1].s, nil)
+			yyVAL.keyspaceTerm.SetJoinHint(yyS[yypt-0].joinHint)
 		}
-	case 70:
-		//line n1ql.y:700
+	case 91:
+		//line n1ql.y:865
 		{
-			yyVAL.keyspaceTerm = algebra.NewKeyspaceTerm(yyS[yypt-5].s, yyS[yypt-3].s, yyS[yypt-2].path, yyS[yypt-1].s, yyS[yypt-0].expr)
+			yyVAL.keyspaceTerm = algebra.NewKeyspaceTerm(yyS[yypt-5].s, yyS[yypt-3].s, yyS[yypt-2].path, yyS[yypt-1].s, nil)
+			yyVAL.keyspaceTerm.SetJoinHint(yyS[yypt-0].joinHint)
 		}
-	case 71:
-		//line n1ql.y:705
+	case 92:
+		//line n1ql.y:871
 		{
-			yyVAL.keyspaceTerm = algebra.NewKeyspaceTerm("#system", yyS[yypt-3].s, yyS[yypt-2].path, yyS[yypt-1].s, yyS[yypt-0].expr)
+			yyVAL.keyspaceTerm = algebra.NewKeyspaceTerm("#system", yyS[yypt-3].s, yyS[yypt-2].path, yyS[yypt-1].s, nil)
+			yyVAL.keyspaceTerm.SetJoinHint(yyS[yypt-0].joinHint)
 		}
-	case 72:
+	case 93:
 		yyVAL.s = yyS[yypt-0].s
-	case 73:
+	case 94:
 		yyVAL.s = yyS[yypt-0].s
-	case 74:
-		//line n1ql.y:720
+	case 95:
+		//line n1ql.y:885
+		{
+			/* Allow system:statistics */
+			yyVAL.s = "statistics"
+		}
+	case 96:
+		//line n1ql.y:892
 		{
 			yyVAL.path = nil
 		}
-	case 75:
-		//line n1ql.y:725
+	case 97:
+		//line n1ql.y:897
 		{
 			yyVAL.path = yyS[yypt-0].path
 		}
-	case 76:
-		//line n1ql.y:732
+	case 98:
+		//line n1ql.y:904
 		{
 			yyVAL.expr = nil
 		}
-	case 77:
+	case 99:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 78:
-		//line n1ql.y:741
+	case 100:
+		//line n1ql.y:913
 		{
 			yyVAL.expr = yyS[yypt-0].expr
 		}
-	case 79:
-		//line n1ql.y:748
+	case 101:
+		//line n1ql.y:920
 		{
 		}
-	case 81:
-		//line n1ql.y:756
+	case 103:
+		//line n1ql.y:928
 		{
 			yyVAL.b = false
 		}
-	case 82:
-		//line n1ql.y:761
+	case 104:
+		//line n1ql.y:933
 		{
 			yyVAL.b = false
 		}
-	case 83:
-		//line n1ql.y:766
+	case 105:
+		//line n1ql.y:938
 		{
 			yyVAL.b = true
 		}
-	case 86:
-		//line n1ql.y:779
+	case 108:
+		//line n1ql.y:951
+		{
+			yyVAL.joinHint = algebra.JOIN_HINT_NONE
+		}
+	case 109:
+		//line n1ql.y:956
+		{
+			yyVAL.joinHint = algebra.USE_HASH_BUILD
+		}
+	case 110:
+		//line n1ql.y:961
+		{
+			yyVAL.joinHint = algebra.USE_HASH_PROBE
+		}
+	case 111:
+		//line n1ql.y:968
 		{
 			yyVAL.expr = yyS[yypt-0].expr
 		}
-	case 87:
-		//line n1ql.y:793
+	case 112:
+		//line n1ql.y:982
 		{
 			yyVAL.bindings = nil
 		}
-	case 88:
+	case 113:
 		yyVAL.bindings = yyS[yypt-0].bindings
-	case 89:
-		//line n1ql.y:802
+	case 114:
+		//line n1ql.y:991
 		{
 			yyVAL.bindings = yyS[yypt-0].bindings
 		}
-	case 90:
-		//line n1ql.y:809
+	case 115:
+		//line n1ql.y:998
 		{
 			yyVAL.bindings = expression.Bindings{yyS[yypt-0].binding}
 		}
-	case 91:
-		//line n1ql.y:814
+	case 116:
+		//line n1ql.y:1003
 		{
 			yyVAL.bindings = append(yyS[yypt-2].bindings, yyS[yypt-0].binding)
 		}
-	case 92:
-		//line n1ql.y:821
+	case 117:
+		//line n1ql.y:1010
 		{
 			yyVAL.binding = expression.NewBinding(yyS[yypt-2].s, yyS[yypt-0].expr)
 		}
-	case 93:
-		//line n1ql.y:835
+	case 118:
+		//line n1ql.y:1024
 		{
 			yyVAL.expr = nil
 		}
-	case 94:
+	case 119:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 95:
-		//line n1ql.y:844
+	case 120:
+		//line n1ql.y:1033
 		{
 			yyVAL.expr = yyS[yypt-0].expr
 		}
-	case 96:
-		//line n1ql.y:858
+	case 121:
+		//line n1ql.y:1047
 		{
 			yyVAL.group = nil
 		}
-	case 97:
+	case 122:
 		yyVAL.group = yyS[yypt-0].group
-	case 98:
-		//line n1ql.y:867
+	case 123:
+		//line n1ql.y:1056
 		{
 			yyVAL.group = algebra.NewGroup(yyS[yypt-2].exprs, yyS[yypt-1].bindings, yyS[yypt-0].expr)
 		}
-	case 99:
-		//line n1ql.y:872
+	case 124:
+		//line n1ql.y:1061
 		{
 			yyVAL.group = algebra.NewGroup(nil, yyS[yypt-0].bindings, nil)
 		}
-	case 100:
-		//line n1ql.y:879
+	case 125:
+		//line n1ql.y:1068
 		{
 			yyVAL.exprs = expression.Expressions{yyS[yypt-0].expr}
 		}
-	case 101:
-		//line n1ql.y:884
+	case 126:
+		//line n1ql.y:1073
 		{
 			yyVAL.exprs = append(yyS[yypt-2].exprs, yyS[yypt-0].expr)
 		}
-	case 102:
-		//line n1ql.y:891
+	case 127:
+		//line n1ql.y:1080
 		{
 			yyVAL.bindings = nil
 		}
-	case 103:
+	case 128:
 		yyVAL.bindings = yyS[yypt-0].bindings
-	case 104:
-		//line n1ql.y:900
+	case 129:
+		//line n1ql.y:1089
 		{
 			yyVAL.bindings = yyS[yypt-0].bindings
 		}
-	case 105:
-		//line n1ql.y:907
+	case 130:
+		//line n1ql.y:1096
 		{
 			yyVAL.expr = nil
 		}
-	case 106:
+	case 131:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 107:
-		//line n1ql.y:916
+	case 132:
+		//line n1ql.y:1105
 		{
 			yyVAL.expr = yyS[yypt-0].expr
 		}
-	case 108:
-		//line n1ql.y:930
+	case 133:
+		//line n1ql.y:1119
 		{
 			yyVAL.order = nil
 		}
-	case 109:
+	case 134:
 		yyVAL.order = yyS[yypt-0].order
-	case 110:
-		//line n1ql.y:939
+	case 135:
+		//line n1ql.y:1128
 		{
 			yyVAL.order = algebra.NewOrder(yyS[yypt-0].sortTerms)
 		}
-	case 111:
-		//line n1ql.y:946
+	case 136:
+		//line n1ql.y:1135
 		{
 			yyVAL.sortTerms = algebra.SortTerms{yyS[yypt-0].sortTerm}
 		}
-	case 112:
-		//line n1ql.y:951
+	case 137:
+		//line n1ql.y:1140
 		{
 			yyVAL.sortTerms = append(yyS[yypt-2].sortTerms, yyS[yypt-0].sortTerm)
 		}
-	case 113:
-		//line n1ql.y:958
+	case 138:
+		//line n1ql.y:1147
 		{
 			yyVAL.sortTerm = algebra.NewSortTerm(yyS[yypt-1].expr, yyS[yypt-0].b)
 		}
-	case 114:
-		//line n1ql.y:965
+	case 139:
+		//line n1ql.y:1154
 		{
 			yyVAL.b = false
 		}
-	case 115:
+	case 140:
 		yyVAL.b = yyS[yypt-0].b
-	case 116:
-		//line n1ql.y:974
+	case 141:
+		//line n1ql.y:1163
 		{
 			yyVAL.b = false
 		}
-	case 117:
-		//line n1ql.y:979
+	case 142:
+		//line n1ql.y:1168
 		{
 			yyVAL.b = true
 		}
-	case 118:
-		//line n1ql.y:993
+	case 143:
+		//line n1ql.y:1182
 		{
 			yyVAL.expr = nil
 		}
-	case 119:
+	case 144:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 120:
-		//line n1ql.y:1002
+	case 145:
+		//line n1ql.y:1191
 		{
 			yyVAL.expr = yyS[yypt-0].expr
 		}
-	case 121:
-		//line n1ql.y:1016
+	case 146:
+		//line n1ql.y:1205
 		{
 			yyVAL.expr = nil
 		}
-	case 122:
+	case 147:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 123:
-		//line n1ql.y:1025
+	case 148:
+		//line n1ql.y:1214
 		{
 			yyVAL.expr = yyS[yypt-0].expr
 		}
-	case 124:
-		//line n1ql.y:1039
+	case 149:
+		//line n1ql.y:1228
 		{
 			yyVAL.statement = algebra.NewInsertValues(yyS[yypt-3].keyspaceRef, yyS[yypt-1].pairs, yyS[yypt-0].projection)
 		}
-	case 125:
-		//line n1ql.y:1044
+	case 150:
+		//line n1ql.y:1233
 		{
 			yyVAL.statement = algebra.NewInsertSelect(yyS[yypt-6].keyspaceRef, yyS[yypt-4].expr, yyS[yypt-3].expr, yyS[yypt-1].fullselect, yyS[yypt-0].projection)
 		}
-	case 126:
-		//line n1ql.y:1051
+	case 151:
+		//line n1ql.y:1240
 		{
 			yyVAL.keyspaceRef = algebra.NewKeyspaceRef(yyS[yypt-3].s, yyS[yypt-1].s, yyS[yypt-0].s)
 		}
-	case 127:
-		//line n1ql.y:1056
+	case 152:
+		//line n1ql.y:1245
+		{
+			yyVAL.keyspaceRef = algebra.NewKeyspaceRef("#system", yyS[yypt-1].s, yyS[yypt-0].s)
+		}
+	case 153:
+		//line n1ql.y:1250
 		{
 			yyVAL.keyspaceRef = algebra.NewKeyspaceRef("", yyS[yypt-1].s, yyS[yypt-0].s)
 		}
-	case 133:
+	case 159:
 		yyVAL.pairs = yyS[yypt-0].pairs
-	case 134:
-		//line n1ql.y:1079
+	case 160:
+		//line n1ql.y:1273
 		{
 			yyVAL.pairs = append(yyS[yypt-2].pairs, yyS[yypt-0].pairs...)
 		}
-	case 135:
-		//line n1ql.y:1086
+	case 161:
+		//line n1ql.y:1280
 		{
 			yyVAL.pairs = algebra.Pairs{&algebra.Pair{Key: yyS[yypt-3].expr, Value: yyS[yypt-1].expr}}
 		}
-	case 136:
-		//line n1ql.y:1093
+	case 162:
+		//line n1ql.y:1287
 		{
 			yyVAL.projection = nil
 		}
-	case 137:
+	case 163:
 		yyVAL.projection = yyS[yypt-0].projection
-	case 138:
-		//line n1ql.y:1102
+	case 164:
+		//line n1ql.y:1296
 		{
 			yyVAL.projection = yyS[yypt-0].projection
 		}
-	case 139:
-		//line n1ql.y:1109
+	case 165:
+		//line n1ql.y:1303
 		{
 			yyVAL.projection = algebra.NewProjection(false, yyS[yypt-0].resultTerms)
 		}
-	case 140:
-		//line n1ql.y:1114
+	case 166:
+		//line n1ql.y:1308
 		{
 			yyVAL.projection = algebra.NewRawProjection(false, yyS[yypt-0].expr, "")
 		}
-	case 141:
-		//line n1ql.y:1121
+	case 167:
+		//line n1ql.y:1315
 		{
 			yyVAL.expr = yyS[yypt-0].expr
 		}
-	case 142:
-		//line n1ql.y:1128
+	case 168:
+		//line n1ql.y:1322
 		{
 			yyVAL.expr = nil
 		}
-	case 143:
-		//line n1ql.y:1133
+	case 169:
+		//line n1ql.y:1327
 		{
 			yyVAL.expr = yyS[yypt-0].expr
 		}
-	case 144:
-		//line n1ql.y:1147
+	case 170:
+		//line n1ql.y:1341
 		{
 			yyVAL.statement = algebra.NewUpsertValues(yyS[yypt-3].keyspaceRef, yyS[yypt-1].pairs, yyS[yypt-0].projection)
 		}
-	case 145:
-		//line n1ql.y:1152
+	case 171:
+		//line n1ql.y:1346
 		{
 			yyVAL.statement = algebra.NewUpsertSelect(yyS[yypt-6].keyspaceRef, yyS[yypt-4].expr, yyS[yypt-3].expr, yyS[yypt-1].fullselect, yyS[yypt-0].projection)
 		}
-	case 146:
-		//line n1ql.y:1166
+	case 172:
+		//line n1ql.y:1360
 		{
 			yyVAL.statement = algebra.NewDelete(yyS[yypt-4].keyspaceRef, yyS[yypt-3].expr, yyS[yypt-2].expr, yyS[yypt-1].expr, yyS[yypt-0].projection)
 		}
-	case 147:
-		//line n1ql.y:1180
+	case 173:
+		//line n1ql.y:1374
 		{
 			yyVAL.statement = algebra.NewUpdate(yyS[yypt-6].keyspaceRef, yyS[yypt-5].expr, yyS[yypt-4].set, yyS[yypt-3].unset, yyS[yypt-2].expr, yyS[yypt-1].expr, yyS[yypt-0].projection)
 		}
-	case 148:
-		//line n1ql.y:1185
+	case 174:
+		//line n1ql.y:1379
 		{
 			yyVAL.statement = algebra.NewUpdate(yyS[yypt-5].keyspaceRef, yyS[yypt-4].expr, yyS[yypt-3].set, nil, yyS[yypt-2].expr, yyS[yypt-1].expr, yyS[yypt-0].projection)
 		}
-	case 149:
-		//line n1ql.y:1190
+	case 175:
+		//line n1ql.y:1384
 		{
 			yyVAL.statement = algebra.NewUpdate(yyS[yypt-5].keyspaceRef, yyS[yypt-4].expr, nil, yyS[yypt-3].unset, yyS[yypt-2].expr, yyS[yypt-1].expr, yyS[yypt-0].projection)
 		}
-	case 150:
-		//line n1ql.y:1197
+	case 176:
+		//line n1ql.y:1391
 		{
 			yyVAL.set = algebra.NewSet(yyS[yypt-0].setTerms)
 		}
-	case 151:
-		//line n1ql.y:1204
+	case 177:
+		//line n1ql.y:1398
 		{
 			yyVAL.setTerms = algebra.SetTerms{yyS[yypt-0].setTerm}
 		}
-	case 152:
-		//line n1ql.y:1209
+	case 178:
+		//line n1ql.y:1403
 		{
 			yyVAL.setTerms = append(yyS[yypt-2].setTerms, yyS[yypt-0].setTerm)
 		}
-	case 153:
-		//line n1ql.y:1216
+	case 179:
+		//line n1ql.y:1410
 		{
 			yyVAL.setTerm = algebra.NewSetTerm(yyS[yypt-3].path, yyS[yypt-1].expr, yyS[yypt-0].updateFor)
 		}
-	case 154:
-		//line n1ql.y:1223
+	case 180:
+		//line n1ql.y:1417
 		{
 			yyVAL.updateFor = nil
 		}
-	case 155:
+	case 181:
 		yyVAL.updateFor = yyS[yypt-0].updateFor
-	case 156:
-		//line n1ql.y:1232
+	case 182:
+		//line n1ql.y:1426
 		{
 			yyVAL.updateFor = algebra.NewUpdateFor(yyS[yypt-2].bindings, yyS[yypt-1].expr)
 		}
-	case 157:
-		//line n1ql.y:1239
+	case 183:
+		//line n1ql.y:1433
 		{
 			yyVAL.bindings = expression.Bindings{yyS[yypt-0].binding}
 		}
-	case 158:
-		//line n1ql.y:1244
+	case 184:
+		//line n1ql.y:1438
 		{
 			yyVAL.bindings = append(yyS[yypt-2].bindings, yyS[yypt-0].binding)
 		}
-	case 159:
-		//line n1ql.y:1251
+	case 185:
+		//line n1ql.y:1445
 		{
 			yyVAL.binding = expression.NewBinding(yyS[yypt-2].s, yyS[yypt-0].expr)
 		}
-	case 160:
-		//line n1ql.y:1256
+	case 186:
+		//line n1ql.y:1450
 		{
 			yyVAL.binding = expression.NewDescendantBinding(yyS[yypt-2].s, yyS[yypt-0].expr)
 		}
-	case 161:
+	case 187:
 		yyVAL.s = yyS[yypt-0].s
-	case 162:
-		//line n1ql.y:1267
+	case 188:
+		//line n1ql.y:1461
 		{
 			yyVAL.expr = yyS[yypt-0].path
 		}
-	case 163:
-		//line n1ql.y:1274
+	case 189:
+		//line n1ql.y:1468
 		{
 			yyVAL.expr = nil
 		}
-	case 164:
-		//line n1ql.y:1279
+	case 190:
+		//line n1ql.y:1473
 		{
 			yyVAL.expr = yyS[yypt-0].expr
 		}
-	case 165:
-		//line n1ql.y:1286
+	case 191:
+		//line n1ql.y:1480
 		{
 			yyVAL.unset = algebra.NewUnset(yyS[yypt-0].unsetTerms)
 		}
-	case 166:
-		//line n1ql.y:1293
+	case 192:
+		//line n1ql.y:1487
 		{
 			yyVAL.unsetTerms = algebra.UnsetTerms{yyS[yypt-0].unsetTerm}
 		}
-	case 167:
-		//line n1ql.y:1298
+	case 193:
+		//line n1ql.y:1492
 		{
 			yyVAL.unsetTerms = append(yyS[yypt-2].unsetTerms, yyS[yypt-0].unsetTerm)
 		}
-	case 168:
-		//line n1ql.y:1305
+	case 194:
+		//line n1ql.y:1499
 		{
 			yyVAL.unsetTerm = algebra.NewUnsetTerm(yyS[yypt-1].path, yyS[yypt-0].updateFor)
 		}
-	case 169:
-		//line n1ql.y:1319
+	case 195:
+		//line n1ql.y:1513
 		{
 			source := algebra.NewMergeSourceFrom(yyS[yypt-5].keyspaceTerm, "")
 			yyVAL.statement = algebra.NewMerge(yyS[yypt-7].keyspaceRef, source, yyS[yypt-3].expr, yyS[yypt-2].mergeActions, yyS[yypt-1].expr, yyS[yypt-0].projection)
 		}
-	case 170:
-		//line n1ql.y:1325
+	case 196:
+		//line n1ql.y:1519
 		{
 			source := algebra.NewMergeSourceSelect(yyS[yypt-7].fullselect, yyS[yypt-5].s)
 			yyVAL.statement = algebra.NewMerge(yyS[yypt-10].keyspaceRef, source, yyS[yypt-3].expr, yyS[yypt-2].mergeActions, yyS[yypt-1].expr, yyS[yypt-0].projection)
 		}
-	case 171:
-		//line n1ql.y:1333
+	case 197:
+		//line n1ql.y:1527
 		{
 			yyVAL.mergeActions = algebra.NewMergeActions(nil, nil, nil)
 		}
-	case 172:
-		//line n1ql.y:1338
+	case 198:
+		//line n1ql.y:1532
 		{
 			yyVAL.mergeActions = algebra.NewMergeActions(yyS[yypt-1].mergeUpdate, yyS[yypt-0].mergeActions.Delete(), yyS[yypt-0].mergeActions.Insert())
 		}
-	case 173:
-		//line n1ql.y:1343
+	case 199:
+		//line n1ql.y:1537
 		{
 			yyVAL.mergeActions = algebra.NewMergeActions(nil, yyS[yypt-1].mergeDelete, yyS[yypt-0].mergeInsert)
 		}
-	case 174:
-		//line n1ql.y:1348
+	case 200:
+		//line n1ql.y:1542
 		{
 			yyVAL.mergeActions = algebra.NewMergeActions(nil, nil, yyS[yypt-0].mergeInsert)
 		}
-	case 175:
-		//line n1ql.y:1355
+	case 201:
+		//line n1ql.y:1549
 		{
 			yyVAL.mergeActions = algebra.NewMergeActions(nil, nil, nil)
 		}
-	case 176:
-		//line n1ql.y:1360
+	case 202:
+		//line n1ql.y:1554
 		{
 			yyVAL.mergeActions = algebra.NewMergeActions(nil, yyS[yypt-1].mergeDelete, yyS[yypt-0].mergeInsert)
 		}
-	case 177:
-		//line n1ql.y:1365
+	case 203:
+		//line n1ql.y:1559
 		{
 			yyVAL.mergeActions = algebra.NewMergeActions(nil, nil, yyS[yypt-0].mergeInsert)
 		}
-	case 178:
-		//line n1ql.y:1372
+	case 204:
+		//line n1ql.y:1566
 		{
 			yyVAL.mergeInsert = nil
 		}
-	case 179:
-		//line n1ql.y:1377
+	case 205:
+		//line n1ql.y:1571
 		{
 			yyVAL.mergeInsert = yyS[yypt-0].mergeInsert
 		}
-	case 180:
-		//line n1ql.y:1384
+	case 206:
+		//line n1ql.y:1578
 		{
 			yyVAL.mergeUpdate = algebra.NewMergeUpdate(yyS[yypt-1].set, nil, yyS[yypt-0].expr)
 		}
-	case 181:
-		//line n1ql.y:1389
+	case 207:
+		//line n1ql.y:1583
 		{
 			yyVAL.mergeUpdate = algebra.NewMergeUpdate(yyS[yypt-2].set, yyS[yypt-1].unset, yyS[yypt-0].expr)
 		}
-	case 182:
-		//line n1ql.y:1394
+	case 208:
+		//line n1ql.y:1588
 		{
 			yyVAL.mergeUpdate = algebra.NewMergeUpdate(nil, yyS[yypt-1].unset, yyS[yypt-0].expr)
 		}
-	case 183:
-		//line n1ql.y:1401
+	case 209:
+		//line n1ql.y:1595
 		{
 			yyVAL.mergeDelete = algebra.NewMergeDelete(yyS[yypt-0].expr)
 		}
-	case 184:
-		//line n1ql.y:1408
+	case 210:
+		//line n1ql.y:1602
 		{
 			yyVAL.mergeInsert = algebra.NewMergeInsert(yyS[yypt-1].expr, yyS[yypt-0].expr)
 		}
-	case 185:
-		//line n1ql.y:1422
+	case 211:
+		//line n1ql.y:1616
 		{
 			yyVAL.statement = algebra.NewCreatePrimaryIndex(yyS[yypt-4].s, yyS[yypt-2].keyspaceRef, yyS[yypt-1].indexType, yyS[yypt-0].val)
 		}
-	case 186:
-		//line n1ql.y:1427
+	case 212:
+		//line n1ql.y:1621
 		{
 			yyVAL.statement = algebra.NewCreateIndex(yyS[yypt-9].s, yyS[yypt-7].keyspaceRef, yyS[yypt-5].exprs, yyS[yypt-3].expr, yyS[yypt-2].expr, yyS[yypt-1].indexType, yyS[yypt-0].val)
 		}
-	case 187:
-		//line n1ql.y:1434
+	case 213:
+		//line n1ql.y:1628
 		{
 			yyVAL.s = "#primary"
 		}
-	case 188:
+	case 214:
 		yyVAL.s = yyS[yypt-0].s
-	case 189:
+	case 215:
 		yyVAL.s = yyS[yypt-0].s
-	case 190:
-		//line n1ql.y:1447
+	case 216:
+		//line n1ql.y:1641
 		{
 			yyVAL.keyspaceRef = algebra.NewKeyspaceRef("", yyS[yypt-0].s, "")
 		}
-	case 191:
-		//line n1ql.y:1452
+	case 217:
+		//line n1ql.y:1646
 		{
 			yyVAL.keyspaceRef = algebra.NewKeyspaceRef(yyS[yypt-2].s, yyS[yypt-0].s, "")
 		}
-	case 192:
-		//line n1ql.y:1459
+	case 218:
+		//line n1ql.y:1653
 		{
 			yyVAL.expr = nil
 		}
-	case 193:
-		//line n1ql.y:1464
+	case 219:
+		//line n1ql.y:1658
 		{
 			yyVAL.expr = yyS[yypt-0].expr
 		}
-	case 194:
-		//line n1ql.y:1471
+	case 220:
+		//line n1ql.y:1665
 		{
 			yyVAL.indexType = datastore.DEFAULT
 		}
-	case 195:
+	case 221:
 		yyVAL.indexType = yyS[yypt-0].indexType
-	case 196:
-		//line n1ql.y:1480
+	case 222:
+		//line n1ql.y:1674
 		{
 			yyVAL.indexType = datastore.VIEW
 		}
-	case 197:
-		//line n1ql.y:1485
+	case 223:
+		//line n1ql.y:1679
 		{
 			yyVAL.indexType = datastore.GSI
 		}
-	case 198:
-		//line n1ql.y:1492
+	case 224:
+		//line n1ql.y:1686
 		{
 			yyVAL.val = nil
 		}
-	case 199:
+	case 225:
 		yyVAL.val = yyS[yypt-0].val
-	case 200:
-		//line n1ql.y:1501
+	case 226:
+		//line n1ql.y:1695
 		{
 			yyVAL.val = yyS[yypt-0].expr.Value()
 			if yyVAL.val == nil {
 				yylex.Error("WITH value must be static.")
 			}
 		}
-	case 201:
-		//line n1ql.y:1511
+	case 227:
+		//line n1ql.y:1705
 		{
 			yyVAL.exprs = expression.Expressions{yyS[yypt-0].expr}
 		}
-	case 202:
-		//line n1ql.y:1516
+	case 228:
+		//line n1ql.y:1710
 		{
 			yyVAL.exprs = append(yyS[yypt-2].exprs, yyS[yypt-0].expr)
 		}
-	case 203:
-		//line n1ql.y:1523
+	case 229:
+		yyVAL.expr = yyS[yypt-0].expr
+	case 230:
+		//line n1ql.y:1719
+		{
+			exp := expression.NewDistinctArray(yyS[yypt-4].expr, yyS[yypt-2].bindings, yyS[yypt-1].expr)
+			if !exp.Indexable() {
+				yylex.Error(fmt.Sprintf("Expression not indexable: %s", exp.String()))
+			}
+
+			yyVAL.expr = exp
+		}
+	case 231:
+		//line n1ql.y:1731
 		{
 			exp := yyS[yypt-0].expr
 			if !exp.Indexable() || exp.Value() != nil {
@@ -2268,569 +2538,634 @@ yydefault:
 
 			yyVAL.expr = exp
 		}
-	case 204:
-		//line n1ql.y:1534
+	case 232:
+		//line n1ql.y:1742
 		{
 			yyVAL.expr = nil
 		}
-	case 205:
-		//line n1ql.y:1539
+	case 233:
+		//line n1ql.y:1747
 		{
 			yyVAL.expr = yyS[yypt-0].expr
 		}
-	case 206:
-		//line n1ql.y:1553
+	case 234:
+		//line n1ql.y:1761
 		{
 			yyVAL.statement = algebra.NewDropIndex(yyS[yypt-1].keyspaceRef, "#primary", yyS[yypt-0].indexType)
 		}
-	case 207:
-		//line n1ql.y:1558
+	case 235:
+		//line n1ql.y:1766
 		{
 			yyVAL.statement = algebra.NewDropIndex(yyS[yypt-3].keyspaceRef, yyS[yypt-1].s, yyS[yypt-0].indexType)
 		}
-	case 208:
-		//line n1ql.y:1571
+	case 236:
+		//line n1ql.y:1779
 		{
 			yyVAL.statement = algebra.NewAlterIndex(yyS[yypt-4].keyspaceRef, yyS[yypt-2].s, yyS[yypt-1].indexType, yyS[yypt-0].s)
 		}
-	case 209:
-		//line n1ql.y:1577
+	case 237:
+		//line n1ql.y:1785
 		{
 			yyVAL.s = ""
 		}
-	case 210:
-		//line n1ql.y:1582
+	case 238:
+		//line n1ql.y:1790
 		{
 			yyVAL.s = yyS[yypt-0].s
 		}
-	case 211:
-		//line n1ql.y:1595
+	case 239:
+		//line n1ql.y:1803
 		{
 			yyVAL.statement = algebra.NewBuildIndexes(yyS[yypt-4].keyspaceRef, yyS[yypt-0].indexType, yyS[yypt-2].ss...)
 		}
-	case 212:
-		//line n1ql.y:1602
+	case 240:
+		//line n1ql.y:1810
 		{
 			yyVAL.ss = []string{yyS[yypt-0].s}
 		}
-	case 213:
-		//line n1ql.y:1607
+	case 241:
+		//line n1ql.y:1815
 		{
 			yyVAL.ss = append(yyS[yypt-2].ss, yyS[yypt-0].s)
 		}
-	case 214:
-		//line n1ql.y:1621
+	case 242:
+		//line n1ql.y:1829
+		{
+			yyVAL.statement = algebra.NewCreateFunction(yyS[yypt-6].functionRef, yyS[yypt-4].ss, yyS[yypt-1].expr, yyS[yypt-8].b)
+		}
+	case 243:
+		//line n1ql.y:1836
+		{
+			yyVAL.b = false
+		}
+	case 244:
+		//line n1ql.y:1841
+		{
+			if strings.ToLower(yyS[yypt-0].s) != "replace" {
+				yylex.Error(fmt.Sprintf("Invalid CREATE OR %s.", yyS[yypt-0].s))
+			}
+			yyVAL.b = true
+		}
+	case 245:
+		//line n1ql.y:1851
+		{
+			yyVAL.functionRef = algebra.NewFunctionRef("", yyS[yypt-0].s)
+		}
+	case 246:
+		//line n1ql.y:1856
+		{
+			yyVAL.functionRef = algebra.NewFunctionRef(yyS[yypt-2].s, yyS[yypt-0].s)
+		}
+	case 247:
+		//line n1ql.y:1863
+		{
+			yyVAL.ss = nil
+		}
+	case 248:
+		yyVAL.ss = yyS[yypt-0].ss
+	case 249:
+		//line n1ql.y:1872
+		{
+			yyVAL.ss = []string{yyS[yypt-0].s}
+		}
+	case 250:
+		//line n1ql.y:1877
+		{
+			yyVAL.ss = append(yyS[yypt-2].ss, yyS[yypt-0].s)
+		}
+	case 251:
+		//line n1ql.y:1891
+		{
+			yyVAL.statement = algebra.NewDropFunction(yyS[yypt-0].functionRef)
+		}
+	case 252:
+		//line n1ql.y:1905
+		{
+			yyVAL.statement = algebra.NewUpdateStatistics(yyS[yypt-4].keyspaceRef, yyS[yypt-2].exprs, yyS[yypt-0].val)
+		}
+	case 253:
+		//line n1ql.y:1910
+		{
+			yyVAL.statement = algebra.NewDeleteStatistics(yyS[yypt-4].keyspaceRef, yyS[yypt-1].exprs)
+		}
+	case 254:
+		//line n1ql.y:1915
+		{
+			yyVAL.statement = algebra.NewDeleteStatistics(yyS[yypt-2].keyspaceRef, nil)
+		}
+	case 255:
+		//line n1ql.y:1929
 		{
 			yyVAL.path = expression.NewIdentifier(yyS[yypt-0].s)
 		}
-	case 215:
-		//line n1ql.y:1626
+	case 256:
+		//line n1ql.y:1934
 		{
 			yyVAL.path = expression.NewField(yyS[yypt-2].path, expression.NewFieldName(yyS[yypt-0].s))
 		}
-	case 216:
-		//line n1ql.y:1631
+	case 257:
+		//line n1ql.y:1939
 		{
 			field := expression.NewField(yyS[yypt-2].path, expression.NewFieldName(yyS[yypt-0].s))
 			field.SetCaseInsensitive(true)
 			yyVAL.path = field
 		}
-	case 217:
-		//line n1ql.y:1638
+	case 258:
+		//line n1ql.y:1946
 		{
 			yyVAL.path = expression.NewElement(yyS[yypt-3].path, yyS[yypt-1].expr)
 		}
-	case 218:
+	case 259:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 219:
-		//line n1ql.y:1655
+	case 260:
+		//line n1ql.y:1963
 		{
 			yyVAL.expr = expression.NewField(yyS[yypt-2].expr, expression.NewFieldName(yyS[yypt-0].s))
 		}
-	case 220:
-		//line n1ql.y:1660
+	case 261:
+		//line n1ql.y:1968
 		{
 			field := expression.NewField(yyS[yypt-2].expr, expression.NewFieldName(yyS[yypt-0].s))
 			field.SetCaseInsensitive(true)
 			yyVAL.expr = field
 		}
-	case 221:
-		//line n1ql.y:1667
+	case 262:
+		//line n1ql.y:1975
 		{
 			yyVAL.expr = expression.NewField(yyS[yypt-4].expr, yyS[yypt-1].expr)
 		}
-	case 222:
-		//line n1ql.y:1672
+	case 263:
+		//line n1ql.y:1980
 		{
 			field := expression.NewField(yyS[yypt-4].expr, yyS[yypt-1].expr)
 			field.SetCaseInsensitive(true)
 			yyVAL.expr = field
 		}
-	case 223:
-		//line n1ql.y:1679
+	case 264:
+		//line n1ql.y:1987
 		{
 			yyVAL.expr = expression.NewElement(yyS[yypt-3].expr, yyS[yypt-1].expr)
 		}
-	case 224:
-		//line n1ql.y:1684
+	case 265:
+		//line n1ql.y:1992
 		{
 			yyVAL.expr = expression.NewSlice(yyS[yypt-4].expr, yyS[yypt-2].expr)
 		}
-	case 225:
-		//line n1ql.y:1689
+	case 266:
+		//line n1ql.y:1997
 		{
 			yyVAL.expr = expression.NewSlice(yyS[yypt-5].expr, yyS[yypt-3].expr, yyS[yypt-1].expr)
 		}
-	case 226:
-		//line n1ql.y:1695
+	case 267:
+		//line n1ql.y:2003
 		{
 			yyVAL.expr = expression.NewAdd(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 227:
-		//line n1ql.y:1700
+	case 268:
+		//line n1ql.y:2008
 		{
 			yyVAL.expr = expression.NewSub(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 228:
-		//line n1ql.y:1705
+	case 269:
+		//line n1ql.y:2013
 		{
 			yyVAL.expr = expression.NewMult(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 229:
-		//line n1ql.y:1710
+	case 270:
+		//line n1ql.y:2018
 		{
 			yyVAL.expr = expression.NewDiv(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 230:
-		//line n1ql.y:1715
+	case 271:
+		//line n1ql.y:2023
 		{
 			yyVAL.expr = expression.NewMod(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 231:
-		//line n1ql.y:1721
+	case 272:
+		//line n1ql.y:2029
 		{
 			yyVAL.expr = expression.NewConcat(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 232:
-		//line n1ql.y:1727
+	case 273:
+		//line n1ql.y:2035
 		{
 			yyVAL.expr = expression.NewAnd(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 233:
-		//line n1ql.y:1732
+	case 274:
+		//line n1ql.y:2040
 		{
 			yyVAL.expr = expression.NewOr(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 234:
-		//line n1ql.y:1737
+	case 275:
+		//line n1ql.y:2045
 		{
 			yyVAL.expr = expression.NewNot(yyS[yypt-0].expr)
 		}
-	case 235:
-		//line n1ql.y:1743
+	case 276:
+		//line n1ql.y:2051
 		{
 			yyVAL.expr = expression.NewEq(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 236:
-		//line n1ql.y:1748
+	case 277:
+		//line n1ql.y:2056
 		{
 			yyVAL.expr = expression.NewEq(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 237:
-		//line n1ql.y:1753
+	case 278:
+		//line n1ql.y:2061
 		{
 			yyVAL.expr = expression.NewNE(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 238:
-		//line n1ql.y:1758
+	case 279:
+		//line n1ql.y:2066
 		{
 			yyVAL.expr = expression.NewLT(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 239:
-		//line n1ql.y:1763
+	case 280:
+		//line n1ql.y:2071
 		{
 			yyVAL.expr = expression.NewGT(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 240:
-		//line n1ql.y:1768
+	case 281:
+		//line n1ql.y:2076
 		{
 			yyVAL.expr = expression.NewLE(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 241:
-		//line n1ql.y:1773
+	case 282:
+		//line n1ql.y:2081
 		{
 			yyVAL.expr = expression.NewGE(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 242:
-		//line n1ql.y:1778
+	case 283:
+		//line n1ql.y:2086
 		{
 			yyVAL.expr = expression.NewBetween(yyS[yypt-4].expr, yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 243:
-		//line n1ql.y:1783
+	case 284:
+		//line n1ql.y:2091
 		{
 			yyVAL.expr = expression.NewNotBetween(yyS[yypt-5].expr, yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 244:
-		//line n1ql.y:1788
+	case 285:
+		//line n1ql.y:2096
 		{
 			yyVAL.expr = expression.NewLike(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 245:
-		//line n1ql.y:1793
+	case 286:
+		//line n1ql.y:2101
 		{
 			yyVAL.expr = expression.NewNotLike(yyS[yypt-3].expr, yyS[yypt-0].expr)
 		}
-	case 246:
-		//line n1ql.y:1798
+	case 287:
+		//line n1ql.y:2106
 		{
 			yyVAL.expr = expression.NewIn(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 247:
-		//line n1ql.y:1803
+	case 288:
+		//line n1ql.y:2111
 		{
 			yyVAL.expr = expression.NewNotIn(yyS[yypt-3].expr, yyS[yypt-0].expr)
 		}
-	case 248:
-		//line n1ql.y:1808
+	case 289:
+		//line n1ql.y:2116
 		{
 			yyVAL.expr = expression.NewWithin(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 249:
-		//line n1ql.y:1813
+	case 290:
+		//line n1ql.y:2121
 		{
 			yyVAL.expr = expression.NewNotWithin(yyS[yypt-3].expr, yyS[yypt-0].expr)
 		}
-	case 250:
-		//line n1ql.y:1818
+	case 291:
+		//line n1ql.y:2126
 		{
 			yyVAL.expr = expression.NewIsNull(yyS[yypt-2].expr)
 		}
-	case 251:
-		//line n1ql.y:1823
+	case 292:
+		//line n1ql.y:2131
 		{
 			yyVAL.expr = expression.NewIsNotNull(yyS[yypt-3].expr)
 		}
-	case 252:
-		//line n1ql.y:1828
+	case 293:
+		//line n1ql.y:2136
 		{
 			yyVAL.expr = expression.NewIsMissing(yyS[yypt-2].expr)
 		}
-	case 253:
-		//line n1ql.y:1833
+	case 294:
+		//line n1ql.y:2141
 		{
 			yyVAL.expr = expression.NewIsNotMissing(yyS[yypt-3].expr)
 		}
-	case 254:
-		//line n1ql.y:1838
+	case 295:
+		//line n1ql.y:2146
 		{
 			yyVAL.expr = expression.NewIsValued(yyS[yypt-2].expr)
 		}
-	case 255:
-		//line n1ql.y:1843
+	case 296:
+		//line n1ql.y:2151
 		{
 			yyVAL.expr = expression.NewIsNotValued(yyS[yypt-3].expr)
 		}
-	case 256:
-		//line n1ql.y:1848
+	case 297:
+		//line n1ql.y:2156
 		{
 			yyVAL.expr = expression.NewIsBoolean(yyS[yypt-2].expr)
 		}
-	case 257:
-		//line n1ql.y:1853
+	case 298:
+		//line n1ql.y:2161
 		{
 			yyVAL.expr = expression.NewNot(expression.NewIsBoolean(yyS[yypt-3].expr))
 		}
-	case 258:
-		//line n1ql.y:1858
+	case 299:
+		//line n1ql.y:2166
 		{
 			yyVAL.expr = expression.NewIsNumber(yyS[yypt-2].expr)
 		}
-	case 259:
-		//line n1ql.y:1863
+	case 300:
+		//line n1ql.y:2171
 		{
 			yyVAL.expr = expression.NewNot(expression.NewIsNumber(yyS[yypt-3].expr))
 		}
-	case 260:
-		//line n1ql.y:1868
+	case 301:
+		//line n1ql.y:2176
 		{
 			yyVAL.expr = expression.NewIsString(yyS[yypt-2].expr)
 		}
-	case 261:
-		//line n1ql.y:1873
+	case 302:
+		//line n1ql.y:2181
 		{
 			yyVAL.expr = expression.NewNot(expression.NewIsString(yyS[yypt-3].expr))
 		}
-	case 262:
-		//line n1ql.y:1878
+	case 303:
+		//line n1ql.y:2186
 		{
 			yyVAL.expr = expression.NewIsArray(yyS[yypt-2].expr)
 		}
-	case 263:
-		//line n1ql.y:1883
+	case 304:
+		//line n1ql.y:2191
 		{
 			yyVAL.expr = expression.NewNot(expression.NewIsArray(yyS[yypt-3].expr))
 		}
-	case 264:
-		//line n1ql.y:1888
+	case 305:
+		//line n1ql.y:2196
 		{
 			yyVAL.expr = expression.NewIsObject(yyS[yypt-2].expr)
 		}
-	case 265:
-		//line n1ql.y:1893
+	case 306:
+		//line n1ql.y:2201
 		{
 			yyVAL.expr = expression.NewNot(expression.NewIsObject(yyS[yypt-3].expr))
 		}
-	case 266:
-		//line n1ql.y:1898
+	case 307:
+		//line n1ql.y:2206
 		{
 			yyVAL.expr = expression.NewIsBinary(yyS[yypt-2].expr)
 		}
-	case 267:
-		//line n1ql.y:1903
+	case 308:
+		//line n1ql.y:2211
 		{
 			yyVAL.expr = expression.NewNot(expression.NewIsBinary(yyS[yypt-3].expr))
 		}
-	case 268:
-		//line n1ql.y:1908
+	case 309:
+		//line n1ql.y:2216
 		{
 			yyVAL.expr = expression.NewExists(yyS[yypt-0].expr)
 		}
-	case 269:
+	case 310:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 270:
+	case 311:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 271:
-		//line n1ql.y:1922
+	case 312:
+		//line n1ql.y:2230
 		{
 			yyVAL.expr = expression.NewIdentifier(yyS[yypt-0].s)
 		}
-	case 272:
-		//line n1ql.y:1928
+	case 313:
+		//line n1ql.y:2236
 		{
 			yyVAL.expr = expression.NewSelf()
 		}
-	case 273:
+	case 314:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 274:
+	case 315:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 275:
-		//line n1ql.y:1940
+	case 316:
+		//line n1ql.y:2248
 		{
 			yyVAL.expr = expression.NewNeg(yyS[yypt-0].expr)
 		}
-	case 276:
+	case 317:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 277:
+	case 318:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 278:
+	case 319:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 279:
+	case 320:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 280:
-		//line n1ql.y:1959
+	case 321:
+		//line n1ql.y:2267
 		{
 			yyVAL.expr = expression.NewField(yyS[yypt-2].expr, expression.NewFieldName(yyS[yypt-0].s))
 		}
-	case 281:
-		//line n1ql.y:1964
+	case 322:
+		//line n1ql.y:2272
 		{
 			field := expression.NewField(yyS[yypt-2].expr, expression.NewFieldName(yyS[yypt-0].s))
 			field.SetCaseInsensitive(true)
 			yyVAL.expr = field
 		}
-	case 282:
-		//line n1ql.y:1971
+	case 323:
+		//line n1ql.y:2279
 		{
 			yyVAL.expr = expression.NewField(yyS[yypt-4].expr, yyS[yypt-1].expr)
 		}
-	case 283:
-		//line n1ql.y:1976
+	case 324:
+		//line n1ql.y:2284
 		{
 			field := expression.NewField(yyS[yypt-4].expr, yyS[yypt-1].expr)
 			field.SetCaseInsensitive(true)
 			yyVAL.expr = field
 		}
-	case 284:
-		//line n1ql.y:1983
+	case 325:
+		//line n1ql.y:2291
 		{
 			yyVAL.expr = expression.NewElement(yyS[yypt-3].expr, yyS[yypt-1].expr)
 		}
-	case 285:
-		//line n1ql.y:1988
+	case 326:
+		//line n1ql.y:2296
 		{
 			yyVAL.expr = expression.NewSlice(yyS[yypt-4].expr, yyS[yypt-2].expr)
 		}
-	case 286:
-		//line n1ql.y:1993
+	case 327:
+		//line n1ql.y:2301
 		{
 			yyVAL.expr = expression.NewSlice(yyS[yypt-5].expr, yyS[yypt-3].expr, yyS[yypt-1].expr)
 		}
-	case 287:
-		//line n1ql.y:1999
+	case 328:
+		//line n1ql.y:2307
 		{
 			yyVAL.expr = expression.NewAdd(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 288:
-		//line n1ql.y:2004
+	case 329:
+		//line n1ql.y:2312
 		{
 			yyVAL.expr = expression.NewSub(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 289:
-		//line n1ql.y:2009
+	case 330:
+		//line n1ql.y:2317
 		{
 			yyVAL.expr = expression.NewMult(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 290:
-		//line n1ql.y:2014
+	case 331:
+		//line n1ql.y:2322
 		{
 			yyVAL.expr = expression.NewDiv(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 291:
-		//line n1ql.y:2019
+	case 332:
+		//line n1ql.y:2327
 		{
 			yyVAL.expr = expression.NewMod(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 292:
-		//line n1ql.y:2025
+	case 333:
+		//line n1ql.y:2333
 		{
 			yyVAL.expr = expression.NewConcat(yyS[yypt-2].expr, yyS[yypt-0].expr)
 		}
-	case 293:
-		//line n1ql.y:2039
+	case 334:
+		//line n1ql.y:2347
 		{
 			yyVAL.expr = expression.NULL_EXPR
 		}
-	case 294:
-		//line n1ql.y:2044
+	case 335:
+		//line n1ql.y:2352
 		{
 			yyVAL.expr = expression.MISSING_EXPR
 		}
-	case 295:
-		//line n1ql.y:2049
+	case 336:
+		//line n1ql.y:2357
 		{
 			yyVAL.expr = expression.FALSE_EXPR
 		}
-	case 296:
-		//line n1ql.y:2054
+	case 337:
+		//line n1ql.y:2362
 		{
 			yyVAL.expr = expression.TRUE_EXPR
 		}
-	case 297:
-		//line n1ql.y:2059
+	case 338:
+		//line n1ql.y:2367
 		{
 			yyVAL.expr = expression.NewConstant(value.NewValue(yyS[yypt-0].f))
 		}
-	case 298:
-		//line n1ql.y:2064
+	case 339:
+		//line n1ql.y:2372
 		{
 			yyVAL.expr = expression.NewConstant(value.NewValue(yyS[yypt-0].n))
 		}
-	case 299:
-		//line n1ql.y:2069
+	case 340:
+		//line n1ql.y:2377
 		{
 			yyVAL.expr = expression.NewConstant(value.NewValue(yyS[yypt-0].s))
 		}
-	case 300:
+	case 341:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 301:
+	case 342:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 302:
-		//line n1ql.y:2089
+	case 343:
+		//line n1ql.y:2397
 		{
 			yyVAL.expr = expression.NewObjectConstruct(yyS[yypt-1].bindings)
 		}
-	case 303:
-		//line n1ql.y:2096
+	case 344:
+		//line n1ql.y:2404
 		{
 			yyVAL.bindings = nil
 		}
-	case 304:
+	case 345:
 		yyVAL.bindings = yyS[yypt-0].bindings
-	case 305:
-		//line n1ql.y:2105
+	case 346:
+		//line n1ql.y:2413
 		{
 			yyVAL.bindings = expression.Bindings{yyS[yypt-0].binding}
 		}
-	case 306:
-		//line n1ql.y:2110
+	case 347:
+		//line n1ql.y:2418
 		{
 			yyVAL.bindings = append(yyS[yypt-2].bindings, yyS[yypt-0].binding)
 		}
-	case 307:
-		//line n1ql.y:2117
+	case 348:
+		//line n1ql.y:2425
 		{
 			yyVAL.binding = expression.NewBinding(yyS[yypt-2].s, yyS[yypt-0].expr)
 		}
-	case 308:
-		//line n1ql.y:2124
+	case 349:
+		//line n1ql.y:2432
 		{
 			yyVAL.expr = expression.NewArrayConstruct(yyS[yypt-1].exprs...)
 		}
-	case 309:
-		//line n1ql.y:2131
+	case 350:
+		//line n1ql.y:2439
 		{
 			yyVAL.exprs = nil
 		}
-	case 310:
+	case 351:
 		yyVAL.exprs = yyS[yypt-0].exprs
-	case 311:
-		//line n1ql.y:2147
+	case 352:
+		//line n1ql.y:2455
 		{
 			yyVAL.expr = algebra.NewNamedParameter(yyS[yypt-0].s)
 		}
-	case 312:
-		//line n1ql.y:2152
+	case 353:
+		//line n1ql.y:2460
 		{
 			yyVAL.expr = algebra.NewPositionalParameter(yyS[yypt-0].n)
 		}
-	case 313:
-		//line n1ql.y:2157
+	case 354:
+		//line n1ql.y:2465
 		{
 			n := yylex.(*lexer).nextParam()
 			yyVAL.expr = algebra.NewPositionalParameter(n)
 		}
-	case 314:
-		//line n1ql.y:2172
+	case 355:
+		//line n1ql.y:2480
 		{
 			yyVAL.expr = yyS[yypt-1].expr
 		}
-	case 315:
+	case 356:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 316:
+	case 357:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 317:
-		//line n1ql.y:2185
+	case 358:
+		//line n1ql.y:2493
 		{
 			yyVAL.expr = expression.NewSimpleCase(yyS[yypt-2].expr, yyS[yypt-1].whenTerms, yyS[yypt-0].expr)
 		}
-	case 318:
-		//line n1ql.y:2192
+	case 359:
+		//line n1ql.y:2500
 		{
 			yyVAL.whenTerms = expression.WhenTerms{&expression.WhenTerm{yyS[yypt-2].expr, yyS[yypt-0].expr}}
 		}
-	case 319:
-		//line n1ql.y:2197
+	case 360:
+		//line n1ql.y:2505
 		{
 			yyVAL.whenTerms = append(yyS[yypt-4].whenTerms, &expression.WhenTerm{yyS[yypt-2].expr, yyS[yypt-0].expr})
 		}
-	case 320:
-		//line n1ql.y:2205
+	case 361:
+		//line n1ql.y:2513
 		{
 			yyVAL.expr = expression.NewSearchedCase(yyS[yypt-1].whenTerms, yyS[yypt-0].expr)
 		}
-	case 321:
-		//line n1ql.y:2212
+	case 362:
+		//line n1ql.y:2520
 		{
 			yyVAL.expr = nil
 		}
-	case 322:
-		//line n1ql.y:2217
+	case 363:
+		//line n1ql.y:2525
 		{
 			yyVAL.expr = yyS[yypt-0].expr
 		}
-	case 323:
-		//line n1ql.y:2231
+	case 364:
+		//line n1ql.y:2539
 		{
 			yyVAL.expr = nil
 			f, ok := expression.GetFunction(yyS[yypt-3].s)
@@ -2848,8 +3183,8 @@ yydefault:
 				yylex.Error(fmt.Sprintf("Invalid function %s.", yyS[yypt-3].s))
 			}
 		}
-	case 324:
-		//line n1ql.y:2250
+	case 365:
+		//line n1ql.y:2558
 		{
 			yyVAL.expr = nil
 			if !yylex.(*lexer).parsingStatement() {
@@ -2863,8 +3198,8 @@ yydefault:
 				}
 			}
 		}
-	case 325:
-		//line n1ql.y:2265
+	case 366:
+		//line n1ql.y:2573
 		{
 			yyVAL.expr = nil
 			if !yylex.(*lexer).parsingStatement() {
@@ -2882,71 +3217,213 @@ yydefault:
 				}
 			}
 		}
-	case 326:
+	case 367:
+		//line n1ql.y:2592
+		{
+			yyVAL.expr = nil
+			if !yylex.(*lexer).parsingStatement() {
+				yylex.Error("Cannot use window function as an inline expression.")
+			} else {
+				f, ok := algebra.GetWindowFunction(yyS[yypt-7].s, false)
+				if ok {
+					if len(yyS[yypt-5].exprs) < f.MinArgs() || len(yyS[yypt-5].exprs) > f.MaxArgs() {
+						yylex.Error(fmt.Sprintf("Wrong number of arguments to function %s.", yyS[yypt-7].s))
+					} else {
+						w := f.Constructor()(yyS[yypt-5].exprs...).(algebra.WindowFunction)
+						w.SetWindow(yyS[yypt-1].windowTerm)
+						yyVAL.expr = w
+					}
+				} else {
+					yylex.Error(fmt.Sprintf("Invalid window function %s.", yyS[yypt-7].s))
+				}
+			}
+		}
+	case 368:
+		//line n1ql.y:2613
+		{
+			yyVAL.expr = nil
+			if !yylex.(*lexer).parsingStatement() {
+				yylex.Error("Cannot use window function as an inline expression.")
+			} else {
+				f, ok := algebra.GetWindowFunction(yyS[yypt-8].s, true)
+				if ok {
+					w := f.Constructor()(yyS[yypt-5].expr).(algebra.WindowFunction)
+					w.SetWindow(yyS[yypt-1].windowTerm)
+					yyVAL.expr = w
+				} else {
+					yylex.Error(fmt.Sprintf("Invalid window function %s.", yyS[yypt-8].s))
+				}
+			}
+		}
+	case 369:
+		//line n1ql.y:2630
+		{
+			yyVAL.expr = nil
+			if !yylex.(*lexer).parsingStatement() {
+				yylex.Error("Cannot use window function as an inline expression.")
+			} else {
+				if strings.ToLower(yyS[yypt-7].s) != "count" {
+					yylex.Error(fmt.Sprintf("Invalid window function %s(*).", yyS[yypt-7].s))
+				} else {
+					f, ok := algebra.GetWindowFunction(yyS[yypt-7].s, false)
+					if ok {
+						w := f.Constructor()(nil).(algebra.WindowFunction)
+						w.SetWindow(yyS[yypt-1].windowTerm)
+						yyVAL.expr = w
+					} else {
+						yylex.Error(fmt.Sprintf("Invalid window function %s.", yyS[yypt-7].s))
+					}
+				}
+			}
+		}
+	case 370:
 		yyVAL.s = yyS[yypt-0].s
-	case 327:
+	case 371:
+		//line n1ql.y:2657
+		{
+			yyVAL.windowTerm = algebra.NewWindowTerm(yyS[yypt-2].exprs, yyS[yypt-1].sortTerms, yyS[yypt-0].windowFrame)
+		}
+	case 372:
+		//line n1ql.y:2664
+		{
+			yyVAL.exprs = nil
+		}
+	case 373:
+		//line n1ql.y:2669
+		{
+			yyVAL.exprs = yyS[yypt-0].exprs
+		}
+	case 374:
+		//line n1ql.y:2676
+		{
+			yyVAL.sortTerms = nil
+		}
+	case 375:
+		//line n1ql.y:2681
+		{
+			yyVAL.sortTerms = yyS[yypt-0].sortTerms
+		}
+	case 376:
+		//line n1ql.y:2688
+		{
+			yyVAL.windowFrame = nil
+		}
+	case 377:
+		//line n1ql.y:2693
+		{
+			yyVAL.windowFrame = yyS[yypt-0].windowFrame
+			if err := yyVAL.windowFrame.Validate(); err != nil {
+				yylex.Error(err.Error())
+			}
+		}
+	case 378:
+		//line n1ql.y:2703
+		{
+			yyVAL.windowFrame = algebra.NewWindowFrame(yyS[yypt-1].b, yyS[yypt-0].windowFrameBound, algebra.NewWindowFrameBound(algebra.CURRENT_ROW, nil))
+		}
+	case 379:
+		//line n1ql.y:2708
+		{
+			yyVAL.windowFrame = algebra.NewWindowFrame(yyS[yypt-4].b, yyS[yypt-2].windowFrameBound, yyS[yypt-0].windowFrameBound)
+		}
+	case 380:
+		//line n1ql.y:2715
+		{
+			yyVAL.b = true
+		}
+	case 381:
+		//line n1ql.y:2720
+		{
+			yyVAL.b = false
+		}
+	case 382:
+		//line n1ql.y:2727
+		{
+			yyVAL.windowFrameBound = algebra.NewWindowFrameBound(algebra.UNBOUNDED_PRECEDING, nil)
+		}
+	case 383:
+		//line n1ql.y:2732
+		{
+			yyVAL.windowFrameBound = algebra.NewWindowFrameBound(algebra.UNBOUNDED_FOLLOWING, nil)
+		}
+	case 384:
+		//line n1ql.y:2737
+		{
+			yyVAL.windowFrameBound = algebra.NewWindowFrameBound(algebra.CURRENT_ROW, nil)
+		}
+	case 385:
+		//line n1ql.y:2742
+		{
+			yyVAL.windowFrameBound = algebra.NewWindowFrameBound(algebra.PRECEDING, yyS[yypt-1].expr)
+		}
+	case 386:
+		//line n1ql.y:2747
+		{
+			yyVAL.windowFrameBound = algebra.NewWindowFrameBound(algebra.FOLLOWING, yyS[yypt-1].expr)
+		}
+	case 387:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 328:
+	case 388:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 329:
-		//line n1ql.y:2303
+	case 389:
+		//line n1ql.y:2767
 		{
 			yyVAL.expr = expression.NewAny(yyS[yypt-2].bindings, yyS[yypt-1].expr)
 		}
-	case 330:
-		//line n1ql.y:2308
+	case 390:
+		//line n1ql.y:2772
 		{
 			yyVAL.expr = expression.NewAny(yyS[yypt-2].bindings, yyS[yypt-1].expr)
 		}
-	case 331:
-		//line n1ql.y:2313
+	case 391:
+		//line n1ql.y:2777
 		{
 			yyVAL.expr = expression.NewEvery(yyS[yypt-2].bindings, yyS[yypt-1].expr)
 		}
-	case 332:
-		//line n1ql.y:2320
+	case 392:
+		//line n1ql.y:2784
 		{
 			yyVAL.bindings = expression.Bindings{yyS[yypt-0].binding}
 		}
-	case 333:
-		//line n1ql.y:2325
+	case 393:
+		//line n1ql.y:2789
 		{
 			yyVAL.bindings = append(yyS[yypt-2].bindings, yyS[yypt-0].binding)
 		}
-	case 334:
-		//line n1ql.y:2332
+	case 394:
+		//line n1ql.y:2796
 		{
 			yyVAL.binding = expression.NewBinding(yyS[yypt-2].s, yyS[yypt-0].expr)
 		}
-	case 335:
-		//line n1ql.y:2337
+	case 395:
+		//line n1ql.y:2801
 		{
 			yyVAL.binding = expression.NewDescendantBinding(yyS[yypt-2].s, yyS[yypt-0].expr)
 		}
-	case 336:
-		//line n1ql.y:2344
+	case 396:
+		//line n1ql.y:2808
 		{
 			yyVAL.expr = yyS[yypt-0].expr
 		}
-	case 337:
-		//line n1ql.y:2351
+	case 397:
+		//line n1ql.y:2815
 		{
 			yyVAL.expr = expression.NewArray(yyS[yypt-4].expr, yyS[yypt-2].bindings, yyS[yypt-1].expr)
 		}
-	case 338:
-		//line n1ql.y:2356
+	case 398:
+		//line n1ql.y:2820
 		{
 			yyVAL.expr = expression.NewFirst(yyS[yypt-4].expr, yyS[yypt-2].bindings, yyS[yypt-1].expr)
 		}
-	case 339:
-		//line n1ql.y:2370
+	case 399:
+		//line n1ql.y:2834
 		{
 			yyVAL.expr = yyS[yypt-1].expr
 		}
-	case 340:
+	case 400:
 		yyVAL.expr = yyS[yypt-0].expr
-	case 341:
-		//line n1ql.y:2379
+	case 401:
+		//line n1ql.y:2843
 		{
 			yyVAL.expr = nil
 			if yylex.(*lexer).parsingStatement() {
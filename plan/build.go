@@ -53,6 +53,10 @@ type builder struct {
 	delayProjection bool                  // Used to allow ORDER BY non-projected expressions
 	where           expression.Expression // Used for index selection
 	order           *algebra.Order        // Used to collect aggregates from ORDER BY
+	cover           *algebra.Subselect    // Used to build covering index scans
+	pushdown        *algebra.Select       // Used to push ORDER BY, LIMIT and OFFSET into scans
+	ordered         bool                  // Whether the scan produces results in ORDER BY order
+	grouped         bool                  // Whether the scan produces results grouped by GROUP BY keys
 	distinct        bool
 	children        []Operator
 	subChildren     []Operator
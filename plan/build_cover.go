@@ -0,0 +1,193 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package plan
+
+import (
+	"github.com/couchbaselabs/query/algebra"
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/expression"
+)
+
+// Rebuild an index scan to cover the statement, if every expression
+// of the statement can be evaluated from the index keys and the
+// primary key, so that no documents need be fetched. Returns nil if
+// the scan does not cover the statement.
+func (this *builder) buildCoveringScan(scan Operator, node *algebra.KeyspaceTerm) (Operator, error) {
+	var scans []Operator
+	union, ok := scan.(*UnionScan)
+	if ok {
+		scans = union.Scans()
+	} else {
+		scans = []Operator{scan}
+	}
+
+	if !singleIndex(scans) {
+		// Only the scans of a single index can cover
+		return nil, nil
+	}
+
+	covers, err := this.coveringKeys(scans[0].(*IndexScan).Index(), node)
+	if err != nil || covers == nil {
+		return nil, err
+	}
+
+	coverer := newCoverer(covers)
+	err = this.cover.MapExpressions(coverer)
+	if err != nil {
+		return nil, err
+	}
+
+	if this.order != nil {
+		err = this.order.MapExpressions(coverer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, s := range scans {
+		s := s.(*IndexScan)
+		scans[i] = NewIndexScan(s.Index(), s.Term(), s.Spans(), s.Distinct(), s.Reverse(), s.Limit(), covers)
+	}
+
+	if union != nil {
+		return NewUnionScan(scans...), nil
+	}
+
+	return scans[0], nil
+}
+
+// The covers of the statement by an index, or nil if the index does
+// not cover the statement.
+func (this *builder) coveringKeys(index datastore.Index, node *algebra.KeyspaceTerm) (
+	expression.Expressions, error) {
+	if this.cover == nil {
+		return nil, nil
+	}
+
+	covers, err := coverKeys(index, node)
+	if err != nil || covers == nil {
+		return nil, err
+	}
+
+	for _, term := range this.cover.Projection().Terms() {
+		if term.Star() {
+			return nil, nil
+		}
+	}
+
+	exprs := this.cover.Expressions()
+	if this.order != nil {
+		exprs = append(exprs, this.order.Expressions()...)
+	}
+
+	for _, expr := range exprs {
+		if !coveredBy(expr, node.Alias(), covers) {
+			return nil, nil
+		}
+	}
+
+	return covers, nil
+}
+
+// Whether the scans are all index scans of the same index.
+func singleIndex(scans []Operator) bool {
+	for _, s := range scans {
+		indexScan, ok := s.(*IndexScan)
+		if !ok || indexScan.Index() != scans[0].(*IndexScan).Index() {
+			return false
+		}
+	}
+
+	return len(scans) > 0
+}
+
+// The index keys followed by the primary key, formalized for the
+// keyspace alias. Returns nil if the keys are not all indexable, or
+// if the index is an array index, whose entries hold array elements.
+func coverKeys(index datastore.Index, node *algebra.KeyspaceTerm) (expression.Expressions, error) {
+	formalizer := expression.NewFormalizer()
+	formalizer.Keyspace = node.Alias()
+
+	rangeKey := index.RangeKey()
+	if arrayIndex(rangeKey) {
+		return nil, nil
+	}
+
+	covers := make(expression.Expressions, 0, len(rangeKey)+1)
+	for _, key := range rangeKey {
+		if key == nil {
+			return nil, nil
+		}
+
+		cover, err := formalizer.Map(key.Copy())
+		if err != nil {
+			return nil, err
+		}
+
+		covers = append(covers, cover)
+	}
+
+	id := expression.NewField(
+		expression.NewMeta(expression.NewIdentifier(node.Alias())),
+		expression.NewFieldName("id"))
+	return append(covers, id), nil
+}
+
+// Whether an expression can be evaluated from the covers alone,
+// without any other reference to the keyspace.
+func coveredBy(expr expression.Expression, alias string, covers expression.Expressions) bool {
+	for _, cover := range covers {
+		if expr.EquivalentTo(cover) {
+			return true
+		}
+	}
+
+	switch expr := expr.(type) {
+	case *expression.Identifier:
+		return expr.Identifier() != alias
+	case *expression.Self, expression.Subquery:
+		return false
+	}
+
+	for _, child := range expr.Children() {
+		if child != nil && !coveredBy(child, alias, covers) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Replace each covered expression with its cover.
+type coverer struct {
+	expression.MapperBase
+	covers expression.Expressions
+}
+
+func newCoverer(covers expression.Expressions) *coverer {
+	rv := &coverer{
+		covers: covers,
+	}
+
+	rv.SetMapper(rv)
+	return rv
+}
+
+func (this *coverer) Map(expr expression.Expression) (expression.Expression, error) {
+	for _, cover := range this.covers {
+		if expr.EquivalentTo(cover) {
+			return expression.NewCover(cover.Copy()), nil
+		}
+	}
+
+	return expr, expr.MapChildren(this)
+}
+
+func (this *coverer) MapBindings() bool { return true }
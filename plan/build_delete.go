@@ -10,14 +10,27 @@
 package plan
 
 import (
+	"strings"
+
 	"github.com/couchbaselabs/query/algebra"
+	"github.com/couchbaselabs/query/datastore"
 )
 
 func (this *builder) VisitDelete(stmt *algebra.Delete) (interface{}, error) {
 	this.where = stmt.Where()
 
 	ksref := stmt.KeyspaceRef()
-	keyspace, err := this.getNameKeyspace(ksref.Namespace(), ksref.Keyspace())
+
+	var keyspace datastore.Keyspace
+	var err error
+	if strings.ToLower(ksref.Namespace()) == "#system" {
+		// Deleting from system keyspaces, e.g. active_requests,
+		// removes or cancels the corresponding objects
+		keyspace, err = this.getSystemKeyspace(ksref.Keyspace())
+	} else {
+		keyspace, err = this.getNameKeyspace(ksref.Namespace(), ksref.Keyspace())
+	}
+
 	if err != nil {
 		return nil, err
 	}
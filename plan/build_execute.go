@@ -9,10 +9,25 @@
 
 package plan
 
-import "github.com/couchbaselabs/query/algebra"
+import (
+	"fmt"
+
+	"github.com/couchbaselabs/query/algebra"
+)
 
 func (this *builder) VisitExecute(stmt *algebra.Execute) (interface{}, error) {
 
+	// stmt names a prepared statement registered by PREPARE name FROM
+	name := stmt.Name()
+	if name != "" {
+		prepared := PreparedCache().GetNamed(name)
+		if prepared == nil {
+			return nil, fmt.Errorf("No such prepared statement: %s.", name)
+		}
+
+		return prepared, nil
+	}
+
 	// stmt contains a JSON representation of a plan.Prepared
 	prepared_object := stmt.Prepared()
 
@@ -19,5 +19,5 @@ func (this *builder) VisitExplain(stmt *algebra.Explain) (interface{}, error) {
 		return nil, err
 	}
 
-	return NewExplain(op.(Operator)), nil
+	return NewExplain(op.(Operator), stmt.Analyze(), stmt.Format()), nil
 }
@@ -0,0 +1,51 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package plan
+
+import (
+	"fmt"
+
+	"github.com/couchbaselabs/query/algebra"
+	"github.com/couchbaselabs/query/datastore"
+)
+
+func (this *builder) VisitCreateFunction(stmt *algebra.CreateFunction) (interface{}, error) {
+	namespace, err := this.getFunctionNamespace(stmt.Function())
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCreateFunction(namespace, stmt), nil
+}
+
+func (this *builder) VisitDropFunction(stmt *algebra.DropFunction) (interface{}, error) {
+	namespace, err := this.getFunctionNamespace(stmt.Function())
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDropFunction(namespace, stmt), nil
+}
+
+func (this *builder) getFunctionNamespace(function *algebra.FunctionRef) (datastore.FunctionNamespace, error) {
+	function.SetDefaultNamespace(this.namespace)
+
+	namespace, err := this.datastore.NamespaceByName(function.Namespace())
+	if err != nil {
+		return nil, err
+	}
+
+	fnamespace, ok := namespace.(datastore.FunctionNamespace)
+	if !ok {
+		return nil, fmt.Errorf("Namespace %s does not support functions.", namespace.Name())
+	}
+
+	return fnamespace, nil
+}
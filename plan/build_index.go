@@ -116,3 +116,12 @@ func (this *builder) getNameKeyspace(ns, ks string) (datastore.Keyspace, error)
 
 	return keyspace, nil
 }
+
+func (this *builder) getSystemKeyspace(ks string) (datastore.Keyspace, error) {
+	namespace, err := this.systemstore.NamespaceByName("#system")
+	if err != nil {
+		return nil, err
+	}
+
+	return namespace.KeyspaceByName(ks)
+}
@@ -0,0 +1,216 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package plan
+
+import (
+	"fmt"
+
+	"github.com/couchbaselabs/query/algebra"
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/planner"
+)
+
+// ANSI JOIN and NEST
+
+// Select the scan used to find right-hand documents for each
+// left-hand item of an ANSI join or nest. If an online index on the
+// right-hand keyspace is sargable for the ON predicate, return it
+// with its spans (index nested-loop join). Otherwise return an
+// online primary index and nil spans (nested-loop scan).
+func (this *builder) selectJoinScan(keyspace datastore.Keyspace,
+	node *algebra.KeyspaceTerm, onclause expression.Expression) (
+	datastore.Index, planner.Spans, error) {
+	nnf := planner.NewNNF()
+	onclause, err := nnf.Map(onclause.Copy())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	alias := node.Alias()
+	formalizer := expression.NewFormalizer()
+	formalizer.Keyspace = alias
+	primaryKey := expression.NewField(
+		expression.NewMeta(expression.NewConstant(alias)),
+		expression.NewFieldName("id"))
+
+	indexers, err := keyspace.Indexers()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var primary datastore.PrimaryIndex
+	var rangeIndex datastore.Index
+	var rangeSpans planner.Spans
+
+	for _, indexer := range indexers {
+		primaryIdxs, er := indexer.PrimaryIndexes()
+		if er != nil {
+			return nil, nil, er
+		}
+
+		primaryIndexes := make(map[datastore.Index]bool, len(primaryIdxs))
+		for _, p := range primaryIdxs {
+			primaryIndexes[p] = true
+		}
+
+		indexes, er := indexer.Indexes()
+		if er != nil {
+			return nil, nil, er
+		}
+
+		for _, index := range indexes {
+			state, _, er := index.State()
+			if er != nil {
+				return nil, nil, er
+			}
+
+			if state != datastore.ONLINE {
+				continue
+			}
+
+			var key expression.Expression
+
+			if primaryIndexes[index] {
+				if primary == nil {
+					primary = index.(datastore.PrimaryIndex)
+				}
+
+				key = primaryKey
+			} else {
+				rangeKey := index.RangeKey()
+				if len(rangeKey) == 0 || rangeKey[0] == nil {
+					// Index not rangeable
+					continue
+				}
+
+				key, err = formalizer.Map(rangeKey[0].Copy())
+				if err != nil {
+					return nil, nil, err
+				}
+
+				key, err = nnf.Map(key)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				indexCond := index.Condition()
+				if indexCond != nil {
+					indexCond, err = formalizer.Map(indexCond.Copy())
+					if err != nil {
+						return nil, nil, err
+					}
+
+					indexCond, err = nnf.Map(indexCond)
+					if err != nil {
+						return nil, nil, err
+					}
+
+					if !planner.SubsetOf(onclause, indexCond) {
+						// Index condition does not cover ON predicate
+						continue
+					}
+				}
+			}
+
+			spans := planner.SargForJoin(onclause, key, alias)
+			if spans == nil {
+				continue
+			}
+
+			// Prefer equality spans
+			rg := spans[0].Range
+			if len(rg.Low) > 0 && len(rg.High) > 0 && rg.Low[0] == rg.High[0] {
+				return index, spans, nil
+			}
+
+			if rangeIndex == nil {
+				rangeIndex = index
+				rangeSpans = spans
+			}
+		}
+	}
+
+	if rangeIndex != nil {
+		return rangeIndex, rangeSpans, nil
+	}
+
+	if primary != nil {
+		return primary, nil, nil
+	}
+
+	return nil, nil, fmt.Errorf(
+		"No index available for ANSI join term %s. Use CREATE PRIMARY INDEX or CREATE INDEX.",
+		alias)
+}
+
+// Build a hash join for node, if its ON predicate has equality
+// conjuncts between the two sides. By default the hash table is
+// built from the right-hand keyspace; USE HASH(PROBE) builds it from
+// the left-hand side instead. Outer joins always build from the
+// right-hand keyspace, so that every left-hand item is probed.
+func (this *builder) buildHashJoin(keyspace datastore.Keyspace,
+	node *algebra.Join) (bool, error) {
+	right := node.Right()
+	alias := right.Alias()
+	hint := right.JoinHint()
+	left := fromAliases(node.Left())
+
+	rightExprs, leftExprs := planner.EquiJoinKeys(node.Onclause(), alias, left)
+	if len(rightExprs) == 0 {
+		if hint != algebra.JOIN_HINT_NONE {
+			return false, fmt.Errorf(
+				"USE HASH on %s requires an equality ON predicate between the two sides of the join.",
+				alias)
+		}
+
+		return false, nil
+	}
+
+	scan, err := this.selectPrimaryScan(keyspace, right)
+	if err != nil {
+		return false, err
+	}
+
+	fetch := NewFetch(keyspace, right)
+
+	// The hash join consumes a single stream
+	if len(this.subChildren) > 0 {
+		this.children = append(this.children, NewParallel(NewSequence(this.subChildren...)))
+	}
+
+	if hint == algebra.USE_HASH_PROBE && !node.Outer() {
+		build := NewSequence(this.children...)
+		this.children = make([]Operator, 0, 16)
+		this.children = append(this.children, scan, NewParallel(fetch),
+			NewHashJoin(keyspace, node, build, leftExprs, rightExprs, left))
+	} else {
+		build := NewSequence(scan, NewParallel(fetch))
+		this.children = append(this.children,
+			NewHashJoin(keyspace, node, build, rightExprs, leftExprs, []string{alias}))
+	}
+
+	this.subChildren = make([]Operator, 0, 16)
+	return true, nil
+}
+
+// Aliases bound by a FROM term.
+func fromAliases(term algebra.FromTerm) []string {
+	switch term := term.(type) {
+	case *algebra.Join:
+		return append(fromAliases(term.Left()), term.Alias())
+	case *algebra.Nest:
+		return append(fromAliases(term.Left()), term.Alias())
+	case *algebra.Unnest:
+		return append(fromAliases(term.Left()), term.Alias())
+	default:
+		return []string{term.Alias()}
+	}
+}
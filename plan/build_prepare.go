@@ -20,6 +20,16 @@ func (this *builder) VisitPrepare(stmt *algebra.Prepare) (interface{}, error) {
 		return nil, err
 	}
 
+	plan.SetText(stmt.Text())
+	if stmt.Name() != "" {
+		plan.SetName(stmt.Name())
+
+		err = PreparedCache().AddNamed(plan)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	PreparedCache().AddPrepared(plan)
 
 	json_bytes, err := plan.MarshalJSON()
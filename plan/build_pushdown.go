@@ -0,0 +1,358 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package plan
+
+import (
+	"math"
+
+	"github.com/couchbaselabs/query/algebra"
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/planner"
+	"github.com/couchbaselabs/query/value"
+)
+
+// Push the ORDER BY, LIMIT and OFFSET of the statement into a scan.
+// The ORDER BY is pushed if the scan returns entries in index key
+// order and the order terms follow the index keys; the LIMIT and
+// OFFSET are pushed if, in addition, no later operator can remove
+// rows. MIN or MAX of an index key is answered from the first or last
+// entry of the scan.
+func (this *builder) pushDown(scan Operator, node *algebra.KeyspaceTerm) (Operator, error) {
+	sel := this.pushdown
+	if sel == nil || this.cover == nil || sel.Subresult() != this.cover {
+		return scan, nil
+	}
+
+	if primary, ok := scan.(*PrimaryScan); ok {
+		if this.where != nil || sel.Order() != nil || !this.preservesRows() {
+			return scan, nil
+		}
+
+		return NewPrimaryScan(primary.Index(), primary.Term(), pushedLimit(sel)), nil
+	}
+
+	indexScan, ok := scan.(*IndexScan)
+	if !ok || indexScan.Distinct() || len(indexScan.Spans()) != 1 {
+		return scan, nil
+	}
+
+	nnf := planner.NewNNF()
+	index := indexScan.Index()
+	keys, err := scanKeys(index, node, nnf)
+	if err != nil {
+		return nil, err
+	}
+
+	where, err := nnf.Map(this.where.Copy())
+	if err != nil {
+		return nil, err
+	}
+
+	span := indexScan.Spans()[0]
+	exact := planner.ExactFor(where, keys, span)
+	reverse := false
+	limit := int64(math.MaxInt64)
+
+	if sel.Order() != nil {
+		if !this.preservesRows() {
+			return scan, nil
+		}
+
+		reverse, ok = orderedBy(sel.Order(), keys, span, nnf)
+		if !ok || (reverse && !reversible(index)) {
+			return scan, nil
+		}
+
+		this.ordered = true
+		if exact {
+			limit = pushedLimit(sel)
+		}
+	} else if exact && this.preservesRows() {
+		limit = pushedLimit(sel)
+	} else if exact {
+		reverse, ok = minMax(this.cover, keys, span, nnf)
+		if !ok || (reverse && !reversible(index)) {
+			return scan, nil
+		}
+
+		limit = 1
+	}
+
+	return NewIndexScan(index, indexScan.Term(), indexScan.Spans(),
+		false, reverse, limit, indexScan.Covers()), nil
+}
+
+// Whether the scan returns the rows of each group of the GROUP BY
+// together, so that the groups can be aggregated as the rows stream.
+func (this *builder) groupedScan(scan Operator, node *algebra.KeyspaceTerm) (bool, error) {
+	if this.cover == nil || this.cover.Group() == nil || len(this.cover.Group().By()) == 0 {
+		return false, nil
+	}
+
+	indexScan, ok := scan.(*IndexScan)
+	if !ok || indexScan.Distinct() || len(indexScan.Spans()) != 1 {
+		return false, nil
+	}
+
+	nnf := planner.NewNNF()
+	keys, err := scanKeys(indexScan.Index(), node, nnf)
+	if err != nil {
+		return false, err
+	}
+
+	return groupedBy(this.cover.Group().By(), keys, indexScan.Spans()[0], nnf), nil
+}
+
+// The keys of an index, in the order of its scans.
+func scanKeys(index datastore.Index, node *algebra.KeyspaceTerm,
+	nnf *planner.NNF) (expression.Expressions, error) {
+	if _, ok := index.(datastore.PrimaryIndex); ok {
+		return expression.Expressions{expression.NewField(
+			expression.NewMeta(expression.NewConstant(node.Alias())),
+			expression.NewFieldName("id"))}, nil
+	}
+
+	formalizer := expression.NewFormalizer()
+	formalizer.Keyspace = node.Alias()
+	return rangeKeys(index, formalizer, nnf)
+}
+
+// Whether every row produced by the scan is a row of the result, so
+// that the scan can stop once it has produced the LIMIT and OFFSET.
+func (this *builder) preservesRows() bool {
+	if this.cover.Group() != nil || this.cover.Projection().Distinct() || this.distinct {
+		return false
+	}
+
+	aggs := make(map[string]algebra.Aggregate)
+	windows := make(map[string]algebra.WindowFunction)
+	for _, term := range this.cover.Projection().Terms() {
+		if term.Expression() != nil {
+			collectAggregates(aggs, term.Expression())
+			collectWindows(windows, term.Expression())
+		}
+	}
+
+	if this.order != nil {
+		for _, term := range this.order.Terms() {
+			collectAggregates(aggs, term.Expression())
+			collectWindows(windows, term.Expression())
+		}
+	}
+
+	return len(aggs) == 0 && len(windows) == 0
+}
+
+// The number of rows needed to produce the LIMIT after the OFFSET, if
+// both are known at planning time.
+func pushedLimit(sel *algebra.Select) int64 {
+	limit, ok := staticCount(sel.Limit())
+	if !ok || limit <= 0 {
+		return math.MaxInt64
+	}
+
+	if sel.Offset() != nil {
+		offset, ok := staticCount(sel.Offset())
+		if !ok {
+			return math.MaxInt64
+		}
+
+		if offset > 0 {
+			limit += offset
+		}
+	}
+
+	return limit
+}
+
+func staticCount(expr expression.Expression) (int64, bool) {
+	if expr == nil {
+		return 0, false
+	}
+
+	v := expr.Value()
+	if v == nil || v.Type() != value.NUMBER {
+		return 0, false
+	}
+
+	n, ok := v.Actual().(float64)
+	if !ok || n != math.Trunc(n) || n > math.MaxInt64/2 {
+		return 0, false
+	}
+
+	return int64(n), true
+}
+
+// Whether the order terms follow the index keys, skipping keys that
+// have a single value in the span. Returns whether the order is
+// descending, which requires a reverse scan.
+func orderedBy(order *algebra.Order, keys expression.Expressions,
+	span *planner.Span, nnf *planner.NNF) (bool, bool) {
+	terms := order.Terms()
+	eq := equalityKeys(span)
+	descending := terms[0].Descending()
+	j := 0
+
+	for _, term := range terms {
+		if term.Descending() != descending {
+			return false, false
+		}
+
+		expr, err := nnf.Map(term.Expression().Copy())
+		if err != nil {
+			return false, false
+		}
+
+		if keyIndex(expr, keys[0:eq]) >= 0 {
+			// A single value does not affect the order
+			continue
+		}
+
+		if j < eq {
+			j = eq
+		}
+
+		if j >= len(keys) || !expr.EquivalentTo(keys[j]) {
+			return false, false
+		}
+
+		j++
+	}
+
+	return descending, true
+}
+
+// Whether the group keys are the leading index keys, in any order,
+// skipping keys that have a single value in the span.
+func groupedBy(by expression.Expressions, keys expression.Expressions,
+	span *planner.Span, nnf *planner.NNF) bool {
+	eq := equalityKeys(span)
+	found := make([]bool, len(keys)-eq)
+	n := 0
+
+	for _, key := range by {
+		expr, err := nnf.Map(key.Copy())
+		if err != nil {
+			return false
+		}
+
+		if keyIndex(expr, keys[0:eq]) >= 0 {
+			// A single value does not affect the grouping
+			continue
+		}
+
+		k := keyIndex(expr, keys[eq:])
+		if k < 0 {
+			return false
+		}
+
+		if !found[k] {
+			found[k] = true
+			n++
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		if !found[k] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Whether the projection is MIN or MAX of a key bounded by the span
+// and preceded only by keys that have a single value in the span, so
+// that the result is found in the first or last entry of the scan.
+// Returns whether the result is the MAX, which requires a reverse
+// scan.
+func minMax(node *algebra.Subselect, keys expression.Expressions,
+	span *planner.Span, nnf *planner.NNF) (bool, bool) {
+	if node.Group() != nil || node.Projection().Distinct() {
+		return false, false
+	}
+
+	var operand expression.Expression
+	max := false
+
+	for i, term := range node.Projection().Terms() {
+		var agg algebra.Aggregate
+		switch expr := term.Expression().(type) {
+		case *algebra.Min:
+			agg = expr
+			if i > 0 && max {
+				return false, false
+			}
+		case *algebra.Max:
+			agg = expr
+			if i > 0 && !max {
+				return false, false
+			}
+			max = true
+		default:
+			return false, false
+		}
+
+		if agg.Operand() == nil {
+			return false, false
+		}
+
+		op, err := nnf.Map(agg.Operand().Copy())
+		if err != nil || (operand != nil && !op.EquivalentTo(operand)) {
+			return false, false
+		}
+
+		operand = op
+	}
+
+	if operand == nil {
+		return false, false
+	}
+
+	k := keyIndex(operand, keys[0:len(span.Range.Low)])
+	if k < 0 || k > equalityKeys(span) {
+		return false, false
+	}
+
+	return max, true
+}
+
+// The number of leading keys that have a single value in the span.
+func equalityKeys(span *planner.Span) int {
+	low := span.Range.Low
+	high := span.Range.High
+	n := 0
+
+	for n < len(low) && n < len(high) {
+		if low[n] == nil || high[n] == nil || !low[n].EquivalentTo(high[n]) {
+			break
+		}
+
+		n++
+	}
+
+	return n
+}
+
+func keyIndex(expr expression.Expression, keys expression.Expressions) int {
+	for i, key := range keys {
+		if expr.EquivalentTo(key) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func reversible(index datastore.Index) bool {
+	_, ok := index.(datastore.ReverseIndex)
+	return ok
+}
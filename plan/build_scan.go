@@ -12,6 +12,7 @@ package plan
 import (
 	"fmt"
 	"math"
+	"sort"
 
 	"github.com/couchbaselabs/query/algebra"
 	"github.com/couchbaselabs/query/datastore"
@@ -32,15 +33,68 @@ func (this *builder) selectScan(keyspace datastore.Keyspace,
 		return nil, err
 	}
 
+	indexes, err := this.scanIndexes(keyspace, node, nnf)
+	if err != nil {
+		return nil, err
+	}
+
+	scan, cost, err := this.selectIndexScan(keyspace, node, this.where, where, indexes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if disjuncts := scanDisjuncts(this.where); len(disjuncts) > 1 {
+		// Each disjunct may use a different index
+		union, unionCost, err := this.selectUnionScan(keyspace, node, disjuncts, nnf, indexes)
+		if err != nil {
+			return nil, err
+		}
+
+		if union != nil && (scan == nil || unionCost < cost) {
+			scan, cost = union, unionCost
+		}
+	}
+
+	if scan == nil {
+		return this.selectPrimaryScan(keyspace, node)
+	}
+
+	if cost > primaryScanCost(keyspace) {
+		// Full scan is cheaper than any index, if it can be done
+		primary, _, err := primaryIndexes(keyspace)
+		if err != nil {
+			return nil, err
+		}
+
+		if primary != nil {
+			return NewPrimaryScan(primary, node, math.MaxInt64), nil
+		}
+	}
+
+	return scan, nil
+}
+
+// An online, rangeable index, with its keys and condition formalized
+// and in NNF
+type scanIndex struct {
+	index   datastore.Index
+	key     expression.Expression // Leading key
+	keys    expression.Expressions
+	cond    expression.Expression
+	primary bool
+}
+
+func (this *builder) scanIndexes(keyspace datastore.Keyspace, node *algebra.KeyspaceTerm,
+	nnf *planner.NNF) ([]*scanIndex, error) {
 	formalizer := expression.NewFormalizer()
 	formalizer.Keyspace = node.Alias()
 	primaryKey := expression.NewField(
 		expression.NewMeta(expression.NewConstant(node.Alias())),
 		expression.NewFieldName("id"))
 
-	indexers, err := keyspace.Indexers()
-	if err != nil {
-		return nil, err
+	indexers, er := keyspace.Indexers()
+	if er != nil {
+		return nil, er
 	}
 
 	indexes := make([]datastore.Index, 0, len(indexers)*16)
@@ -64,8 +118,7 @@ func (this *builder) selectScan(keyspace datastore.Keyspace,
 		}
 	}
 
-	unfiltered := make(map[datastore.Index]expression.Expression, len(indexes))
-	filtered := make(map[datastore.Index]expression.Expression, len(indexes))
+	rv := make([]*scanIndex, 0, len(indexes))
 
 	for _, index := range indexes {
 		state, _, er := index.State()
@@ -77,118 +130,388 @@ func (this *builder) selectScan(keyspace datastore.Keyspace,
 			continue
 		}
 
-		var key expression.Expression
+		var err error
 
-		if primaryIndexes[index] {
-			key = primaryKey
-		} else {
-			rangeKey := index.RangeKey()
-			if len(rangeKey) == 0 || rangeKey[0] == nil {
+		keys := expression.Expressions{primaryKey}
+		primary := primaryIndexes[index]
+		if !primary {
+			keys, err = rangeKeys(index, formalizer, nnf)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(keys) == 0 {
 				// Index not rangeable
 				continue
 			}
+		}
 
-			key := rangeKey[0].Copy()
+		indexCond := index.Condition()
+		if indexCond != nil {
+			indexCond = indexCond.Copy()
 
-			key, err = formalizer.Map(key)
+			indexCond, err = formalizer.Map(indexCond)
 			if err != nil {
 				return nil, err
 			}
 
-			key, err = nnf.Map(key)
+			indexCond, err = nnf.Map(indexCond)
 			if err != nil {
 				return nil, err
 			}
 		}
 
-		if !planner.SargableFor(where, key) {
+		rv = append(rv, &scanIndex{
+			index:   index,
+			key:     keys[0],
+			keys:    keys,
+			cond:    indexCond,
+			primary: primary,
+		})
+	}
+
+	return rv, nil
+}
+
+// The range keys of an index up to the first missing key, formalized
+// and in NNF.
+func rangeKeys(index datastore.Index, formalizer *expression.Formalizer,
+	nnf *planner.NNF) (expression.Expressions, error) {
+	rangeKey := index.RangeKey()
+	keys := make(expression.Expressions, 0, len(rangeKey))
+	for _, key := range rangeKey {
+		if key == nil {
+			break
+		}
+
+		key, err := formalizer.Map(key.Copy())
+		if err != nil {
+			return nil, err
+		}
+
+		key, err = nnf.Map(key)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// Select the cheapest scan of one or more indexes for a condition,
+// given both as written and in NNF. Indexes that cover the statement
+// are priced without fetches, unless covering is false. Returns a nil
+// scan if no index is applicable.
+func (this *builder) selectIndexScan(keyspace datastore.Keyspace, node *algebra.KeyspaceTerm,
+	pred, where expression.Expression, indexes []*scanIndex, covering bool) (
+	Operator, float64, error) {
+	candidates := make([]*indexCandidate, 0, len(indexes))
+
+	for _, si := range indexes {
+		if !planner.SargableFor(where, si.key) {
 			// Index not applicable
 			continue
 		}
 
-		indexCond := index.Condition()
-		if indexCond == nil {
-			unfiltered[index] = key
+		if si.cond != nil && !planner.SubsetOf(where, si.cond) {
+			// Index condition does not satisfy query condition
 			continue
 		}
 
-		indexCond = indexCond.Copy()
+		spans := planner.SargForKeys(where, si.keys)
+		if len(spans) == 0 {
+			continue
+		}
+
+		covers := false
+		if covering {
+			keys, err := this.coveringKeys(si.index, node)
+			if err != nil {
+				return nil, 0.0, err
+			}
+
+			covers = keys != nil
+		}
+
+		entries := spansCardinality(keyspace, si.index, spans)
+		candidates = append(candidates, &indexCandidate{
+			index:    si.index,
+			key:      si.key,
+			spans:    spans,
+			primary:  si.primary,
+			filtered: si.cond != nil,
+			array:    arrayIndex(si.keys),
+			entries:  entries,
+			cost:     indexScanCost(entries, si.primary, covers, len(spans)),
+		})
+	}
+
+	if len(candidates) == 0 {
+		return nil, 0.0, nil
+	}
+
+	sort.Sort(candidatesByCost(candidates))
+	chosen, cost := intersectCandidates(keyspace, candidates)
+
+	scans := make([]Operator, len(chosen))
+	for i, c := range chosen {
+		// Array indexes may have several entries for a document
+		scans[i] = NewIndexScan(c.index, node, c.spans, c.array, false, math.MaxInt64, nil)
+		if len(c.spans) > 1 {
+			// Use UnionScan to de-dup multiple spans
+			scans[i] = NewUnionScan(scans[i])
+		}
+	}
+
+	if len(scans) == 1 {
+		return scans[0], cost, nil
+	}
 
-		indexCond, err = formalizer.Map(indexCond)
+	// Record the conjuncts of the condition covered by each scan
+	nnf := planner.NewNNF()
+	terms := planner.Conjuncts(pred)
+	nnfTerms := make(expression.Expressions, len(terms))
+	for i, term := range terms {
+		var err error
+		nnfTerms[i], err = nnf.Map(term.Copy())
 		if err != nil {
-			return nil, err
+			return nil, 0.0, err
+		}
+	}
+
+	conjuncts := make(expression.Expressions, len(chosen))
+	for i, c := range chosen {
+		covered := make(expression.Expressions, 0, len(terms))
+		for j, term := range terms {
+			if planner.SargableFor(nnfTerms[j], c.key) {
+				covered = append(covered, term)
+			}
+		}
+
+		if len(covered) == 1 {
+			conjuncts[i] = covered[0]
+		} else {
+			conjuncts[i] = expression.NewAnd(covered...)
+		}
+	}
+
+	return NewIntersectScan(scans, conjuncts), cost, nil
+}
+
+// Select a scan for each disjunct of the query condition, and union
+// the results. Returns a nil scan unless every disjunct has an
+// applicable index.
+func (this *builder) selectUnionScan(keyspace datastore.Keyspace, node *algebra.KeyspaceTerm,
+	disjuncts expression.Expressions, nnf *planner.NNF, indexes []*scanIndex) (
+	Operator, float64, error) {
+	scans, cost, err := this.selectDisjunctScans(keyspace, node, disjuncts, nnf, indexes, true)
+	if err != nil || scans == nil {
+		return nil, 0.0, err
+	}
+
+	if !singleIndex(scans) {
+		// Only the scans of a single index can cover, so price the
+		// fetches of every disjunct
+		scans, cost, err = this.selectDisjunctScans(keyspace, node, disjuncts, nnf, indexes, false)
+		if err != nil || scans == nil {
+			return nil, 0.0, err
 		}
+	}
+
+	return NewUnionScan(scans...), cost, nil
+}
+
+func (this *builder) selectDisjunctScans(keyspace datastore.Keyspace, node *algebra.KeyspaceTerm,
+	disjuncts expression.Expressions, nnf *planner.NNF, indexes []*scanIndex, covering bool) (
+	[]Operator, float64, error) {
+	scans := make([]Operator, 0, len(disjuncts))
+	cost := 0.0
 
-		indexCond, err = nnf.Map(indexCond)
+	for _, disjunct := range disjuncts {
+		where, err := nnf.Map(disjunct.Copy())
 		if err != nil {
-			return nil, err
+			return nil, 0.0, err
 		}
 
-		if planner.SubsetOf(where, indexCond) {
-			// Index condition satisfies query condition
-			filtered[index] = key
-			break
+		scan, scanCost, err := this.selectIndexScan(keyspace, node, disjunct, where, indexes, covering)
+		if err != nil || scan == nil {
+			return nil, 0.0, err
+		}
+
+		if union, ok := scan.(*UnionScan); ok {
+			// Flatten the multiple spans of a disjunct
+			scans = append(scans, union.Scans()...)
+		} else {
+			scans = append(scans, scan)
 		}
+
+		cost += scanCost
 	}
 
-	var indexMap map[datastore.Index]expression.Expression
-	if len(filtered) > 0 {
-		indexMap = filtered
-	} else if len(unfiltered) > 0 {
-		indexMap = unfiltered
+	return scans, cost, nil
+}
+
+// The disjuncts of a condition that each may be scanned with a
+// different index. An OR nested under a top-level AND is distributed
+// over the other conjuncts, so that a AND (b OR c) yields a AND b and
+// a AND c. Only the first such OR is distributed, to bound the number
+// of disjuncts; the others remain in each disjunct.
+func scanDisjuncts(where expression.Expression) expression.Expressions {
+	if _, ok := where.(*expression.Or); ok {
+		return planner.Disjuncts(where)
 	}
 
-	for index, key := range indexMap {
-		spans := planner.SargFor(where, key)
-		var scan Operator
-		scan = NewIndexScan(index, node, spans, false, math.MaxInt64)
-		if len(spans) > 1 {
-			// Use UnionScan to de-dup multiple spans
-			scan = NewUnionScan(scan)
+	conjuncts := planner.Conjuncts(where)
+	for i, term := range conjuncts {
+		if _, ok := term.(*expression.Or); !ok {
+			continue
 		}
 
-		return scan, err
+		disjuncts := planner.Disjuncts(term)
+		rv := make(expression.Expressions, len(disjuncts))
+		for j, disjunct := range disjuncts {
+			terms := make(expression.Expressions, 0, len(conjuncts))
+			terms = append(terms, conjuncts[:i]...)
+			terms = append(terms, disjunct)
+			terms = append(terms, conjuncts[i+1:]...)
+			rv[j] = expression.NewAnd(terms...)
+		}
+
+		return rv
 	}
 
-	return this.selectPrimaryScan(keyspace, node)
+	return nil
+}
+
+// Starting from the cheapest candidate, add the candidate indexes on
+// other keys that reduce the cost of the scan by reducing the number
+// of documents fetched. The candidates must be sorted by cost.
+func intersectCandidates(keyspace datastore.Keyspace,
+	candidates []*indexCandidate) ([]*indexCandidate, float64) {
+	chosen := candidates[0:1]
+	cost := candidates[0].cost
+	keys := map[string]bool{candidates[0].key.String(): true}
+
+	for _, c := range candidates[1:] {
+		key := c.key.String()
+		if keys[key] {
+			continue
+		}
+
+		with := make([]*indexCandidate, len(chosen), len(chosen)+1)
+		copy(with, chosen)
+		with = append(with, c)
+
+		withCost := intersectScanCost(keyspace, with)
+		if withCost < cost {
+			chosen = with
+			cost = withCost
+			keys[key] = true
+		}
+	}
+
+	return chosen, cost
+}
+
+// An index applicable to a query, with its spans and estimated number
+// of entries and cost
+type indexCandidate struct {
+	index    datastore.Index
+	key      expression.Expression
+	spans    planner.Spans
+	primary  bool
+	filtered bool
+	array    bool
+	entries  float64
+	cost     float64
+}
+
+// Whether any key of an index is an array index key, with an entry
+// for each distinct element of an array.
+func arrayIndex(keys expression.Expressions) bool {
+	for _, key := range keys {
+		if array, ok := key.(*expression.Array); ok && array.Distinct() {
+			return true
+		}
+	}
+
+	return false
+}
+
+type candidatesByCost []*indexCandidate
+
+func (this candidatesByCost) Len() int           { return len(this) }
+func (this candidatesByCost) Less(i, j int) bool { return this[i].cheaperThan(this[j]) }
+func (this candidatesByCost) Swap(i, j int)      { this[i], this[j] = this[j], this[i] }
+
+// Ties are broken in favor of filtered indexes, and then by index
+// name, so that the choice of index is deterministic.
+func (this *indexCandidate) cheaperThan(other *indexCandidate) bool {
+	if other == nil {
+		return true
+	}
+
+	if this.cost != other.cost {
+		return this.cost < other.cost
+	}
+
+	if this.filtered != other.filtered {
+		return this.filtered
+	}
+
+	return this.index.Name() < other.index.Name()
 }
 
 func (this *builder) selectPrimaryScan(keyspace datastore.Keyspace,
 	node *algebra.KeyspaceTerm) (Operator, error) {
-	indexers, err := keyspace.Indexers()
+	primary, offline, err := primaryIndexes(keyspace)
 	if err != nil {
 		return nil, err
 	}
 
-	var primary datastore.PrimaryIndex
+	if primary != nil {
+		return NewPrimaryScan(primary, node, math.MaxInt64), nil
+	}
+
+	if offline == nil {
+		return nil, fmt.Errorf(
+			"No primary index on keyspace %s. Use CREATE PRIMARY INDEX to create one.",
+			keyspace.Name())
+	}
+
+	return nil, fmt.Errorf("Primary index %s not online.", offline.Name())
+}
+
+// The first online primary index of a keyspace, if any, and otherwise
+// a primary index that is not online, if any.
+func primaryIndexes(keyspace datastore.Keyspace) (online, offline datastore.PrimaryIndex, err error) {
+	indexers, err := keyspace.Indexers()
+	if err != nil {
+		return nil, nil, err
+	}
 
 	for _, indexer := range indexers {
 		indexes, err := indexer.PrimaryIndexes()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		for _, index := range indexes {
 			state, _, er := index.State()
 			if er != nil {
-				return nil, er
+				return nil, nil, er
 			}
 
 			if state != datastore.ONLINE {
-				primary = index
+				offline = index
 				continue
 			}
 
-			scan := NewPrimaryScan(index, node)
-			return scan, nil
+			return index, nil, nil
 		}
 	}
 
-	if primary == nil {
-		return nil, fmt.Errorf(
-			"No primary index on keyspace %s. Use CREATE PRIMARY INDEX to create one.",
-			keyspace.Name())
-	}
-
-	return nil, fmt.Errorf("Primary index %s not online.", primary.Name())
+	return nil, offline, nil
 }
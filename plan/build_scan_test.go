@@ -0,0 +1,129 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/couchbaselabs/query/algebra"
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/datastore/file"
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/parser/n1ql"
+)
+
+// A keyspace whose indexers hide their primary indexes
+type noPrimaryKeyspace struct {
+	datastore.Keyspace
+}
+
+func (this *noPrimaryKeyspace) Indexers() ([]datastore.Indexer, errors.Error) {
+	indexers, err := this.Keyspace.Indexers()
+	if err != nil {
+		return nil, err
+	}
+
+	rv := make([]datastore.Indexer, len(indexers))
+	for i, indexer := range indexers {
+		rv[i] = &noPrimaryIndexer{indexer}
+	}
+
+	return rv, nil
+}
+
+type noPrimaryIndexer struct {
+	datastore.Indexer
+}
+
+func (this *noPrimaryIndexer) PrimaryIndexes() ([]datastore.PrimaryIndex, errors.Error) {
+	return nil, nil
+}
+
+func (this *noPrimaryIndexer) Indexes() ([]datastore.Index, errors.Error) {
+	indexes, err := this.Indexer.Indexes()
+	if err != nil {
+		return nil, err
+	}
+
+	rv := make([]datastore.Index, 0, len(indexes))
+	for _, index := range indexes {
+		if _, ok := index.(datastore.PrimaryIndex); !ok {
+			rv = append(rv, index)
+		}
+	}
+
+	return rv, nil
+}
+
+func TestSelectScanWithoutPrimaryIndex(t *testing.T) {
+	store, err := file.NewDatastore("../test/json")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	namespace, err := store.NamespaceByName("default")
+	if err != nil {
+		t.Fatalf("failed to get namespace: %v", err)
+	}
+
+	keyspace, err := namespace.KeyspaceByName("contacts")
+	if err != nil {
+		t.Fatalf("failed to get keyspace: %v", err)
+	}
+
+	indexers, err := keyspace.Indexers()
+	if err != nil {
+		t.Fatalf("failed to get indexers: %v", err)
+	}
+
+	rangeKey := expression.Expressions{expression.NewIdentifier("name")}
+	index, err := indexers[0].CreateIndex("scan_name", nil, rangeKey, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create index: %v", err)
+	}
+	defer index.Drop()
+
+	// Most names are in the span, so a full scan is cheaper
+	where, e := n1ql.ParseExpression(`contacts.name > "a"`)
+	if e != nil {
+		t.Fatalf("failed to parse condition: %v", e)
+	}
+
+	builder := newBuilder(store, nil, "default", false)
+	builder.where = where
+	node := algebra.NewKeyspaceTerm("default", "contacts", nil, "contacts", nil)
+
+	scan, e := builder.selectScan(keyspace, node)
+	if e != nil {
+		t.Fatalf("failed to select scan: %v", e)
+	}
+
+	if _, ok := scan.(*PrimaryScan); !ok {
+		t.Errorf("Expected a primary scan, got %T", scan)
+	}
+
+	// Without a primary index, the index is scanned regardless
+	scan, e = builder.selectScan(&noPrimaryKeyspace{keyspace}, node)
+	if e != nil {
+		t.Fatalf("failed to select scan without a primary index: %v", e)
+	}
+
+	if s, ok := scan.(*IndexScan); !ok || s.Index() != index {
+		t.Errorf("Expected a scan of index scan_name, got %T", scan)
+	}
+
+	// Without a usable index either, there is nothing to scan
+	builder.where = nil
+	_, e = builder.selectScan(&noPrimaryKeyspace{keyspace}, node)
+	if e == nil {
+		t.Errorf("Expected an error without a primary index")
+	}
+}
@@ -21,6 +21,54 @@ import (
 // SELECT
 
 func (this *builder) VisitSelect(stmt *algebra.Select) (interface{}, error) {
+	if stmt.With() == nil {
+		return this.visitSelect(stmt)
+	}
+
+	terms, err := this.visitWith(stmt.With())
+	if err != nil {
+		return nil, err
+	}
+
+	sel, err := this.visitSelect(stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWith(terms, sel.(Operator)), nil
+}
+
+// Each WITH term is planned separately, and materialized before the
+// rest of the statement runs.
+func (this *builder) visitWith(with *algebra.With) ([]*WithTerm, error) {
+	terms := make([]*WithTerm, len(with.Terms()))
+	for i, term := range with.Terms() {
+		query := term.Query()
+		if term.Recursive() {
+			query = term.Anchor()
+		}
+
+		qp, err := query.Accept(newBuilder(this.datastore, this.systemstore, this.namespace, this.subquery))
+		if err != nil {
+			return nil, err
+		}
+
+		var sp interface{}
+		if term.Recursive() {
+			sp, err = term.Step().Accept(newBuilder(this.datastore, this.systemstore, this.namespace, this.subquery))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		step, _ := sp.(Operator)
+		terms[i] = NewWithTerm(term.Alias(), qp.(Operator), step, term.Distinct())
+	}
+
+	return terms, nil
+}
+
+func (this *builder) visitSelect(stmt *algebra.Select) (interface{}, error) {
 	order := stmt.Order()
 	offset := stmt.Offset()
 	limit := stmt.Limit()
@@ -32,7 +80,17 @@ func (this *builder) VisitSelect(stmt *algebra.Select) (interface{}, error) {
 		this.delayProjection = true
 	}
 
+	// Only a single subselect can push down its ORDER BY, LIMIT
+	// and OFFSET
+	pushdown, ordered := this.pushdown, this.ordered
+	this.pushdown, this.ordered = nil, false
+	if _, ok := stmt.Subresult().(*algebra.Subselect); ok {
+		this.pushdown = stmt
+	}
+
 	sub, err := stmt.Subresult().Accept(this)
+	pushedOrder := this.ordered
+	this.pushdown, this.ordered = pushdown, ordered
 	if err != nil {
 		return nil, err
 	}
@@ -56,7 +114,14 @@ func (this *builder) VisitSelect(stmt *algebra.Select) (interface{}, error) {
 			}
 		}
 
-		children = append(children, NewOrder(order))
+		if !pushedOrder {
+			if knownBefore(limit) && (offset == nil || knownBefore(offset)) {
+				// Keep only the first LIMIT plus OFFSET results
+				children = append(children, NewOrder(order, offset, limit))
+			} else {
+				children = append(children, NewOrder(order, nil, nil))
+			}
+		}
 	}
 
 	if offset != nil {
@@ -76,8 +141,27 @@ func (this *builder) VisitSelect(stmt *algebra.Select) (interface{}, error) {
 	return NewSequence(children...), nil
 }
 
+// Whether an expression is a constant or a parameter, whose value is
+// known before execution.
+func knownBefore(expr expression.Expression) bool {
+	switch expr.(type) {
+	case nil:
+		return false
+	case expression.NamedParameter, expression.PositionalParameter:
+		return true
+	default:
+		return expr.Value() != nil
+	}
+}
+
 func (this *builder) VisitSubselect(node *algebra.Subselect) (interface{}, error) {
 	this.where = node.Where()
+	this.cover = nil
+	this.grouped = false
+	if _, ok := node.From().(*algebra.KeyspaceTerm); ok {
+		this.cover = node
+	}
+
 	this.children = make([]Operator, 0, 16)    // top-level children, executed sequentially
 	this.subChildren = make([]Operator, 0, 16) // sub-children, executed across data-parallel streams
 
@@ -100,6 +184,7 @@ func (this *builder) VisitSubselect(node *algebra.Subselect) (interface{}, error
 	}
 
 	aggs := make(map[string]algebra.Aggregate)
+	windows := make(map[string]algebra.WindowFunction)
 
 	if node.Let() != nil {
 		for _, binding := range node.Let() {
@@ -107,6 +192,11 @@ func (this *builder) VisitSubselect(node *algebra.Subselect) (interface{}, error
 			if len(aggs) > 0 {
 				return nil, fmt.Errorf("Aggregates not allowed in LET.")
 			}
+
+			collectWindows(windows, binding.Expression())
+			if len(windows) > 0 {
+				return nil, fmt.Errorf("Window functions not allowed in LET.")
+			}
 		}
 
 		this.subChildren = append(this.subChildren, NewLet(node.Let()))
@@ -118,6 +208,11 @@ func (this *builder) VisitSubselect(node *algebra.Subselect) (interface{}, error
 			return nil, fmt.Errorf("Aggregates not allowed in WHERE.")
 		}
 
+		collectWindows(windows, node.Where())
+		if len(windows) > 0 {
+			return nil, fmt.Errorf("Window functions not allowed in WHERE.")
+		}
+
 		this.subChildren = append(this.subChildren, NewFilter(node.Where()))
 	}
 
@@ -127,6 +222,17 @@ func (this *builder) VisitSubselect(node *algebra.Subselect) (interface{}, error
 		for _, term := range projection.Terms() {
 			if term.Expression() != nil {
 				collectAggregates(aggs, term.Expression())
+				collectWindows(windows, term.Expression())
+			}
+		}
+	}
+
+	// Window functions in ORDER BY are computed with those in the
+	// projection, whether or not there is grouping
+	if this.order != nil {
+		for _, term := range this.order.Terms() {
+			if term.Expression() != nil {
+				collectWindows(windows, term.Expression())
 			}
 		}
 	}
@@ -149,9 +255,21 @@ func (this *builder) VisitSubselect(node *algebra.Subselect) (interface{}, error
 	}
 
 	if group != nil {
+		err = checkGroupWindows(group)
+		if err != nil {
+			return nil, err
+		}
+
 		this.visitGroup(group, aggs)
 	}
 
+	if len(windows) > 0 {
+		err = this.visitWindow(windows)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	this.subChildren = append(this.subChildren, NewInitialProject(projection))
 
 	// Initial DISTINCT (parallel)
@@ -164,8 +282,13 @@ func (this *builder) VisitSubselect(node *algebra.Subselect) (interface{}, error
 		this.subChildren = append(this.subChildren, NewFinalProject())
 	}
 
-	// Parallelize the subChildren
-	this.children = append(this.children, NewParallel(NewSequence(this.subChildren...)))
+	if this.ordered {
+		// Preserve the order of the scan
+		this.children = append(this.children, this.subChildren...)
+	} else {
+		// Parallelize the subChildren
+		this.children = append(this.children, NewParallel(NewSequence(this.subChildren...)))
+	}
 
 	// Final DISTINCT (serial)
 	if projection.Distinct() || this.distinct {
@@ -198,11 +321,19 @@ func (this *builder) visitGroup(group *algebra.Group, aggs map[string]algebra.Ag
 		aggv[i] = aggs[n]
 	}
 
-	this.subChildren = append(this.subChildren, NewInitialGroup(group.By(), aggv))
-	this.subChildren = append(this.subChildren, NewIntermediateGroup(group.By(), aggv))
-	this.children = append(this.children, NewParallel(NewSequence(this.subChildren...)))
-	this.children = append(this.children, NewIntermediateGroup(group.By(), aggv))
-	this.children = append(this.children, NewFinalGroup(group.By(), aggv))
+	if this.grouped {
+		// Preserve the order of the scan, and aggregate each group
+		// as soon as it is complete
+		this.children = append(this.children, this.subChildren...)
+		this.children = append(this.children, NewStreamGroup(group.By(), aggv))
+	} else {
+		this.subChildren = append(this.subChildren, NewInitialGroup(group.By(), aggv))
+		this.subChildren = append(this.subChildren, NewIntermediateGroup(group.By(), aggv))
+		this.children = append(this.children, NewParallel(NewSequence(this.subChildren...)))
+		this.children = append(this.children, NewIntermediateGroup(group.By(), aggv))
+		this.children = append(this.children, NewFinalGroup(group.By(), aggv))
+	}
+
 	this.subChildren = make([]Operator, 0, 4)
 
 	if letting != nil {
@@ -215,6 +346,12 @@ func (this *builder) visitGroup(group *algebra.Group, aggs map[string]algebra.Ag
 }
 
 func (this *builder) VisitKeyspaceTerm(node *algebra.KeyspaceTerm) (interface{}, error) {
+	if node.FromWith() {
+		scan := NewExpressionScan(expression.NewIdentifier(node.Keyspace()), node.Alias())
+		this.children = append(this.children, scan)
+		return nil, nil
+	}
+
 	node.SetDefaultNamespace(this.namespace)
 	keyspace, err := this.getTermKeyspace(node)
 	if err != nil {
@@ -236,6 +373,27 @@ func (this *builder) VisitKeyspaceTerm(node *algebra.KeyspaceTerm) (interface{},
 			return nil, err
 		}
 
+		scan, err = this.pushDown(scan, node)
+		if err != nil {
+			return nil, err
+		}
+
+		this.grouped, err = this.groupedScan(scan, node)
+		if err != nil {
+			return nil, err
+		}
+
+		covering, err := this.buildCoveringScan(scan, node)
+		if err != nil {
+			return nil, err
+		}
+
+		if covering != nil {
+			// No need to fetch documents
+			this.children = append(this.children, covering)
+			return nil, nil
+		}
+
 		this.children = append(this.children, scan)
 	}
 
@@ -253,6 +411,7 @@ func (this *builder) VisitSubqueryTerm(node *algebra.SubqueryTerm) (interface{},
 	this.children = make([]Operator, 0, 16)    // top-level children, executed sequentially
 	this.subChildren = make([]Operator, 0, 16) // sub-children, executed across data-parallel streams
 	this.children = append(this.children, sel.(Operator), NewAlias(node.Alias()))
+	this.grouped = false // The subquery results are not ordered by a scan
 	return nil, nil
 }
 
@@ -274,6 +433,30 @@ func (this *builder) VisitJoin(node *algebra.Join) (interface{}, error) {
 		return nil, err
 	}
 
+	if node.Onclause() != nil {
+		if right.JoinHint() != algebra.JOIN_HINT_NONE {
+			_, err := this.buildHashJoin(keyspace, node)
+			return nil, err
+		}
+
+		index, spans, err := this.selectJoinScan(keyspace, right, node.Onclause())
+		if err != nil {
+			return nil, err
+		}
+
+		// No usable index; use a hash join for equi-joins
+		if spans == nil {
+			hash, err := this.buildHashJoin(keyspace, node)
+			if hash || err != nil {
+				return nil, err
+			}
+		}
+
+		join := NewAnsiJoin(keyspace, node, index, spans)
+		this.subChildren = append(this.subChildren, join)
+		return nil, nil
+	}
+
 	join := NewJoin(keyspace, node)
 	this.subChildren = append(this.subChildren, join)
 	return nil, nil
@@ -297,6 +480,17 @@ func (this *builder) VisitNest(node *algebra.Nest) (interface{}, error) {
 		return nil, err
 	}
 
+	if node.Onclause() != nil {
+		index, spans, err := this.selectJoinScan(keyspace, right, node.Onclause())
+		if err != nil {
+			return nil, err
+		}
+
+		nest := NewAnsiNest(keyspace, node, index, spans)
+		this.subChildren = append(this.subChildren, nest)
+		return nil, nil
+	}
+
 	nest := NewNest(keyspace, node)
 	this.subChildren = append(this.subChildren, nest)
 	return nil, nil
@@ -441,6 +635,74 @@ func (this *builder) VisitExceptAll(node *algebra.ExceptAll) (interface{}, error
 	return NewExceptAll(first.(Operator), second.(Operator)), nil
 }
 
+func (this *builder) visitWindow(windows map[string]algebra.WindowFunction) error {
+	winn := make(sort.StringSlice, 0, len(windows))
+	for n, w := range windows {
+		nested := make(map[string]algebra.WindowFunction)
+		collectWindows(nested, w.Children()...)
+		if len(nested) > 0 {
+			return fmt.Errorf("Window functions cannot be nested: %s.", n)
+		}
+
+		winn = append(winn, n)
+	}
+
+	winn.Sort()
+	winv := make(algebra.WindowFunctions, len(windows))
+	for i, n := range winn {
+		winv[i] = windows[n]
+	}
+
+	// Window functions see all the rows of each partition
+	if len(this.subChildren) > 0 {
+		this.children = append(this.children, NewParallel(NewSequence(this.subChildren...)))
+	}
+
+	this.children = append(this.children, NewWindow(winv))
+	this.subChildren = make([]Operator, 0, 4)
+	return nil
+}
+
+// Disallow window functions in GROUP BY, LETTING, and HAVING, which
+// are evaluated before window functions.
+func checkGroupWindows(group *algebra.Group) error {
+	windows := make(map[string]algebra.WindowFunction)
+	collectWindows(windows, group.By()...)
+	for _, binding := range group.Letting() {
+		collectWindows(windows, binding.Expression())
+	}
+
+	if group.Having() != nil {
+		collectWindows(windows, group.Having())
+	}
+
+	if len(windows) > 0 {
+		return fmt.Errorf("Window functions not allowed in GROUP BY, LETTING, or HAVING.")
+	}
+
+	return nil
+}
+
+func collectWindows(windows map[string]algebra.WindowFunction, exprs ...expression.Expression) {
+	stringer := expression.NewStringer()
+
+	for _, expr := range exprs {
+		window, ok := expr.(algebra.WindowFunction)
+		if ok {
+			str := stringer.Visit(window)
+			windows[str] = window
+		}
+
+		_, ok = expr.(*algebra.Subquery)
+		if !ok {
+			children := expr.Children()
+			if len(children) > 0 {
+				collectWindows(windows, children...)
+			}
+		}
+	}
+}
+
 func collectAggregates(aggs map[string]algebra.Aggregate, exprs ...expression.Expression) {
 	stringer := expression.NewStringer()
 
@@ -469,7 +731,7 @@ func (this *builder) fastCount(node *algebra.Subselect) (bool, error) {
 	}
 
 	from, ok := node.From().(*algebra.KeyspaceTerm)
-	if !ok {
+	if !ok || from.FromWith() {
 		return false, nil
 	}
 
@@ -0,0 +1,183 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package plan
+
+import (
+	"math"
+
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/planner"
+	"github.com/couchbaselabs/query/value"
+)
+
+// Relative costs of the work done by scans
+const (
+	_PRIMARY_ENTRY_COST  = 0.5 // Scan one primary index entry
+	_INDEX_ENTRY_COST    = 1.0 // Scan one secondary index entry
+	_FETCH_COST          = 2.0 // Fetch one document
+	_SPAN_COST           = 1.0 // Start scanning one span
+	_DEFAULT_SELECTIVITY = 0.1 // Fraction of an index assumed to fall in a span without statistics
+)
+
+// Estimate the cost of a full scan of the keyspace using its primary
+// index. The cost is infinite if the keyspace cannot be counted, so
+// that any applicable index is preferred.
+func primaryScanCost(keyspace datastore.Keyspace) float64 {
+	count, err := keyspace.Count()
+	if err != nil {
+		return math.Inf(1)
+	}
+
+	return float64(count) * (_PRIMARY_ENTRY_COST + _FETCH_COST)
+}
+
+// Estimate the cost of scanning the spans of an index and fetching
+// the documents of the scanned entries, unless the index covers the
+// statement.
+func indexScanCost(entries float64, primary, covering bool, spans int) float64 {
+	cost := entries*entryCost(primary) + float64(spans)*_SPAN_COST
+	if !covering {
+		cost += entries * _FETCH_COST
+	}
+
+	return cost
+}
+
+// Estimate the cost of scanning several indexes and fetching only the
+// documents found by every scan. The conditions of the scans are
+// assumed to be independent, so the fraction of documents fetched is
+// the product of the fractions found by each scan. The cost is
+// infinite if the keyspace cannot be counted.
+func intersectScanCost(keyspace datastore.Keyspace, candidates []*indexCandidate) float64 {
+	count, err := keyspace.Count()
+	if err != nil || count <= 0 {
+		return math.Inf(1)
+	}
+
+	cost := 0.0
+	fetches := float64(count)
+	for _, c := range candidates {
+		cost += c.entries*entryCost(c.primary) + float64(len(c.spans))*_SPAN_COST
+		fetches *= math.Min(c.entries/float64(count), 1.0)
+	}
+
+	return cost + fetches*_FETCH_COST
+}
+
+func entryCost(primary bool) float64 {
+	if primary {
+		return _PRIMARY_ENTRY_COST
+	}
+
+	return _INDEX_ENTRY_COST
+}
+
+// Estimate the number of index entries in the spans of an index.
+func spansCardinality(keyspace datastore.Keyspace, index datastore.Index, spans planner.Spans) float64 {
+	entries := 0.0
+	for _, span := range spans {
+		entries += spanCardinality(keyspace, index, span)
+	}
+
+	return entries
+}
+
+// Estimate the number of index entries in a span. Spans whose bounds
+// are known at planning time are estimated from the statistics of the
+// index for the span; other spans are assumed to cover a default
+// fraction of the index for each key they bound, or of the keyspace
+// if the index provides no statistics.
+func spanCardinality(keyspace datastore.Keyspace, index datastore.Index, span *planner.Span) float64 {
+	dspan, ok := staticSpan(span)
+	if ok {
+		count, ok := statisticsCount(index, dspan)
+		if ok {
+			return count
+		}
+	}
+
+	full := &datastore.Span{}
+	full.Range.Inclusion = datastore.BOTH
+	count, ok := statisticsCount(index, full)
+	if !ok {
+		n, err := keyspace.Count()
+		if err != nil {
+			return 1.0
+		}
+
+		count = float64(n)
+	}
+
+	keys := len(span.Range.Low)
+	if len(span.Range.High) > keys {
+		keys = len(span.Range.High)
+	}
+
+	return count * math.Pow(_DEFAULT_SELECTIVITY, math.Max(float64(keys), 1.0))
+}
+
+func statisticsCount(index datastore.Index, span *datastore.Span) (float64, bool) {
+	stats, err := index.Statistics(span)
+	if err != nil || stats == nil {
+		return 0.0, false
+	}
+
+	count, err := stats.Count()
+	if err != nil {
+		return 0.0, false
+	}
+
+	return float64(count), true
+}
+
+// Evaluate the bounds of a span that are constant at planning time.
+func staticSpan(span *planner.Span) (*datastore.Span, bool) {
+	dspan := &datastore.Span{}
+	ok := true
+
+	dspan.Seek, ok = staticValues(span.Seek)
+	if !ok {
+		return nil, false
+	}
+
+	dspan.Range.Low, ok = staticValues(span.Range.Low)
+	if !ok {
+		return nil, false
+	}
+
+	dspan.Range.High, ok = staticValues(span.Range.High)
+	if !ok {
+		return nil, false
+	}
+
+	dspan.Range.Inclusion = span.Range.Inclusion
+	return dspan, true
+}
+
+func staticValues(exprs expression.Expressions) (value.Values, bool) {
+	if exprs == nil {
+		return nil, true
+	}
+
+	values := make(value.Values, len(exprs))
+	for i, expr := range exprs {
+		if expr == nil {
+			continue
+		}
+
+		values[i] = expr.Value()
+		if values[i] == nil {
+			return nil, false
+		}
+	}
+
+	return values, true
+}
@@ -11,12 +11,16 @@ package plan
 
 type Explain struct {
 	readonly
-	op Operator
+	op      Operator
+	analyze bool
+	format  string
 }
 
-func NewExplain(op Operator) *Explain {
+func NewExplain(op Operator, analyze bool, format string) *Explain {
 	return &Explain{
-		op: op,
+		op:      op,
+		analyze: analyze,
+		format:  format,
 	}
 }
 
@@ -31,3 +35,17 @@ func (this *Explain) New() Operator {
 func (this *Explain) Operator() Operator {
 	return this.op
 }
+
+// EXPLAIN ANALYZE runs the operator
+func (this *Explain) Analyze() bool {
+	return this.analyze
+}
+
+// The format of the output, or empty for that of the request
+func (this *Explain) Format() string {
+	return this.format
+}
+
+func (this *Explain) Readonly() bool {
+	return !this.analyze || this.op.Readonly()
+}
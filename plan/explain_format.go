@@ -0,0 +1,470 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package plan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Render a plan as an indented tree of operators, one per line, with
+// the details of each operator, such as its index, spans and filter
+// expressions.
+func ExplainText(op Operator) (string, error) {
+	node, err := explainTree(op)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	node.writeText(&buf, 0)
+	return buf.String(), nil
+}
+
+// Render a plan as a Graphviz DOT digraph, with an edge from each
+// operator to each of its children.
+func ExplainDot(op Operator) (string, error) {
+	node, err := explainTree(op)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("digraph plan {\n")
+	buf.WriteString("    node [shape=box];\n")
+	id := 0
+	node.writeDot(&buf, &id)
+	buf.WriteString("}\n")
+	return buf.String(), nil
+}
+
+func explainTree(op Operator) (*explainNode, error) {
+	node, err := op.Accept(&explainer{})
+	if err != nil {
+		return nil, err
+	}
+
+	return node.(*explainNode), nil
+}
+
+type explainNode struct {
+	name     string
+	details  []string
+	children []*explainNode
+}
+
+func (this *explainNode) writeText(buf *bytes.Buffer, depth int) {
+	buf.WriteString(strings.Repeat("    ", depth))
+	buf.WriteString(this.name)
+	if len(this.details) > 0 {
+		buf.WriteString(" (")
+		buf.WriteString(strings.Join(this.details, ", "))
+		buf.WriteString(")")
+	}
+	buf.WriteString("\n")
+
+	for _, child := range this.children {
+		child.writeText(buf, depth+1)
+	}
+}
+
+// Write the node and its children, and return the id of the node
+func (this *explainNode) writeDot(buf *bytes.Buffer, id *int) int {
+	nodeId := *id
+	*id++
+
+	label := dotEscape(this.name)
+	for _, detail := range this.details {
+		label += "\\n" + dotEscape(detail)
+	}
+
+	fmt.Fprintf(buf, "    n%d [label=\"%s\"];\n", nodeId, label)
+
+	for _, child := range this.children {
+		childId := child.writeDot(buf, id)
+		fmt.Fprintf(buf, "    n%d -> n%d;\n", nodeId, childId)
+	}
+
+	return nodeId
+}
+
+func dotEscape(s string) string {
+	s = strings.Replace(s, "\\", "\\\\", -1)
+	s = strings.Replace(s, "\"", "\\\"", -1)
+	return strings.Replace(s, "\n", "\\n", -1)
+}
+
+// The keys of the JSON plan that hold child operators; children are
+// visited rather than rendered as details.
+var _EXPLAIN_CHILD_KEYS = map[string]bool{
+	"~children": true,
+	"~child":    true,
+	"child":     true,
+	"children":  true,
+	"scans":     true,
+	"first":     true,
+	"second":    true,
+	"update":    true,
+	"delete":    true,
+	"insert":    true,
+}
+
+// Visits a plan to build its tree of operators.
+type explainer struct {
+}
+
+// Build the node of an operator from its JSON plan, and visit its
+// children.
+func (this *explainer) node(op Operator, children ...Operator) (interface{}, error) {
+	b, err := op.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep numbers, such as limits, as they are in the plan
+	var r map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	err = decoder.Decode(&r)
+	if err != nil {
+		return nil, err
+	}
+
+	name, _ := r["#operator"].(string)
+	node := &explainNode{name: name}
+
+	keys := make([]string, 0, len(r))
+	for k := range r {
+		if k != "#operator" && !_EXPLAIN_CHILD_KEYS[k] {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+	for _, k := range keys {
+		detail, err := explainDetail(r[k])
+		if err != nil {
+			return nil, err
+		}
+
+		node.details = append(node.details, k+": "+detail)
+	}
+
+	err = this.visitChildren(node, children)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+func (this *explainer) visitChildren(node *explainNode, children []Operator) error {
+	for _, child := range children {
+		if child == nil {
+			continue
+		}
+
+		c, err := child.Accept(this)
+		if err != nil {
+			return err
+		}
+
+		node.children = append(node.children, c.(*explainNode))
+	}
+
+	return nil
+}
+
+// Strings, such as expressions, are rendered verbatim; other values
+// as compact JSON.
+func explainDetail(val interface{}) (string, error) {
+	if s, ok := val.(string); ok {
+		return s, nil
+	}
+
+	bytes, err := json.Marshal(val)
+	if err != nil {
+		return "", err
+	}
+
+	return string(bytes), nil
+}
+
+// Scan
+func (this *explainer) VisitPrimaryScan(op *PrimaryScan) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitParentScan(op *ParentScan) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitIndexScan(op *IndexScan) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitKeyScan(op *KeyScan) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitValueScan(op *ValueScan) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitDummyScan(op *DummyScan) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitExpressionScan(op *ExpressionScan) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitCountScan(op *CountScan) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitIntersectScan(op *IntersectScan) (interface{}, error) {
+	return this.node(op, op.Scans()...)
+}
+
+func (this *explainer) VisitUnionScan(op *UnionScan) (interface{}, error) {
+	return this.node(op, op.Scans()...)
+}
+
+// Fetch
+func (this *explainer) VisitFetch(op *Fetch) (interface{}, error) {
+	return this.node(op)
+}
+
+// Join
+func (this *explainer) VisitJoin(op *Join) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitNest(op *Nest) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitHashJoin(op *HashJoin) (interface{}, error) {
+	return this.node(op, op.Child())
+}
+
+func (this *explainer) VisitUnnest(op *Unnest) (interface{}, error) {
+	return this.node(op)
+}
+
+// Let + Letting
+func (this *explainer) VisitLet(op *Let) (interface{}, error) {
+	return this.node(op)
+}
+
+// Filter
+func (this *explainer) VisitFilter(op *Filter) (interface{}, error) {
+	return this.node(op)
+}
+
+// Group
+func (this *explainer) VisitInitialGroup(op *InitialGroup) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitIntermediateGroup(op *IntermediateGroup) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitFinalGroup(op *FinalGroup) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitStreamGroup(op *StreamGroup) (interface{}, error) {
+	return this.node(op)
+}
+
+// Window
+func (this *explainer) VisitWindow(op *Window) (interface{}, error) {
+	return this.node(op)
+}
+
+// Project
+func (this *explainer) VisitInitialProject(op *InitialProject) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitFinalProject(op *FinalProject) (interface{}, error) {
+	return this.node(op)
+}
+
+// Distinct
+func (this *explainer) VisitDistinct(op *Distinct) (interface{}, error) {
+	return this.node(op)
+}
+
+// With
+func (this *explainer) VisitWith(op *With) (interface{}, error) {
+	return this.node(op, op.Child())
+}
+
+// Set operators
+func (this *explainer) VisitUnionAll(op *UnionAll) (interface{}, error) {
+	return this.node(op, op.Children()...)
+}
+
+func (this *explainer) VisitIntersectAll(op *IntersectAll) (interface{}, error) {
+	return this.node(op, op.First(), op.Second())
+}
+
+func (this *explainer) VisitExceptAll(op *ExceptAll) (interface{}, error) {
+	return this.node(op, op.First(), op.Second())
+}
+
+// Order
+func (this *explainer) VisitOrder(op *Order) (interface{}, error) {
+	return this.node(op)
+}
+
+// Offset
+func (this *explainer) VisitOffset(op *Offset) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitLimit(op *Limit) (interface{}, error) {
+	return this.node(op)
+}
+
+// Insert
+func (this *explainer) VisitSendInsert(op *SendInsert) (interface{}, error) {
+	return this.node(op)
+}
+
+// Upsert
+func (this *explainer) VisitSendUpsert(op *SendUpsert) (interface{}, error) {
+	return this.node(op)
+}
+
+// Delete
+func (this *explainer) VisitSendDelete(op *SendDelete) (interface{}, error) {
+	return this.node(op)
+}
+
+// Update
+func (this *explainer) VisitClone(op *Clone) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitSet(op *Set) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitUnset(op *Unset) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitSendUpdate(op *SendUpdate) (interface{}, error) {
+	return this.node(op)
+}
+
+// Merge
+func (this *explainer) VisitMerge(op *Merge) (interface{}, error) {
+	return this.node(op, op.Update(), op.Delete(), op.Insert())
+}
+
+// Framework
+func (this *explainer) VisitAlias(op *Alias) (interface{}, error) {
+	// The plan of Alias has no #operator
+	node, err := this.node(op)
+	if err != nil {
+		return nil, err
+	}
+
+	node.(*explainNode).name = "Alias"
+	return node, nil
+}
+
+func (this *explainer) VisitAuthorize(op *Authorize) (interface{}, error) {
+	return this.node(op, op.Child())
+}
+
+func (this *explainer) VisitParallel(op *Parallel) (interface{}, error) {
+	return this.node(op, op.Child())
+}
+
+func (this *explainer) VisitSequence(op *Sequence) (interface{}, error) {
+	return this.node(op, op.Children()...)
+}
+
+func (this *explainer) VisitDiscard(op *Discard) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitStream(op *Stream) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitCollect(op *Collect) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitChannel(op *Channel) (interface{}, error) {
+	return this.node(op)
+}
+
+// Index DDL
+func (this *explainer) VisitCreatePrimaryIndex(op *CreatePrimaryIndex) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitCreateIndex(op *CreateIndex) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitDropIndex(op *DropIndex) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitAlterIndex(op *AlterIndex) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitBuildIndexes(op *BuildIndexes) (interface{}, error) {
+	return this.node(op)
+}
+
+// Function DDL
+func (this *explainer) VisitCreateFunction(op *CreateFunction) (interface{}, error) {
+	return this.node(op)
+}
+
+func (this *explainer) VisitDropFunction(op *DropFunction) (interface{}, error) {
+	return this.node(op)
+}
+
+// Statistics
+func (this *explainer) VisitUpdateStatistics(op *UpdateStatistics) (interface{}, error) {
+	return this.node(op)
+}
+
+// Explain has no plan of its own
+func (this *explainer) VisitExplain(op *Explain) (interface{}, error) {
+	node := &explainNode{name: "Explain"}
+	err := this.visitChildren(node, []Operator{op.Operator()})
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Prepare
+func (this *explainer) VisitPrepare(op *Prepare) (interface{}, error) {
+	return this.node(op)
+}
@@ -0,0 +1,115 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package plan
+
+import (
+	"encoding/json"
+
+	"github.com/couchbaselabs/query/algebra"
+	"github.com/couchbaselabs/query/datastore"
+)
+
+// Create function
+type CreateFunction struct {
+	readwrite
+	namespace datastore.FunctionNamespace
+	node      *algebra.CreateFunction
+}
+
+func NewCreateFunction(namespace datastore.FunctionNamespace, node *algebra.CreateFunction) *CreateFunction {
+	return &CreateFunction{
+		namespace: namespace,
+		node:      node,
+	}
+}
+
+func (this *CreateFunction) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitCreateFunction(this)
+}
+
+func (this *CreateFunction) New() Operator {
+	return &CreateFunction{}
+}
+
+func (this *CreateFunction) Namespace() datastore.FunctionNamespace {
+	return this.namespace
+}
+
+func (this *CreateFunction) Node() *algebra.CreateFunction {
+	return this.node
+}
+
+func (this *CreateFunction) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"#operator": "CreateFunction"}
+	r["namespace"] = this.namespace.Name()
+	r["node"] = this.node
+	return json.Marshal(r)
+}
+
+func (this *CreateFunction) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_         string                  `json:"#operator"`
+		Namespace string                  `json:"namespace"`
+		Node      *algebra.CreateFunction `json:"node"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	return err
+	// TODO: recover namespace from namespace name
+}
+
+// Drop function
+type DropFunction struct {
+	readwrite
+	namespace datastore.FunctionNamespace
+	node      *algebra.DropFunction
+}
+
+func NewDropFunction(namespace datastore.FunctionNamespace, node *algebra.DropFunction) *DropFunction {
+	return &DropFunction{
+		namespace: namespace,
+		node:      node,
+	}
+}
+
+func (this *DropFunction) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitDropFunction(this)
+}
+
+func (this *DropFunction) New() Operator {
+	return &DropFunction{}
+}
+
+func (this *DropFunction) Namespace() datastore.FunctionNamespace {
+	return this.namespace
+}
+
+func (this *DropFunction) Node() *algebra.DropFunction {
+	return this.node
+}
+
+func (this *DropFunction) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"#operator": "DropFunction"}
+	r["namespace"] = this.namespace.Name()
+	r["node"] = this.node
+	return json.Marshal(r)
+}
+
+func (this *DropFunction) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_         string                `json:"#operator"`
+		Namespace string                `json:"namespace"`
+		Node      *algebra.DropFunction `json:"node"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	return err
+	// TODO: recover namespace from namespace name
+}
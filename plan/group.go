@@ -250,3 +250,82 @@ func (this *FinalGroup) UnmarshalJSON(body []byte) error {
 
 	return nil
 }
+
+// Grouping and aggregation of input data that is ordered on the group
+// keys. Each group is final when its key changes.
+type StreamGroup struct {
+	readonly
+	keys       expression.Expressions
+	aggregates algebra.Aggregates
+}
+
+func NewStreamGroup(keys expression.Expressions, aggregates algebra.Aggregates) *StreamGroup {
+	return &StreamGroup{
+		keys:       keys,
+		aggregates: aggregates,
+	}
+}
+
+func (this *StreamGroup) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitStreamGroup(this)
+}
+
+func (this *StreamGroup) New() Operator {
+	return &StreamGroup{}
+}
+
+func (this *StreamGroup) Keys() expression.Expressions {
+	return this.keys
+}
+
+func (this *StreamGroup) Aggregates() algebra.Aggregates {
+	return this.aggregates
+}
+
+func (this *StreamGroup) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"#operator": "StreamGroup"}
+	keylist := make([]string, 0, len(this.keys))
+	for _, key := range this.keys {
+		keylist = append(keylist, expression.NewStringer().Visit(key))
+	}
+	r["group_keys"] = keylist
+	s := make([]interface{}, 0, len(this.aggregates))
+	for _, agg := range this.aggregates {
+		s = append(s, expression.NewStringer().Visit(agg))
+	}
+	r["aggregates"] = s
+	return json.Marshal(r)
+}
+
+func (this *StreamGroup) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_    string   "#operator"
+		Keys []string "group_keys"
+		Aggs []string "aggregates"
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	if err != nil {
+		return err
+	}
+
+	this.keys = make(expression.Expressions, len(_unmarshalled.Keys))
+	for i, key := range _unmarshalled.Keys {
+		key_expr, err := parser.Parse(key)
+		if err != nil {
+			return err
+		}
+		this.keys[i] = key_expr
+	}
+
+	this.aggregates = make(algebra.Aggregates, len(_unmarshalled.Aggs))
+	for i, agg := range _unmarshalled.Aggs {
+		agg_expr, err := parser.Parse(agg)
+		if err != nil {
+			return err
+		}
+		this.aggregates[i], _ = agg_expr.(algebra.Aggregate)
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package plan
+
+import (
+	"encoding/json"
+
+	"github.com/couchbaselabs/query/algebra"
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/expression/parser"
+)
+
+// Hash join. The child is run to completion first, and its items
+// are hashed on buildExprs. Each input item is then probed on
+// probeExprs, and the buildAliases of each matching child item are
+// copied into it before the ON predicate is applied.
+type HashJoin struct {
+	readonly
+	keyspace     datastore.Keyspace
+	term         *algebra.KeyspaceTerm
+	outer        bool
+	onclause     expression.Expression
+	buildExprs   expression.Expressions
+	probeExprs   expression.Expressions
+	buildAliases []string
+	child        Operator
+}
+
+func NewHashJoin(keyspace datastore.Keyspace, join *algebra.Join, child Operator,
+	buildExprs, probeExprs expression.Expressions, buildAliases []string) *HashJoin {
+	return &HashJoin{
+		keyspace:     keyspace,
+		term:         join.Right(),
+		outer:        join.Outer(),
+		onclause:     join.Onclause(),
+		buildExprs:   buildExprs,
+		probeExprs:   probeExprs,
+		buildAliases: buildAliases,
+		child:        child,
+	}
+}
+
+func (this *HashJoin) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitHashJoin(this)
+}
+
+func (this *HashJoin) New() Operator {
+	return &HashJoin{}
+}
+
+func (this *HashJoin) Keyspace() datastore.Keyspace {
+	return this.keyspace
+}
+
+func (this *HashJoin) Term() *algebra.KeyspaceTerm {
+	return this.term
+}
+
+func (this *HashJoin) Outer() bool {
+	return this.outer
+}
+
+func (this *HashJoin) Onclause() expression.Expression {
+	return this.onclause
+}
+
+func (this *HashJoin) BuildExprs() expression.Expressions {
+	return this.buildExprs
+}
+
+func (this *HashJoin) ProbeExprs() expression.Expressions {
+	return this.probeExprs
+}
+
+func (this *HashJoin) BuildAliases() []string {
+	return this.buildAliases
+}
+
+func (this *HashJoin) Child() Operator {
+	return this.child
+}
+
+func (this *HashJoin) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"#operator": "HashJoin"}
+	r["namespace"] = this.term.Namespace()
+	r["keyspace"] = this.term.Keyspace()
+	r["on"] = expression.NewStringer().Visit(this.onclause)
+	r["build_exprs"] = marshalExprs(this.buildExprs)
+	r["probe_exprs"] = marshalExprs(this.probeExprs)
+	r["build_aliases"] = this.buildAliases
+
+	if this.outer {
+		r["outer"] = this.outer
+	}
+
+	if this.term.As() != "" {
+		r["as"] = this.term.As()
+	}
+
+	r["~child"] = this.child
+	return json.Marshal(r)
+}
+
+func (this *HashJoin) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_            string          `json:"#operator"`
+		Names        string          `json:"namespace"`
+		Keys         string          `json:"keyspace"`
+		Onclause     string          `json:"on"`
+		BuildExprs   []string        `json:"build_exprs"`
+		ProbeExprs   []string        `json:"probe_exprs"`
+		BuildAliases []string        `json:"build_aliases"`
+		Outer        bool            `json:"outer"`
+		As           string          `json:"as"`
+		Child        json.RawMessage `json:"~child"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	if err != nil {
+		return err
+	}
+
+	this.outer = _unmarshalled.Outer
+	this.buildAliases = _unmarshalled.BuildAliases
+	this.term, this.keyspace, err = unmarshalJoinTerm(_unmarshalled.Names,
+		_unmarshalled.Keys, _unmarshalled.As, "")
+	if err != nil {
+		return err
+	}
+
+	this.onclause, err = parser.Parse(_unmarshalled.Onclause)
+	if err != nil {
+		return err
+	}
+
+	this.buildExprs, err = unmarshalExprs(_unmarshalled.BuildExprs)
+	if err != nil {
+		return err
+	}
+
+	this.probeExprs, err = unmarshalExprs(_unmarshalled.ProbeExprs)
+	if err != nil {
+		return err
+	}
+
+	var op_type struct {
+		Operator string `json:"#operator"`
+	}
+
+	err = json.Unmarshal(_unmarshalled.Child, &op_type)
+	if err != nil {
+		return err
+	}
+
+	this.child, err = MakeOperator(op_type.Operator, _unmarshalled.Child)
+	return err
+}
+
+func marshalExprs(exprs expression.Expressions) []string {
+	rv := make([]string, len(exprs))
+	for i, expr := range exprs {
+		rv[i] = expression.NewStringer().Visit(expr)
+	}
+
+	return rv
+}
+
+func unmarshalExprs(strs []string) (expression.Expressions, error) {
+	rv := make(expression.Expressions, len(strs))
+	for i, s := range strs {
+		expr, err := parser.Parse(s)
+		if err != nil {
+			return nil, err
+		}
+
+		rv[i] = expr
+	}
+
+	return rv, nil
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/couchbaselabs/query/datastore"
 	"github.com/couchbaselabs/query/expression"
 	"github.com/couchbaselabs/query/expression/parser"
+	"github.com/couchbaselabs/query/planner"
 )
 
 type Join struct {
@@ -23,6 +24,9 @@ type Join struct {
 	keyspace datastore.Keyspace
 	term     *algebra.KeyspaceTerm
 	outer    bool
+	onclause expression.Expression
+	index    datastore.Index
+	spans    planner.Spans
 }
 
 func NewJoin(keyspace datastore.Keyspace, join *algebra.Join) *Join {
@@ -33,6 +37,22 @@ func NewJoin(keyspace datastore.Keyspace, join *algebra.Join) *Join {
 	}
 }
 
+// ANSI JOIN. The right-hand keys are obtained by scanning index
+// with spans evaluated against each left-hand item. If spans is
+// nil, index must be a primary index, and the whole keyspace is
+// scanned for each left-hand item.
+func NewAnsiJoin(keyspace datastore.Keyspace, join *algebra.Join,
+	index datastore.Index, spans planner.Spans) *Join {
+	return &Join{
+		keyspace: keyspace,
+		term:     join.Right(),
+		outer:    join.Outer(),
+		onclause: join.Onclause(),
+		index:    index,
+		spans:    spans,
+	}
+}
+
 func (this *Join) Accept(visitor Visitor) (interface{}, error) {
 	return visitor.VisitJoin(this)
 }
@@ -53,11 +73,35 @@ func (this *Join) Outer() bool {
 	return this.outer
 }
 
+func (this *Join) Onclause() expression.Expression {
+	return this.onclause
+}
+
+func (this *Join) Index() datastore.Index {
+	return this.index
+}
+
+func (this *Join) Spans() planner.Spans {
+	return this.spans
+}
+
 func (this *Join) MarshalJSON() ([]byte, error) {
 	r := map[string]interface{}{"#operator": "Join"}
 	r["namespace"] = this.term.Namespace()
 	r["keyspace"] = this.term.Keyspace()
-	r["on_keys"] = expression.NewStringer().Visit(this.term.Keys())
+
+	if this.onclause != nil {
+		r["on"] = expression.NewStringer().Visit(this.onclause)
+		r["index"] = this.index.Name()
+		r["using"] = this.index.Type()
+
+		// FIXME
+		if this.spans != nil {
+			r["spans"] = this.spans
+		}
+	} else {
+		r["on_keys"] = expression.NewStringer().Visit(this.term.Keys())
+	}
 
 	if this.outer {
 		r["outer"] = this.outer
@@ -71,31 +115,33 @@ func (this *Join) MarshalJSON() ([]byte, error) {
 
 func (this *Join) UnmarshalJSON(body []byte) error {
 	var _unmarshalled struct {
-		_     string `json:"#operator"`
-		Names string `json:"namespace"`
-		Keys  string `json:"keyspace"`
-		On    string `json:"on_keys"`
-		Outer bool   `json:"outer"`
-		As    string `json:"as"`
+		_        string              `json:"#operator"`
+		Names    string              `json:"namespace"`
+		Keys     string              `json:"keyspace"`
+		On       string              `json:"on_keys"`
+		Onclause string              `json:"on"`
+		Index    string              `json:"index"`
+		Using    datastore.IndexType `json:"using"`
+		Spans    planner.Spans       `json:"spans"`
+		Outer    bool                `json:"outer"`
+		As       string              `json:"as"`
 	}
-	var keys_expr expression.Expression
 
 	err := json.Unmarshal(body, &_unmarshalled)
 	if err != nil {
 		return err
 	}
 
-	if _unmarshalled.On != "" {
-		keys_expr, err = parser.Parse(_unmarshalled.On)
-		if err != nil {
-			return err
-		}
+	this.outer = _unmarshalled.Outer
+	this.term, this.keyspace, err = unmarshalJoinTerm(_unmarshalled.Names,
+		_unmarshalled.Keys, _unmarshalled.As, _unmarshalled.On)
+	if err != nil || _unmarshalled.Onclause == "" {
+		return err
 	}
 
-	this.outer = _unmarshalled.Outer
-	this.term = algebra.NewKeyspaceTerm(_unmarshalled.Names, _unmarshalled.Keys,
-		nil, _unmarshalled.As, keys_expr)
-	this.keyspace, err = datastore.GetKeyspace(_unmarshalled.Names, _unmarshalled.Keys)
+	this.spans = _unmarshalled.Spans
+	this.onclause, this.index, err = unmarshalJoinScan(this.keyspace,
+		_unmarshalled.Onclause, _unmarshalled.Index, _unmarshalled.Using)
 	return err
 }
 
@@ -104,6 +150,9 @@ type Nest struct {
 	keyspace datastore.Keyspace
 	term     *algebra.KeyspaceTerm
 	outer    bool
+	onclause expression.Expression
+	index    datastore.Index
+	spans    planner.Spans
 }
 
 func NewNest(keyspace datastore.Keyspace, nest *algebra.Nest) *Nest {
@@ -114,6 +163,22 @@ func NewNest(keyspace datastore.Keyspace, nest *algebra.Nest) *Nest {
 	}
 }
 
+// ANSI NEST. The right-hand keys are obtained by scanning index
+// with spans evaluated against each left-hand item. If spans is
+// nil, index must be a primary index, and the whole keyspace is
+// scanned for each left-hand item.
+func NewAnsiNest(keyspace datastore.Keyspace, nest *algebra.Nest,
+	index datastore.Index, spans planner.Spans) *Nest {
+	return &Nest{
+		keyspace: keyspace,
+		term:     nest.Right(),
+		outer:    nest.Outer(),
+		onclause: nest.Onclause(),
+		index:    index,
+		spans:    spans,
+	}
+}
+
 func (this *Nest) Accept(visitor Visitor) (interface{}, error) {
 	return visitor.VisitNest(this)
 }
@@ -134,11 +199,35 @@ func (this *Nest) Outer() bool {
 	return this.outer
 }
 
+func (this *Nest) Onclause() expression.Expression {
+	return this.onclause
+}
+
+func (this *Nest) Index() datastore.Index {
+	return this.index
+}
+
+func (this *Nest) Spans() planner.Spans {
+	return this.spans
+}
+
 func (this *Nest) MarshalJSON() ([]byte, error) {
 	r := map[string]interface{}{"#operator": "Nest"}
 	r["namespace"] = this.term.Namespace()
 	r["keyspace"] = this.term.Keyspace()
-	r["on_keys"] = expression.NewStringer().Visit(this.term.Keys())
+
+	if this.onclause != nil {
+		r["on"] = expression.NewStringer().Visit(this.onclause)
+		r["index"] = this.index.Name()
+		r["using"] = this.index.Type()
+
+		// FIXME
+		if this.spans != nil {
+			r["spans"] = this.spans
+		}
+	} else {
+		r["on_keys"] = expression.NewStringer().Visit(this.term.Keys())
+	}
 
 	if this.outer {
 		r["outer"] = this.outer
@@ -152,33 +241,71 @@ func (this *Nest) MarshalJSON() ([]byte, error) {
 
 func (this *Nest) UnmarshalJSON(body []byte) error {
 	var _unmarshalled struct {
-		_     string `json:"#operator"`
-		Names string `json:"namespace"`
-		Keys  string `json:"keyspace"`
-		On    string `json:"on_keys"`
-		Outer bool   `json:"outer"`
-		As    string `json:"as"`
+		_        string              `json:"#operator"`
+		Names    string              `json:"namespace"`
+		Keys     string              `json:"keyspace"`
+		On       string              `json:"on_keys"`
+		Onclause string              `json:"on"`
+		Index    string              `json:"index"`
+		Using    datastore.IndexType `json:"using"`
+		Spans    planner.Spans       `json:"spans"`
+		Outer    bool                `json:"outer"`
+		As       string              `json:"as"`
 	}
-	var keys_expr expression.Expression
 
 	err := json.Unmarshal(body, &_unmarshalled)
 	if err != nil {
 		return err
 	}
 
-	if _unmarshalled.On != "" {
-		keys_expr, err = parser.Parse(_unmarshalled.On)
+	this.outer = _unmarshalled.Outer
+	this.term, this.keyspace, err = unmarshalJoinTerm(_unmarshalled.Names,
+		_unmarshalled.Keys, _unmarshalled.As, _unmarshalled.On)
+	if err != nil || _unmarshalled.Onclause == "" {
+		return err
+	}
+
+	this.spans = _unmarshalled.Spans
+	this.onclause, this.index, err = unmarshalJoinScan(this.keyspace,
+		_unmarshalled.Onclause, _unmarshalled.Index, _unmarshalled.Using)
+	return err
+}
+
+func unmarshalJoinTerm(names, keys, as, on string) (
+	*algebra.KeyspaceTerm, datastore.Keyspace, error) {
+	var keys_expr expression.Expression
+	var err error
+
+	if on != "" {
+		keys_expr, err = parser.Parse(on)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 	}
 
-	this.outer = _unmarshalled.Outer
-	this.term = algebra.NewKeyspaceTerm(_unmarshalled.Names, _unmarshalled.Keys,
-		nil, _unmarshalled.As, keys_expr)
-	this.keyspace, err = datastore.GetKeyspace(_unmarshalled.Names, _unmarshalled.Keys)
-	return err
+	term := algebra.NewKeyspaceTerm(names, keys, nil, as, keys_expr)
+	keyspace, err := datastore.GetKeyspace(names, keys)
+	return term, keyspace, err
+}
+
+func unmarshalJoinScan(keyspace datastore.Keyspace, on, name string, using datastore.IndexType) (
+	expression.Expression, datastore.Index, error) {
+	onclause, err := parser.Parse(on)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	indexer, err := keyspace.Indexer(using)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	index, err := indexer.IndexByName(name)
+	if err != nil {
+		return nil, nil, err
+	}
 
+	return onclause, index, nil
 }
 
 type Unnest struct {
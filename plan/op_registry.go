@@ -53,13 +53,20 @@ var _OPERATORS = map[string]Operator{
 	"InitialGroup":       &InitialGroup{},
 	"IntermediateGroup":  &IntermediateGroup{},
 	"FinalGroup":         &FinalGroup{},
+	"StreamGroup":        &StreamGroup{},
+	"Window":             &Window{},
+	"With":               &With{},
 	"CreatePrimaryIndex": &CreatePrimaryIndex{},
 	"CreateIndex":        &CreateIndex{},
 	"DropIndex":          &DropIndex{},
 	"AlterIndex":         &AlterIndex{},
+	"CreateFunction":     &CreateFunction{},
+	"DropFunction":       &DropFunction{},
+	"UpdateStatistics":   &UpdateStatistics{},
 	"Insert":             &SendInsert{},
 	"IntersectAll":       &IntersectAll{},
 	"Join":               &Join{},
+	"HashJoin":           &HashJoin{},
 	"Nest":               &Nest{},
 	"Unnest":             &Unnest{},
 	"Let":                &Let{},
@@ -76,6 +83,7 @@ var _OPERATORS = map[string]Operator{
 	"KeyScan":            &KeyScan{},
 	"ParentScan":         &ParentScan{},
 	"ValueScan":          &ValueScan{},
+	"ExpressionScan":     &ExpressionScan{},
 	"CountScan":          &CountScan{},
 	"DummyScan":          &DummyScan{},
 	"IntersectScan":      &IntersectScan{},
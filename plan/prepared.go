@@ -10,11 +10,18 @@
 package plan
 
 import (
+	"container/list"
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/couchbaselabs/query/accounting"
 	"github.com/couchbaselabs/query/algebra"
 	"github.com/couchbaselabs/query/datastore"
 	"github.com/couchbaselabs/query/value"
@@ -27,13 +34,26 @@ func BuildPrepared(stmt algebra.Statement, datastore, systemstore datastore.Data
 		return nil, err
 	}
 
+	// EXECUTE returns the prepared statement being executed
+	if prepared, ok := operator.(*Prepared); ok {
+		return prepared, nil
+	}
+
 	signature := stmt.Signature()
-	return newPrepared(operator, signature), nil
+	rv := newPrepared(operator, signature)
+	rv.statementType = StatementType(stmt)
+	rv.keyspaces = statementKeyspaces(stmt, namespace)
+	return rv, nil
 }
 
 type Prepared struct {
 	Operator
-	signature value.Value
+	signature     value.Value
+	name          string
+	text          string
+	statementType string
+	keyspaces     []string
+	stale         int32
 }
 
 func newPrepared(operator Operator, signature value.Value) *Prepared {
@@ -47,6 +67,18 @@ func (this *Prepared) MarshalJSON() ([]byte, error) {
 	r := make(map[string]interface{}, 2)
 	r["operator"] = this.Operator
 	r["signature"] = this.signature
+	if this.name != "" {
+		r["name"] = this.name
+	}
+	if this.text != "" {
+		r["text"] = this.text
+	}
+	if this.statementType != "" {
+		r["statement_type"] = this.statementType
+	}
+	if len(this.keyspaces) > 0 {
+		r["keyspaces"] = this.keyspaces
+	}
 
 	return json.Marshal(r)
 }
@@ -55,6 +87,8 @@ func (this *Prepared) UnmarshalJSON(body []byte) error {
 	var _unmarshalled struct {
 		Operator  json.RawMessage `json:"operator"`
 		Signature json.RawMessage `json:"signature"`
+		Name      string          `json:"name"`
+		Text      string          `json:"text"`
 	}
 
 	var op_type struct {
@@ -72,8 +106,21 @@ func (this *Prepared) UnmarshalJSON(body []byte) error {
 	}
 
 	this.signature = value.NewValue(_unmarshalled.Signature)
+	this.name = _unmarshalled.Name
+	this.text = _unmarshalled.Text
 	this.Operator, err = MakeOperator(op_type.Operator, _unmarshalled.Operator)
+	if err != nil {
+		return err
+	}
+
+	// The statement type and keyspaces given by the client are not
+	// trusted; they are determined from the operators of the plan
+	this.statementType = operatorStatementType(this.Operator)
+	if this.statementType == "" {
+		this.statementType = "SELECT"
+	}
 
+	this.keyspaces, err = planKeyspaces(_unmarshalled.Operator)
 	return err
 }
 
@@ -81,29 +128,266 @@ func (this *Prepared) Signature() value.Value {
 	return this.signature
 }
 
+// The name given in PREPARE name FROM ..., if any
+func (this *Prepared) Name() string {
+	return this.name
+}
+
+func (this *Prepared) SetName(name string) {
+	this.name = name
+}
+
+// The text of the PREPARE statement
+func (this *Prepared) Text() string {
+	return this.text
+}
+
+func (this *Prepared) SetText(text string) {
+	this.text = text
+}
+
+// The type of the prepared statement, e.g. SELECT or INSERT
+func (this *Prepared) StatementType() string {
+	return this.statementType
+}
+
+// The leading keyword of a statement, e.g. SELECT for a SELECT with a
+// WITH clause
+func StatementType(stmt algebra.Statement) string {
+	switch stmt.(type) {
+	case *algebra.Select:
+		return "SELECT"
+	case *algebra.Insert:
+		return "INSERT"
+	case *algebra.Upsert:
+		return "UPSERT"
+	case *algebra.Delete:
+		return "DELETE"
+	case *algebra.Update, *algebra.UpdateStatistics:
+		return "UPDATE"
+	case *algebra.Merge:
+		return "MERGE"
+	case *algebra.CreatePrimaryIndex, *algebra.CreateIndex, *algebra.CreateFunction:
+		return "CREATE"
+	case *algebra.DropIndex, *algebra.DropFunction:
+		return "DROP"
+	case *algebra.AlterIndex:
+		return "ALTER"
+	case *algebra.BuildIndexes:
+		return "BUILD"
+	case *algebra.Explain:
+		return "EXPLAIN"
+	case *algebra.Prepare:
+		return "PREPARE"
+	case *algebra.Execute:
+		return "EXECUTE"
+	default:
+		return ""
+	}
+}
+
+// The type of the statement executed by a plan, from the operators
+// that modify data or indexes, or an empty string if there are none
+func operatorStatementType(op Operator) string {
+	switch op := op.(type) {
+	case *Sequence:
+		for _, child := range op.Children() {
+			if stmtType := operatorStatementType(child); stmtType != "" {
+				return stmtType
+			}
+		}
+	case *Parallel:
+		return operatorStatementType(op.Child())
+	case *Authorize:
+		return operatorStatementType(op.Child())
+	case *SendInsert:
+		return "INSERT"
+	case *SendUpsert:
+		return "UPSERT"
+	case *SendDelete:
+		return "DELETE"
+	case *SendUpdate, *UpdateStatistics:
+		return "UPDATE"
+	case *Merge:
+		return "MERGE"
+	case *CreatePrimaryIndex, *CreateIndex, *CreateFunction:
+		return "CREATE"
+	case *DropIndex, *DropFunction:
+		return "DROP"
+	case *AlterIndex:
+		return "ALTER"
+	case *BuildIndexes:
+		return "BUILD"
+	case *Explain:
+		return "EXPLAIN"
+	case *Prepare:
+		return "PREPARE"
+	}
+
+	return ""
+}
+
+// The keyspaces used by the prepared statement, as namespace:keyspace
+func (this *Prepared) Keyspaces() []string {
+	return this.keyspaces
+}
+
+// The keyspaces of a statement, including those of its subqueries,
+// after the statement has been planned with the default namespace
+func statementKeyspaces(stmt algebra.Statement, namespace string) []string {
+	privs, err := stmt.Privileges()
+	if err != nil {
+		return nil
+	}
+
+	rv := make([]string, 0, len(privs))
+	for name, _ := range privs {
+		if strings.HasPrefix(name, ":") {
+			name = namespace + name
+		}
+
+		rv = append(rv, name)
+	}
+
+	// Sorted, so that the same statement has the same JSON
+	sort.Strings(rv)
+	return rv
+}
+
+// The keyspaces named by the operators of a JSON plan, as
+// namespace:keyspace. Keyspaces used only by subqueries are not
+// included, as subqueries are expressions of the operators.
+func planKeyspaces(body []byte) ([]string, error) {
+	var plan interface{}
+	err := json.Unmarshal(body, &plan)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	collectKeyspaces(plan, names)
+
+	rv := make([]string, 0, len(names))
+	for name, _ := range names {
+		rv = append(rv, name)
+	}
+
+	sort.Strings(rv)
+	return rv, nil
+}
+
+func collectKeyspaces(plan interface{}, names map[string]bool) {
+	switch plan := plan.(type) {
+	case map[string]interface{}:
+		namespace, _ := plan["namespace"].(string)
+		keyspace, _ := plan["keyspace"].(string)
+		if namespace != "" && keyspace != "" {
+			names[namespace+":"+keyspace] = true
+		}
+
+		for _, child := range plan {
+			collectKeyspaces(child, names)
+		}
+	case []interface{}:
+		for _, child := range plan {
+			collectKeyspaces(child, names)
+		}
+	}
+}
+
+// Whether an index of a keyspace used by the prepared statement has
+// been created, dropped or altered since it was prepared
+func (this *Prepared) Stale() bool {
+	return atomic.LoadInt32(&this.stale) != 0
+}
+
+const DEFAULT_PREPARED_LIMIT = 16384
+
+// An LRU cache of prepared statements. Unnamed prepared statements
+// are keyed by the digest of their JSON representation, and named
+// ones by their name.
 type cacheType struct {
-	sync.RWMutex
-	prepareds map[string]*Prepared
+	sync.Mutex
+	limit     int
+	prepareds map[string]*cacheEntry
+	named     map[string]*cacheEntry
+	lru       *list.List
+	metrics   accounting.MetricRegistry
+}
+
+// A cached prepared statement and its usage
+type cacheEntry struct {
+	key      string
+	named    bool
+	prepared *Prepared
+	uses     int64
+	lastUse  time.Time
+	elem     *list.Element
+}
+
+// A snapshot of a cached prepared statement and its usage
+type CachedPrepared struct {
+	Key       string
+	Prepared  *Prepared
+	Keyspaces []string
+	Uses      int64
+	LastUse   time.Time
 }
 
 var preparedCache = &cacheType{
-	prepareds: make(map[string]*Prepared),
+	limit:     DEFAULT_PREPARED_LIMIT,
+	prepareds: make(map[string]*cacheEntry),
+	named:     make(map[string]*cacheEntry),
+	lru:       list.New(),
 }
 
 func PreparedCache() *cacheType {
 	return preparedCache
 }
 
+// Set the maximum number of cached prepared statements; use zero or
+// a negative value to disable the limit
+func (this *cacheType) SetLimit(limit int) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.limit = limit
+	this.evict()
+}
+
+func (this *cacheType) Limit() int {
+	this.Lock()
+	defer this.Unlock()
+
+	return this.limit
+}
+
+// Record cache hits, misses and evictions in the given registry
+func (this *cacheType) SetMetrics(metrics accounting.MetricRegistry) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.metrics = metrics
+}
+
 func (this *cacheType) GetPrepared(value value.Value) (*Prepared, error) {
 	json_bytes, err := value.MarshalJSON()
 	if err != nil {
 		return nil, err
 	}
 	key := makeKey(json_bytes)
-	this.RLock()
-	prepared := this.prepareds[key]
-	this.RUnlock()
-	return prepared, nil
+
+	this.Lock()
+	defer this.Unlock()
+
+	entry, ok := this.prepareds[key]
+	if !ok {
+		this.record(accounting.PREPARED_CACHE_MISSES)
+		return nil, nil
+	}
+
+	this.use(entry)
+	return entry.prepared, nil
 }
 
 func (this *cacheType) AddPrepared(plan *Prepared) error {
@@ -112,9 +396,11 @@ func (this *cacheType) AddPrepared(plan *Prepared) error {
 		return err
 	}
 	key := makeKey(json_bytes)
+
 	this.Lock()
-	this.prepareds[key] = plan
-	this.Unlock()
+	defer this.Unlock()
+
+	this.add(this.prepareds, key, false, plan)
 	return nil
 }
 
@@ -123,3 +409,212 @@ func makeKey(body []byte) string {
 	hasher.Write(body)
 	return hex.EncodeToString(hasher.Sum(nil))
 }
+
+// Register a named prepared statement. A name can only be reused to
+// prepare the same statement text again.
+func (this *cacheType) AddNamed(plan *Prepared) error {
+	this.Lock()
+	defer this.Unlock()
+
+	entry, ok := this.named[plan.name]
+	if ok && entry.prepared.text != plan.text {
+		return fmt.Errorf("Prepared statement %s already exists with a different statement.", plan.name)
+	}
+
+	this.add(this.named, plan.name, true, plan)
+	return nil
+}
+
+// Return a named prepared statement, and record its use
+func (this *cacheType) GetNamed(name string) *Prepared {
+	this.Lock()
+	defer this.Unlock()
+
+	entry, ok := this.named[name]
+	if !ok {
+		this.record(accounting.PREPARED_CACHE_MISSES)
+		return nil
+	}
+
+	this.use(entry)
+	return entry.prepared
+}
+
+func (this *cacheType) Names() []string {
+	this.Lock()
+	defer this.Unlock()
+
+	rv := make([]string, 0, len(this.named))
+	for name, _ := range this.named {
+		rv = append(rv, name)
+	}
+
+	return rv
+}
+
+// Return a named prepared statement and its usage, without
+// recording a use
+func (this *cacheType) Named(name string) (*CachedPrepared, bool) {
+	this.Lock()
+	defer this.Unlock()
+
+	entry, ok := this.named[name]
+	if !ok {
+		return nil, false
+	}
+
+	return entry.snapshot(), true
+}
+
+// Return all cached prepared statements, most recently used first
+func (this *cacheType) Entries() []*CachedPrepared {
+	this.Lock()
+	defer this.Unlock()
+
+	rv := make([]*CachedPrepared, 0, this.lru.Len())
+	for elem := this.lru.Front(); elem != nil; elem = elem.Next() {
+		rv = append(rv, elem.Value.(*cacheEntry).snapshot())
+	}
+
+	return rv
+}
+
+// Return the cached prepared statement with the given name or key
+func (this *cacheType) Entry(id string) (*CachedPrepared, bool) {
+	this.Lock()
+	defer this.Unlock()
+
+	entry := this.lookup(id)
+	if entry == nil {
+		return nil, false
+	}
+
+	return entry.snapshot(), true
+}
+
+// Remove the cached prepared statement with the given name or key
+func (this *cacheType) Delete(id string) bool {
+	this.Lock()
+	defer this.Unlock()
+
+	entry := this.lookup(id)
+	if entry == nil {
+		return false
+	}
+
+	this.remove(entry)
+	return true
+}
+
+// Mark the cached prepared statements that use the given keyspace as
+// stale, so that they are prepared again before their next execution
+func (this *cacheType) InvalidateKeyspace(namespace, keyspace string) {
+	name := namespace + ":" + keyspace
+
+	this.Lock()
+	defer this.Unlock()
+
+	for elem := this.lru.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*cacheEntry)
+		for _, keyspace := range entry.prepared.keyspaces {
+			if keyspace == name {
+				atomic.StoreInt32(&entry.prepared.stale, 1)
+				break
+			}
+		}
+	}
+}
+
+// Replace a stale prepared statement with one prepared again from the
+// same text. Unnamed prepared statements keep their original key, so
+// that clients holding the stale plan get the new one.
+func (this *cacheType) Replace(stale, plan *Prepared) error {
+	if stale.name != "" {
+		return this.AddNamed(plan)
+	}
+
+	stale_bytes, err := stale.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	json_bytes, err := plan.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	this.Lock()
+	defer this.Unlock()
+
+	this.add(this.prepareds, makeKey(stale_bytes), false, plan)
+	this.add(this.prepareds, makeKey(json_bytes), false, plan)
+	return nil
+}
+
+func (this *cacheType) lookup(id string) *cacheEntry {
+	entry, ok := this.named[id]
+	if !ok {
+		entry = this.prepareds[id]
+	}
+
+	return entry
+}
+
+func (this *cacheType) add(entries map[string]*cacheEntry, key string, named bool,
+	plan *Prepared) {
+	entry, ok := entries[key]
+	if ok {
+		entry.prepared = plan
+		this.lru.MoveToFront(entry.elem)
+		return
+	}
+
+	entry = &cacheEntry{
+		key:      key,
+		named:    named,
+		prepared: plan,
+	}
+	entry.elem = this.lru.PushFront(entry)
+	entries[key] = entry
+	this.evict()
+}
+
+func (this *cacheType) use(entry *cacheEntry) {
+	entry.uses++
+	entry.lastUse = time.Now()
+	this.lru.MoveToFront(entry.elem)
+	this.record(accounting.PREPARED_CACHE_HITS)
+}
+
+func (this *cacheType) remove(entry *cacheEntry) {
+	this.lru.Remove(entry.elem)
+	if entry.named {
+		delete(this.named, entry.key)
+	} else {
+		delete(this.prepareds, entry.key)
+	}
+}
+
+// Evict the least recently used entries beyond the limit
+func (this *cacheType) evict() {
+	for this.limit > 0 && this.lru.Len() > this.limit {
+		this.remove(this.lru.Back().Value.(*cacheEntry))
+		this.record(accounting.PREPARED_CACHE_EVICTIONS)
+	}
+}
+
+func (this *cacheType) record(name string) {
+	if this.metrics != nil {
+		this.metrics.Counter(name).Inc(1)
+	}
+}
+
+func (this *cacheEntry) snapshot() *CachedPrepared {
+	return &CachedPrepared{
+		Key:       this.key,
+		Prepared:  this.prepared,
+		Keyspaces: this.prepared.keyspaces,
+		Uses:      this.uses,
+		LastUse:   this.lastUse,
+	}
+}
@@ -0,0 +1,225 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package plan
+
+import (
+	"container/list"
+	"reflect"
+	"testing"
+
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/datastore/mock"
+	"github.com/couchbaselabs/query/parser/n1ql"
+	"github.com/couchbaselabs/query/value"
+)
+
+func newTestCache(limit int) *cacheType {
+	return &cacheType{
+		limit:     limit,
+		prepareds: make(map[string]*cacheEntry),
+		named:     make(map[string]*cacheEntry),
+		lru:       list.New(),
+	}
+}
+
+func newTestPrepared(name, text string, keyspaces ...string) *Prepared {
+	rv := newPrepared(NewDummyScan(), value.NewValue(text))
+	rv.name = name
+	rv.text = text
+	rv.keyspaces = keyspaces
+	return rv
+}
+
+func cacheKeys(cache *cacheType) []string {
+	entries := cache.Entries()
+	rv := make([]string, len(entries))
+	for i, entry := range entries {
+		rv[i] = entry.Key
+	}
+
+	return rv
+}
+
+func TestPreparedCacheEviction(t *testing.T) {
+	cache := newTestCache(3)
+
+	for _, name := range []string{"a", "b", "c"} {
+		err := cache.AddNamed(newTestPrepared(name, "PREPARE "+name))
+		if err != nil {
+			t.Fatalf("failed to add %s: %v", name, err)
+		}
+	}
+
+	// Using a moves it to the front, so b is the least recently used
+	if cache.GetNamed("a") == nil {
+		t.Fatalf("expected a to be cached")
+	}
+
+	err := cache.AddNamed(newTestPrepared("d", "PREPARE d"))
+	if err != nil {
+		t.Fatalf("failed to add d: %v", err)
+	}
+
+	expected := []string{"d", "a", "c"}
+	if keys := cacheKeys(cache); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected %v, got %v", expected, keys)
+	}
+
+	cache.SetLimit(1)
+	expected = []string{"d"}
+	if keys := cacheKeys(cache); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected %v after lowering the limit, got %v", expected, keys)
+	}
+
+	// No limit
+	cache.SetLimit(0)
+	for _, name := range []string{"e", "f", "g"} {
+		cache.AddNamed(newTestPrepared(name, "PREPARE "+name))
+	}
+
+	if n := len(cache.Entries()); n != 4 {
+		t.Errorf("expected 4 entries without a limit, got %d", n)
+	}
+}
+
+func TestPreparedCacheUnnamed(t *testing.T) {
+	cache := newTestCache(DEFAULT_PREPARED_LIMIT)
+	prepared := newTestPrepared("", "SELECT 1")
+
+	err := cache.AddPrepared(prepared)
+	if err != nil {
+		t.Fatalf("failed to add prepared: %v", err)
+	}
+
+	json_bytes, err := prepared.MarshalJSON()
+	if err != nil {
+		t.Fatalf("failed to marshal prepared: %v", err)
+	}
+
+	rv, err := cache.GetPrepared(value.NewValue(json_bytes))
+	if err != nil || rv != prepared {
+		t.Fatalf("expected the cached prepared, got %v, %v", rv, err)
+	}
+
+	entry, ok := cache.Entry(makeKey(json_bytes))
+	if !ok || entry.Uses != 1 {
+		t.Fatalf("expected an entry with 1 use, got %v", entry)
+	}
+
+	if !cache.Delete(entry.Key) {
+		t.Errorf("expected to delete %s", entry.Key)
+	}
+
+	if cache.Delete(entry.Key) {
+		t.Errorf("expected %s to be deleted already", entry.Key)
+	}
+}
+
+func TestPreparedCacheNamed(t *testing.T) {
+	cache := newTestCache(DEFAULT_PREPARED_LIMIT)
+
+	err := cache.AddNamed(newTestPrepared("p", "PREPARE p FROM SELECT 1"))
+	if err != nil {
+		t.Fatalf("failed to add p: %v", err)
+	}
+
+	// The same name can be prepared again from the same text only
+	err = cache.AddNamed(newTestPrepared("p", "PREPARE p FROM SELECT 1"))
+	if err != nil {
+		t.Errorf("expected p to be prepared again, got %v", err)
+	}
+
+	err = cache.AddNamed(newTestPrepared("p", "PREPARE p FROM SELECT 2"))
+	if err == nil {
+		t.Errorf("expected an error preparing p from a different statement")
+	}
+
+	// Named does not record a use, GetNamed does
+	entry, ok := cache.Named("p")
+	if !ok || entry.Uses != 0 {
+		t.Fatalf("expected an unused entry, got %v", entry)
+	}
+
+	cache.GetNamed("p")
+	entry, _ = cache.Named("p")
+	if entry.Uses != 1 || entry.LastUse.IsZero() {
+		t.Errorf("expected 1 use, got %d", entry.Uses)
+	}
+}
+
+func TestPreparedCacheInvalidateKeyspace(t *testing.T) {
+	cache := newTestCache(DEFAULT_PREPARED_LIMIT)
+	orders := newTestPrepared("orders", "PREPARE orders", "default:orders")
+	both := newTestPrepared("both", "PREPARE both", "default:customers", "default:orders")
+	customers := newTestPrepared("customers", "PREPARE customers", "default:customers")
+
+	for _, prepared := range []*Prepared{orders, both, customers} {
+		cache.AddNamed(prepared)
+	}
+
+	cache.InvalidateKeyspace("default", "orders")
+
+	if !orders.Stale() || !both.Stale() {
+		t.Errorf("expected the prepareds using default:orders to be stale")
+	}
+
+	if customers.Stale() {
+		t.Errorf("expected the prepared not using default:orders not to be stale")
+	}
+
+	// Replacing a stale prepared keeps its name
+	fresh := newTestPrepared("orders", "PREPARE orders", "default:orders")
+	err := cache.Replace(orders, fresh)
+	if err != nil {
+		t.Fatalf("failed to replace orders: %v", err)
+	}
+
+	if rv := cache.GetNamed("orders"); rv != fresh || rv.Stale() {
+		t.Errorf("expected the fresh prepared, got %v", rv)
+	}
+}
+
+func TestPreparedKeyspaces(t *testing.T) {
+	store, e := mock.NewDatastore("mock:namespaces=2,keyspaces=2,items=10")
+	if e != nil {
+		t.Fatalf("failed to create mock store: %v", e)
+	}
+
+	stmt, err := n1ql.ParseStatement("SELECT * FROM b1 WHERE b1.a IN (SELECT RAW a FROM p1:b0)")
+	if err != nil {
+		t.Fatalf("failed to parse statement: %v", err)
+	}
+
+	prepared, err := BuildPrepared(stmt, store, nil, "p0", false)
+	if err != nil {
+		t.Fatalf("failed to build prepared: %v", err)
+	}
+
+	expected := []string{"p0:b1", "p1:b0"}
+	if keyspaces := prepared.Keyspaces(); !reflect.DeepEqual(keyspaces, expected) {
+		t.Errorf("expected %v, got %v", expected, keyspaces)
+	}
+
+	// A decoded plan takes its keyspaces from its operators, and not
+	// from the JSON
+	datastore.SetDatastore(store)
+	rv := &Prepared{}
+	err = rv.UnmarshalJSON([]byte(`{"operator": {"#operator": "Sequence", "~children": [
+		{"#operator": "PrimaryScan", "namespace": "p0", "keyspace": "b1", "index": "#primary"},
+		{"#operator": "Fetch", "namespace": "p1", "keyspace": "b0"}]},
+		"keyspaces": ["p1:b1"]}`))
+	if err != nil {
+		t.Fatalf("failed to unmarshal prepared: %v", err)
+	}
+
+	if !reflect.DeepEqual(rv.Keyspaces(), expected) {
+		t.Errorf("expected %v after unmarshaling, got %v", expected, rv.Keyspaces())
+	}
+}
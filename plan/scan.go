@@ -12,6 +12,7 @@ package plan
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 
 	"github.com/couchbaselabs/query/algebra"
 	"github.com/couchbaselabs/query/datastore"
@@ -24,12 +25,14 @@ type PrimaryScan struct {
 	readonly
 	index datastore.PrimaryIndex
 	term  *algebra.KeyspaceTerm
+	limit int64
 }
 
-func NewPrimaryScan(index datastore.PrimaryIndex, term *algebra.KeyspaceTerm) *PrimaryScan {
+func NewPrimaryScan(index datastore.PrimaryIndex, term *algebra.KeyspaceTerm, limit int64) *PrimaryScan {
 	return &PrimaryScan{
 		index: index,
 		term:  term,
+		limit: limit,
 	}
 }
 
@@ -49,12 +52,21 @@ func (this *PrimaryScan) Term() *algebra.KeyspaceTerm {
 	return this.term
 }
 
+func (this *PrimaryScan) Limit() int64 {
+	return this.limit
+}
+
 func (this *PrimaryScan) MarshalJSON() ([]byte, error) {
 	r := map[string]interface{}{"#operator": "PrimaryScan"}
 	r["index"] = this.index.Name()
 	r["namespace"] = this.term.Namespace()
 	r["keyspace"] = this.term.Keyspace()
 	r["using"] = this.index.Type()
+
+	if this.limit < math.MaxInt64 {
+		r["limit"] = this.limit
+	}
+
 	return json.Marshal(r)
 }
 
@@ -65,6 +77,7 @@ func (this *PrimaryScan) UnmarshalJSON(body []byte) error {
 		Names string              `json:"namespace"`
 		Keys  string              `json:"keyspace"`
 		Using datastore.IndexType `json:"using"`
+		Limit int64               `json:"limit"`
 	}
 
 	err := json.Unmarshal(body, &_unmarshalled)
@@ -81,6 +94,11 @@ func (this *PrimaryScan) UnmarshalJSON(body []byte) error {
 		_unmarshalled.Names, _unmarshalled.Keys,
 		nil, "", nil)
 
+	this.limit = _unmarshalled.Limit
+	if this.limit <= 0 {
+		this.limit = math.MaxInt64
+	}
+
 	indexer, err := k.Indexer(_unmarshalled.Using)
 	if err != nil {
 		return err
@@ -106,17 +124,24 @@ type IndexScan struct {
 	term     *algebra.KeyspaceTerm
 	spans    planner.Spans
 	distinct bool
+	reverse  bool
 	limit    int64
+	covers   expression.Expressions
 }
 
-func NewIndexScan(index datastore.Index, term *algebra.KeyspaceTerm,
-	spans planner.Spans, distinct bool, limit int64) *IndexScan {
+// The covers of a covering scan are the index keys followed by the
+// primary key. They are nil if the scan does not cover the query. A
+// reverse scan requires an index that implements ReverseIndex.
+func NewIndexScan(index datastore.Index, term *algebra.KeyspaceTerm, spans planner.Spans,
+	distinct, reverse bool, limit int64, covers expression.Expressions) *IndexScan {
 	return &IndexScan{
 		index:    index,
 		term:     term,
 		spans:    spans,
 		distinct: distinct,
+		reverse:  reverse,
 		limit:    limit,
+		covers:   covers,
 	}
 }
 
@@ -144,10 +169,18 @@ func (this *IndexScan) Distinct() bool {
 	return this.distinct
 }
 
+func (this *IndexScan) Reverse() bool {
+	return this.reverse
+}
+
 func (this *IndexScan) Limit() int64 {
 	return this.limit
 }
 
+func (this *IndexScan) Covers() expression.Expressions {
+	return this.covers
+}
+
 func (this *IndexScan) MarshalJSON() ([]byte, error) {
 	r := map[string]interface{}{"#operator": "IndexScan"}
 	r["index"] = this.index.Name()
@@ -162,10 +195,22 @@ func (this *IndexScan) MarshalJSON() ([]byte, error) {
 		r["distinct"] = this.distinct
 	}
 
+	if this.reverse {
+		r["reverse"] = this.reverse
+	}
+
 	if this.limit >= 0 {
 		r["limit"] = this.limit
 	}
 
+	if this.covers != nil {
+		covers := make([]string, len(this.covers))
+		for i, c := range this.covers {
+			covers[i] = expression.NewStringer().Visit(c)
+		}
+		r["covers"] = covers
+	}
+
 	return json.Marshal(r)
 }
 
@@ -178,7 +223,9 @@ func (this *IndexScan) UnmarshalJSON(body []byte) error {
 		Using    datastore.IndexType `json:"using"`
 		Spans    planner.Spans       `json:"spans"`
 		Distinct bool                `json:"distinct"`
+		Reverse  bool                `json:"reverse"`
 		Limit    int64               `json:"limit"`
+		Covers   []string            `json:"covers"`
 	}
 
 	err := json.Unmarshal(body, &_unmarshalled)
@@ -196,8 +243,19 @@ func (this *IndexScan) UnmarshalJSON(body []byte) error {
 		nil, "", nil)
 	this.spans = _unmarshalled.Spans
 	this.distinct = _unmarshalled.Distinct
+	this.reverse = _unmarshalled.Reverse
 	this.limit = _unmarshalled.Limit
 
+	if _unmarshalled.Covers != nil {
+		this.covers = make(expression.Expressions, len(_unmarshalled.Covers))
+		for i, c := range _unmarshalled.Covers {
+			this.covers[i], err = parser.Parse(c)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	indexer, err := k.Indexer(_unmarshalled.Using)
 	if err != nil {
 		return err
@@ -367,6 +425,65 @@ func (this *DummyScan) UnmarshalJSON([]byte) error {
 	return nil
 }
 
+// ExpressionScan is used for FROM terms that name a WITH term. It
+// scans the elements of the value of an expression.
+type ExpressionScan struct {
+	readonly
+	expr  expression.Expression
+	alias string
+}
+
+func NewExpressionScan(expr expression.Expression, alias string) *ExpressionScan {
+	return &ExpressionScan{
+		expr:  expr,
+		alias: alias,
+	}
+}
+
+func (this *ExpressionScan) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitExpressionScan(this)
+}
+
+func (this *ExpressionScan) New() Operator {
+	return &ExpressionScan{}
+}
+
+func (this *ExpressionScan) Expression() expression.Expression {
+	return this.expr
+}
+
+func (this *ExpressionScan) Alias() string {
+	return this.alias
+}
+
+func (this *ExpressionScan) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"#operator": "ExpressionScan"}
+	r["expr"] = expression.NewStringer().Visit(this.expr)
+	r["as"] = this.alias
+	return json.Marshal(r)
+}
+
+func (this *ExpressionScan) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_     string `json:"#operator"`
+		Expr  string `json:"expr"`
+		Alias string `json:"as"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	if err != nil {
+		return err
+	}
+
+	this.expr, err = parser.Parse(_unmarshalled.Expr)
+	if err != nil {
+		return err
+	}
+
+	this.alias = _unmarshalled.Alias
+	return nil
+}
+
 // CountScan is used for SELECT COUNT(*) with no WHERE clause.
 type CountScan struct {
 	readonly
@@ -422,14 +539,17 @@ func (this *CountScan) UnmarshalJSON(body []byte) error {
 }
 
 // IntersectScan scans multiple indexes and intersects the results.
+// Each scan covers a conjunct of the query condition.
 type IntersectScan struct {
 	readonly
-	scans []Operator
+	scans     []Operator
+	conjuncts expression.Expressions
 }
 
-func NewIntersectScan(scans ...Operator) *IntersectScan {
+func NewIntersectScan(scans []Operator, conjuncts expression.Expressions) *IntersectScan {
 	return &IntersectScan{
-		scans: scans,
+		scans:     scans,
+		conjuncts: conjuncts,
 	}
 }
 
@@ -445,19 +565,32 @@ func (this *IntersectScan) Scans() []Operator {
 	return this.scans
 }
 
+func (this *IntersectScan) Conjuncts() expression.Expressions {
+	return this.conjuncts
+}
+
 func (this *IntersectScan) MarshalJSON() ([]byte, error) {
 	r := map[string]interface{}{"#operator": "IntersectScan"}
 
 	// FIXME
 	r["scans"] = this.scans
 
+	if this.conjuncts != nil {
+		conjuncts := make([]string, len(this.conjuncts))
+		for i, c := range this.conjuncts {
+			conjuncts[i] = expression.NewStringer().Visit(c)
+		}
+		r["conjuncts"] = conjuncts
+	}
+
 	return json.Marshal(r)
 }
 
 func (this *IntersectScan) UnmarshalJSON(body []byte) error {
 	var _unmarshalled struct {
-		_     string            `json:"#operator"`
-		Scans []json.RawMessage `json:"scans"`
+		_         string            `json:"#operator"`
+		Scans     []json.RawMessage `json:"scans"`
+		Conjuncts []string          `json:"conjuncts"`
 	}
 	err := json.Unmarshal(body, &_unmarshalled)
 	if err != nil {
@@ -495,6 +628,16 @@ func (this *IntersectScan) UnmarshalJSON(body []byte) error {
 		}
 	}
 
+	if _unmarshalled.Conjuncts != nil {
+		this.conjuncts = make(expression.Expressions, len(_unmarshalled.Conjuncts))
+		for i, c := range _unmarshalled.Conjuncts {
+			this.conjuncts[i], err = parser.Parse(c)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	return err
 }
 
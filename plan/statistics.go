@@ -0,0 +1,83 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/couchbaselabs/query/algebra"
+	"github.com/couchbaselabs/query/datastore"
+)
+
+// Update statistics
+type UpdateStatistics struct {
+	readwrite
+	keyspace datastore.StatisticsKeyspace
+	node     *algebra.UpdateStatistics
+}
+
+func NewUpdateStatistics(keyspace datastore.StatisticsKeyspace, node *algebra.UpdateStatistics) *UpdateStatistics {
+	return &UpdateStatistics{
+		keyspace: keyspace,
+		node:     node,
+	}
+}
+
+func (this *UpdateStatistics) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitUpdateStatistics(this)
+}
+
+func (this *UpdateStatistics) New() Operator {
+	return &UpdateStatistics{}
+}
+
+func (this *UpdateStatistics) Keyspace() datastore.StatisticsKeyspace {
+	return this.keyspace
+}
+
+func (this *UpdateStatistics) Node() *algebra.UpdateStatistics {
+	return this.node
+}
+
+func (this *UpdateStatistics) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"#operator": "UpdateStatistics"}
+	r["keyspace"] = this.keyspace.Name()
+	r["namespace"] = this.keyspace.NamespaceId()
+	r["node"] = this.node
+	return json.Marshal(r)
+}
+
+func (this *UpdateStatistics) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_     string                    `json:"#operator"`
+		Keys  string                    `json:"keyspace"`
+		Names string                    `json:"namespace"`
+		Node  *algebra.UpdateStatistics `json:"node"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	if err != nil {
+		return err
+	}
+
+	keyspace, err := datastore.GetKeyspace(_unmarshalled.Names, _unmarshalled.Keys)
+	if err != nil {
+		return err
+	}
+
+	skeyspace, ok := keyspace.(datastore.StatisticsKeyspace)
+	if !ok {
+		return fmt.Errorf("Keyspace %s does not support statistics.", keyspace.Name())
+	}
+
+	this.keyspace = skeyspace
+	return nil
+}
@@ -17,6 +17,7 @@ type Visitor interface {
 	VisitKeyScan(op *KeyScan) (interface{}, error)
 	VisitValueScan(op *ValueScan) (interface{}, error)
 	VisitDummyScan(op *DummyScan) (interface{}, error)
+	VisitExpressionScan(op *ExpressionScan) (interface{}, error)
 	VisitCountScan(op *CountScan) (interface{}, error)
 	VisitIntersectScan(op *IntersectScan) (interface{}, error)
 	VisitUnionScan(op *UnionScan) (interface{}, error)
@@ -27,6 +28,7 @@ type Visitor interface {
 	// Join
 	VisitJoin(op *Join) (interface{}, error)
 	VisitNest(op *Nest) (interface{}, error)
+	VisitHashJoin(op *HashJoin) (interface{}, error)
 	VisitUnnest(op *Unnest) (interface{}, error)
 
 	// Let + Letting
@@ -39,6 +41,10 @@ type Visitor interface {
 	VisitInitialGroup(op *InitialGroup) (interface{}, error)
 	VisitIntermediateGroup(op *IntermediateGroup) (interface{}, error)
 	VisitFinalGroup(op *FinalGroup) (interface{}, error)
+	VisitStreamGroup(op *StreamGroup) (interface{}, error)
+
+	// Window
+	VisitWindow(op *Window) (interface{}, error)
 
 	// Project
 	VisitInitialProject(op *InitialProject) (interface{}, error)
@@ -47,6 +53,9 @@ type Visitor interface {
 	// Distinct
 	VisitDistinct(op *Distinct) (interface{}, error)
 
+	// With
+	VisitWith(op *With) (interface{}, error)
+
 	// Set operators
 	VisitUnionAll(op *UnionAll) (interface{}, error)
 	VisitIntersectAll(op *IntersectAll) (interface{}, error)
@@ -94,6 +103,13 @@ type Visitor interface {
 	VisitAlterIndex(op *AlterIndex) (interface{}, error)
 	VisitBuildIndexes(op *BuildIndexes) (interface{}, error)
 
+	// Function DDL
+	VisitCreateFunction(op *CreateFunction) (interface{}, error)
+	VisitDropFunction(op *DropFunction) (interface{}, error)
+
+	// Statistics
+	VisitUpdateStatistics(op *UpdateStatistics) (interface{}, error)
+
 	// Explain
 	VisitExplain(op *Explain) (interface{}, error)
 
@@ -0,0 +1,76 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package plan
+
+import (
+	"encoding/json"
+
+	"github.com/couchbaselabs/query/algebra"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/expression/parser"
+)
+
+// Computation of window functions. Runs after grouping and before the
+// projection, over all the input items. Serial.
+type Window struct {
+	readonly
+	functions algebra.WindowFunctions
+}
+
+func NewWindow(functions algebra.WindowFunctions) *Window {
+	return &Window{
+		functions: functions,
+	}
+}
+
+func (this *Window) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitWindow(this)
+}
+
+func (this *Window) New() Operator {
+	return &Window{}
+}
+
+func (this *Window) Functions() algebra.WindowFunctions {
+	return this.functions
+}
+
+func (this *Window) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"#operator": "Window"}
+	s := make([]interface{}, 0, len(this.functions))
+	for _, fn := range this.functions {
+		s = append(s, expression.NewStringer().Visit(fn))
+	}
+	r["functions"] = s
+	return json.Marshal(r)
+}
+
+func (this *Window) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_   string   `json:"#operator"`
+		Fns []string `json:"functions"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	if err != nil {
+		return err
+	}
+
+	this.functions = make(algebra.WindowFunctions, len(_unmarshalled.Fns))
+	for i, fn := range _unmarshalled.Fns {
+		fn_expr, err := parser.Parse(fn)
+		if err != nil {
+			return err
+		}
+		this.functions[i], _ = fn_expr.(algebra.WindowFunction)
+	}
+
+	return nil
+}
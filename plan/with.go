@@ -0,0 +1,157 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package plan
+
+import (
+	"encoding/json"
+)
+
+// Evaluation of the WITH clause. Materializes the results of each
+// WITH term, and runs the child with the results in scope.
+type With struct {
+	terms []*WithTerm
+	child Operator
+}
+
+func NewWith(terms []*WithTerm, child Operator) *With {
+	return &With{
+		terms: terms,
+		child: child,
+	}
+}
+
+func (this *With) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitWith(this)
+}
+
+func (this *With) New() Operator {
+	return &With{}
+}
+
+func (this *With) Readonly() bool {
+	return this.child.Readonly()
+}
+
+func (this *With) Terms() []*WithTerm {
+	return this.terms
+}
+
+func (this *With) Child() Operator {
+	return this.child
+}
+
+func (this *With) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"#operator": "With"}
+	r["terms"] = this.terms
+	r["~child"] = this.child
+	return json.Marshal(r)
+}
+
+func (this *With) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_     string          `json:"#operator"`
+		Terms []*WithTerm     `json:"terms"`
+		Child json.RawMessage `json:"~child"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	if err != nil {
+		return err
+	}
+
+	this.terms = _unmarshalled.Terms
+	this.child, err = unmarshalChild(_unmarshalled.Child)
+	return err
+}
+
+// A WITH term. For a recursive term, query is the anchor, and step is
+// evaluated repeatedly against the results of the previous iteration.
+type WithTerm struct {
+	alias    string
+	query    Operator
+	step     Operator
+	distinct bool
+}
+
+func NewWithTerm(alias string, query, step Operator, distinct bool) *WithTerm {
+	return &WithTerm{
+		alias:    alias,
+		query:    query,
+		step:     step,
+		distinct: distinct,
+	}
+}
+
+func (this *WithTerm) Alias() string {
+	return this.alias
+}
+
+func (this *WithTerm) Query() Operator {
+	return this.query
+}
+
+func (this *WithTerm) Step() Operator {
+	return this.step
+}
+
+func (this *WithTerm) Distinct() bool {
+	return this.distinct
+}
+
+func (this *WithTerm) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"as": this.alias}
+	r["~query"] = this.query
+	if this.step != nil {
+		r["~step"] = this.step
+		r["distinct"] = this.distinct
+	}
+
+	return json.Marshal(r)
+}
+
+func (this *WithTerm) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		Alias    string          `json:"as"`
+		Query    json.RawMessage `json:"~query"`
+		Step     json.RawMessage `json:"~step"`
+		Distinct bool            `json:"distinct"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	if err != nil {
+		return err
+	}
+
+	this.alias = _unmarshalled.Alias
+	this.distinct = _unmarshalled.Distinct
+	this.query, err = unmarshalChild(_unmarshalled.Query)
+	if err != nil {
+		return err
+	}
+
+	if len(_unmarshalled.Step) > 0 {
+		this.step, err = unmarshalChild(_unmarshalled.Step)
+	}
+
+	return err
+}
+
+func unmarshalChild(body []byte) (Operator, error) {
+	var child_type struct {
+		Operator string `json:"#operator"`
+	}
+
+	err := json.Unmarshal(body, &child_type)
+	if err != nil {
+		return nil, err
+	}
+
+	return MakeOperator(child_type.Operator, body)
+}
@@ -0,0 +1,97 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package planner
+
+import (
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/expression"
+	"github.com/couchbaselabs/query/value"
+)
+
+// ExactFor returns true if every index entry in a span satisfies the
+// condition the span was built from, so that filtering the scanned
+// documents by the condition cannot remove any of them. This holds
+// when each term of the condition compares a key bounded by the span
+// with a constant, and the span bounds each of these keys from below
+// by a value above NULL. Comparisons collate values of different
+// types, but are NULL or MISSING for NULL and MISSING keys, which
+// an unbounded or NULL-inclusive span would include.
+func ExactFor(expr expression.Expression, keys expression.Expressions, span *Span) bool {
+	if len(span.Seek) > 0 {
+		return false
+	}
+
+	n := len(span.Range.Low)
+	if n == 0 || len(span.Range.High) > n || n > len(keys) {
+		return false
+	}
+
+	for i, low := range span.Range.Low {
+		if low == nil {
+			return false
+		}
+
+		v := low.Value()
+		if v == nil || v.Type() == value.MISSING {
+			return false
+		}
+
+		if v.Type() == value.NULL &&
+			(i < n-1 || span.Range.Inclusion&datastore.LOW != 0) {
+			return false
+		}
+	}
+
+	for _, high := range span.Range.High {
+		if high != nil && high.Value() == nil {
+			return false
+		}
+	}
+
+	for _, term := range Conjuncts(expr) {
+		if !exactTerm(term, keys[0:n]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func exactTerm(term expression.Expression, keys expression.Expressions) bool {
+	switch term := term.(type) {
+	case *expression.Eq:
+		return comparesKey(term.First(), term.Second(), keys)
+	case *expression.LT:
+		return comparesKey(term.First(), term.Second(), keys)
+	case *expression.LE:
+		return comparesKey(term.First(), term.Second(), keys)
+	case *expression.IsNotNull:
+		return isKey(term.Operand(), keys)
+	case *expression.IsValued:
+		return isKey(term.Operand(), keys)
+	default:
+		return false
+	}
+}
+
+func comparesKey(first, second expression.Expression, keys expression.Expressions) bool {
+	return (isKey(first, keys) && second.Value() != nil) ||
+		(isKey(second, keys) && first.Value() != nil)
+}
+
+func isKey(expr expression.Expression, keys expression.Expressions) bool {
+	for _, key := range keys {
+		if expr.EquivalentTo(key) {
+			return true
+		}
+	}
+
+	return false
+}
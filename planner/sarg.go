@@ -43,6 +43,85 @@ func SargFor(expr1, expr2 expression.Expression) Spans {
 	return nil
 }
 
+// SargForKeys returns the spans of a composite index key. Spans on
+// the leading key are extended to the next key as long as they are
+// equalities, so that a scan can use equality on leading keys and a
+// range on the next key. Conditions on later keys are left to the
+// filter that follows the scan.
+func SargForKeys(expr expression.Expression, keys expression.Expressions) Spans {
+	spans := SargFor(expr, keys[0])
+
+	for i := 1; i < len(keys) && len(spans) > 0; i++ {
+		if !equalitySpans(spans, i) {
+			break
+		}
+
+		next := SargFor(expr, keys[i])
+		if len(next) == 0 || len(spans)*len(next) > _MAX_COMPOSITE_SPANS {
+			break
+		}
+
+		composite := make(Spans, 0, len(spans)*len(next))
+		for _, span := range spans {
+			for _, n := range next {
+				composite = append(composite, composeSpan(span, n))
+			}
+		}
+
+		spans = composite
+	}
+
+	return spans
+}
+
+// Limit on the number of spans from combining the spans of each key
+const _MAX_COMPOSITE_SPANS = 64
+
+// Whether every span is an equality on each of the first n keys.
+func equalitySpans(spans Spans, n int) bool {
+	for _, span := range spans {
+		if len(span.Seek) > 0 || span.Range.Inclusion != datastore.BOTH ||
+			len(span.Range.Low) != n || len(span.Range.High) != n {
+			return false
+		}
+
+		for i, low := range span.Range.Low {
+			high := span.Range.High[i]
+			if low == nil || high == nil || !low.EquivalentTo(high) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Extend an equality span on leading keys with a span on the next
+// key. A bound missing from the next span leaves the composite bound
+// on the leading keys only, which is inclusive.
+func composeSpan(span, next *Span) *Span {
+	rv := &Span{}
+	rv.Range.Low = span.Range.Low
+	rv.Range.High = span.Range.High
+	rv.Range.Inclusion = datastore.BOTH
+
+	if len(next.Range.Low) > 0 {
+		rv.Range.Low = append(span.Range.Low[0:len(span.Range.Low):len(span.Range.Low)],
+			next.Range.Low[0])
+		rv.Range.Inclusion = (rv.Range.Inclusion & datastore.HIGH) |
+			(next.Range.Inclusion & datastore.LOW)
+	}
+
+	if len(next.Range.High) > 0 {
+		rv.Range.High = append(span.Range.High[0:len(span.Range.High):len(span.Range.High)],
+			next.Range.High[0])
+		rv.Range.Inclusion = (rv.Range.Inclusion & datastore.LOW) |
+			(next.Range.Inclusion & datastore.HIGH)
+	}
+
+	return rv
+}
+
 func newSarg(expr expression.Expression) expression.Visitor {
 	s, _ := expr.Accept(_SARG_FACTORY)
 	return s.(expression.Visitor)
@@ -27,7 +27,7 @@ func newSargAnd(expr *expression.And) *sargAnd {
 
 		for _, op := range expr.Operands() {
 			s := SargFor(op, expr2)
-			if s == nil {
+			if len(s) == 0 {
 				continue
 			}
 
@@ -45,7 +45,9 @@ func newSargAnd(expr *expression.And) *sargAnd {
 }
 
 func constrain(spans1, spans2 Spans) Spans {
-	span1 := spans1[0]
+	// Copy, as spans may be shared
+	span1 := &Span{}
+	*span1 = *spans1[0]
 	span2 := spans2[0]
 
 	if span2.Range.Low != nil {
@@ -72,7 +74,7 @@ func constrain(spans1, spans2 Spans) Spans {
 		} else {
 			high1 := span1.Range.High[0].Value()
 			high2 := span2.Range.High[0].Value()
-			if high1 != nil && (high2 == nil && high1.Collate(high2) > 0) {
+			if high1 != nil && (high2 == nil || high1.Collate(high2) > 0) {
 				span1.Range.High = span2.Range.High
 				span1.Range.Inclusion = (span1.Range.Inclusion & datastore.LOW) |
 					(span2.Range.Inclusion & datastore.HIGH)
@@ -80,5 +82,5 @@ func constrain(spans1, spans2 Spans) Spans {
 		}
 	}
 
-	return spans1
+	return append(Spans{span1}, spans1[1:]...)
 }
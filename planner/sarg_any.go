@@ -0,0 +1,111 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package planner
+
+import (
+	"github.com/couchbaselabs/query/expression"
+)
+
+type sargAny struct {
+	sargBase
+}
+
+// ANY is sargable for an array index key that ranges over the same
+// bindings, by sarging its condition on the mapping of the key.
+//
+// EVERY is not sargable for array index keys, because an empty array
+// satisfies EVERY but has no index entries. Use ANY AND EVERY instead.
+func newSargAny(expr *expression.Any) *sargAny {
+	rv := &sargAny{}
+	rv.sarg = func(expr2 expression.Expression) (Spans, error) {
+		if expr.EquivalentTo(expr2) {
+			return _SELF_SPANS, nil
+		}
+
+		array, satisfies := arrayKeyFor(expr, expr2)
+		if array == nil {
+			return nil, nil
+		}
+
+		return SargFor(satisfies, array.Mapping()), nil
+	}
+
+	return rv
+}
+
+// Returns the array index key that ranges over the bindings of the
+// predicate, and the condition of the predicate with its variables
+// renamed to those of the key. The WHEN condition of the key, if
+// any, must be implied by the condition of the predicate.
+func arrayKeyFor(expr *expression.Any, key expression.Expression) (
+	*expression.Array, expression.Expression) {
+	array, ok := key.(*expression.Array)
+	if !ok || !array.Distinct() {
+		return nil, nil
+	}
+
+	bindings := expr.Bindings()
+	keyBindings := array.Bindings()
+	if len(bindings) != len(keyBindings) {
+		return nil, nil
+	}
+
+	renames := make(map[string]string, len(bindings))
+	for i, b := range bindings {
+		kb := keyBindings[i]
+		if b.Descend() != kb.Descend() || !b.Expression().EquivalentTo(kb.Expression()) {
+			return nil, nil
+		}
+
+		if b.Variable() != kb.Variable() {
+			renames[b.Variable()] = kb.Variable()
+		}
+	}
+
+	satisfies := expr.Satisfies()
+	if len(renames) > 0 {
+		var err error
+		satisfies, err = newRenamer(renames).Map(satisfies.Copy())
+		if err != nil {
+			return nil, nil
+		}
+	}
+
+	if array.When() != nil && !SubsetOf(satisfies, array.When()) {
+		return nil, nil
+	}
+
+	return array, satisfies
+}
+
+// Rename the variables of a collection predicate.
+type renamer struct {
+	expression.MapperBase
+	renames map[string]string
+}
+
+func newRenamer(renames map[string]string) *renamer {
+	rv := &renamer{
+		renames: renames,
+	}
+
+	rv.SetMapper(rv)
+	return rv
+}
+
+func (this *renamer) MapBindings() bool { return true }
+
+func (this *renamer) VisitIdentifier(expr *expression.Identifier) (interface{}, error) {
+	if name, ok := this.renames[expr.Identifier()]; ok {
+		return expression.NewIdentifier(name), nil
+	}
+
+	return expr, nil
+}
@@ -0,0 +1,157 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package planner
+
+import (
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/expression"
+)
+
+/*
+SargForJoin returns the spans on index key expr2 implied by the ON
+predicate expr1 of an ANSI join, where alias names the right-hand
+keyspace. Unlike SargFor, the span bounds need not be static: any
+operand that does not refer to the right-hand keyspace is allowed,
+and is evaluated against each left-hand item at execution time.
+
+Only the top-level conjuncts of expr1 are considered. Returns nil
+if no conjunct is sargable for expr2.
+*/
+func SargForJoin(expr1, expr2 expression.Expression, alias string) Spans {
+	if expr2.Value() != nil {
+		return nil
+	}
+
+	right := expression.NewIdentifier(alias)
+
+	var spans Spans
+	for _, term := range Conjuncts(expr1) {
+		s := sargJoinTerm(term, expr2, right)
+		if s == nil {
+			continue
+		}
+
+		if spans == nil {
+			spans = s
+		} else {
+			spans = constrain(spans, s)
+		}
+	}
+
+	return spans
+}
+
+func sargJoinTerm(term, key expression.Expression, right expression.Expression) Spans {
+	var first, second expression.Expression
+	var low, high datastore.Inclusion
+
+	switch term := term.(type) {
+	case *expression.Eq:
+		first, second = term.First(), term.Second()
+		low, high = datastore.BOTH, datastore.BOTH
+	case *expression.LT:
+		first, second = term.First(), term.Second()
+		low, high = datastore.NEITHER, datastore.NEITHER
+	case *expression.LE:
+		first, second = term.First(), term.Second()
+		low, high = datastore.LOW, datastore.HIGH
+	default:
+		return nil
+	}
+
+	span := &Span{}
+
+	if first.EquivalentTo(key) && !dependsOn(second, right) {
+		// key < second, key <= second, key = second
+		span.Range.High = expression.Expressions{second}
+		span.Range.Inclusion = high
+	} else if second.EquivalentTo(key) && !dependsOn(first, right) {
+		// first < key, first <= key, first = key
+		span.Range.Low = expression.Expressions{first}
+		span.Range.Inclusion = low
+	} else {
+		return nil
+	}
+
+	if _, ok := term.(*expression.Eq); ok {
+		if span.Range.Low == nil {
+			span.Range.Low = span.Range.High
+		} else {
+			span.Range.High = span.Range.Low
+		}
+	}
+
+	return Spans{span}
+}
+
+func dependsOn(expr, ident expression.Expression) bool {
+	if expr.EquivalentTo(ident) {
+		return true
+	}
+
+	for _, child := range expr.Children() {
+		if dependsOn(child, ident) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+EquiJoinKeys returns the pairs of expressions equated by the top-level
+conjuncts of the ON predicate expr of an ANSI join, for use as hash
+join keys. alias names the right-hand keyspace and left names the
+aliases available on the left-hand side. Each right expression refers
+only to the right-hand keyspace, and each left expression only to the
+left-hand side.
+*/
+func EquiJoinKeys(expr expression.Expression, alias string, left []string) (
+	rightExprs, leftExprs expression.Expressions) {
+	right := expression.NewIdentifier(alias)
+	lefts := make(expression.Expressions, len(left))
+	for i, l := range left {
+		lefts[i] = expression.NewIdentifier(l)
+	}
+
+	side := func(expr expression.Expression) (isRight, isLeft bool) {
+		r := dependsOn(expr, right)
+		l := false
+		for _, ident := range lefts {
+			if dependsOn(expr, ident) {
+				l = true
+				break
+			}
+		}
+
+		return r && !l, l && !r
+	}
+
+	for _, term := range Conjuncts(expr) {
+		eq, ok := term.(*expression.Eq)
+		if !ok {
+			continue
+		}
+
+		first, second := eq.First(), eq.Second()
+		firstRight, firstLeft := side(first)
+		secondRight, secondLeft := side(second)
+
+		if firstRight && secondLeft {
+			rightExprs = append(rightExprs, first)
+			leftExprs = append(leftExprs, second)
+		} else if secondRight && firstLeft {
+			rightExprs = append(rightExprs, second)
+			leftExprs = append(leftExprs, first)
+		}
+	}
+
+	return
+}
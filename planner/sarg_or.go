@@ -27,9 +27,12 @@ func newSargOr(expr *expression.Or) *sargOr {
 		spans := make(Spans, 0, len(expr.Operands()))
 		for _, child := range expr.Operands() {
 			cspans := SargFor(child, expr2)
-			if len(cspans) > 0 {
-				spans = append(spans, cspans...)
+			if len(cspans) == 0 {
+				// Every disjunct must constrain the key
+				return nil, nil
 			}
+
+			spans = append(spans, cspans...)
 		}
 
 		return spans, nil
@@ -0,0 +1,46 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package planner
+
+import (
+	"github.com/couchbaselabs/query/expression"
+)
+
+// Conjuncts returns the terms of a conjunction, flattening nested
+// ANDs. Any other expression is its own single term.
+func Conjuncts(expr expression.Expression) expression.Expressions {
+	and, ok := expr.(*expression.And)
+	if !ok {
+		return expression.Expressions{expr}
+	}
+
+	rv := make(expression.Expressions, 0, len(and.Operands()))
+	for _, op := range and.Operands() {
+		rv = append(rv, Conjuncts(op)...)
+	}
+
+	return rv
+}
+
+// Disjuncts returns the terms of a disjunction, flattening nested
+// ORs. Any other expression is its own single term.
+func Disjuncts(expr expression.Expression) expression.Expressions {
+	or, ok := expr.(*expression.Or)
+	if !ok {
+		return expression.Expressions{expr}
+	}
+
+	rv := make(expression.Expressions, 0, len(or.Operands()))
+	for _, op := range or.Operands() {
+		rv = append(rv, Disjuncts(op)...)
+	}
+
+	return rv
+}
@@ -0,0 +1,228 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/errors"
+)
+
+type Phase string
+
+const (
+	QUEUED    Phase = "queued"
+	PLANNING  Phase = "planning"
+	EXECUTING Phase = "executing"
+)
+
+type activeRequest struct {
+	request   Request
+	workload  string
+	phase     Phase
+	cancelled bool
+}
+
+// The requests that have been submitted to the server and have not
+// completed, by request id. They are listed by system:active_requests,
+// and can be cancelled.
+type ActiveRequests struct {
+	mutex    sync.RWMutex
+	requests map[string]*activeRequest
+	admins   datastore.Credentials
+}
+
+func newActiveRequests() *ActiveRequests {
+	return &ActiveRequests{
+		requests: make(map[string]*activeRequest),
+	}
+}
+
+func (this *ActiveRequests) add(request Request, workload string) {
+	this.mutex.Lock()
+	this.requests[request.Id().String()] = &activeRequest{
+		request:  request,
+		workload: workload,
+		phase:    QUEUED,
+	}
+	this.mutex.Unlock()
+}
+
+func (this *ActiveRequests) remove(request Request) {
+	this.mutex.Lock()
+	delete(this.requests, request.Id().String())
+	this.mutex.Unlock()
+}
+
+// Move a request to the given phase. Return false if the request was
+// cancelled while queued.
+func (this *ActiveRequests) setPhase(request Request, phase Phase) bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	entry, ok := this.requests[request.Id().String()]
+	if !ok {
+		return true
+	}
+
+	entry.phase = phase
+	return !entry.cancelled
+}
+
+// Read the credentials of administrators from a JSON file containing
+// an object of user names and passwords.
+func LoadAdmins(path string) (datastore.Credentials, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var admins datastore.Credentials
+	err = json.Unmarshal(bytes, &admins)
+	if err != nil {
+		return nil, err
+	}
+
+	return admins, nil
+}
+
+// Set the credentials of the administrators, who can cancel any
+// request through the admin endpoint
+func (this *ActiveRequests) SetAdmins(admins datastore.Credentials) {
+	this.mutex.Lock()
+	this.admins = admins
+	this.mutex.Unlock()
+}
+
+// Whether the credentials are those of an administrator
+func (this *ActiveRequests) IsAdmin(creds datastore.Credentials) bool {
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+
+	return matchCredentials(this.admins, creds)
+}
+
+// The ids of the active requests, in order
+func (this *ActiveRequests) Ids() []string {
+	this.mutex.RLock()
+	ids := make([]string, 0, len(this.requests))
+	for id := range this.requests {
+		ids = append(ids, id)
+	}
+	this.mutex.RUnlock()
+
+	sort.Strings(ids)
+	return ids
+}
+
+// A description of an active request, or false if it is not active
+func (this *ActiveRequests) Entry(id string) (map[string]interface{}, bool) {
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+
+	entry, ok := this.requests[id]
+	if !ok {
+		return nil, false
+	}
+
+	request := entry.request
+	statement := request.Statement()
+	if statement == "" && request.Prepared() != nil {
+		statement = request.Prepared().Text()
+	}
+
+	data := map[string]interface{}{
+		"requestId":   id,
+		"statement":   statement,
+		"state":       string(request.State()),
+		"phase":       string(entry.phase),
+		"workload":    entry.workload,
+		"requestTime": request.RequestTime().Format(time.RFC3339Nano),
+		"elapsedTime": time.Since(request.RequestTime()).String(),
+	}
+
+	if request.ClientID().IsValid() {
+		data["clientContextID"] = request.ClientID().String()
+	}
+
+	users := requestUsers(request)
+	if len(users) > 0 {
+		// Values are limited to the types supported by value
+		userValues := make([]interface{}, len(users))
+		for i, user := range users {
+			userValues[i] = user
+		}
+
+		data["users"] = userValues
+	}
+
+	return data, true
+}
+
+// Cancel an active request. A queued request fails when it is
+// dequeued, and an executing request is stopped. Return false if the
+// request is not active.
+func (this *ActiveRequests) Cancel(id string) bool {
+	ok, _ := this.cancel(id, nil, false)
+	return ok
+}
+
+// Cancel an active request on behalf of the credentials of another
+// request, as DELETE FROM system:active_requests does. A request can
+// only be cancelled by a request with one of its users and passwords;
+// other requests, including those without users, can be cancelled by
+// an administrator through the admin endpoint.
+func (this *ActiveRequests) CancelAs(id string, creds datastore.Credentials) (bool, errors.Error) {
+	return this.cancel(id, creds, true)
+}
+
+func (this *ActiveRequests) cancel(id string, creds datastore.Credentials,
+	authorize bool) (bool, errors.Error) {
+	this.mutex.Lock()
+	entry, ok := this.requests[id]
+	if ok && authorize && !canCancel(entry.request, creds) {
+		this.mutex.Unlock()
+		return false, errors.NewSystemCancelNotPermittedError(id)
+	}
+
+	queued := false
+	if ok {
+		entry.cancelled = true
+		queued = entry.phase == QUEUED
+	}
+	this.mutex.Unlock()
+
+	if ok && !queued {
+		entry.request.Stop(STOPPED)
+	}
+
+	return ok, nil
+}
+
+// Whether the credentials share a user and password with the request
+func canCancel(request Request, creds datastore.Credentials) bool {
+	return matchCredentials(request.Credentials(), creds)
+}
+
+// Whether the credentials share a user and password with the users
+func matchCredentials(users, creds datastore.Credentials) bool {
+	for user, password := range users {
+		if p, ok := creds[user]; ok && p == password {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,169 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/datastore/mock"
+	"github.com/couchbaselabs/query/datastore/system"
+)
+
+type testRequestID string
+
+func (this testRequestID) String() string {
+	return string(this)
+}
+
+// A request with only what cancellation looks at
+type cancelRequest struct {
+	Request
+	id    string
+	creds datastore.Credentials
+	state State
+}
+
+func (this *cancelRequest) Id() RequestID {
+	return testRequestID(this.id)
+}
+
+func (this *cancelRequest) Credentials() datastore.Credentials {
+	return this.creds
+}
+
+func (this *cancelRequest) Stop(state State) {
+	this.state = state
+}
+
+// The active_requests keyspace of a system store over a mock store
+func activeRequestsKeyspace(t *testing.T, active *ActiveRequests) datastore.AuthorizedDeleteKeyspace {
+	store, err := mock.NewDatastore("mock:")
+	if err != nil {
+		t.Fatalf("failed to create mock store: %v", err)
+	}
+
+	systemstore, err := system.NewDatastore(store)
+	if err != nil {
+		t.Fatalf("failed to create system store: %v", err)
+	}
+
+	namespace, err := systemstore.NamespaceByName(system.NAMESPACE_NAME)
+	if err != nil {
+		t.Fatalf("failed to get system namespace: %v", err)
+	}
+
+	keyspace, err := namespace.KeyspaceByName("active_requests")
+	if err != nil {
+		t.Fatalf("failed to get active_requests: %v", err)
+	}
+
+	system.SetActiveRequests(active)
+	return keyspace.(datastore.AuthorizedDeleteKeyspace)
+}
+
+func TestCancelQueued(t *testing.T) {
+	active := newActiveRequests()
+	keyspace := activeRequestsKeyspace(t, active)
+	defer system.SetActiveRequests(nil)
+
+	alice := datastore.Credentials{"alice": ""}
+	bob := datastore.Credentials{"bob": ""}
+	request := &cancelRequest{id: "queued", creds: alice}
+	active.add(request, DEFAULT_WORKLOAD)
+
+	// Another user cannot cancel the request
+	deleted, err := keyspace.DeleteAs([]string{"queued"}, bob)
+	if err == nil || err.Code() != 11007 || len(deleted) != 0 {
+		t.Fatalf("expected error 11007, got %v %v", deleted, err)
+	}
+
+	deleted, err = keyspace.Delete([]string{"queued"})
+	if err == nil || len(deleted) != 0 {
+		t.Fatalf("expected an error without credentials, got %v %v", deleted, err)
+	}
+
+	deleted, err = keyspace.DeleteAs([]string{"queued", "unknown"}, alice)
+	if err != nil || len(deleted) != 1 || deleted[0] != "queued" {
+		t.Fatalf("expected to cancel queued, got %v %v", deleted, err)
+	}
+
+	// A queued request is not stopped, but fails when it is dequeued
+	if request.state != "" {
+		t.Errorf("expected a queued request not to be stopped, got %s", request.state)
+	}
+
+	if active.setPhase(request, PLANNING) {
+		t.Errorf("expected a cancelled request not to be planned")
+	}
+}
+
+func TestCancelRunning(t *testing.T) {
+	active := newActiveRequests()
+	keyspace := activeRequestsKeyspace(t, active)
+	defer system.SetActiveRequests(nil)
+
+	request := &cancelRequest{id: "running", creds: datastore.Credentials{"alice": "secret"}}
+	active.add(request, DEFAULT_WORKLOAD)
+	active.setPhase(request, EXECUTING)
+
+	_, err := keyspace.DeleteAs([]string{"running"}, datastore.Credentials{"bob": ""})
+	if err == nil || request.state != "" {
+		t.Fatalf("expected another user not to stop the request, got %v", err)
+	}
+
+	_, err = keyspace.DeleteAs([]string{"running"}, datastore.Credentials{"alice": "guess"})
+	if err == nil || request.state != "" {
+		t.Fatalf("expected a wrong password not to stop the request, got %v", err)
+	}
+
+	deleted, err := keyspace.DeleteAs([]string{"running"}, datastore.Credentials{"alice": "secret", "bob": ""})
+	if err != nil || len(deleted) != 1 {
+		t.Fatalf("expected to cancel running, got %v %v", deleted, err)
+	}
+
+	if request.state != STOPPED {
+		t.Errorf("expected a running request to be stopped, got %s", request.state)
+	}
+}
+
+func TestCancelAnonymous(t *testing.T) {
+	active := newActiveRequests()
+	keyspace := activeRequestsKeyspace(t, active)
+	defer system.SetActiveRequests(nil)
+
+	// A request without users can be cancelled by no request
+	request := &cancelRequest{id: "anonymous"}
+	active.add(request, DEFAULT_WORKLOAD)
+	active.setPhase(request, EXECUTING)
+
+	for _, creds := range []datastore.Credentials{nil, datastore.Credentials{"alice": ""}} {
+		deleted, err := keyspace.DeleteAs([]string{"anonymous"}, creds)
+		if err == nil || len(deleted) != 0 || request.state != "" {
+			t.Fatalf("expected not to cancel anonymous as %v, got %v %v", creds, deleted, err)
+		}
+	}
+
+	// Only administrators, through the admin endpoint
+	active.SetAdmins(datastore.Credentials{"root": "secret"})
+	for _, creds := range []datastore.Credentials{nil, datastore.Credentials{"root": ""}} {
+		if active.IsAdmin(creds) {
+			t.Errorf("expected %v not to be an administrator", creds)
+		}
+	}
+
+	if !active.IsAdmin(datastore.Credentials{"root": "secret"}) {
+		t.Errorf("expected root to be an administrator")
+	}
+
+	if !active.Cancel("anonymous") || request.state != STOPPED {
+		t.Errorf("expected the admin endpoint to cancel the request")
+	}
+}
@@ -22,11 +22,14 @@ import (
 
 	"github.com/couchbaselabs/query/accounting"
 	acct_resolver "github.com/couchbaselabs/query/accounting/resolver"
+	"github.com/couchbaselabs/query/clustering"
 	config_resolver "github.com/couchbaselabs/query/clustering/resolver"
 	datastore_package "github.com/couchbaselabs/query/datastore"
 	"github.com/couchbaselabs/query/datastore/resolver"
+	"github.com/couchbaselabs/query/execution"
 	"github.com/couchbaselabs/query/logging"
 	log_resolver "github.com/couchbaselabs/query/logging/resolver"
+	"github.com/couchbaselabs/query/plan"
 	"github.com/couchbaselabs/query/server"
 	"github.com/couchbaselabs/query/server/http"
 	"github.com/couchbaselabs/query/util"
@@ -42,10 +45,22 @@ var TIMEOUT = flag.Duration("timeout", 0*time.Second, "Server execution timeout,
 var READONLY = flag.Bool("readonly", false, "Read-only mode")
 var SIGNATURE = flag.Bool("signature", true, "Whether to provide signature")
 var METRICS = flag.Bool("metrics", true, "Whether to provide metrics")
-var REQUEST_CAP = flag.Int("request-cap", runtime.NumCPU()<<16, "Maximum number of queued requests")
+var REQUEST_CAP = flag.Int("request-cap", runtime.NumCPU()<<16, "Maximum number of queued requests of each workload class")
+var WORKLOAD_CLASSES = flag.String("workload-classes", "", "JSON file of workload classes, each with its own queue depth, concurrency and priority")
 var THREAD_COUNT = flag.Int("threads", runtime.NumCPU()<<6, "Thread count")
-var ORDER_LIMIT = flag.Int64("order-limit", 0, "Maximum LIMIT for ORDER BY clauses; use zero or negative value to disable")
+var ORDER_LIMIT = flag.Int64("order-limit", 0, "Maximum LIMIT plus OFFSET for ORDER BY clauses; use zero or negative value to disable")
+var SORT_MEMORY = flag.String("sort-memory", "0", "Memory budget for each ORDER BY, e.g. 64mb, beyond which sorted runs are spilled to disk; use zero or negative value to disable")
+var HASH_MEMORY = flag.String("hash-memory", "0", "Memory budget for each GROUP BY, DISTINCT, INTERSECT and EXCEPT hash table, e.g. 64mb, beyond which it is partitioned to disk; use zero or negative value to disable")
+var PREPARED_LIMIT = flag.Int("prepared-limit", plan.DEFAULT_PREPARED_LIMIT, "Maximum number of cached prepared statements; use zero or negative value to disable")
 var MUTATION_LIMIT = flag.Int64("mutation-limit", 0, "Maximum LIMIT for data modification statements; use zero or negative value to disable")
+var MAX_MUTATIONS = flag.Int64("max-mutations", 0, "Maximum number of mutations of each request; use zero or negative value to disable")
+var MEMORY_QUOTA = flag.String("memory-quota", "0", "Maximum memory held by ORDER BY, GROUP BY, DISTINCT, INTERSECT and EXCEPT for each request, e.g. 256mb; use zero or negative value to disable")
+var MAX_FETCH = flag.Int64("max-fetch", 0, "Maximum number of documents fetched by each request; use zero or negative value to disable")
+var MAX_RESULT_SIZE = flag.String("max-result-size", "0", "Maximum size of the results of each request, e.g. 64mb; use zero or negative value to disable")
+var COMPLETED_LIMIT = flag.Int("completed-limit", server.DEFAULT_COMPLETED_LIMIT, "Maximum number of requests in the completed requests log; use zero or negative value to disable")
+var COMPLETED_THRESHOLD = flag.Duration("completed-threshold", server.DEFAULT_COMPLETED_THRESHOLD, "Elapsed time beyond which requests are logged as completed requests, e.g. 500ms or 2s; use zero to log all requests, or negative value to only log failed requests")
+var COMPLETED_ERRORS = flag.Bool("completed-errors", true, "Whether to log failed requests as completed requests regardless of their elapsed time")
+var ADMINS = flag.String("admins", "", "JSON file of the user names and passwords of administrators, who can cancel any request through the admin endpoint")
 var HTTP_ADDR = flag.String("http", ":8093", "HTTP service address")
 var HTTPS_ADDR = flag.String("https", ":18093", "HTTPS service address")
 var CERT_FILE = flag.String("certfile", "", "HTTPS certificate file")
@@ -139,14 +154,95 @@ func main() {
 		)
 	}
 
-	channel := make(server.RequestChannel, *REQUEST_CAP)
-	server, err := server.NewServer(datastore, configstore, acctstore, *NAMESPACE, *READONLY, channel,
+	sort_memory, e := util.ParseQuantity(*SORT_MEMORY)
+
+	if e != nil {
+		logging.Errorp("Error parsing sort memory; disabling spilling to disk",
+			logging.Pair{"sort memory", *SORT_MEMORY},
+			logging.Pair{"error", e},
+		)
+		sort_memory = 0
+	}
+
+	hash_memory, e := util.ParseQuantity(*HASH_MEMORY)
+
+	if e != nil {
+		logging.Errorp("Error parsing hash memory; disabling partitioning to disk",
+			logging.Pair{"hash memory", *HASH_MEMORY},
+			logging.Pair{"error", e},
+		)
+		hash_memory = 0
+	}
+
+	memory_quota, e := util.ParseQuantity(*MEMORY_QUOTA)
+
+	if e != nil {
+		logging.Errorp("Error parsing memory quota; disabling memory quota",
+			logging.Pair{"memory quota", *MEMORY_QUOTA},
+			logging.Pair{"error", e},
+		)
+		memory_quota = 0
+	}
+
+	max_result_size, e := util.ParseQuantity(*MAX_RESULT_SIZE)
+
+	if e != nil {
+		logging.Errorp("Error parsing max result size; disabling max result size",
+			logging.Pair{"max result size", *MAX_RESULT_SIZE},
+			logging.Pair{"error", e},
+		)
+		max_result_size = 0
+	}
+
+	plan.PreparedCache().SetLimit(*PREPARED_LIMIT)
+
+	var workload_classes []*server.WorkloadClass
+	if *WORKLOAD_CLASSES != "" {
+		workload_classes, e = server.LoadWorkloadClasses(*WORKLOAD_CLASSES)
+		if e != nil {
+			logging.Errorp("Error loading workload classes",
+				logging.Pair{"workload classes", *WORKLOAD_CLASSES},
+				logging.Pair{"error", e},
+			)
+			os.Exit(1)
+		}
+	}
+
+	var admins datastore_package.Credentials
+	if *ADMINS != "" {
+		admins, e = server.LoadAdmins(*ADMINS)
+		if e != nil {
+			logging.Errorp("Error loading administrators",
+				logging.Pair{"admins", *ADMINS},
+				logging.Pair{"error", e},
+			)
+			os.Exit(1)
+		}
+	}
+
+	server, err := server.NewServer(datastore, configstore, acctstore, *NAMESPACE, *READONLY, *REQUEST_CAP,
 		*THREAD_COUNT, *TIMEOUT, *SIGNATURE, *METRICS, keep_alive_length)
 	if err != nil {
 		logging.Errorp(err.Error())
 		os.Exit(1)
 	}
 
+	server.SetOptions(clustering.NewOptions(*DATASTORE, *CONFIGSTORE, *ACCTSTORE, *NAMESPACE,
+		*READONLY, *SIGNATURE, *METRICS, *REQUEST_CAP, *THREAD_COUNT, int(*ORDER_LIMIT),
+		int(*MUTATION_LIMIT), *HTTP_ADDR, *HTTPS_ADDR, *LOGGER, *DEBUG, "", *CERT_FILE, *KEY_FILE))
+	server.SetSortMemory(int64(sort_memory))
+	server.SetHashMemory(int64(hash_memory))
+	server.SetWorkloadClasses(workload_classes)
+	server.SetLimits(execution.Limits{
+		MemoryQuota:   int64(memory_quota),
+		MaxFetch:      *MAX_FETCH,
+		MaxResultSize: int64(max_result_size),
+		MaxMutations:  *MAX_MUTATIONS,
+	})
+	server.ActiveRequests().SetAdmins(admins)
+	server.CompletedRequests().SetLimit(*COMPLETED_LIMIT)
+	server.CompletedRequests().SetThreshold(*COMPLETED_THRESHOLD)
+	server.CompletedRequests().SetErrors(*COMPLETED_ERRORS)
 	go server.Serve()
 
 	logging.Infop("cbq-engine started",
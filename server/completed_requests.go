@@ -0,0 +1,276 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package server
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/couchbaselabs/query/plan"
+	"github.com/couchbaselabs/query/value"
+)
+
+const DEFAULT_COMPLETED_LIMIT = 4000
+const DEFAULT_COMPLETED_THRESHOLD = time.Second
+
+type completedRequest struct {
+	id          string
+	clientId    string
+	statement   string
+	prepared    *plan.Prepared
+	state       State
+	users       []string
+	requestTime time.Time
+	elapsedTime time.Duration
+	serviceTime time.Duration
+	resultCount int
+	resultSize  int
+	errorCodes  []int
+}
+
+// A bounded log of completed requests, by request id. Requests are
+// logged if they ran for at least the threshold, or if they failed
+// and errors are logged; the oldest requests are evicted beyond the
+// limit. The log is listed by system:completed_requests.
+type CompletedRequests struct {
+	mutex     sync.RWMutex
+	limit     int
+	threshold time.Duration
+	errors    bool
+	requests  map[string]*list.Element
+	log       *list.List // Oldest first
+}
+
+func newCompletedRequests() *CompletedRequests {
+	return &CompletedRequests{
+		limit:     DEFAULT_COMPLETED_LIMIT,
+		threshold: DEFAULT_COMPLETED_THRESHOLD,
+		errors:    true,
+		requests:  make(map[string]*list.Element),
+		log:       list.New(),
+	}
+}
+
+// Set the maximum number of logged requests; use zero or a negative
+// value to disable the log
+func (this *CompletedRequests) SetLimit(limit int) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.limit = limit
+	this.evict()
+}
+
+func (this *CompletedRequests) Limit() int {
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+
+	return this.limit
+}
+
+// Set the elapsed time beyond which requests are logged; use zero to
+// log all requests, or a negative value to only log failed requests
+func (this *CompletedRequests) SetThreshold(threshold time.Duration) {
+	this.mutex.Lock()
+	this.threshold = threshold
+	this.mutex.Unlock()
+}
+
+func (this *CompletedRequests) Threshold() time.Duration {
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+
+	return this.threshold
+}
+
+// Set whether requests that failed are logged regardless of their
+// elapsed time
+func (this *CompletedRequests) SetErrors(errors bool) {
+	this.mutex.Lock()
+	this.errors = errors
+	this.mutex.Unlock()
+}
+
+func (this *CompletedRequests) Errors() bool {
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+
+	return this.errors
+}
+
+// Log a request that has completed, if it meets the thresholds.
+func (this *CompletedRequests) Record(request Request, resultCount, resultSize int, errorCodes []int) {
+	elapsedTime := time.Since(request.RequestTime())
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.limit <= 0 {
+		return
+	}
+
+	slow := this.threshold >= 0 && elapsedTime >= this.threshold
+	failed := this.errors && (len(errorCodes) > 0 || request.State() == FATAL ||
+		request.State() == TIMEOUT)
+	if !slow && !failed {
+		return
+	}
+
+	state := request.State()
+	if state == COMPLETED {
+		if len(errorCodes) == 0 {
+			state = SUCCESS
+		} else {
+			state = ERRORS
+		}
+	}
+
+	entry := &completedRequest{
+		id:          request.Id().String(),
+		statement:   request.Statement(),
+		prepared:    request.Prepared(),
+		state:       state,
+		users:       requestUsers(request),
+		requestTime: request.RequestTime(),
+		elapsedTime: elapsedTime,
+		serviceTime: time.Since(request.ServiceTime()),
+		resultCount: resultCount,
+		resultSize:  resultSize,
+		errorCodes:  errorCodes,
+	}
+
+	if entry.statement == "" && entry.prepared != nil {
+		entry.statement = entry.prepared.Text()
+	}
+
+	if request.ClientID().IsValid() {
+		entry.clientId = request.ClientID().String()
+	}
+
+	elem, ok := this.requests[entry.id]
+	if ok {
+		this.log.Remove(elem)
+	}
+
+	this.requests[entry.id] = this.log.PushBack(entry)
+	this.evict()
+}
+
+func (this *CompletedRequests) evict() {
+	for this.log.Len() > 0 && this.log.Len() > this.limit {
+		elem := this.log.Front()
+		this.log.Remove(elem)
+		delete(this.requests, elem.Value.(*completedRequest).id)
+	}
+}
+
+// The ids of the logged requests, in order
+func (this *CompletedRequests) Ids() []string {
+	this.mutex.RLock()
+	ids := make([]string, 0, len(this.requests))
+	for id := range this.requests {
+		ids = append(ids, id)
+	}
+	this.mutex.RUnlock()
+
+	sort.Strings(ids)
+	return ids
+}
+
+// A description of a logged request, or false if it is not logged
+func (this *CompletedRequests) Entry(id string) (map[string]interface{}, bool) {
+	this.mutex.RLock()
+	elem, ok := this.requests[id]
+	this.mutex.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*completedRequest)
+	data := map[string]interface{}{
+		"requestId":   entry.id,
+		"statement":   entry.statement,
+		"state":       string(entry.state),
+		"requestTime": entry.requestTime.Format(time.RFC3339Nano),
+		"elapsedTime": entry.elapsedTime.String(),
+		"serviceTime": entry.serviceTime.String(),
+		"resultCount": entry.resultCount,
+		"resultSize":  entry.resultSize,
+		"errorCount":  len(entry.errorCodes),
+	}
+
+	if entry.clientId != "" {
+		data["clientContextID"] = entry.clientId
+	}
+
+	if entry.prepared != nil {
+		bytes, err := entry.prepared.MarshalJSON()
+		if err == nil {
+			data["plan"] = value.NewValue(bytes)
+		}
+	}
+
+	if len(entry.errorCodes) > 0 {
+		codes := make([]interface{}, len(entry.errorCodes))
+		for i, code := range entry.errorCodes {
+			codes[i] = code
+		}
+
+		data["errors"] = codes
+	}
+
+	if len(entry.users) > 0 {
+		users := make([]interface{}, len(entry.users))
+		for i, user := range entry.users {
+			users[i] = user
+		}
+
+		data["users"] = users
+	}
+
+	return data, true
+}
+
+// Remove a request from the log. Return false if it is not logged.
+func (this *CompletedRequests) Delete(id string) bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	elem, ok := this.requests[id]
+	if ok {
+		this.log.Remove(elem)
+		delete(this.requests, id)
+	}
+
+	return ok
+}
+
+// Remove all requests from the log.
+func (this *CompletedRequests) Clear() {
+	this.mutex.Lock()
+	this.requests = make(map[string]*list.Element)
+	this.log.Init()
+	this.mutex.Unlock()
+}
+
+// The users of the credentials of a request, in order
+func requestUsers(request Request) []string {
+	creds := request.Credentials()
+	users := make([]string, 0, len(creds))
+	for user := range creds {
+		users = append(users, user)
+	}
+
+	sort.Strings(users)
+	return users
+}
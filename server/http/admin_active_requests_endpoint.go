@@ -0,0 +1,97 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package http
+
+import (
+	"net/http"
+
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/server"
+	"github.com/gorilla/mux"
+)
+
+const (
+	activeRequestsPrefix = adminPrefix + "/active_requests"
+)
+
+func registerActiveRequestsHandlers(r *mux.Router, server *server.Server) {
+	activeRequestsHandler := func(w http.ResponseWriter, req *http.Request) {
+		wrapAPI(server, w, req, doActiveRequests)
+	}
+	activeRequestHandler := func(w http.ResponseWriter, req *http.Request) {
+		wrapAPI(server, w, req, doActiveRequest)
+	}
+
+	routeMap := map[string]struct {
+		handler handlerFunc
+		methods []string
+	}{
+		activeRequestsPrefix:                {handler: activeRequestsHandler, methods: []string{"GET"}},
+		activeRequestsPrefix + "/{request}": {handler: activeRequestHandler, methods: []string{"GET", "DELETE"}},
+	}
+
+	for route, h := range routeMap {
+		r.HandleFunc(route, h.handler).Methods(h.methods...)
+	}
+}
+
+func doActiveRequests(s *server.Server, w http.ResponseWriter, req *http.Request) (interface{}, errors.Error) {
+	switch req.Method {
+	case "GET":
+		active := s.ActiveRequests()
+		ids := active.Ids()
+		data := make([]map[string]interface{}, 0, len(ids))
+		for _, id := range ids {
+			entry, ok := active.Entry(id)
+			if ok {
+				data = append(data, entry)
+			}
+		}
+		return data, nil
+	default:
+		return nil, nil
+	}
+}
+
+func doActiveRequest(s *server.Server, w http.ResponseWriter, req *http.Request) (interface{}, errors.Error) {
+	vars := mux.Vars(req)
+	id := vars["request"]
+
+	active := s.ActiveRequests()
+	if req.Method == "DELETE" && !active.IsAdmin(basicCredentials(req)) {
+		return nil, errors.NewAdminAuthError("cancel request " + id)
+	}
+
+	entry, ok := active.Entry(id)
+	if !ok {
+		return nil, nil
+	}
+
+	switch req.Method {
+	case "GET":
+		return entry, nil
+	case "DELETE":
+		active.Cancel(id)
+		return entry, nil
+	default:
+		return nil, nil
+	}
+}
+
+// The credentials of HTTP basic authentication, if any
+func basicCredentials(req *http.Request) datastore.Credentials {
+	user, password, ok := req.BasicAuth()
+	if !ok {
+		return nil
+	}
+
+	return datastore.Credentials{user: password}
+}
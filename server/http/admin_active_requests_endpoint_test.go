@@ -0,0 +1,73 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	accounting_stub "github.com/couchbaselabs/query/accounting/stub"
+	clustering_stub "github.com/couchbaselabs/query/clustering/stub"
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/datastore/mock"
+	"github.com/couchbaselabs/query/server"
+	"github.com/gorilla/mux"
+)
+
+// Cancelling a request through the admin endpoint requires the
+// credentials of an administrator.
+func TestActiveRequestDeleteRequiresAdmin(t *testing.T) {
+	store, _ := mock.NewDatastore("mock:")
+	configstore, _ := clustering_stub.NewConfigurationStore()
+	acctstore, _ := accounting_stub.NewAccountingStore("stub:")
+	srvr, err := server.NewServer(store, configstore, acctstore, "p0", false, 10,
+		1, 0, false, false, server.KEEP_ALIVE_DEFAULT)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	srvr.ActiveRequests().SetAdmins(datastore.Credentials{"root": "secret"})
+
+	r := mux.NewRouter()
+	registerActiveRequestsHandlers(r, srvr)
+
+	tests := []struct {
+		user, password string
+		status         int
+	}{
+		{"", "", http.StatusUnauthorized},
+		{"root", "guess", http.StatusUnauthorized},
+		{"alice", "secret", http.StatusUnauthorized},
+		{"root", "secret", http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		req, e := http.NewRequest("DELETE", activeRequestsPrefix+"/no_such_request", nil)
+		if e != nil {
+			t.Fatalf("failed to create request: %v", e)
+		}
+
+		if test.user != "" {
+			req.SetBasicAuth(test.user, test.password)
+		}
+
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		if resp.Code != test.status {
+			t.Errorf("%q: expected status %d, got %d", test.user, test.status, resp.Code)
+		}
+
+		if test.status == http.StatusUnauthorized && !strings.Contains(resp.Body.String(), "2130") {
+			t.Errorf("%q: expected error 2130, got %s", test.user, resp.Body.String())
+		}
+	}
+}
@@ -0,0 +1,87 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package http
+
+import (
+	"net/http"
+
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/server"
+	"github.com/gorilla/mux"
+)
+
+const (
+	completedRequestsPrefix = adminPrefix + "/completed_requests"
+)
+
+func registerCompletedRequestsHandlers(r *mux.Router, server *server.Server) {
+	completedRequestsHandler := func(w http.ResponseWriter, req *http.Request) {
+		wrapAPI(server, w, req, doCompletedRequests)
+	}
+	completedRequestHandler := func(w http.ResponseWriter, req *http.Request) {
+		wrapAPI(server, w, req, doCompletedRequest)
+	}
+
+	routeMap := map[string]struct {
+		handler handlerFunc
+		methods []string
+	}{
+		completedRequestsPrefix:                {handler: completedRequestsHandler, methods: []string{"GET", "DELETE"}},
+		completedRequestsPrefix + "/{request}": {handler: completedRequestHandler, methods: []string{"GET", "DELETE"}},
+	}
+
+	for route, h := range routeMap {
+		r.HandleFunc(route, h.handler).Methods(h.methods...)
+	}
+}
+
+// A DELETE clears the log, and returns the requests it contained
+func doCompletedRequests(s *server.Server, w http.ResponseWriter, req *http.Request) (interface{}, errors.Error) {
+	completed := s.CompletedRequests()
+	ids := completed.Ids()
+	data := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		entry, ok := completed.Entry(id)
+		if ok {
+			data = append(data, entry)
+		}
+	}
+
+	switch req.Method {
+	case "GET":
+		return data, nil
+	case "DELETE":
+		completed.Clear()
+		return data, nil
+	default:
+		return nil, nil
+	}
+}
+
+func doCompletedRequest(s *server.Server, w http.ResponseWriter, req *http.Request) (interface{}, errors.Error) {
+	vars := mux.Vars(req)
+	id := vars["request"]
+
+	completed := s.CompletedRequests()
+	entry, ok := completed.Entry(id)
+	if !ok {
+		return nil, nil
+	}
+
+	switch req.Method {
+	case "GET":
+		return entry, nil
+	case "DELETE":
+		completed.Delete(id)
+		return entry, nil
+	default:
+		return nil, nil
+	}
+}
@@ -63,7 +63,14 @@ func writeError(w http.ResponseWriter, err errors.Error) {
 }
 
 func mapErrorToHttpStatus(err errors.Error) int {
-	return http.StatusInternalServerError
+	switch err.Code() {
+	case 2120: // no such prepared statement
+		return http.StatusNotFound
+	case 2130: // administrator credentials required
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
 }
 
 func GetAdminURL(host string, port int) string {
@@ -0,0 +1,96 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/plan"
+	"github.com/couchbaselabs/query/server"
+	"github.com/gorilla/mux"
+)
+
+const (
+	preparedsPrefix = adminPrefix + "/prepareds"
+)
+
+func registerPreparedsHandlers(r *mux.Router, server *server.Server) {
+	preparedsHandler := func(w http.ResponseWriter, req *http.Request) {
+		wrapAPI(server, w, req, doPrepareds)
+	}
+	preparedHandler := func(w http.ResponseWriter, req *http.Request) {
+		wrapAPI(server, w, req, doPrepared)
+	}
+
+	routeMap := map[string]struct {
+		handler handlerFunc
+		methods []string
+	}{
+		preparedsPrefix:                 {handler: preparedsHandler, methods: []string{"GET"}},
+		preparedsPrefix + "/{prepared}": {handler: preparedHandler, methods: []string{"GET", "DELETE"}},
+	}
+
+	for route, h := range routeMap {
+		r.HandleFunc(route, h.handler).Methods(h.methods...)
+	}
+}
+
+func doPrepareds(s *server.Server, w http.ResponseWriter, req *http.Request) (interface{}, errors.Error) {
+	switch req.Method {
+	case "GET":
+		entries := plan.PreparedCache().Entries()
+		data := make([]map[string]interface{}, len(entries))
+		for i, entry := range entries {
+			data[i] = getPreparedData(entry)
+		}
+		return data, nil
+	default:
+		return nil, nil
+	}
+}
+
+func doPrepared(s *server.Server, w http.ResponseWriter, req *http.Request) (interface{}, errors.Error) {
+	vars := mux.Vars(req)
+	id := vars["prepared"]
+
+	entry, ok := plan.PreparedCache().Entry(id)
+	if !ok {
+		return nil, errors.NewAdminNoPreparedError(id)
+	}
+
+	switch req.Method {
+	case "GET":
+		return getPreparedData(entry), nil
+	case "DELETE":
+		plan.PreparedCache().Delete(id)
+		return getPreparedData(entry), nil
+	default:
+		return nil, nil
+	}
+}
+
+func getPreparedData(entry *plan.CachedPrepared) map[string]interface{} {
+	data := map[string]interface{}{
+		"key":       entry.Key,
+		"statement": entry.Prepared.Text(),
+		"keyspaces": entry.Keyspaces,
+		"uses":      entry.Uses,
+		"stale":     entry.Prepared.Stale(),
+	}
+	if entry.Prepared.Name() != "" {
+		data["name"] = entry.Prepared.Name()
+	}
+	if entry.Uses > 0 {
+		data["last_use"] = entry.LastUse.Format(time.RFC3339Nano)
+	}
+	return data
+}
@@ -0,0 +1,83 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/couchbaselabs/query/plan"
+	"github.com/gorilla/mux"
+)
+
+func doPreparedsRequest(t *testing.T, r *mux.Router, method, path string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(method, path, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	return resp
+}
+
+// An unknown prepared statement is not found; a known one is returned
+// and can be deleted.
+func TestPreparedsEndpoint(t *testing.T) {
+	r := mux.NewRouter()
+	registerPreparedsHandlers(r, nil)
+
+	for _, method := range []string{"GET", "DELETE"} {
+		resp := doPreparedsRequest(t, r, method, preparedsPrefix+"/no_such_prepared")
+		if resp.Code != http.StatusNotFound {
+			t.Errorf("%s: expected status %d, got %d", method, http.StatusNotFound, resp.Code)
+		}
+
+		var body map[string]interface{}
+		err := json.Unmarshal(resp.Body.Bytes(), &body)
+		if err != nil || body["code"] != 2120.0 {
+			t.Errorf("%s: expected error 2120, got %s", method, resp.Body.String())
+		}
+	}
+
+	prepared := &plan.Prepared{}
+	err := prepared.UnmarshalJSON([]byte(`{"operator": {"#operator": "DummyScan"}, "name": "ep_test",
+		"text": "PREPARE ep_test FROM SELECT 1"}`))
+	if err != nil {
+		t.Fatalf("failed to unmarshal prepared: %v", err)
+	}
+
+	err = plan.PreparedCache().AddNamed(prepared)
+	if err != nil {
+		t.Fatalf("failed to add named prepared: %v", err)
+	}
+	defer plan.PreparedCache().Delete("ep_test")
+
+	resp := doPreparedsRequest(t, r, "GET", preparedsPrefix+"/ep_test")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.Code)
+	}
+
+	resp = doPreparedsRequest(t, r, "DELETE", preparedsPrefix+"/ep_test")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.Code)
+	}
+
+	if _, ok := plan.PreparedCache().Entry("ep_test"); ok {
+		t.Errorf("expected ep_test to be deleted")
+	}
+
+	resp = doPreparedsRequest(t, r, "GET", preparedsPrefix+"/ep_test")
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("expected status %d after delete, got %d", http.StatusNotFound, resp.Code)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/couchbaselabs/query/accounting"
+	"github.com/couchbaselabs/query/errors"
 	"github.com/couchbaselabs/query/logging"
 	"github.com/couchbaselabs/query/server"
 	"github.com/gorilla/mux"
@@ -36,6 +37,10 @@ const (
 	servicePrefix = "/query/service"
 )
 
+// Seconds after which a client should retry a request whose queue was
+// full
+const RETRY_AFTER = 1
+
 func NewServiceEndpoint(server *server.Server, staticPath string, metrics bool) *HttpEndpoint {
 	rv := &HttpEndpoint{
 		server:  server,
@@ -76,8 +81,8 @@ func (this *HttpEndpoint) ListenTLS(addr, certFile, keyFile string) error {
 	return err
 }
 
-// If the server channel is full and we are unable to queue a request,
-// we respond with a timeout status.
+// If the queue of the workload class of a request is full, we respond
+// with a service unavailable status and a Retry-After header.
 func (this *HttpEndpoint) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	request := newHttpRequest(resp, req, this.bufpool)
 
@@ -89,15 +94,17 @@ func (this *HttpEndpoint) ServeHTTP(resp http.ResponseWriter, req *http.Request)
 		return
 	}
 
-	select {
-	case this.server.Channel() <- request:
-		// Wait until the request exits.
-		<-request.CloseNotify()
-	default:
-		// Timeout.
-		resp.WriteHeader(http.StatusServiceUnavailable)
+	class, ok := this.server.Submit(request)
+	if !ok {
+		// The queue of the workload class is full
+		resp.Header().Set("Retry-After", strconv.Itoa(RETRY_AFTER))
+		request.Fail(errors.NewServiceErrorQueueFull(class.Name))
+		request.Failed(this.server)
+		return
 	}
 
+	// Wait until the request exits.
+	<-request.CloseNotify()
 }
 
 func (this *HttpEndpoint) Close() {
@@ -126,6 +133,9 @@ func (this *HttpEndpoint) registerHandlers(staticPath string) {
 
 	registerClusterHandlers(this.mux, this.server)
 	registerAccountingHandlers(this.mux, this.server)
+	registerPreparedsHandlers(this.mux, this.server)
+	registerActiveRequestsHandlers(this.mux, this.server)
+	registerCompletedRequestsHandlers(this.mux, this.server)
 }
 
 func (this *HttpEndpoint) doStats(request *httpRequest) {
@@ -135,6 +145,9 @@ func (this *HttpEndpoint) doStats(request *httpRequest) {
 	acctstore := this.server.AccountingStore()
 	accounting.RecordMetrics(acctstore, request_time, service_time, request.resultCount,
 		request.resultSize, request.errorCount, request.warningCount, request.Statement())
+
+	this.server.CompletedRequests().Record(request, request.resultCount,
+		request.resultSize, request.errorCodes)
 }
 
 func GetServiceURL(host string, port int) string {
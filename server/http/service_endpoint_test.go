@@ -0,0 +1,115 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	accounting_stub "github.com/couchbaselabs/query/accounting/stub"
+	clustering_stub "github.com/couchbaselabs/query/clustering/stub"
+	"github.com/couchbaselabs/query/datastore/mock"
+	"github.com/couchbaselabs/query/server"
+)
+
+type testResponse struct {
+	*httptest.ResponseRecorder
+	closeNotify chan bool
+}
+
+func newTestResponse() *testResponse {
+	return &testResponse{
+		ResponseRecorder: httptest.NewRecorder(),
+		closeNotify:      make(chan bool, 1),
+	}
+}
+
+func (this *testResponse) CloseNotify() <-chan bool {
+	return this.closeNotify
+}
+
+func newTestRequest(t *testing.T, statement string) *http.Request {
+	form := url.Values{"statement": {statement}}
+	req, err := http.NewRequest("POST", servicePrefix, strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+// A request whose workload queue is full is rejected with a service
+// unavailable status, a Retry-After header and error 1080.
+func TestServiceQueueFull(t *testing.T) {
+	store, err := mock.NewDatastore("mock:")
+	if err != nil {
+		t.Fatalf("failed to create mock store: %v", err)
+	}
+
+	configstore, err := clustering_stub.NewConfigurationStore()
+	if err != nil {
+		t.Fatalf("failed to create config store: %v", err)
+	}
+
+	acctstore, err := accounting_stub.NewAccountingStore("stub:")
+	if err != nil {
+		t.Fatalf("failed to create accounting store: %v", err)
+	}
+
+	srvr, err := server.NewServer(store, configstore, acctstore, "p0", false, 10,
+		1, 0, false, false, server.KEEP_ALIVE_DEFAULT)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	srvr.SetWorkloadClasses([]*server.WorkloadClass{
+		&server.WorkloadClass{Name: server.DEFAULT_WORKLOAD, QueueDepth: 1},
+	})
+
+	// The server is not serving, so the first request stays queued
+	endpoint := NewServiceEndpoint(srvr, "", false)
+	queued := newHttpRequest(newTestResponse(), newTestRequest(t, "SELECT 1"), endpoint.bufpool)
+	_, ok := srvr.Submit(queued)
+	if !ok {
+		t.Fatalf("expected the first request to be queued")
+	}
+
+	resp := newTestResponse()
+	endpoint.ServeHTTP(resp, newTestRequest(t, "SELECT 2"))
+
+	if resp.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, resp.Code)
+	}
+
+	if resp.Header().Get("Retry-After") != strconv.Itoa(RETRY_AFTER) {
+		t.Errorf("expected Retry-After %d, got %q", RETRY_AFTER, resp.Header().Get("Retry-After"))
+	}
+
+	var body struct {
+		Errors []struct {
+			Code int `json:"code"`
+		} `json:"errors"`
+	}
+
+	e := json.Unmarshal(resp.Body.Bytes(), &body)
+	if e != nil {
+		t.Fatalf("failed to unmarshal response %s: %v", resp.Body.String(), e)
+	}
+
+	if len(body.Errors) != 1 || body.Errors[0].Code != 1080 {
+		t.Errorf("expected error 1080, got %s", resp.Body.String())
+	}
+}
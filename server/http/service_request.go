@@ -20,11 +20,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/couchbaselabs/query/algebra"
 	"github.com/couchbaselabs/query/datastore"
 	"github.com/couchbaselabs/query/errors"
+	"github.com/couchbaselabs/query/execution"
 	"github.com/couchbaselabs/query/plan"
 	"github.com/couchbaselabs/query/server"
 	"github.com/couchbaselabs/query/timestamp"
+	"github.com/couchbaselabs/query/util"
 	"github.com/couchbaselabs/query/value"
 )
 
@@ -39,6 +42,7 @@ type httpRequest struct {
 	resultCount  int
 	resultSize   int
 	errorCount   int
+	errorCodes   []int
 	warningCount int
 }
 
@@ -164,6 +168,26 @@ func newHttpRequest(resp http.ResponseWriter, req *http.Request, bp BufferPool)
 		client_id, err = httpArgs.getString(CLIENT_CONTEXT_ID, "")
 	}
 
+	var limits execution.Limits
+	if err == nil {
+		limits, err = getLimits(httpArgs)
+	}
+
+	priority := ""
+	if err == nil {
+		priority, err = httpArgs.getString(PRIORITY, "")
+	}
+
+	var profile server.Profile
+	if err == nil {
+		profile, err = getProfile(httpArgs)
+	}
+
+	explainFormat := ""
+	if err == nil {
+		explainFormat, err = getExplainFormat(httpArgs)
+	}
+
 	base := server.NewBaseRequest(statement, prepared, namedArgs, positionalArgs,
 		namespace, readonly, metrics, signature, consistency, client_id, creds)
 
@@ -174,6 +198,10 @@ func newHttpRequest(resp http.ResponseWriter, req *http.Request, bp BufferPool)
 	}
 
 	rv.SetTimeout(rv, timeout)
+	rv.SetLimits(limits)
+	rv.SetPriority(priority)
+	rv.SetProfile(profile)
+	rv.SetExplainFormat(explainFormat)
 
 	rv.writer = NewBufferedWriter(rv, bp)
 
@@ -212,6 +240,13 @@ const ( // Request argument names
 	SCAN_VECTOR       = "scan_vector"
 	CREDS             = "creds"
 	CLIENT_CONTEXT_ID = "client_context_id"
+	MEMORY_QUOTA      = "memory_quota"
+	MAX_FETCH         = "max_fetch"
+	MAX_RESULT_SIZE   = "max_result_size"
+	MAX_MUTATIONS     = "max_mutations"
+	PRIORITY          = "priority"
+	PROFILE           = "profile"
+	EXPLAIN_FORMAT    = "explain_format"
 )
 
 func getPrepared(a httpRequestArgs) (*plan.Prepared, errors.Error) {
@@ -220,6 +255,26 @@ func getPrepared(a httpRequestArgs) (*plan.Prepared, errors.Error) {
 		return nil, err
 	}
 
+	// A prepared statement may be given by the name it was
+	// prepared under, either as a JSON string or a bare name
+	var name string
+	switch prepared_field.Type() {
+	case value.STRING:
+		name = prepared_field.Actual().(string)
+	case value.BINARY:
+		name = string(prepared_field.Actual().([]byte))
+	}
+
+	if name != "" {
+		prepared := plan.PreparedCache().GetNamed(name)
+		if prepared == nil {
+			return nil, errors.NewServiceErrorBadValue(
+				fmt.Errorf("No such prepared statement: %s.", name), PREPARED)
+		}
+
+		return prepared, nil
+	}
+
 	prepared, e := plan.PreparedCache().GetPrepared(prepared_field)
 	if e != nil {
 		return nil, errors.NewServiceErrorBadValue(e, PREPARED)
@@ -260,6 +315,49 @@ func getCompression(a httpRequestArgs) (Compression, errors.Error) {
 	return compression, err
 }
 
+func getProfile(a httpRequestArgs) (server.Profile, errors.Error) {
+	var profile server.Profile
+
+	profile_field, err := a.getString(PROFILE, "OFF")
+	if err == nil && profile_field != "" {
+		profile = newProfile(profile_field)
+		if profile == server.UNDEFINED_PROFILE {
+			err = errors.NewServiceErrorUnrecognizedValue(PROFILE, profile_field)
+		}
+	}
+	return profile, err
+}
+
+func getExplainFormat(a httpRequestArgs) (string, errors.Error) {
+	format_field, err := a.getString(EXPLAIN_FORMAT, "")
+	if err != nil || format_field == "" {
+		return "", err
+	}
+
+	format := strings.ToLower(format_field)
+	if !algebra.IsExplainFormat(format) {
+		return "", errors.NewServiceErrorUnrecognizedValue(EXPLAIN_FORMAT, format_field)
+	}
+	return format, nil
+}
+
+func getLimits(a httpRequestArgs) (execution.Limits, errors.Error) {
+	var limits execution.Limits
+	var err errors.Error
+
+	limits.MemoryQuota, err = a.getQuantity(MEMORY_QUOTA)
+	if err == nil {
+		limits.MaxFetch, err = a.getQuantity(MAX_FETCH)
+	}
+	if err == nil {
+		limits.MaxResultSize, err = a.getQuantity(MAX_RESULT_SIZE)
+	}
+	if err == nil {
+		limits.MaxMutations, err = a.getQuantity(MAX_MUTATIONS)
+	}
+	return limits, err
+}
+
 func getScanConfiguration(a httpRequestArgs) (*scanConfigImpl, errors.Error) {
 	var sc scanConfigImpl
 
@@ -372,6 +470,7 @@ type httpRequestArgs interface {
 	getTristate(f string) (value.Tristate, errors.Error)
 	getValue(field string) (value.Value, errors.Error)
 	getDuration(string) (time.Duration, errors.Error)
+	getQuantity(string) (int64, errors.Error)
 	getNamedArgs() (map[string]value.Value, errors.Error)
 	getPositionalArgs() (value.Values, errors.Error)
 	getStatement() (string, errors.Error)
@@ -508,6 +607,16 @@ func (this *urlArgs) getDuration(f string) (time.Duration, errors.Error) {
 	return timeout, err
 }
 
+func (this *urlArgs) getQuantity(f string) (int64, errors.Error) {
+	var quantity int64
+
+	quantity_field, err := this.formValue(f)
+	if err == nil && quantity_field != "" {
+		quantity, err = newQuantity(quantity_field, f)
+	}
+	return quantity, err
+}
+
 func (this *urlArgs) getString(f string, dflt string) (string, errors.Error) {
 	value := dflt
 
@@ -725,6 +834,24 @@ func (this *jsonArgs) getDuration(f string) (time.Duration, errors.Error) {
 	return timeout, err
 }
 
+// helper function to get a quantity argument, either a number or a
+// string with an optional unit
+func (this *jsonArgs) getQuantity(f string) (int64, errors.Error) {
+	value_field, in_request := this.args[f]
+	if !in_request {
+		return 0, nil
+	}
+
+	switch value_field := value_field.(type) {
+	case float64:
+		return int64(value_field), nil
+	case string:
+		return newQuantity(value_field, f)
+	default:
+		return 0, errors.NewServiceErrorTypeMismatch(f, "number or string")
+	}
+}
+
 func (this *jsonArgs) getTristate(f string) (value.Tristate, errors.Error) {
 	value_tristate := value.NONE
 	value_field, in_request := this.args[f]
@@ -1000,6 +1127,17 @@ func newScanConsistency(s string) server.ScanConsistency {
 	}
 }
 
+func newProfile(s string) server.Profile {
+	switch strings.ToUpper(s) {
+	case "OFF":
+		return server.PROFILE_OFF
+	case "TIMINGS":
+		return server.PROFILE_TIMINGS
+	default:
+		return server.UNDEFINED_PROFILE
+	}
+}
+
 // addNamedArgs is used by getNamedArgs implementations to add a named argument
 func addNamedArg(args map[string]value.Value, name string, arg value.Value) map[string]value.Value {
 	if args == nil {
@@ -1030,3 +1168,17 @@ func newDuration(s string) (duration time.Duration, err errors.Error) {
 	}
 	return
 }
+
+// helper function to parse a quantity from a given string, e.g. 1000,
+// 64kb or 16mb
+func newQuantity(s string, f string) (int64, errors.Error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	q, e := util.ParseQuantity(s)
+	if e != nil {
+		return 0, errors.NewServiceErrorBadValue(e, f)
+	}
+	return int64(q), nil
+}
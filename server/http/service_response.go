@@ -46,6 +46,8 @@ func mapErrorToHttpResponse(err errors.Error) int {
 		return http.StatusMethodNotAllowed
 	case 1020, 1030, 1040, 1050, 1060, 1070:
 		return http.StatusBadRequest
+	case 1080: // request queue full
+		return http.StatusServiceUnavailable
 	case 3000: // parse error range
 		return http.StatusBadRequest
 	case 4000: // plan error range
@@ -152,20 +154,25 @@ func (this *httpRequest) writeResults() bool {
 }
 
 func (this *httpRequest) writeResult(item value.Value) bool {
-	var rv bool
-	if this.resultCount == 0 {
-		rv = this.writeString("\n")
-	} else {
-		rv = this.writeString(",\n")
-	}
-
 	bytes, err := json.MarshalIndent(item, "        ", "    ")
 	if err != nil {
 		this.Errors() <- errors.NewServiceErrorInvalidJSON(err)
 		return false
 	}
 
+	if !this.WithinResultSize(this.resultSize + len(bytes)) {
+		return false
+	}
+
 	this.resultSize += len(bytes)
+
+	var rv bool
+	if this.resultCount == 0 {
+		rv = this.writeString("\n")
+	} else {
+		rv = this.writeString(",\n")
+	}
+
 	this.resultCount++
 
 	return rv &&
@@ -184,6 +191,7 @@ func (this *httpRequest) writeValue(item value.Value) bool {
 
 func (this *httpRequest) writeSuffix(metrics bool, state server.State) bool {
 	return this.writeString("\n    ]") &&
+		this.writeProfile(state) &&
 		this.writeErrors() &&
 		this.writeWarnings() &&
 		this.writeState(state) &&
@@ -191,6 +199,24 @@ func (this *httpRequest) writeSuffix(metrics bool, state server.State) bool {
 		this.writeString("\n}\n")
 }
 
+// The profile is only written once the request has completed, when
+// its pipeline has run or is about to.
+func (this *httpRequest) writeProfile(state server.State) bool {
+	profiler := this.Profiler()
+	if profiler == nil || state != "" || this.State() != server.COMPLETED {
+		return true
+	}
+
+	plan, err := profiler.Plan()
+	if err != nil {
+		this.Errors() <- errors.NewServiceErrorInvalidJSON(err)
+		return true
+	}
+
+	return this.writeString(",\n    \"profile\": ") &&
+		this.writeValue(value.NewValue(plan))
+}
+
 func (this *httpRequest) writeString(s string) bool {
 	return this.writer.writeString(s)
 }
@@ -224,6 +250,7 @@ loop:
 				}
 				ok = this.writeError(err, this.errorCount)
 				this.errorCount++
+				this.errorCodes = append(this.errorCodes, int(err.Code()))
 			}
 		default:
 			break loop
@@ -296,6 +323,11 @@ func (this *httpRequest) writeMetrics(metrics bool) bool {
 		rv = rv && this.writeString(fmt.Sprintf(",\n        \"mutationCount\": %d", this.MutationCount()))
 	}
 
+	if this.SortSpillCount() > 0 {
+		rv = rv && this.writeString(fmt.Sprintf(",\n        \"sortSpillCount\": %d", this.SortSpillCount())) &&
+			this.writeString(fmt.Sprintf(",\n        \"sortSpillSize\": %d", this.SortSpillSize()))
+	}
+
 	if this.errorCount > 0 {
 		rv = rv && this.writeString(fmt.Sprintf(",\n        \"errorCount\": %d", this.errorCount))
 	}
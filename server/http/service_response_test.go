@@ -0,0 +1,53 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package http
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/couchbaselabs/query/execution"
+	"github.com/couchbaselabs/query/value"
+)
+
+// The maximum result size applies to the bytes written for each row;
+// the row that exceeds it is not written, and the request fails.
+func TestWriteResultMaxSize(t *testing.T) {
+	row := value.NewValue(map[string]interface{}{"a": 1.0})
+	bytes, _ := json.MarshalIndent(row, "        ", "    ")
+
+	resp := newTestResponse()
+	request := newHttpRequest(resp, newTestRequest(t, "SELECT 1"), NewSyncPool(1024))
+	request.SetLimits(execution.Limits{MaxResultSize: int64(2*len(bytes) + 1)})
+
+	for i := 0; i < 2; i++ {
+		if !request.writeResult(row) {
+			t.Fatalf("expected row %d within the maximum result size", i)
+		}
+	}
+
+	if request.writeResult(row) {
+		t.Fatalf("expected the third row to exceed the maximum result size")
+	}
+
+	if request.resultCount != 2 || request.resultSize != 2*len(bytes) {
+		t.Errorf("expected 2 rows and %d bytes, got %d and %d", 2*len(bytes),
+			request.resultCount, request.resultSize)
+	}
+
+	select {
+	case err := <-request.Errors():
+		if err.Code() != 5520 {
+			t.Errorf("expected error 5520, got %v", err)
+		}
+	default:
+		t.Errorf("expected an error for the maximum result size")
+	}
+}
@@ -0,0 +1,102 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/couchbaselabs/query/algebra"
+	"github.com/couchbaselabs/query/datastore/mock"
+	"github.com/couchbaselabs/query/logging"
+	log_resolver "github.com/couchbaselabs/query/logging/resolver"
+	"github.com/couchbaselabs/query/parser/n1ql"
+	"github.com/couchbaselabs/query/plan"
+)
+
+// A request with only what getPrepared looks at
+type preparedRequest struct {
+	Request
+	prepared *plan.Prepared
+}
+
+func (this *preparedRequest) Prepared() *plan.Prepared {
+	return this.prepared
+}
+
+// A stale prepared statement is prepared again from its text before
+// it is executed, and replaces the stale one in the cache.
+func TestReprepare(t *testing.T) {
+	logger, _ := log_resolver.NewLogger("golog")
+	if logger == nil {
+		t.Fatalf("Invalid logger")
+	}
+
+	logging.SetLogger(logger)
+
+	store, e := mock.NewDatastore("mock:items=10")
+	if e != nil {
+		t.Fatalf("failed to create mock store: %v", e)
+	}
+
+	srvr := &Server{datastore: store}
+
+	text := "PREPARE rp_test FROM SELECT * FROM b0"
+	stmt, err := n1ql.ParseStatement(text)
+	if err != nil {
+		t.Fatalf("failed to parse statement: %v", err)
+	}
+
+	prepared, err := plan.BuildPrepared(stmt.(*algebra.Prepare).Statement(), store, nil, "p0", false)
+	if err != nil {
+		t.Fatalf("failed to build prepared: %v", err)
+	}
+
+	prepared.SetName("rp_test")
+	prepared.SetText(text)
+	err = plan.PreparedCache().AddNamed(prepared)
+	if err != nil {
+		t.Fatalf("failed to add named prepared: %v", err)
+	}
+	defer plan.PreparedCache().Delete("rp_test")
+
+	// Not stale, so the prepared statement is used as is
+	rv, er := srvr.getPrepared(&preparedRequest{prepared: prepared}, "p0")
+	if er != nil || rv != prepared {
+		t.Fatalf("expected the cached prepared, got %v, %v", rv, er)
+	}
+
+	// Another keyspace does not make it stale
+	plan.PreparedCache().InvalidateKeyspace("p0", "b1")
+	if prepared.Stale() {
+		t.Fatalf("expected the prepared not to be stale")
+	}
+
+	plan.PreparedCache().InvalidateKeyspace("p0", "b0")
+	if !prepared.Stale() {
+		t.Fatalf("expected the prepared to be stale")
+	}
+
+	rv, er = srvr.getPrepared(&preparedRequest{prepared: prepared}, "p0")
+	if er != nil {
+		t.Fatalf("failed to prepare again: %v", er)
+	}
+
+	if rv == prepared || rv.Stale() {
+		t.Errorf("expected a new prepared that is not stale")
+	}
+
+	if rv.Name() != "rp_test" || rv.Text() != text {
+		t.Errorf("expected the name and text to be kept, got %s, %s", rv.Name(), rv.Text())
+	}
+
+	if cached := plan.PreparedCache().GetNamed("rp_test"); cached != rv {
+		t.Errorf("expected the new prepared to replace the stale one in the cache")
+	}
+}
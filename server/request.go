@@ -22,8 +22,6 @@ import (
 	"github.com/couchbaselabs/query/value"
 )
 
-type RequestChannel chan Request
-
 const RESULT_CAP = 1 << 14
 const ERROR_CAP = 1 << 10
 
@@ -44,10 +42,19 @@ type Request interface {
 	ClientID() ClientContextID
 	Statement() string
 	Prepared() *plan.Prepared
+	SetPrepared(prepared *plan.Prepared)
 	NamedArgs() map[string]value.Value
+	SetNamedArgs(args map[string]value.Value)
 	PositionalArgs() value.Values
+	SetPositionalArgs(args value.Values)
 	Namespace() string
 	Timeout() time.Duration
+	Limits() execution.Limits
+	SetLimits(limits execution.Limits)
+	Priority() string
+	Profile() Profile
+	SetProfiler(profiler *execution.Profiler)
+	ExplainFormat() string
 	Readonly() value.Tristate
 	Metrics() value.Tristate
 	Signature() value.Tristate
@@ -62,6 +69,7 @@ type Request interface {
 	Execute(server *Server, signature value.Value, notifyStop chan bool)
 	Failed(server *Server)
 	Expire()
+	Stop(state State)
 	State() State
 	Credentials() datastore.Credentials
 }
@@ -85,6 +93,14 @@ const (
 	UNDEFINED_CONSISTENCY
 )
 
+type Profile int
+
+const (
+	PROFILE_OFF Profile = iota
+	PROFILE_TIMINGS
+	UNDEFINED_PROFILE
+)
+
 type ScanConfiguration interface {
 	ScanConsistency() datastore.ScanConsistency
 	ScanWait() time.Duration
@@ -100,11 +116,18 @@ type BaseRequest struct {
 	positionalArgs value.Values
 	namespace      string
 	timeout        time.Duration
+	limits         execution.Limits
+	priority       string
+	profile        Profile
+	profiler       *execution.Profiler
+	explainFormat  string
 	readonly       value.Tristate
 	signature      value.Tristate
 	metrics        value.Tristate
 	consistency    ScanConfiguration
 	mutationCount  uint64
+	sortSpillCount uint64
+	sortSpillSize  uint64
 	requestTime    time.Time
 	serviceTime    time.Time
 	state          State
@@ -205,14 +228,28 @@ func (this *BaseRequest) Prepared() *plan.Prepared {
 	return this.prepared
 }
 
+// Set the prepared statement of the request, once the server has
+// planned its statement text.
+func (this *BaseRequest) SetPrepared(prepared *plan.Prepared) {
+	this.prepared = prepared
+}
+
 func (this *BaseRequest) NamedArgs() map[string]value.Value {
 	return this.namedArgs
 }
 
+func (this *BaseRequest) SetNamedArgs(args map[string]value.Value) {
+	this.namedArgs = args
+}
+
 func (this *BaseRequest) PositionalArgs() value.Values {
 	return this.positionalArgs
 }
 
+func (this *BaseRequest) SetPositionalArgs(args value.Values) {
+	this.positionalArgs = args
+}
+
 func (this *BaseRequest) Namespace() string {
 	return this.namespace
 }
@@ -221,6 +258,68 @@ func (this *BaseRequest) Timeout() time.Duration {
 	return this.timeout
 }
 
+// Set the resource limits of the request; the server applies the
+// lower of these and its own limits, and sets them before execution.
+func (this *BaseRequest) SetLimits(limits execution.Limits) {
+	this.limits = limits
+}
+
+func (this *BaseRequest) Limits() execution.Limits {
+	return this.limits
+}
+
+// Whether results of the given size in bytes, as written to the
+// response, are within the maximum result size. If not, the request
+// fails.
+func (this *BaseRequest) WithinResultSize(size int) bool {
+	max := this.limits.MaxResultSize
+	if max <= 0 || int64(size) <= max {
+		return true
+	}
+
+	this.Fatal(errors.NewResultSizeExceededError(max))
+	return false
+}
+
+// Set the name of the workload class of the request, which takes
+// precedence over classifying it by user or statement type.
+func (this *BaseRequest) SetPriority(priority string) {
+	this.priority = priority
+}
+
+func (this *BaseRequest) Priority() string {
+	return this.priority
+}
+
+// Set whether the response includes the plan annotated with the
+// statistics of each operator.
+func (this *BaseRequest) SetProfile(profile Profile) {
+	this.profile = profile
+}
+
+func (this *BaseRequest) Profile() Profile {
+	return this.profile
+}
+
+// Set the profiler of the execution of the request, if it is profiled.
+func (this *BaseRequest) SetProfiler(profiler *execution.Profiler) {
+	this.profiler = profiler
+}
+
+func (this *BaseRequest) Profiler() *execution.Profiler {
+	return this.profiler
+}
+
+// Set the format of EXPLAIN output, unless the statement specifies
+// one; empty for JSON.
+func (this *BaseRequest) SetExplainFormat(format string) {
+	this.explainFormat = format
+}
+
+func (this *BaseRequest) ExplainFormat() string {
+	return this.explainFormat
+}
+
 func (this *BaseRequest) Readonly() value.Tristate {
 	return this.readonly
 }
@@ -322,6 +421,19 @@ func (this *BaseRequest) MutationCount() uint64 {
 	return atomic.LoadUint64(&this.mutationCount)
 }
 
+func (this *BaseRequest) AddSortSpill(size uint64) {
+	atomic.AddUint64(&this.sortSpillCount, 1)
+	atomic.AddUint64(&this.sortSpillSize, size)
+}
+
+func (this *BaseRequest) SortSpillCount() uint64 {
+	return atomic.LoadUint64(&this.sortSpillCount)
+}
+
+func (this *BaseRequest) SortSpillSize() uint64 {
+	return atomic.LoadUint64(&this.sortSpillSize)
+}
+
 func (this *BaseRequest) Results() value.ValueChannel {
 	return this.results
 }
@@ -10,19 +10,23 @@
 package server
 
 import (
+	"fmt"
 	"os"
 	"runtime"
 
 	"encoding/json"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/couchbaselabs/query/accounting"
+	"github.com/couchbaselabs/query/algebra"
 	"github.com/couchbaselabs/query/clustering"
 	"github.com/couchbaselabs/query/datastore"
 	"github.com/couchbaselabs/query/datastore/system"
 	"github.com/couchbaselabs/query/errors"
 	"github.com/couchbaselabs/query/execution"
+	"github.com/couchbaselabs/query/expression"
 	"github.com/couchbaselabs/query/logging"
 	"github.com/couchbaselabs/query/parser/n1ql"
 	"github.com/couchbaselabs/query/plan"
@@ -36,12 +40,20 @@ type Server struct {
 	acctstore   accounting.AccountingStore
 	namespace   string
 	readonly    bool
-	channel     RequestChannel
+	requestCap  int
+	workloads   *workloads
+	active      *ActiveRequests
+	completed   *CompletedRequests
 	threadCount int
 	timeout     time.Duration
 	signature   bool
 	metrics     bool
 	keepAlive   int
+	orderLimit  int64
+	updateLimit int64
+	sortMemory  int64
+	hashMemory  int64
+	limits      execution.Limits
 	once        sync.Once
 }
 
@@ -51,7 +63,7 @@ const KEEP_ALIVE_DEFAULT = 1024 * 16
 
 func NewServer(store datastore.Datastore, config clustering.ConfigurationStore,
 	acctng accounting.AccountingStore, namespace string, readonly bool,
-	channel RequestChannel, threadCount int, timeout time.Duration,
+	requestCap, threadCount int, timeout time.Duration,
 	signature, metrics bool, keepAlive int) (*Server, errors.Error) {
 	rv := &Server{
 		datastore:   store,
@@ -59,7 +71,10 @@ func NewServer(store datastore.Datastore, config clustering.ConfigurationStore,
 		acctstore:   acctng,
 		namespace:   namespace,
 		readonly:    readonly,
-		channel:     channel,
+		requestCap:  requestCap,
+		workloads:   newWorkloads(nil, requestCap, threadCount),
+		active:      newActiveRequests(),
+		completed:   newCompletedRequests(),
 		threadCount: threadCount,
 		timeout:     timeout,
 		signature:   signature,
@@ -73,9 +88,83 @@ func NewServer(store datastore.Datastore, config clustering.ConfigurationStore,
 	}
 
 	rv.systemstore = sys
+	system.SetActiveRequests(rv.active)
+	system.SetCompletedRequests(rv.completed)
+
+	if acctng != nil {
+		plan.PreparedCache().SetMetrics(acctng.MetricRegistry())
+	}
+
+	err = loadFunctions(store)
+	if err != nil {
+		return nil, err
+	}
+
 	return rv, nil
 }
 
+// Register the user-defined functions persisted in the datastore. All
+// the functions are registered before their bodies are parsed, so that
+// bodies can call functions defined later. A function whose body no
+// longer parses, e.g. because it calls a dropped function, stays
+// registered without a body, so that it can be replaced or dropped.
+func loadFunctions(store datastore.Datastore) errors.Error {
+	namespaceIds, err := store.NamespaceIds()
+	if err != nil {
+		return err
+	}
+
+	type function struct {
+		namespace  string
+		definition *datastore.FunctionDefinition
+	}
+
+	functions := make([]function, 0, 16)
+	for _, namespaceId := range namespaceIds {
+		namespace, err := store.NamespaceById(namespaceId)
+		if err != nil {
+			return err
+		}
+
+		fnamespace, ok := namespace.(datastore.FunctionNamespace)
+		if !ok {
+			continue
+		}
+
+		names, err := fnamespace.FunctionNames()
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			definition, err := fnamespace.FunctionByName(name)
+			if err != nil {
+				return err
+			}
+
+			functions = append(functions, function{namespace.Name(), definition})
+			expression.RegisterUserFunction(expression.NewUserFunctionDefinition(
+				namespace.Name(), definition.Name, definition.Parameters, nil))
+		}
+	}
+
+	for _, f := range functions {
+		body, e := n1ql.ParseExpression(f.definition.Body)
+		if e != nil {
+			logging.Errorp("Invalid body of function",
+				logging.Pair{"name", f.definition.Name},
+				logging.Pair{"error", e},
+			)
+			continue
+		}
+
+		expression.RegisterUserFunction(expression.NewUserFunctionDefinition(
+			f.namespace, f.definition.Name, f.definition.Parameters, body))
+	}
+
+	return nil
+}
+
 func (this *Server) Datastore() datastore.Datastore {
 	return this.datastore
 }
@@ -88,8 +177,26 @@ func (this *Server) AccountingStore() accounting.AccountingStore {
 	return this.acctstore
 }
 
-func (this *Server) Channel() RequestChannel {
-	return this.channel
+// Queue a request for execution. Return its workload class, and false
+// if the queue of the class is full.
+func (this *Server) Submit(request Request) (*WorkloadClass, bool) {
+	return this.workloads.submit(request, func(class *WorkloadClass) {
+		this.active.add(request, class.Name)
+	})
+}
+
+func (this *Server) ActiveRequests() *ActiveRequests {
+	return this.active
+}
+
+func (this *Server) CompletedRequests() *CompletedRequests {
+	return this.completed
+}
+
+// Set the workload classes of requests, in addition to the default
+// class; must be called before Serve
+func (this *Server) SetWorkloadClasses(classes []*WorkloadClass) {
+	this.workloads = newWorkloads(classes, this.requestCap, this.threadCount)
 }
 
 func (this *Server) Signature() bool {
@@ -104,6 +211,65 @@ func (this *Server) KeepAlive() int {
 	return this.keepAlive
 }
 
+func (this *Server) OrderLimit() int64 {
+	return this.orderLimit
+}
+
+// Set the maximum LIMIT plus OFFSET for ORDER BY; use zero or negative
+// value to disable
+func (this *Server) SetOrderLimit(limit int64) {
+	this.orderLimit = limit
+}
+
+func (this *Server) UpdateLimit() int64 {
+	return this.updateLimit
+}
+
+// Set the maximum LIMIT, and number of mutated documents, of DELETE
+// and UPDATE; use zero or negative value to disable
+func (this *Server) SetUpdateLimit(limit int64) {
+	this.updateLimit = limit
+}
+
+// Apply the ORDER BY and update limits of the options the query node
+// was started with
+func (this *Server) SetOptions(options clustering.QueryNodeOptions) {
+	this.SetOrderLimit(int64(options.OrderLimit()))
+	this.SetUpdateLimit(int64(options.UpdateLimit()))
+}
+
+func (this *Server) SortMemory() int64 {
+	return this.sortMemory
+}
+
+// Set the memory budget in bytes for each ORDER BY, beyond which
+// sorted runs are spilled to disk; use zero or negative value to
+// disable
+func (this *Server) SetSortMemory(size int64) {
+	this.sortMemory = size
+}
+
+func (this *Server) HashMemory() int64 {
+	return this.hashMemory
+}
+
+// Set the memory budget in bytes for each hash table of GROUP BY,
+// DISTINCT, INTERSECT and EXCEPT, beyond which it is partitioned to
+// disk; use zero or negative value to disable
+func (this *Server) SetHashMemory(size int64) {
+	this.hashMemory = size
+}
+
+func (this *Server) Limits() execution.Limits {
+	return this.limits
+}
+
+// Set the default resource limits of each request; a request can
+// lower them, but not raise them
+func (this *Server) SetLimits(limits execution.Limits) {
+	this.limits = limits
+}
+
 func (this *Server) Serve() {
 	this.once.Do(func() {
 		// Use a threading model. Do not spawn a separate
@@ -117,8 +283,10 @@ func (this *Server) Serve() {
 }
 
 func (this *Server) doServe() {
-	for request := range this.channel {
+	for {
+		request, queue := this.workloads.next()
 		this.serviceRequest(request)
+		this.workloads.done(queue)
 	}
 }
 
@@ -136,6 +304,19 @@ func (this *Server) serviceRequest(request Request) {
 		}
 	}()
 
+	defer this.active.remove(request)
+
+	// Skip a request that was stopped while queued, e.g. by its client
+	if request.State() != RUNNING {
+		return
+	}
+
+	if !this.active.setPhase(request, PLANNING) {
+		request.Fail(errors.NewServiceErrorCancelled())
+		request.Failed(this)
+		return
+	}
+
 	request.Servicing()
 
 	namespace := request.Namespace()
@@ -146,6 +327,8 @@ func (this *Server) serviceRequest(request Request) {
 	prepared, err := this.getPrepared(request, namespace)
 	if err != nil {
 		request.Fail(err)
+	} else {
+		request.SetPrepared(prepared)
 	}
 
 	if (this.readonly || value.ToBool(request.Readonly())) &&
@@ -175,15 +358,50 @@ func (this *Server) serviceRequest(request Request) {
 		defer timer.Stop()
 	}
 
+	if request.Profile() == PROFILE_TIMINGS {
+		profiler := execution.NewProfiler(operator)
+		defer profiler.Done()
+		request.SetProfiler(profiler)
+	}
+
+	// The response writer enforces the result size of the request
+	limits := this.requestLimits(request)
+	request.SetLimits(limits)
+
+	this.active.setPhase(request, EXECUTING)
 	go request.Execute(this, prepared.Signature(), operator.StopChannel())
 
 	context := execution.NewContext(this.datastore, this.systemstore, namespace,
 		this.readonly, request.NamedArgs(), request.PositionalArgs(), request.Credentials(),
-		request.ScanConsistency(), request.ScanVector(),
-		request.Output())
+		request.ScanConsistency(), request.ScanVector(), limits,
+		request.ExplainFormat(), request.Output())
 	operator.RunOnce(context, nil)
 }
 
+// The lower of the server and request limits, ignoring zero or
+// negative values, and the limits that only the server sets
+func (this *Server) requestLimits(request Request) execution.Limits {
+	limits := request.Limits()
+	return execution.Limits{
+		MemoryQuota:   minLimit(this.limits.MemoryQuota, limits.MemoryQuota),
+		MaxFetch:      minLimit(this.limits.MaxFetch, limits.MaxFetch),
+		MaxResultSize: minLimit(this.limits.MaxResultSize, limits.MaxResultSize),
+		MaxMutations:  minLimit(this.limits.MaxMutations, limits.MaxMutations),
+		OrderLimit:    this.orderLimit,
+		UpdateLimit:   this.updateLimit,
+		SortMemory:    this.sortMemory,
+		HashMemory:    this.hashMemory,
+	}
+}
+
+func minLimit(server, request int64) int64 {
+	if server <= 0 || (request > 0 && request < server) {
+		return request
+	}
+
+	return server
+}
+
 func (this *Server) getPrepared(request Request, namespace string) (*plan.Prepared, errors.Error) {
 	prepared := request.Prepared()
 	if prepared == nil {
@@ -196,6 +414,19 @@ func (this *Server) getPrepared(request Request, namespace string) (*plan.Prepar
 		if err != nil {
 			return nil, errors.NewPlanError(err, "")
 		}
+
+		execute, ok := stmt.(*algebra.Execute)
+		if ok {
+			setUsingArgs(request, execute.Using())
+		}
+	}
+
+	if prepared.Stale() && prepared.Text() != "" {
+		var err error
+		prepared, err = this.reprepare(prepared, namespace)
+		if err != nil {
+			return nil, errors.NewPlanError(err, "")
+		}
 	}
 	if logging.LogLevel() >= logging.Trace {
 		// log EXPLAIN for the request
@@ -204,6 +435,64 @@ func (this *Server) getPrepared(request Request, namespace string) (*plan.Prepar
 	return prepared, nil
 }
 
+// Prepare a stale prepared statement again from its text, after the
+// indexes of a keyspace it uses have changed
+func (this *Server) reprepare(prepared *plan.Prepared, namespace string) (*plan.Prepared, error) {
+	stmt, err := n1ql.ParseStatement(prepared.Text())
+	if err != nil {
+		return nil, err
+	}
+
+	prepare, ok := stmt.(*algebra.Prepare)
+	if !ok {
+		return nil, fmt.Errorf("Invalid prepared statement text: %s", prepared.Text())
+	}
+
+	rv, err := plan.BuildPrepared(prepare.Statement(), this.datastore, this.systemstore, namespace, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// The request was classified by the type of the stale plan
+	if rv.StatementType() != prepared.StatementType() {
+		return nil, fmt.Errorf("Prepared statement text does not match its plan: %s", prepared.Text())
+	}
+
+	rv.SetName(prepared.Name())
+	rv.SetText(prepared.Text())
+
+	err = plan.PreparedCache().Replace(prepared, rv)
+	if err != nil {
+		return nil, err
+	}
+
+	return rv, nil
+}
+
+// Apply the arguments of EXECUTE ... USING to the request
+func setUsingArgs(request Request, using value.Value) {
+	if using == nil {
+		return
+	}
+
+	switch using.Type() {
+	case value.ARRAY:
+		args := using.Actual().([]interface{})
+		positionalArgs := make(value.Values, len(args))
+		for i, arg := range args {
+			positionalArgs[i] = value.NewValue(arg)
+		}
+		request.SetPositionalArgs(positionalArgs)
+	case value.OBJECT:
+		args := using.Actual().(map[string]interface{})
+		namedArgs := make(map[string]value.Value, len(args))
+		for name, arg := range args {
+			namedArgs[strings.TrimPrefix(name, "$")] = value.NewValue(arg)
+		}
+		request.SetNamedArgs(namedArgs)
+	}
+}
+
 func logExplain(prepared *plan.Prepared) {
 	var plan plan.Operator
 
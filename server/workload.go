@@ -0,0 +1,242 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/couchbaselabs/query/algebra"
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/parser/n1ql"
+	"github.com/couchbaselabs/query/plan"
+)
+
+const DEFAULT_WORKLOAD = "default"
+
+// A class of requests, with its own queue, concurrency and priority.
+// Requests are classified by user, then by statement type; other
+// requests are in the default class. The priority request parameter
+// names a class, and is honored only for the users of that class or
+// for the default class.
+type WorkloadClass struct {
+	Name        string   `json:"name"`
+	QueueDepth  int      `json:"queue_depth"` // Maximum queued requests; zero or negative for the server request cap
+	Concurrency int      `json:"concurrency"` // Maximum executing requests; zero or negative for the server thread count
+	Priority    int      `json:"priority"`    // Classes of higher priority are served first
+	Users       []string `json:"users,omitempty"`
+	Statements  []string `json:"statements,omitempty"` // Statement types, e.g. SELECT or INSERT
+}
+
+// Read workload classes from a JSON file containing an array of
+// classes.
+func LoadWorkloadClasses(path string) ([]*WorkloadClass, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var classes []*WorkloadClass
+	err = json.Unmarshal(bytes, &classes)
+	if err != nil {
+		return nil, err
+	}
+
+	return classes, nil
+}
+
+type workloadQueue struct {
+	class    *WorkloadClass
+	requests []Request
+	running  int
+}
+
+// Admission control of requests. Each class has a bounded queue, and
+// the server threads take the next request from the queue of highest
+// priority that is below its concurrency.
+type workloads struct {
+	mutex  sync.Mutex
+	ready  *sync.Cond
+	queues []*workloadQueue // By decreasing priority
+	byName map[string]*workloadQueue
+	typed  bool // Whether any class has statement types
+}
+
+func newWorkloads(classes []*WorkloadClass, requestCap, threadCount int) *workloads {
+	rv := &workloads{
+		byName: make(map[string]*workloadQueue, len(classes)+1),
+	}
+	rv.ready = sync.NewCond(&rv.mutex)
+
+	for _, class := range classes {
+		rv.add(class, requestCap, threadCount)
+	}
+
+	if rv.byName[DEFAULT_WORKLOAD] == nil {
+		rv.add(&WorkloadClass{Name: DEFAULT_WORKLOAD}, requestCap, threadCount)
+	}
+
+	return rv
+}
+
+func (this *workloads) add(class *WorkloadClass, requestCap, threadCount int) {
+	c := *class
+	this.typed = this.typed || len(c.Statements) > 0
+	if c.QueueDepth <= 0 {
+		c.QueueDepth = requestCap
+	}
+
+	if c.Concurrency <= 0 || c.Concurrency > threadCount {
+		c.Concurrency = threadCount
+	}
+
+	queue := &workloadQueue{class: &c}
+	this.byName[c.Name] = queue
+
+	i := len(this.queues)
+	this.queues = append(this.queues, queue)
+	for ; i > 0 && this.queues[i-1].class.Priority < c.Priority; i-- {
+		this.queues[i] = this.queues[i-1]
+	}
+
+	this.queues[i] = queue
+}
+
+// Queue a request in its class, calling admit before it can be
+// dequeued. Return the class, and false if its queue is full.
+func (this *workloads) submit(request Request, admit func(*WorkloadClass)) (*WorkloadClass, bool) {
+	stmtType := ""
+	if this.typed {
+		stmtType = statementType(request)
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	queue := this.classify(request, stmtType)
+	if len(queue.requests) >= queue.class.QueueDepth {
+		return queue.class, false
+	}
+
+	admit(queue.class)
+	queue.requests = append(queue.requests, request)
+	this.ready.Signal()
+	return queue.class, true
+}
+
+// Wait for the next request that can be served.
+func (this *workloads) next() (Request, *workloadQueue) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for {
+		for _, queue := range this.queues {
+			if len(queue.requests) > 0 && queue.running < queue.class.Concurrency {
+				request := queue.requests[0]
+				queue.requests[0] = nil
+				queue.requests = queue.requests[1:]
+				queue.running++
+				return request, queue
+			}
+		}
+
+		this.ready.Wait()
+	}
+}
+
+// Release the concurrency of a served request.
+func (this *workloads) done(queue *workloadQueue) {
+	this.mutex.Lock()
+	queue.running--
+	this.mutex.Unlock()
+
+	// Another thread may be waiting for this class
+	this.ready.Broadcast()
+}
+
+func (this *workloads) classify(request Request, stmtType string) *workloadQueue {
+	creds := request.Credentials()
+	if queue, ok := this.byName[request.Priority()]; ok {
+		if queue.class.Name == DEFAULT_WORKLOAD || hasUser(queue.class, creds) {
+			return queue
+		}
+	}
+
+	if len(creds) > 0 {
+		for _, queue := range this.queues {
+			if hasUser(queue.class, creds) {
+				return queue
+			}
+		}
+	}
+
+	if stmtType != "" {
+		for _, queue := range this.queues {
+			for _, statement := range queue.class.Statements {
+				if strings.EqualFold(statement, stmtType) {
+					return queue
+				}
+			}
+		}
+	}
+
+	return this.byName[DEFAULT_WORKLOAD]
+}
+
+func hasUser(class *WorkloadClass, creds datastore.Credentials) bool {
+	for _, user := range class.Users {
+		if _, ok := creds[user]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// The type of the parsed statement, e.g. SELECT or INSERT. EXECUTE
+// is classified by the plan of the prepared statement it executes,
+// and never by the statement type given by the client.
+func statementType(request Request) string {
+	if request.Prepared() != nil {
+		return request.Prepared().StatementType()
+	}
+
+	stmt, err := n1ql.ParseStatement(request.Statement())
+	if err != nil {
+		return ""
+	}
+
+	execute, ok := stmt.(*algebra.Execute)
+	if !ok {
+		return plan.StatementType(stmt)
+	}
+
+	if execute.Name() != "" {
+		if entry, ok := plan.PreparedCache().Named(execute.Name()); ok {
+			return entry.Prepared.StatementType()
+		}
+
+		return plan.StatementType(stmt)
+	}
+
+	prepared := &plan.Prepared{}
+	body, err := execute.Prepared().MarshalJSON()
+	if err == nil {
+		err = prepared.UnmarshalJSON(body)
+	}
+
+	if err != nil {
+		return plan.StatementType(stmt)
+	}
+
+	return prepared.StatementType()
+}
@@ -0,0 +1,253 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/couchbaselabs/query/datastore"
+	"github.com/couchbaselabs/query/datastore/mock"
+	"github.com/couchbaselabs/query/parser/n1ql"
+	"github.com/couchbaselabs/query/plan"
+)
+
+// A request with only what admission control looks at
+type workloadRequest struct {
+	Request
+	statement string
+	prepared  *plan.Prepared
+	priority  string
+	creds     datastore.Credentials
+}
+
+func (this *workloadRequest) Statement() string {
+	return this.statement
+}
+
+func (this *workloadRequest) Prepared() *plan.Prepared {
+	return this.prepared
+}
+
+func (this *workloadRequest) Priority() string {
+	return this.priority
+}
+
+func (this *workloadRequest) Credentials() datastore.Credentials {
+	return this.creds
+}
+
+// Prepare a statement against the mock datastore
+func newWorkloadPrepared(t *testing.T, name, text string) *plan.Prepared {
+	store, err := mock.NewDatastore("mock:")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	stmt, e := n1ql.ParseStatement(text)
+	if e != nil {
+		t.Fatalf("failed to parse %s: %v", text, e)
+	}
+
+	prepared, e := plan.BuildPrepared(stmt, store, nil, "p0", false)
+	if e != nil {
+		t.Fatalf("failed to prepare %s: %v", text, e)
+	}
+
+	prepared.SetName(name)
+	return prepared
+}
+
+func TestWorkloadClassify(t *testing.T) {
+	w := newWorkloads([]*WorkloadClass{
+		&WorkloadClass{Name: "admin", Priority: 10, Users: []string{"admin"}},
+		&WorkloadClass{Name: "writes", Priority: 1, Statements: []string{"insert", "update"}},
+	}, 10, 4)
+
+	prepared := newWorkloadPrepared(t, "wl_insert", "INSERT INTO b0 VALUES ('k', 1)")
+	err := plan.PreparedCache().AddNamed(prepared)
+	if err != nil {
+		t.Fatalf("failed to add named prepared: %v", err)
+	}
+	defer plan.PreparedCache().Delete("wl_insert")
+
+	admin := datastore.Credentials{"admin": ""}
+	guest := datastore.Credentials{"guest": ""}
+
+	cases := []struct {
+		request *workloadRequest
+		class   string
+	}{
+		{&workloadRequest{statement: "SELECT 1"}, DEFAULT_WORKLOAD},
+		{&workloadRequest{statement: " (SELECT 1)"}, DEFAULT_WORKLOAD},
+		{&workloadRequest{statement: "INSERT INTO b VALUES ('k', 1)"}, "writes"},
+		{&workloadRequest{statement: "update b set a = 1"}, "writes"},
+		{&workloadRequest{statement: "SELECT 1", creds: admin}, "admin"},
+		{&workloadRequest{statement: "INSERT INTO b VALUES ('k', 1)", creds: admin}, "admin"},
+
+		// EXECUTE is classified by the prepared statement
+		{&workloadRequest{prepared: prepared}, "writes"},
+		{&workloadRequest{statement: "EXECUTE wl_insert"}, "writes"},
+		{&workloadRequest{statement: "EXECUTE wl_missing"}, DEFAULT_WORKLOAD},
+
+		// The priority parameter is honored only for users of the class
+		{&workloadRequest{statement: "SELECT 1", priority: "admin"}, DEFAULT_WORKLOAD},
+		{&workloadRequest{statement: "SELECT 1", priority: "admin", creds: guest}, DEFAULT_WORKLOAD},
+		{&workloadRequest{statement: "SELECT 1", priority: "writes"}, DEFAULT_WORKLOAD},
+		{&workloadRequest{statement: "INSERT INTO b VALUES ('k', 1)", priority: DEFAULT_WORKLOAD}, DEFAULT_WORKLOAD},
+		{&workloadRequest{statement: "INSERT INTO b VALUES ('k', 1)", priority: "admin", creds: admin}, "admin"},
+	}
+
+	for i, c := range cases {
+		queue := w.classify(c.request, statementType(c.request))
+		if queue.class.Name != c.class {
+			t.Errorf("case %d: expected class %s, got %s", i, c.class, queue.class.Name)
+		}
+	}
+
+	// Looking up the class does not count as a use of the prepared statement
+	entry, ok := plan.PreparedCache().Named("wl_insert")
+	if !ok || entry.Uses != 0 {
+		t.Errorf("expected no uses of the prepared statement, got %v", entry)
+	}
+}
+
+func TestWorkloadStatementType(t *testing.T) {
+	store, err := mock.NewDatastore("mock:")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	// Decoding a plan looks up its keyspaces
+	datastore.SetDatastore(store)
+
+	cases := []struct {
+		statement string
+		stmtType  string
+	}{
+		{"SELECT 1", "SELECT"},
+		{"WITH a AS (SELECT 1) SELECT a", "SELECT"},
+		{"update b set a = 1", "UPDATE"},
+		{"EXPLAIN DELETE FROM b", "EXPLAIN"},
+		{"SELECT FROM", ""},
+
+		// EXECUTE of a JSON plan is classified by its operators, not
+		// by the statement type given with it
+		{`EXECUTE {"operator": {"#operator": "Delete", "namespace": "p0", "keyspace": "b0"}, "statement_type": "SELECT"}`, "DELETE"},
+		{`EXECUTE {"operator": {"#operator": "DummyScan"}, "statement_type": "UPDATE"}`, "SELECT"},
+		{"EXECUTE wl_missing", "EXECUTE"},
+	}
+
+	for i, c := range cases {
+		stmtType := statementType(&workloadRequest{statement: c.statement})
+		if stmtType != c.stmtType {
+			t.Errorf("case %d: expected statement type %q for %s, got %q", i, c.stmtType, c.statement, stmtType)
+		}
+	}
+}
+
+func TestWorkloadQueue(t *testing.T) {
+	w := newWorkloads([]*WorkloadClass{
+		&WorkloadClass{Name: "small", QueueDepth: 2, Statements: []string{"DELETE"}},
+	}, 10, 4)
+
+	admitted := 0
+	admit := func(class *WorkloadClass) {
+		admitted++
+	}
+
+	for i := 0; i < 2; i++ {
+		class, ok := w.submit(&workloadRequest{statement: "DELETE FROM b"}, admit)
+		if !ok || class.Name != "small" {
+			t.Fatalf("expected request %d to be queued in small, got %s %v", i, class.Name, ok)
+		}
+	}
+
+	class, ok := w.submit(&workloadRequest{statement: "DELETE FROM b"}, admit)
+	if ok || class.Name != "small" {
+		t.Fatalf("expected the queue of small to be full, got %s %v", class.Name, ok)
+	}
+
+	if admitted != 2 {
+		t.Fatalf("expected 2 admitted requests, got %d", admitted)
+	}
+
+	// Other classes are not affected
+	_, ok = w.submit(&workloadRequest{statement: "SELECT 1"}, admit)
+	if !ok {
+		t.Fatalf("expected the default class to queue the request")
+	}
+
+	// Requests are served in order within a class
+	first := w.byName["small"].requests[0]
+	request, queue := w.next()
+	if request != first {
+		t.Fatalf("expected the first queued request to be served first")
+	}
+	w.done(queue)
+
+	// A served request frees its place in the queue
+	_, ok = w.submit(&workloadRequest{statement: "DELETE FROM b"}, admit)
+	if !ok {
+		t.Fatalf("expected the queue of small to have room")
+	}
+}
+
+func TestWorkloadPriority(t *testing.T) {
+	w := newWorkloads([]*WorkloadClass{
+		&WorkloadClass{Name: "low", Priority: -1, Statements: []string{"DELETE"}},
+		&WorkloadClass{Name: "high", Priority: 5, Concurrency: 1, Statements: []string{"INSERT"}},
+	}, 10, 4)
+
+	admit := func(class *WorkloadClass) {}
+	submit := func(statement string) Request {
+		request := &workloadRequest{statement: statement}
+		_, ok := w.submit(request, admit)
+		if !ok {
+			t.Fatalf("failed to queue %s", statement)
+		}
+		return request
+	}
+
+	low := submit("DELETE FROM b")
+	def := submit("SELECT 1")
+	high1 := submit("INSERT INTO b VALUES ('k1', 1)")
+	high2 := submit("INSERT INTO b VALUES ('k2', 1)")
+
+	// The class of highest priority is served first
+	request, highQueue := w.next()
+	if request != high1 || highQueue.class.Name != "high" {
+		t.Fatalf("expected the first request of high, got class %s", highQueue.class.Name)
+	}
+
+	// high is at its concurrency, so the next classes are served
+	request, queue := w.next()
+	if request != def || queue.class.Name != DEFAULT_WORKLOAD {
+		t.Fatalf("expected the request of default, got class %s", queue.class.Name)
+	}
+	w.done(queue)
+
+	request, queue = w.next()
+	if request != low || queue.class.Name != "low" {
+		t.Fatalf("expected the request of low, got class %s", queue.class.Name)
+	}
+	w.done(queue)
+
+	// Releasing high wakes up a server waiting for it
+	served := make(chan Request)
+	go func() {
+		request, _ := w.next()
+		served <- request
+	}()
+
+	w.done(highQueue)
+	if request := <-served; request != high2 {
+		t.Fatalf("expected the second request of high")
+	}
+}
@@ -33,6 +33,7 @@ type MockQuery struct {
 	server.BaseRequest
 	response    *MockResponse
 	resultCount int
+	resultSize  int
 }
 
 func (this *MockQuery) Output() execution.Output {
@@ -49,6 +50,9 @@ func (this *MockQuery) Execute(srvr *server.Server, signature value.Value, stopN
 	defer this.Stop(server.COMPLETED)
 	this.NotifyStop(stopNotify)
 	this.writeResults()
+	this.writeErrors()
+	this.response.sortSpillCount = this.SortSpillCount()
+	this.response.sortSpillSize = this.SortSpillSize()
 	close(this.response.done)
 }
 
@@ -91,15 +95,37 @@ func (this *MockQuery) writeResults() bool {
 	return true
 }
 
+// Report the first error raised during execution
+func (this *MockQuery) writeErrors() {
+	for {
+		select {
+		case err := <-this.Errors():
+			if this.response.err == nil {
+				this.response.err = err
+			}
+		default:
+			return
+		}
+	}
+}
+
 func (this *MockQuery) writeResult(item value.Value) bool {
 	bytes, err := json.Marshal(item)
 	if err != nil {
 		panic(err.Error())
 	}
 
+	if !this.WithinResultSize(this.resultSize + len(bytes)) {
+		return false
+	}
+
+	this.resultSize += len(bytes)
+
 	this.resultCount++
 
-	var resultLine map[string]interface{}
+	// Rows are not necessarily objects, as with SELECT RAW or
+	// EXPLAIN FORMAT TEXT
+	var resultLine interface{}
 	json.Unmarshal(bytes, &resultLine)
 
 	this.response.results = append(this.response.results, resultLine)
@@ -107,10 +133,12 @@ func (this *MockQuery) writeResult(item value.Value) bool {
 }
 
 type MockResponse struct {
-	err      errors.Error
-	results  []interface{}
-	warnings []errors.Error
-	done     chan bool
+	err            errors.Error
+	results        []interface{}
+	warnings       []errors.Error
+	sortSpillCount uint64
+	sortSpillSize  uint64
+	done           chan bool
 }
 
 func (this *MockResponse) NoMoreResults() {
@@ -118,6 +146,11 @@ func (this *MockResponse) NoMoreResults() {
 }
 
 func Run(mockServer *server.Server, q string) ([]interface{}, []errors.Error, errors.Error) {
+	mr := run(mockServer, q)
+	return mr.results, mr.warnings, mr.err
+}
+
+func run(mockServer *server.Server, q string) *MockResponse {
 
 	var metrics value.Tristate
 	base := server.NewBaseRequest(q, nil, nil, nil, "json", value.FALSE, metrics, value.TRUE, nil, "", nil)
@@ -131,18 +164,17 @@ func Run(mockServer *server.Server, q string) ([]interface{}, []errors.Error, er
 		response:    mr,
 	}
 
-	select {
-	case mockServer.Channel() <- query:
-		// Wait until the request exits.
-		<-query.CloseNotify()
-	default:
-		// Timeout.
-		return nil, nil, errors.NewError(nil, "Query timed out")
+	class, ok := mockServer.Submit(query)
+	if !ok {
+		return &MockResponse{err: errors.NewServiceErrorQueueFull(class.Name)}
 	}
 
+	// Wait until the request exits.
+	<-query.CloseNotify()
+
 	// wait till all the results are ready
 	<-mr.done
-	return mr.results, mr.warnings, mr.err
+	return mr
 }
 
 func Start(site, pool string) *server.Server {
@@ -167,8 +199,7 @@ func Start(site, pool string) *server.Server {
 		)
 	}
 
-	channel := make(server.RequestChannel, 10)
-	server, err := server.NewServer(datastore, configstore, acctstore, "json", false, channel,
+	server, err := server.NewServer(datastore, configstore, acctstore, "json", false, 10,
 		4, 0, false, false, server.KEEP_ALIVE_DEFAULT)
 	if err != nil {
 		logging.Errorp(err.Error())
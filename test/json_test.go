@@ -17,6 +17,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/couchbaselabs/query/execution"
 	"github.com/couchbaselabs/query/server"
 	"github.com/dustin/go-jsonpointer"
 )
@@ -74,6 +75,36 @@ func TestAllCaseFiles(t *testing.T) {
 	}
 }
 
+// Set the server limits of a case, and return a function restoring
+// the previous ones
+func setLimits(qc *server.Server, v interface{}) func() {
+	limits, ok := v.(map[string]interface{})
+	if !ok {
+		return func() {}
+	}
+
+	get := func(name string) int64 {
+		f, _ := limits[name].(float64)
+		return int64(f)
+	}
+
+	prevLimits, orderLimit, updateLimit := qc.Limits(), qc.OrderLimit(), qc.UpdateLimit()
+	qc.SetLimits(execution.Limits{
+		MemoryQuota:   get("memory_quota"),
+		MaxFetch:      get("max_fetch"),
+		MaxResultSize: get("max_result_size"),
+		MaxMutations:  get("max_mutations"),
+	})
+	qc.SetOrderLimit(get("order_limit"))
+	qc.SetUpdateLimit(get("update_limit"))
+
+	return func() {
+		qc.SetLimits(prevLimits)
+		qc.SetOrderLimit(orderLimit)
+		qc.SetUpdateLimit(updateLimit)
+	}
+}
+
 func testCaseFile(t *testing.T, fname string, qc *server.Server) {
 	t.Logf("testCaseFile: %v\n", fname)
 	b, err := ioutil.ReadFile(fname)
@@ -111,7 +142,36 @@ func testCaseFile(t *testing.T, fname string, qc *server.Server) {
 		}
 		statements := v.(string)
 		t.Logf("  %d: %v\n", i, statements)
+		restoreLimits := setLimits(qc, c["limits"])
 		resultsActual, _, errActual := Run(qc, statements)
+		restoreLimits()
+
+		spill, _ := c["spill"].(bool)
+		sortSpill, _ := c["sortSpill"].(bool)
+		if spill || sortSpill {
+			// Operators that can spill to disk must return the same
+			// results as in memory
+			sortMemory, hashMemory := qc.SortMemory(), qc.HashMemory()
+			qc.SetSortMemory(1)
+			qc.SetHashMemory(1)
+			spilled := run(qc, statements)
+			qc.SetSortMemory(sortMemory)
+			qc.SetHashMemory(hashMemory)
+
+			if (errActual == nil) != (spilled.err == nil) {
+				t.Errorf("errors don't match when spilled, actual: %v, spilled: %v"+
+					", for case file: %v, index: %v", errActual, spilled.err, fname, i)
+			}
+			doResultsMatch(t, spilled.results, resultsActual, fname, i)
+
+			// Each value is a sorted run of its own, so that the
+			// runs are merged
+			if sortSpill && (spilled.sortSpillCount < 2 || spilled.sortSpillSize == 0) {
+				t.Errorf("expected sorted runs to be spilled, got %d runs of %d bytes"+
+					", for case file: %v, index: %v", spilled.sortSpillCount,
+					spilled.sortSpillSize, fname, i)
+			}
+		}
 
 		v, ok = c["postStatements"]
 		if ok {
@@ -146,6 +206,11 @@ func testCaseFile(t *testing.T, fname string, qc *server.Server) {
 				return
 			}
 			// TODO: Check that the actual err matches the expected err.
+			v, ok = c["errorCode"]
+			if ok && int32(v.(float64)) != errActual.Code() {
+				t.Errorf("expected error code %v, got %v, statements: %v"+
+					", for case file: %v, index: %v", v, errActual.Code(), statements, fname, i)
+			}
 			continue
 		}
 		if errExpected != "" {